@@ -52,6 +52,15 @@ func NewBaseExecutor(conf *job.ExecutorConfiguration) (*BaseExecutor, error) {
 		return nil, err
 	}
 
+	// The operator jobs of the executor count against the same limits as those of the server
+	// and of the other executors.
+	if processJobManager, ok := jobManager.(*job.ProcessJobManager); ok {
+		err = processJobManager.EnableSharedSlots(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	vault, err := vault.NewVault(conf.Vault)
 	if err != nil {
 		return nil, err
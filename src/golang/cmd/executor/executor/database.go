@@ -4,9 +4,12 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/collections/artifact"
 	"github.com/aqueducthq/aqueduct/lib/collections/artifact_result"
 	"github.com/aqueducthq/aqueduct/lib/collections/integration"
+	"github.com/aqueducthq/aqueduct/lib/collections/job_record"
 	"github.com/aqueducthq/aqueduct/lib/collections/notification"
 	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_cache"
 	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/run_request"
 	"github.com/aqueducthq/aqueduct/lib/collections/user"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
@@ -26,6 +29,7 @@ type Readers struct {
 	WorkflowDagResultReader workflow_dag_result.Reader
 	OperatorResultReader    operator_result.Reader
 	ArtifactResultReader    artifact_result.Reader
+	OperatorCacheReader     operator_cache.Reader
 }
 
 type Writers struct {
@@ -38,6 +42,9 @@ type Writers struct {
 	ArtifactWriter          artifact.Writer
 	ArtifactResultWriter    artifact_result.Writer
 	NotificationWriter      notification.Writer
+	OperatorCacheWriter     operator_cache.Writer
+	RunRequestWriter        run_request.Writer
+	JobRecordWriter         job_record.Writer
 }
 
 func CreateReaders(dbConf *database.DatabaseConfig) (*Readers, error) {
@@ -91,6 +98,11 @@ func CreateReaders(dbConf *database.DatabaseConfig) (*Readers, error) {
 		return nil, err
 	}
 
+	operatorCacheReader, err := operator_cache.NewReader(dbConf)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Readers{
 		WorkflowReader:          workflowReader,
 		WorkflowDagReader:       workflowDagReader,
@@ -102,6 +114,7 @@ func CreateReaders(dbConf *database.DatabaseConfig) (*Readers, error) {
 		WorkflowDagResultReader: workflowDagResultReader,
 		OperatorResultReader:    operatorResultReader,
 		ArtifactResultReader:    artifactResultReader,
+		OperatorCacheReader:     operatorCacheReader,
 	}, nil
 }
 
@@ -151,6 +164,21 @@ func CreateWriters(dbConf *database.DatabaseConfig) (*Writers, error) {
 		return nil, err
 	}
 
+	operatorCacheWriter, err := operator_cache.NewWriter(dbConf)
+	if err != nil {
+		return nil, err
+	}
+
+	runRequestWriter, err := run_request.NewWriter(dbConf)
+	if err != nil {
+		return nil, err
+	}
+
+	jobRecordWriter, err := job_record.NewWriter(dbConf)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Writers{
 		WorkflowWriter:          workflowWriter,
 		WorkflowDagWriter:       workflowDagWriter,
@@ -161,5 +189,8 @@ func CreateWriters(dbConf *database.DatabaseConfig) (*Writers, error) {
 		ArtifactWriter:          artifactWriter,
 		ArtifactResultWriter:    artifactResultWriter,
 		NotificationWriter:      notificationWriter,
+		OperatorCacheWriter:     operatorCacheWriter,
+		RunRequestWriter:        runRequestWriter,
+		JobRecordWriter:         jobRecordWriter,
 	}, nil
 }
@@ -8,7 +8,7 @@ import (
 )
 
 const (
-	requiredSchemaVersion = 8
+	requiredSchemaVersion = 20
 )
 
 type Executor interface {
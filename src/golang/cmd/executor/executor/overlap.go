@@ -0,0 +1,135 @@
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// How often a queued run checks whether the runs ahead of it finished.
+const queuedRunPollInterval = 5 * time.Second
+
+// applyOverlapPolicy enforces the overlap policy of the workflow on a scheduled run. The run is
+// recorded as pending before the policy is evaluated, so that runs that start at the same time
+// see each other. The runs that overlap with it are the pending runs of the workflow that were
+// created before it, so exactly one of the runs that start at the same time proceeds. It returns
+// whether the run should proceed.
+func (ex *WorkflowExecutor) applyOverlapPolicy(
+	ctx context.Context,
+	workflowDag *workflow_dag.WorkflowDag,
+) (bool, error) {
+	policy := workflowDag.Metadata.Schedule.GetOverlapPolicy()
+	if policy == workflow.AllowOverlapPolicy {
+		return true, nil
+	}
+
+	run, err := ex.WorkflowDagResultWriter.CreateWorkflowDagResult(
+		ctx,
+		workflowDag.Id,
+		nil, /* resumedFrom */
+		ex.Parameters,
+		ex.Database,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	// From here on, the run is handled like a run that was created when it was requested.
+	ex.WorkflowDagResultId = &run.Id
+
+	pending, err := utils.GetPendingWorkflowDagResults(ctx, ex.WorkflowId, ex.WorkflowDagResultReader, ex.Database)
+	if err != nil {
+		return false, err
+	}
+
+	ahead := utils.RunsAhead(pending, run)
+	if len(ahead) == 0 {
+		return true, nil
+	}
+
+	switch policy {
+	case workflow.SkipOverlapPolicy:
+		log.Infof("Skipping scheduled run %s of workflow %s, since %d of its runs are pending.", run.Id, ex.WorkflowId, len(ahead))
+		return false, ex.updateRunStatus(ctx, run.Id, shared.SkippedExecutionStatus)
+	case workflow.CancelPreviousOverlapPolicy:
+		// The executors of the cancelled runs stop their operators on their next poll.
+		for _, workflowDagResult := range ahead {
+			log.Infof("Cancelling run %s of workflow %s for scheduled run %s.", workflowDagResult.Id, ex.WorkflowId, run.Id)
+			if err := ex.updateRunStatus(ctx, workflowDagResult.Id, shared.CanceledExecutionStatus); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	case workflow.QueueOverlapPolicy:
+		return ex.waitForPendingRuns(ctx, run)
+	default:
+		return true, nil
+	}
+}
+
+// updateRunStatus sets the status of the run, unless it finished or was cancelled in the meantime.
+func (ex *WorkflowExecutor) updateRunStatus(
+	ctx context.Context,
+	workflowDagResultId uuid.UUID,
+	status shared.ExecutionStatus,
+) error {
+	_, err := ex.WorkflowDagResultWriter.UpdateWorkflowDagResultWithStatus(
+		ctx,
+		workflowDagResultId,
+		workflow_dag_result.RunningStatuses,
+		map[string]interface{}{workflow_dag_result.StatusColumn: status},
+		ex.WorkflowReader,
+		ex.NotificationWriter,
+		ex.UserReader,
+		ex.Database,
+	)
+	if err == database.ErrNoRows {
+		log.Infof("Run %s of workflow %s is no longer running, so it is not marked as %s.", workflowDagResultId, ex.WorkflowId, status)
+		return nil
+	}
+
+	return err
+}
+
+// waitForPendingRuns waits until the pending runs of the workflow that were created before the
+// run finish. It returns false if the run was cancelled while it waited.
+func (ex *WorkflowExecutor) waitForPendingRuns(
+	ctx context.Context,
+	run *workflow_dag_result.WorkflowDagResult,
+) (bool, error) {
+	log.Infof("Queueing scheduled run %s of workflow %s behind its pending runs.", run.Id, ex.WorkflowId)
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(queuedRunPollInterval):
+		}
+
+		run, err := ex.WorkflowDagResultReader.GetWorkflowDagResult(ctx, run.Id, ex.Database)
+		if err != nil {
+			return false, err
+		}
+
+		if run.Status != shared.PendingExecutionStatus {
+			log.Infof("Queued run %s of workflow %s has status %s, so it will not start.", run.Id, ex.WorkflowId, run.Status)
+			return false, nil
+		}
+
+		pending, err := utils.GetPendingWorkflowDagResults(ctx, ex.WorkflowId, ex.WorkflowDagResultReader, ex.Database)
+		if err != nil {
+			return false, err
+		}
+
+		if len(utils.RunsAhead(pending, run)) == 0 {
+			return true, nil
+		}
+	}
+}
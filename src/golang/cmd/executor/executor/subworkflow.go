@@ -0,0 +1,108 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// checkSubWorkflowCycle returns an error if the workflow is already running further up the chain
+// of parent runs, since the run would otherwise keep starting runs of itself.
+func (ex *WorkflowExecutor) checkSubWorkflowCycle(ctx context.Context) error {
+	runId := ex.Parent.WorkflowDagResultId
+	for {
+		workflowDag, err := ex.WorkflowDagReader.GetWorkflowDagByWorkflowDagResultId(ctx, runId, ex.Database)
+		if err != nil {
+			return err
+		}
+
+		if workflowDag.WorkflowId == ex.WorkflowId {
+			return errors.Newf("Workflow %s cannot run itself as a sub-workflow.", ex.WorkflowId)
+		}
+
+		run, err := ex.WorkflowDagResultReader.GetWorkflowDagResult(ctx, runId, ex.Database)
+		if err != nil {
+			return err
+		}
+
+		if run.ParentId.IsNull {
+			return nil
+		}
+
+		runId = run.ParentId.UUID
+	}
+}
+
+// reportToParent completes the parent operator once the run finished with the given status. If the
+// run succeeded, the artifacts the operator outputs are copied to the operator's output paths.
+func (ex *WorkflowExecutor) reportToParent(
+	ctx context.Context,
+	workflowDag *workflow_dag.WorkflowDag,
+	workflowStoragePaths *utils.WorkflowStoragePaths,
+	status shared.ExecutionStatus,
+) error {
+	summary := fmt.Sprintf("Run %s of workflow %s finished with status %s.", *ex.WorkflowDagResultId, workflowDag.Metadata.Name, status)
+	if status != shared.SucceededExecutionStatus {
+		return ex.writeParentMetadata(ctx, &operator_result.Metadata{Error: summary})
+	}
+
+	artifactIds := make(map[string]uuid.UUID, len(workflowDag.Artifacts))
+	for id, artifactObject := range workflowDag.Artifacts {
+		artifactIds[artifactObject.Name] = id
+	}
+
+	childStorage := storage.NewStorage(&workflowDag.StorageConfig)
+	parentStorage := storage.NewStorage(&ex.Parent.StorageConfig)
+	for i, name := range ex.Parent.OutputArtifactNames {
+		id, ok := artifactIds[name]
+		if !ok {
+			return errors.Newf("Workflow %s has no artifact named %s.", workflowDag.Metadata.Name, name)
+		}
+
+		for _, paths := range [][2]string{
+			{workflowStoragePaths.ArtifactPaths[id], ex.Parent.OutputContentPaths[i]},
+			{workflowStoragePaths.ArtifactMetadataPaths[id], ex.Parent.OutputMetadataPaths[i]},
+		} {
+			content, err := childStorage.Get(ctx, paths[0])
+			if err != nil {
+				return errors.Wrapf(err, "Unable to read artifact %s of workflow %s.", name, workflowDag.Metadata.Name)
+			}
+
+			if err := parentStorage.Put(ctx, paths[1], content); err != nil {
+				return errors.Wrapf(err, "Unable to copy artifact %s of workflow %s.", name, workflowDag.Metadata.Name)
+			}
+		}
+	}
+
+	return ex.writeParentMetadata(ctx, &operator_result.Metadata{
+		Logs: map[string]string{"stdout": summary},
+	})
+}
+
+// failParentOperator fails the parent operator with the error the run failed with.
+func (ex *WorkflowExecutor) failParentOperator(ctx context.Context, runErr error) {
+	err := ex.writeParentMetadata(ctx, &operator_result.Metadata{
+		Error: fmt.Sprintf("Unable to run workflow %s: %v", ex.WorkflowId, runErr),
+	})
+	if err != nil {
+		log.Errorf("Unable to write the metadata of the sub-workflow operator: %v", err)
+	}
+}
+
+func (ex *WorkflowExecutor) writeParentMetadata(ctx context.Context, metadata *operator_result.Metadata) error {
+	rawMetadata, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+
+	return storage.NewStorage(&ex.Parent.StorageConfig).Put(ctx, ex.Parent.MetadataPath, rawMetadata)
+}
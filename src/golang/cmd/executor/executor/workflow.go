@@ -4,6 +4,10 @@ import (
 	"context"
 	"time"
 
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/job"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/github"
 	"github.com/aqueducthq/aqueduct/lib/workflow/orchestrator"
@@ -19,6 +23,21 @@ type WorkflowExecutor struct {
 
 	WorkflowId    uuid.UUID
 	GithubManager github.Manager
+	// The operators to run, or nil to run the entire workflow.
+	Selection *workflow_dag.OperatorSelection
+	// The failed workflow dag result to resume, or nil to start a new run.
+	ResumedFrom *uuid.UUID
+	// The values that override the registered values of the param operators.
+	Parameters shared.Parameters
+	// The workflow dag result that was created when the run was requested, if any. Otherwise,
+	// it is created before the run is orchestrated.
+	WorkflowDagResultId *uuid.UUID
+	// Whether the run was launched by the cron schedule of the workflow.
+	Scheduled bool
+	// The priority the run was launched with, which its operator jobs are queued with.
+	Priority job.Priority
+	// The sub-workflow operator that launched the run, if any.
+	Parent *job.ParentOperator
 }
 
 func NewWorkflowExecutor(spec *job.WorkflowSpec, base *BaseExecutor) (*WorkflowExecutor, error) {
@@ -36,10 +55,46 @@ func NewWorkflowExecutor(spec *job.WorkflowSpec, base *BaseExecutor) (*WorkflowE
 		BaseExecutor:  base,
 		WorkflowId:    workflowId,
 		GithubManager: githubManager,
+		Selection:     spec.Selection,
+		ResumedFrom:   spec.ResumedFrom,
+		Parameters:    spec.Parameters,
+
+		WorkflowDagResultId: spec.WorkflowDagResultId,
+		Scheduled:           spec.Scheduled,
+		Parent:              spec.Parent,
+		Priority:            spec.Priority,
 	}, nil
 }
 
-func (ex *WorkflowExecutor) Run(ctx context.Context) error {
+func (ex *WorkflowExecutor) Run(ctx context.Context) (err error) {
+	// Operator jobs count against the workflow's concurrency limit, and are queued behind the
+	// jobs of runs with a higher priority.
+	ctx = job.WithWorkflowId(ctx, ex.WorkflowId.String())
+	ctx = job.WithPriority(ctx, ex.Priority)
+
+	// Once the run is orchestrated, the orchestrator records its status. A run that was created
+	// when it was requested must not stay pending if it fails before that.
+	orchestrated := false
+	defer func() {
+		if err != nil && !orchestrated && ex.WorkflowDagResultId != nil {
+			ex.failRequestedRun(ctx)
+		}
+	}()
+
+	// The sub-workflow operator that launched the run waits for its metadata, so it is written
+	// however the run fails.
+	if ex.Parent != nil {
+		defer func() {
+			if err != nil {
+				ex.failParentOperator(ctx, err)
+			}
+		}()
+
+		if err := ex.checkSubWorkflowCycle(ctx); err != nil {
+			return err
+		}
+	}
+
 	workflowDag, err := utils.ReadLatestWorkflowDagFromDatabase(
 		ctx,
 		ex.WorkflowId,
@@ -54,6 +109,16 @@ func (ex *WorkflowExecutor) Run(ctx context.Context) error {
 		return err
 	}
 
+	if ex.Scheduled {
+		proceed, err := ex.applyOverlapPolicy(ctx, workflowDag)
+		if err != nil {
+			return err
+		}
+		if !proceed {
+			return nil
+		}
+	}
+
 	githubClient, err := ex.GithubManager.GetClient(ctx, workflowDag.Metadata.UserId)
 	if err != nil {
 		return err
@@ -79,22 +144,82 @@ func (ex *WorkflowExecutor) Run(ctx context.Context) error {
 		return err
 	}
 
+	// The dag may have been updated since the run was requested.
+	if err := ex.Selection.Validate(workflowDag); err != nil {
+		return err
+	}
+
+	// A resumed run uses the same parameter values as the run it resumes.
+	parameters := ex.Parameters
+	if ex.ResumedFrom != nil {
+		resumedWorkflowDagResult, err := ex.WorkflowDagResultReader.GetWorkflowDagResult(ctx, *ex.ResumedFrom, ex.Database)
+		if err != nil {
+			return err
+		}
+
+		parameters = resumedWorkflowDagResult.Parameters
+	}
+
+	if err := workflowDag.OverrideParameters(parameters); err != nil {
+		return err
+	}
+
 	workflowStoragePaths := utils.GenerateWorkflowStoragePaths(workflowDag)
 
 	// Do not clean up artifact contents.
 	defer utils.CleanupWorkflowStorageFiles(ctx, workflowStoragePaths, &workflowDag.StorageConfig, true /* metadataOnly */)
 
+	// The run is created before it is orchestrated, so that the job record of the executor can
+	// name it. If the executor exits while the server is not watching it, only this run is
+	// marked as failed.
+	if ex.WorkflowDagResultId == nil {
+		run, err := ex.WorkflowDagResultWriter.CreateWorkflowDagResult(
+			ctx,
+			workflowDag.Id,
+			ex.ResumedFrom,
+			parameters,
+			ex.Database,
+		)
+		if err != nil {
+			return err
+		}
+
+		ex.WorkflowDagResultId = &run.Id
+	}
+
+	if err := job.RecordWorkflowDagResult(
+		ctx,
+		ex.WorkflowId,
+		*ex.WorkflowDagResultId,
+		ex.JobRecordWriter,
+		ex.Database,
+	); err != nil {
+		log.Errorf("Unable to record run %s in the job record: %v", *ex.WorkflowDagResultId, err)
+	}
+
+	orchestrated = true
 	status, err := orchestrator.Execute(
 		ctx,
 		workflowDag,
+		ex.Selection,
+		ex.ResumedFrom,
+		parameters,
+		ex.WorkflowDagResultId,
 		workflowStoragePaths,
 		pollingIntervalMS,
 		ex.WorkflowReader,
+		ex.WorkflowDagReader,
+		ex.WorkflowDagResultReader,
 		ex.WorkflowDagResultWriter,
+		ex.OperatorResultReader,
 		ex.OperatorResultWriter,
+		ex.OperatorCacheReader,
+		ex.OperatorCacheWriter,
+		ex.ArtifactResultReader,
 		ex.ArtifactResultWriter,
 		ex.NotificationWriter,
 		ex.UserReader,
+		ex.RunRequestWriter,
 		ex.Database,
 		ex.JobManager,
 		ex.Vault,
@@ -103,6 +228,13 @@ func (ex *WorkflowExecutor) Run(ctx context.Context) error {
 		return err
 	}
 
+	// The storage files of the run are cleaned up once it returns.
+	if ex.Parent != nil {
+		if err := ex.reportToParent(ctx, workflowDag, workflowStoragePaths, status); err != nil {
+			return err
+		}
+	}
+
 	log.WithFields(log.Fields{
 		"WorkflowId":    workflowDag.WorkflowId,
 		"WorkflowDagId": workflowDag.Id,
@@ -110,3 +242,21 @@ func (ex *WorkflowExecutor) Run(ctx context.Context) error {
 
 	return nil
 }
+
+// failRequestedRun marks the workflow dag result that was created when the run was requested
+// as failed, unless it was cancelled in the meantime.
+func (ex *WorkflowExecutor) failRequestedRun(ctx context.Context) {
+	_, err := ex.WorkflowDagResultWriter.UpdateWorkflowDagResultWithStatus(
+		ctx,
+		*ex.WorkflowDagResultId,
+		workflow_dag_result.RunningStatuses,
+		map[string]interface{}{workflow_dag_result.StatusColumn: shared.FailedExecutionStatus},
+		ex.WorkflowReader,
+		ex.NotificationWriter,
+		ex.UserReader,
+		ex.Database,
+	)
+	if err != nil && err != database.ErrNoRows {
+		log.Errorf("Unable to mark workflow dag result %s as failed: %v", *ex.WorkflowDagResultId, err)
+	}
+}
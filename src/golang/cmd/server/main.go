@@ -44,6 +44,9 @@ func main() {
 		log.Errorf("Failed to run missed workflows: %v", err)
 	}
 
+	s.StartSensors()
+	s.StartRunRequests()
+
 	// Start the HTTP server and listen for requests indefinitely.
 	log.Infof("You can use api key %s to connect to the server", serverConfig.ApiKey)
 	s.Run(*expose)
@@ -14,6 +14,7 @@ import (
 	"github.com/aqueducthq/aqueduct/internal/server/utils"
 	"github.com/aqueducthq/aqueduct/lib/collections"
 	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
 	"github.com/aqueducthq/aqueduct/lib/connection"
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/job"
@@ -21,6 +22,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/storage"
 	"github.com/aqueducthq/aqueduct/lib/vault"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/github"
+	"github.com/aqueducthq/aqueduct/lib/workflow/sensor"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/cors"
@@ -29,7 +31,7 @@ import (
 )
 
 const (
-	RequiredSchemaVersion = 8
+	RequiredSchemaVersion = 20
 
 	accountOrganizationId = "aqueduct"
 )
@@ -39,6 +41,8 @@ type AqServer struct {
 
 	Name          string
 	StorageConfig *shared.StorageConfig
+	// The directory that local directory sensors may watch.
+	SensorRoot    string
 	Database      database.Database
 	GithubManager github.Manager
 	JobManager    job.JobManager
@@ -60,6 +64,10 @@ func NewAqServer(conf *config.ServerConfiguration) *AqServer {
 	jobManager, err := job.NewProcessJobManager(&job.ProcessConfig{
 		BinaryDir:          path.Join(aqPath, job.BinaryDir),
 		OperatorStorageDir: path.Join(aqPath, job.OperatorStorageDir),
+		LogDir:             path.Join(aqPath, job.JobLogDir),
+
+		MaxConcurrentJobs:            conf.MaxConcurrentJobs,
+		MaxConcurrentJobsPerWorkflow: conf.MaxConcurrentJobsPerWorkflow,
 	})
 	if err != nil {
 		db.Close()
@@ -75,6 +83,13 @@ func NewAqServer(conf *config.ServerConfiguration) *AqServer {
 		log.Fatal("Unable to start vault: ", err)
 	}
 
+	storageDir := path.Join(aqPath, storage.DefaultFileStorageDir)
+	sensorRoot, err := sensor.Root(storageDir, conf.SensorDirectory)
+	if err != nil {
+		db.Close()
+		log.Fatal("Unable to set up the sensor directory: ", err)
+	}
+
 	readers, err := CreateReaders(db.Config())
 	if err != nil {
 		db.Close()
@@ -92,9 +107,10 @@ func NewAqServer(conf *config.ServerConfiguration) *AqServer {
 		StorageConfig: &shared.StorageConfig{
 			Type: shared.FileStorageType,
 			FileConfig: &shared.FileConfig{
-				Directory: path.Join(aqPath, storage.DefaultFileStorageDir),
+				Directory: storageDir,
 			},
 		},
+		SensorRoot:    sensorRoot,
 		Database:      db,
 		GithubManager: github.NewUnimplementedManager(),
 		JobManager:    jobManager,
@@ -152,6 +168,23 @@ func NewAqServer(conf *config.ServerConfiguration) *AqServer {
 		}
 	}
 
+	err = jobManager.EnablePersistence(db)
+	if err != nil {
+		db.Close()
+		log.Fatal("Unable to enable job persistence: ", err)
+	}
+
+	err = jobManager.EnableSharedSlots(db)
+	if err != nil {
+		db.Close()
+		log.Fatal("Unable to enable shared job slots: ", err)
+	}
+
+	err = s.reconcileJobs(ctx, jobManager)
+	if err != nil {
+		log.Errorf("Failed to reconcile jobs launched before the server restarted: %v", err)
+	}
+
 	err = s.initializeWorkflowCronJobs(ctx)
 	if err != nil {
 		log.Fatalf("Failed to create cron jobs for existing workflows: %v", err)
@@ -169,16 +202,21 @@ func (s *AqServer) StartWorkflowRetentionJob(period string) error {
 	// Delete old CronJob if it exists
 	s.JobManager.DeleteCronJob(ctx, name)
 
+	schedule, err := (&workflow.Schedule{CronSchedule: workflow.CronString(period)}).Timing()
+	if err != nil {
+		return errors.Wrap(err, "invalid workflow retention period")
+	}
+
 	spec := job.NewWorkflowRetentionJobSpec(
 		s.Database.Config(),
 		s.Vault.Config(),
 		s.JobManager.Config(),
 	)
 
-	err := s.JobManager.DeployCronJob(
+	err = s.JobManager.DeployCronJob(
 		ctx,
 		name,
-		period,
+		schedule,
 		spec,
 	)
 	if err != nil {
@@ -195,6 +233,10 @@ func (s *AqServer) AddHandler(route string, handler Handler) {
 			authentication.RequireApiKey(s.UserReader, s.Database),
 			verification.VerifyRequest(),
 		)
+	} else if handler.AuthMethod() == WebhookAuthMethod {
+		middleware = alice.New(
+			request_id.WithRequestId(),
+		)
 	} else {
 		panic(ErrUnsupportedAuthMethod)
 	}
@@ -0,0 +1,254 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/aqueducthq/aqueduct/internal/server/utils"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/run_request"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/param"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// Route: /workflow/{workflowId}/backfill
+// Method: POST
+// Params: workflowId
+// Request
+//	Headers:
+//		`api-key`: user's API Key
+//	Body:
+//		`parameter`: the name of the param operator to override
+//		`values`: the JSON values to run the workflow with, or
+//		`range`: an inclusive range of values, with a `start`, an `end` and an optional `step`.
+//			Dates (YYYY-MM-DD) advance by `step` days, and integers by `step`.
+//		`concurrency` (optional): the maximum number of runs in flight at once, 1 by default
+// Response:
+//	Body:
+//		`backfill_id`: the ID of the backfill
+//		`num_runs`: the number of runs that are launched
+//		`runs`: the `workflow_dag_result_id` and `parameters` of each run, in launch order
+//
+// One run of the workflow is recorded per value as a pending workflow dag result with the
+// overridden parameter, and requested from the server, which launches the runs in order, at
+// most `concurrency` of them at once.
+
+const maxBackfillRuns = 1000
+
+type backfillWorkflowRequest struct {
+	Parameter   string            `json:"parameter"`
+	Values      []json.RawMessage `json:"values"`
+	Range       *param.Range      `json:"range"`
+	Concurrency int               `json:"concurrency"`
+}
+
+type backfillWorkflowArgs struct {
+	workflowId  uuid.UUID
+	parameter   string
+	values      []string
+	concurrency int
+}
+
+type backfillRun struct {
+	WorkflowDagResultId uuid.UUID         `json:"workflow_dag_result_id"`
+	Parameters          shared.Parameters `json:"parameters"`
+}
+
+type backfillWorkflowResponse struct {
+	BackfillId uuid.UUID     `json:"backfill_id"`
+	NumRuns    int           `json:"num_runs"`
+	Runs       []backfillRun `json:"runs"`
+}
+
+type BackfillWorkflowHandler struct {
+	PostHandler
+
+	Database          database.Database
+	WorkflowReader    workflow.Reader
+	WorkflowDagReader workflow_dag.Reader
+	OperatorReader    operator.Reader
+
+	WorkflowDagResultWriter workflow_dag_result.Writer
+	RunRequestWriter        run_request.Writer
+}
+
+func (*BackfillWorkflowHandler) Name() string {
+	return "BackfillWorkflow"
+}
+
+func (h *BackfillWorkflowHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	common, statusCode, err := ParseCommonArgs(r)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	workflowIdStr := chi.URLParam(r, utils.WorkflowIdUrlParam)
+	workflowId, err := uuid.Parse(workflowIdStr)
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "Malformed workflow ID.")
+	}
+
+	ok, err := h.WorkflowReader.ValidateWorkflowOwnership(
+		r.Context(),
+		workflowId,
+		common.OrganizationId,
+		h.Database,
+	)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error during workflow ownership validation.")
+	}
+	if !ok {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "The organization does not own this workflow.")
+	}
+
+	var request backfillWorkflowRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, http.StatusBadRequest, errors.New("Unable to parse JSON input.")
+	}
+
+	if request.Parameter == "" {
+		return nil, http.StatusBadRequest, errors.New("No parameter was specified.")
+	}
+
+	if (len(request.Values) == 0) == (request.Range == nil) {
+		return nil, http.StatusBadRequest, errors.New("Exactly one of `values` and `range` must be set.")
+	}
+
+	var values []string
+	if request.Range != nil {
+		values, err = request.Range.Values(maxBackfillRuns)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+	} else {
+		values = make([]string, 0, len(request.Values))
+		for _, val := range request.Values {
+			values = append(values, string(val))
+		}
+	}
+
+	if len(values) > maxBackfillRuns {
+		return nil, http.StatusBadRequest, errors.Newf("A backfill can launch at most %d runs.", maxBackfillRuns)
+	}
+
+	if request.Concurrency < 0 {
+		return nil, http.StatusBadRequest, errors.New("The concurrency cannot be negative.")
+	}
+
+	concurrency := request.Concurrency
+	if concurrency == 0 {
+		concurrency = 1
+	}
+
+	statusCode, err = h.validateParameter(r.Context(), workflowId, request.Parameter)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	return &backfillWorkflowArgs{
+		workflowId:  workflowId,
+		parameter:   request.Parameter,
+		values:      values,
+		concurrency: concurrency,
+	}, http.StatusOK, nil
+}
+
+// validateParameter checks that the latest version of the workflow has a param operator
+// with the given name.
+func (h *BackfillWorkflowHandler) validateParameter(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	name string,
+) (int, error) {
+	dag, err := h.WorkflowDagReader.GetLatestWorkflowDag(ctx, workflowId, h.Database)
+	if err != nil {
+		return http.StatusInternalServerError, errors.Wrap(err, "Unable to retrieve the latest version of the workflow.")
+	}
+
+	operators, err := h.OperatorReader.GetOperatorsByWorkflowDagId(ctx, dag.Id, h.Database)
+	if err != nil {
+		return http.StatusInternalServerError, errors.Wrap(err, "Unable to retrieve the operators of the workflow.")
+	}
+
+	for _, op := range operators {
+		if op.Name == name && op.Spec.IsParam() {
+			return http.StatusOK, nil
+		}
+	}
+
+	return http.StatusBadRequest, errors.Newf("The workflow has no parameter named %s.", name)
+}
+
+func (h *BackfillWorkflowHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*backfillWorkflowArgs)
+
+	workflowObject, err := h.WorkflowReader.GetWorkflow(ctx, args.workflowId, h.Database)
+	if err != nil {
+		if err == database.ErrNoRows {
+			return nil, http.StatusBadRequest, errors.New("Unable to find workflow.")
+		}
+		return nil, http.StatusInternalServerError, errors.New("Unable to find workflow.")
+	}
+
+	dag, err := h.WorkflowDagReader.GetLatestWorkflowDag(ctx, workflowObject.Id, h.Database)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to retrieve the latest version of the workflow.")
+	}
+
+	txn, err := h.Database.BeginTx(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to backfill workflow.")
+	}
+	defer txn.Rollback(ctx)
+
+	backfillId := uuid.New()
+	runs := make([]backfillRun, 0, len(args.values))
+	for _, val := range args.values {
+		parameters := shared.Parameters{args.parameter: val}
+		run, err := h.WorkflowDagResultWriter.CreateWorkflowDagResult(
+			ctx,
+			dag.Id,
+			nil, /* resumedFrom */
+			parameters,
+			txn,
+		)
+		if err != nil {
+			return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to create the runs of the backfill.")
+		}
+
+		_, err = h.RunRequestWriter.CreateBackfillRunRequest(
+			ctx,
+			run.Id,
+			workflowObject.Id,
+			backfillId,
+			args.concurrency,
+			txn,
+		)
+		if err != nil {
+			return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to request the runs of the backfill.")
+		}
+
+		runs = append(runs, backfillRun{WorkflowDagResultId: run.Id, Parameters: parameters})
+	}
+
+	if err := txn.Commit(ctx); err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to backfill workflow.")
+	}
+
+	log.Infof("Requested %d runs of workflow %s for backfill %s.", len(runs), workflowObject.Id, backfillId)
+
+	return backfillWorkflowResponse{
+		BackfillId: backfillId,
+		NumRuns:    len(runs),
+		Runs:       runs,
+	}, http.StatusOK, nil
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aqueducthq/aqueduct/internal/server/utils"
+	"github.com/aqueducthq/aqueduct/lib/collections/notification"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/user"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// Route: /workflow/{workflowId}/result/{workflowDagResultId}/cancel
+// Method: POST
+// Params:
+//	`workflowId`: ID for `workflow` object
+//	`workflowDagResultId`: ID for the `workflow_dag_result` object of the run to cancel
+// Request:
+//	Headers:
+//		`api-key`: user's API Key
+// Response: none
+//
+// The run is marked as cancelled in the database. The executor running the workflow
+// picks this up on its next poll and stops all of the run's active operators. The route
+// returns 409 if the run is no longer running, e.g. because it finished in the meantime.
+
+type cancelWorkflowRunArgs struct {
+	*CommonArgs
+	workflowId          uuid.UUID
+	workflowDagResultId uuid.UUID
+}
+
+type CancelWorkflowRunHandler struct {
+	PostHandler
+
+	Database                database.Database
+	UserReader              user.Reader
+	WorkflowReader          workflow.Reader
+	WorkflowDagReader       workflow_dag.Reader
+	WorkflowDagResultReader workflow_dag_result.Reader
+
+	NotificationWriter      notification.Writer
+	WorkflowDagResultWriter workflow_dag_result.Writer
+}
+
+func (*CancelWorkflowRunHandler) Name() string {
+	return "CancelWorkflowRun"
+}
+
+func (h *CancelWorkflowRunHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	common, statusCode, err := ParseCommonArgs(r)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	workflowIdStr := chi.URLParam(r, utils.WorkflowIdUrlParam)
+	workflowId, err := uuid.Parse(workflowIdStr)
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "Malformed workflow ID.")
+	}
+
+	workflowDagResultIdStr := chi.URLParam(r, utils.WorkflowDagResultIdUrlParam)
+	workflowDagResultId, err := uuid.Parse(workflowDagResultIdStr)
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "Malformed workflow dag result ID.")
+	}
+
+	ok, err := h.WorkflowReader.ValidateWorkflowOwnership(
+		r.Context(),
+		workflowId,
+		common.OrganizationId,
+		h.Database,
+	)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error during workflow ownership validation.")
+	}
+	if !ok {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "The organization does not own this workflow.")
+	}
+
+	return &cancelWorkflowRunArgs{
+		CommonArgs:          common,
+		workflowId:          workflowId,
+		workflowDagResultId: workflowDagResultId,
+	}, http.StatusOK, nil
+}
+
+func (h *CancelWorkflowRunHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*cancelWorkflowRunArgs)
+
+	emptyResp := struct{}{}
+
+	workflowDag, err := h.WorkflowDagReader.GetWorkflowDagByWorkflowDagResultId(
+		ctx,
+		args.workflowDagResultId,
+		h.Database,
+	)
+	if err != nil {
+		if err == database.ErrNoRows {
+			return emptyResp, http.StatusBadRequest, errors.New("Unable to find workflow run.")
+		}
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to find workflow run.")
+	}
+
+	if workflowDag.WorkflowId != args.workflowId {
+		return emptyResp, http.StatusBadRequest, errors.New("The workflow run does not belong to this workflow.")
+	}
+
+	// The run is only cancelled if it is still running, since its executor may record that it
+	// finished at the same time.
+	_, err = h.WorkflowDagResultWriter.UpdateWorkflowDagResultWithStatus(
+		ctx,
+		args.workflowDagResultId,
+		workflow_dag_result.RunningStatuses,
+		map[string]interface{}{
+			workflow_dag_result.StatusColumn: shared.CanceledExecutionStatus,
+		},
+		h.WorkflowReader,
+		h.NotificationWriter,
+		h.UserReader,
+		h.Database,
+	)
+	if err == database.ErrNoRows {
+		workflowDagResult, err := h.WorkflowDagResultReader.GetWorkflowDagResult(
+			ctx,
+			args.workflowDagResultId,
+			h.Database,
+		)
+		if err != nil {
+			return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to find workflow run.")
+		}
+
+		return emptyResp, http.StatusConflict, errors.Newf(
+			"Only running workflow runs can be cancelled, but this run has status %s.",
+			workflowDagResult.Status,
+		)
+	}
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to cancel workflow run.")
+	}
+
+	return emptyResp, http.StatusOK, nil
+}
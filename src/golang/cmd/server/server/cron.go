@@ -4,26 +4,45 @@ import (
 	"context"
 	"time"
 
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/job"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/google/uuid"
-	"github.com/gorhill/cronexpr"
 	log "github.com/sirupsen/logrus"
 )
 
+// cronSchedule returns the schedule that the cron job of a workflow with the given schedule
+// follows, or nil if the workflow does not run on a cron schedule.
+func cronSchedule(schedule *workflow.Schedule) (job.CronSchedule, error) {
+	if schedule.CronSchedule == "" {
+		return nil, nil
+	}
+
+	timing, err := schedule.Timing()
+	if err != nil {
+		return nil, err
+	}
+
+	return timing, nil
+}
+
 func (s *AqServer) triggerMissedCronJobs(
 	ctx context.Context,
 	workflowId uuid.UUID,
-	cronSchedule string,
+	schedule *workflow.Schedule,
 	referenceTime time.Time,
 ) {
-	nextTwoTriggerTimeStamp := cronexpr.MustParse(cronSchedule).NextN(time.Now(), 2)
-	// We subtract the next two trigger timestamp to get the duration between consecutive cron jobs.
-	duration := nextTwoTriggerTimeStamp[1].Unix() - nextTwoTriggerTimeStamp[0].Unix()
-	// Subtracting the duration from the next trigger timestamp gives us the last expected trigger timestamp.
-	lastExpectedTriggerTime := nextTwoTriggerTimeStamp[0].Unix() - duration
-	if lastExpectedTriggerTime > referenceTime.Unix() {
+	timing, err := schedule.Timing()
+	if err != nil {
+		log.Errorf("Unable to parse the schedule of workflow %s: %v", workflowId, err)
+		return
+	}
+
+	lastExpectedTriggerTime := timing.Previous(time.Now())
+	if lastExpectedTriggerTime.After(referenceTime) {
 		// This means that the workflow should have been triggered, but it wasn't.
-		// So we manually trigger the workflow here.
+		// So we manually trigger the workflow here, as a scheduled run like the ones
+		// that the cron job launches.
 		_, _, err := (&RefreshWorkflowHandler{
 			Database:       s.Database,
 			JobManager:     s.JobManager,
@@ -34,6 +53,7 @@ func (s *AqServer) triggerMissedCronJobs(
 			ctx,
 			&refreshWorkflowArgs{
 				workflowId: workflowId,
+				scheduled:  true,
 			},
 		)
 		if err != nil {
@@ -60,7 +80,7 @@ func (s *AqServer) RunMissedCronJobs() error {
 			s.triggerMissedCronJobs(
 				ctx,
 				workflowLastRun.WorkflowId,
-				string(workflowLastRun.Schedule.CronSchedule),
+				&workflowLastRun.Schedule,
 				workflowLastRun.LastRunAt,
 			)
 		}
@@ -79,7 +99,7 @@ func (s *AqServer) RunMissedCronJobs() error {
 				s.triggerMissedCronJobs(
 					ctx,
 					workflow.Id,
-					string(workflow.Schedule.CronSchedule),
+					&workflow.Schedule,
 					workflow.CreatedAt,
 				)
 			}
@@ -7,8 +7,11 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/collections/integration"
 	"github.com/aqueducthq/aqueduct/lib/collections/notification"
 	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_cache"
 	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/run_request"
 	"github.com/aqueducthq/aqueduct/lib/collections/schema_version"
+	"github.com/aqueducthq/aqueduct/lib/collections/sensor_observation"
 	"github.com/aqueducthq/aqueduct/lib/collections/user"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
@@ -32,6 +35,8 @@ type Readers struct {
 	WorkflowWatcherReader   workflow_watcher.Reader
 	WorkflowDagResultReader workflow_dag_result.Reader
 	SchemaVersionReader     schema_version.Reader
+	SensorObservationReader sensor_observation.Reader
+	RunRequestReader        run_request.Reader
 	CustomReader            queries.Reader
 }
 
@@ -43,11 +48,14 @@ type Writers struct {
 	ArtifactResultWriter    artifact_result.Writer
 	OperatorWriter          operator.Writer
 	OperatorResultWriter    operator_result.Writer
+	OperatorCacheWriter     operator_cache.Writer
 	WorkflowWriter          workflow.Writer
 	WorkflowDagWriter       workflow_dag.Writer
 	WorkflowDagEdgeWriter   workflow_dag_edge.Writer
 	WorkflowWatcherWriter   workflow_watcher.Writer
 	WorkflowDagResultWriter workflow_dag_result.Writer
+	SensorObservationWriter sensor_observation.Writer
+	RunRequestWriter        run_request.Writer
 }
 
 func CreateReaders(dbConfig *database.DatabaseConfig) (*Readers, error) {
@@ -116,6 +124,16 @@ func CreateReaders(dbConfig *database.DatabaseConfig) (*Readers, error) {
 		return nil, err
 	}
 
+	sensorObservationReader, err := sensor_observation.NewReader(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	runRequestReader, err := run_request.NewReader(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	queriesReader, err := queries.NewReader(dbConfig)
 	if err != nil {
 		return nil, err
@@ -135,6 +153,8 @@ func CreateReaders(dbConfig *database.DatabaseConfig) (*Readers, error) {
 		WorkflowWatcherReader:   workflowWatcherReader,
 		WorkflowDagResultReader: workflowDagResultReader,
 		SchemaVersionReader:     schemaVersionReader,
+		SensorObservationReader: sensorObservationReader,
+		RunRequestReader:        runRequestReader,
 		CustomReader:            queriesReader,
 	}, nil
 }
@@ -175,6 +195,11 @@ func CreateWriters(dbConfig *database.DatabaseConfig) (*Writers, error) {
 		return nil, err
 	}
 
+	operatorCacheWriter, err := operator_cache.NewWriter(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	workflowWriter, err := workflow.NewWriter(dbConfig)
 	if err != nil {
 		return nil, err
@@ -200,6 +225,16 @@ func CreateWriters(dbConfig *database.DatabaseConfig) (*Writers, error) {
 		return nil, err
 	}
 
+	sensorObservationWriter, err := sensor_observation.NewWriter(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	runRequestWriter, err := run_request.NewWriter(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Writers{
 		UserWriter:              userWriter,
 		IntegrationWriter:       integrationWriter,
@@ -208,10 +243,13 @@ func CreateWriters(dbConfig *database.DatabaseConfig) (*Writers, error) {
 		ArtifactResultWriter:    artifactResultWriter,
 		OperatorWriter:          operatorWriter,
 		OperatorResultWriter:    operatorResultWriter,
+		OperatorCacheWriter:     operatorCacheWriter,
 		WorkflowWriter:          workflowWriter,
 		WorkflowDagWriter:       workflowDagWriter,
 		WorkflowDagEdgeWriter:   workflowDagEdgeWriter,
 		WorkflowWatcherWriter:   workflowWatcherWriter,
 		WorkflowDagResultWriter: workflowDagResultWriter,
+		SensorObservationWriter: sensorObservationWriter,
+		RunRequestWriter:        runRequestWriter,
 	}, nil
 }
@@ -0,0 +1,228 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/aqueducthq/aqueduct/internal/server/utils"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// Route: /workflow/{workflowId}/result/{workflowDagResultId}/operator/{operatorId}/approve
+// Route: /workflow/{workflowId}/result/{workflowDagResultId}/operator/{operatorId}/reject
+// Method: POST
+// Params:
+//	`workflowId`: ID for `workflow` object
+//	`workflowDagResultId`: ID for the `workflow_dag_result` object of the run awaiting approval
+//	`operatorId`: ID for the approval `operator` object
+// Request:
+//	Headers:
+//		`api-key`: user's API Key
+//	Body (optional):
+//		`reason`: why the run is approved or rejected
+// Response: none
+//
+// Only the approvers of the operator may approve or reject the run. The decision is recorded on
+// the operator's result. The executor running the workflow picks it up on its next poll, and
+// either continues the run or fails the operator. The route returns 409 if the operator is no
+// longer awaiting approval, e.g. because another approver already decided or it timed out.
+
+type decideApprovalRequest struct {
+	Reason string `json:"reason"`
+}
+
+type decideApprovalArgs struct {
+	*CommonArgs
+	workflowId          uuid.UUID
+	workflowDagResultId uuid.UUID
+	operatorId          uuid.UUID
+	reason              string
+}
+
+type DecideApprovalHandler struct {
+	PostHandler
+
+	// Whether the handler approves the run, or rejects it.
+	Approve bool
+
+	Database             database.Database
+	WorkflowReader       workflow.Reader
+	WorkflowDagReader    workflow_dag.Reader
+	OperatorReader       operator.Reader
+	OperatorResultReader operator_result.Reader
+
+	OperatorResultWriter operator_result.Writer
+}
+
+func (h *DecideApprovalHandler) Name() string {
+	if h.Approve {
+		return "ApproveOperator"
+	}
+
+	return "RejectOperator"
+}
+
+func (h *DecideApprovalHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	common, statusCode, err := ParseCommonArgs(r)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	workflowIdStr := chi.URLParam(r, utils.WorkflowIdUrlParam)
+	workflowId, err := uuid.Parse(workflowIdStr)
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "Malformed workflow ID.")
+	}
+
+	workflowDagResultIdStr := chi.URLParam(r, utils.WorkflowDagResultIdUrlParam)
+	workflowDagResultId, err := uuid.Parse(workflowDagResultIdStr)
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "Malformed workflow dag result ID.")
+	}
+
+	operatorIdStr := chi.URLParam(r, utils.OperatorIdUrlParam)
+	operatorId, err := uuid.Parse(operatorIdStr)
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "Malformed operator ID.")
+	}
+
+	ok, err := h.WorkflowReader.ValidateWorkflowOwnership(
+		r.Context(),
+		workflowId,
+		common.OrganizationId,
+		h.Database,
+	)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error during workflow ownership validation.")
+	}
+	if !ok {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "The organization does not own this workflow.")
+	}
+
+	var request decideApprovalRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && err != io.EOF {
+		return nil, http.StatusBadRequest, errors.New("Unable to parse JSON input.")
+	}
+
+	return &decideApprovalArgs{
+		CommonArgs:          common,
+		workflowId:          workflowId,
+		workflowDagResultId: workflowDagResultId,
+		operatorId:          operatorId,
+		reason:              request.Reason,
+	}, http.StatusOK, nil
+}
+
+func (h *DecideApprovalHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*decideApprovalArgs)
+
+	emptyResp := struct{}{}
+
+	workflowDag, err := h.WorkflowDagReader.GetWorkflowDagByWorkflowDagResultId(
+		ctx,
+		args.workflowDagResultId,
+		h.Database,
+	)
+	if err != nil {
+		if err == database.ErrNoRows {
+			return emptyResp, http.StatusBadRequest, errors.New("Unable to find workflow run.")
+		}
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to find workflow run.")
+	}
+
+	if workflowDag.WorkflowId != args.workflowId {
+		return emptyResp, http.StatusBadRequest, errors.New("The workflow run does not belong to this workflow.")
+	}
+
+	operatorResult, err := h.OperatorResultReader.GetOperatorResultByWorkflowDagResultIdAndOperatorId(
+		ctx,
+		args.workflowDagResultId,
+		args.operatorId,
+		h.Database,
+	)
+	if err != nil {
+		if err == database.ErrNoRows {
+			return emptyResp, http.StatusBadRequest, errors.New("The operator is not part of this workflow run.")
+		}
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to find operator result.")
+	}
+
+	operatorObject, err := h.OperatorReader.GetOperator(ctx, args.operatorId, h.Database)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to find operator.")
+	}
+
+	approval := operatorObject.Spec.Approval()
+	if approval == nil {
+		return emptyResp, http.StatusBadRequest, errors.New("Only approval operators can be approved or rejected.")
+	}
+
+	if operatorResult.Status != shared.AwaitingApprovalExecutionStatus {
+		return emptyResp, http.StatusConflict, errors.Newf(
+			"Only operators awaiting approval can be approved or rejected, but this operator has status %s.",
+			operatorResult.Status,
+		)
+	}
+
+	workflowObject, err := h.WorkflowReader.GetWorkflow(ctx, args.workflowId, h.Database)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to find workflow.")
+	}
+
+	if !approval.CanDecide(args.Id, args.Email, workflowObject.UserId) {
+		return emptyResp, http.StatusForbidden, errors.New("Only the approvers of this operator can approve or reject it.")
+	}
+
+	status, metadata := approvalDecision(h.Approve, args.Email, args.reason)
+	// The decision is only recorded if the operator still awaits approval, since another approver
+	// may have decided, or the approval may have timed out, since the operator result was read.
+	_, err = h.OperatorResultWriter.UpdateOperatorResultWithStatus(
+		ctx,
+		operatorResult.Id,
+		shared.AwaitingApprovalExecutionStatus,
+		map[string]interface{}{
+			operator_result.StatusColumn:   status,
+			operator_result.MetadataColumn: metadata,
+		},
+		h.Database,
+	)
+	if err != nil {
+		if err == database.ErrNoRows {
+			return emptyResp, http.StatusConflict, errors.New("The operator is no longer awaiting approval.")
+		}
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to record the decision.")
+	}
+
+	return emptyResp, http.StatusOK, nil
+}
+
+// approvalDecision returns the status and the metadata of the result of an approval operator that
+// `email` approved or rejected. A rejection is recorded as the operator's error.
+func approvalDecision(approve bool, email string, reason string) (shared.ExecutionStatus, *operator_result.Metadata) {
+	decision := fmt.Sprintf("Rejected by %s.", email)
+	if approve {
+		decision = fmt.Sprintf("Approved by %s.", email)
+	}
+	if reason != "" {
+		decision = fmt.Sprintf("%s Reason: %s", decision, reason)
+	}
+
+	if approve {
+		return shared.SucceededExecutionStatus, &operator_result.Metadata{
+			Logs: map[string]string{"stdout": decision},
+		}
+	}
+
+	return shared.FailedExecutionStatus, &operator_result.Metadata{Error: decision}
+}
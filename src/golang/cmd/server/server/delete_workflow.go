@@ -9,7 +9,10 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/collections/artifact"
 	"github.com/aqueducthq/aqueduct/lib/collections/artifact_result"
 	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_cache"
 	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/run_request"
+	"github.com/aqueducthq/aqueduct/lib/collections/sensor_observation"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_edge"
@@ -53,6 +56,9 @@ type DeleteWorkflowHandler struct {
 	WorkflowWatcherWriter   workflow_watcher.Writer
 	OperatorWriter          operator.Writer
 	OperatorResultWriter    operator_result.Writer
+	OperatorCacheWriter     operator_cache.Writer
+	SensorObservationWriter sensor_observation.Writer
+	RunRequestWriter        run_request.Writer
 	ArtifactWriter          artifact.Writer
 	ArtifactResultWriter    artifact_result.Writer
 }
@@ -212,6 +218,11 @@ func (h *DeleteWorkflowHandler) Perform(ctx context.Context, interfaceArgs inter
 		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error occurred while deleting artifact results.")
 	}
 
+	err = h.RunRequestWriter.DeleteRunRequestsByWorkflowId(ctx, workflowObject.Id, txn)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error occurred while deleting requested runs.")
+	}
+
 	err = h.WorkflowDagResultWriter.DeleteWorkflowDagResults(ctx, workflowDagResultIds, txn)
 	if err != nil {
 		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error occurred while deleting workflow dag results.")
@@ -237,6 +248,16 @@ func (h *DeleteWorkflowHandler) Perform(ctx context.Context, interfaceArgs inter
 		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error occurred while deleting workflow dags.")
 	}
 
+	err = h.OperatorCacheWriter.DeleteCacheEntriesByWorkflowId(ctx, workflowObject.Id, txn)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error occurred while deleting operator cache entries.")
+	}
+
+	err = h.SensorObservationWriter.DeleteObservationsByWorkflowId(ctx, workflowObject.Id, txn)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error occurred while deleting sensor observations.")
+	}
+
 	err = h.WorkflowWriter.DeleteWorkflow(ctx, workflowObject.Id, txn)
 	if err != nil {
 		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error occurred while deleting workflow.")
@@ -247,7 +268,7 @@ func (h *DeleteWorkflowHandler) Perform(ctx context.Context, interfaceArgs inter
 	}
 
 	// Delete storage files (artifact content and function files)
-	storagePaths := make([]string, 0, len(operatorIds)+len(artifactResultIds))
+	storagePaths := make([]string, 0, len(operatorIds)+len(artifactResultIds)+len(operatorResultIds))
 	for _, op := range operatorsToDelete {
 		if op.Spec.IsFunction() {
 			storagePaths = append(storagePaths, op.Spec.Function().StoragePath)
@@ -258,6 +279,10 @@ func (h *DeleteWorkflowHandler) Perform(ctx context.Context, interfaceArgs inter
 		storagePaths = append(storagePaths, art.ContentPath)
 	}
 
+	for _, operatorResult := range operatorResultsToDelete {
+		storagePaths = append(storagePaths, workflow_utils.OperatorResultLogsPath(operatorResult.Id))
+	}
+
 	// Note: for now we assume all workflow dags have the same storage config.
 	// This assumption will stay true until we allow users to configure custom storage config to store stuff.
 	storageConfig := workflowDagsToDelete[0].StorageConfig
@@ -6,12 +6,15 @@ import (
 	"net/http"
 
 	"github.com/aqueducthq/aqueduct/internal/server/utils"
+	"github.com/aqueducthq/aqueduct/lib/collections/integration"
+	"github.com/aqueducthq/aqueduct/lib/collections/sensor_observation"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/job"
 	shared_utils "github.com/aqueducthq/aqueduct/lib/lib_utils"
 	"github.com/aqueducthq/aqueduct/lib/vault"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/github"
+	"github.com/aqueducthq/aqueduct/lib/workflow/sensor"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/go-chi/chi"
 	"github.com/google/uuid"
@@ -20,18 +23,24 @@ import (
 type EditWorkflowHandler struct {
 	PostHandler
 
-	Database       database.Database
-	WorkflowReader workflow.Reader
-	WorkflowWriter workflow.Writer
-	JobManager     job.JobManager
-	Vault          vault.Vault
-	GithubManager  github.Manager
+	Database          database.Database
+	IntegrationReader integration.Reader
+	WorkflowReader    workflow.Reader
+	WorkflowWriter    workflow.Writer
+	JobManager        job.JobManager
+	Vault             vault.Vault
+	GithubManager     github.Manager
+	// The directory that local directory sensors may watch.
+	SensorRoot string
+
+	SensorObservationWriter sensor_observation.Writer
 }
 
 type editWorkflowInput struct {
-	WorkflowName        string             `json:"name"`
-	WorkflowDescription string             `json:"description"`
-	Schedule            *workflow.Schedule `json:"schedule"`
+	WorkflowName        string                    `json:"name"`
+	WorkflowDescription string                    `json:"description"`
+	Schedule            *workflow.Schedule        `json:"schedule"`
+	ExecutionPolicy     *workflow.ExecutionPolicy `json:"execution_policy"`
 }
 
 type editWorkflowArgs struct {
@@ -39,6 +48,7 @@ type editWorkflowArgs struct {
 	workflowName        string
 	workflowDescription string
 	schedule            *workflow.Schedule
+	executionPolicy     *workflow.ExecutionPolicy
 }
 
 func (*EditWorkflowHandler) Name() string {
@@ -94,8 +104,54 @@ func (h *EditWorkflowHandler) Prepare(r *http.Request) (interface{}, int, error)
 		return nil, http.StatusBadRequest, errors.New("Cannot pause a manually updated workflow.")
 	}
 
+	if policy := input.Schedule.GetOverlapPolicy(); !policy.IsValid() {
+		return nil, http.StatusBadRequest, errors.Newf("Unknown overlap policy %s.", policy)
+	}
+
+	if _, err := cronSchedule(input.Schedule); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	statusCode, err = validateCascadeSchedule(
+		r.Context(),
+		workflowId,
+		input.Schedule,
+		h.SensorRoot,
+		common.OrganizationId,
+		h.WorkflowReader,
+		h.Database,
+	)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	statusCode, err = validateSensorSchedule(
+		r.Context(),
+		input.Schedule,
+		h.SensorRoot,
+		common.OrganizationId,
+		h.IntegrationReader,
+		h.Database,
+	)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	if input.ExecutionPolicy != nil {
+		if input.ExecutionPolicy.TimeoutSeconds < 0 {
+			return nil, http.StatusBadRequest, errors.New("The timeout of a workflow cannot be negative.")
+		}
+
+		if !input.ExecutionPolicy.FailurePolicy.IsValid() {
+			return nil, http.StatusBadRequest, errors.Newf("Unknown failure policy %s.", input.ExecutionPolicy.FailurePolicy)
+		}
+	}
+
 	// Finally, we check if there are an updates at all.
-	if input.WorkflowName == "" && input.WorkflowDescription == "" && input.Schedule.Trigger == "" {
+	if input.WorkflowName == "" &&
+		input.WorkflowDescription == "" &&
+		input.Schedule.Trigger == "" &&
+		input.ExecutionPolicy == nil {
 		return nil, http.StatusBadRequest, errors.New("Edit request issued without any updates specified.")
 	}
 
@@ -104,9 +160,108 @@ func (h *EditWorkflowHandler) Prepare(r *http.Request) (interface{}, int, error)
 		workflowName:        input.WorkflowName,
 		workflowDescription: input.WorkflowDescription,
 		schedule:            input.Schedule,
+		executionPolicy:     input.ExecutionPolicy,
 	}, http.StatusOK, nil
 }
 
+// validateCascadeSchedule checks that a cascade trigger lists source workflows that the
+// organization owns and that it does not make the workflow trigger itself. `workflowId` is
+// uuid.Nil for a workflow that is not registered yet.
+func validateCascadeSchedule(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	schedule *workflow.Schedule,
+	sensorRoot string,
+	organizationId string,
+	workflowReader workflow.Reader,
+	db database.Database,
+) (int, error) {
+	if schedule.Trigger != workflow.CascadeUpdateTrigger {
+		return http.StatusOK, nil
+	}
+
+	if len(schedule.SourceWorkflowIds) == 0 {
+		return http.StatusBadRequest, errors.New("A cascade trigger must list at least one source workflow.")
+	}
+
+	if schedule.CronSchedule != "" {
+		return http.StatusBadRequest, errors.New("A workflow with a cascade trigger cannot have a cron schedule.")
+	}
+
+	condition := schedule.GetCascadeCondition()
+	if condition != workflow.AllSucceededCascadeCondition && condition != workflow.AnyFinishedCascadeCondition {
+		return http.StatusBadRequest, errors.Newf("Unknown cascade condition %s.", condition)
+	}
+
+	for _, sourceId := range schedule.SourceWorkflowIds {
+		ok, err := workflowReader.ValidateWorkflowOwnership(ctx, sourceId, organizationId, db)
+		if err != nil {
+			return http.StatusInternalServerError, errors.Wrap(err, "Unexpected error during workflow ownership validation.")
+		}
+		if !ok {
+			return http.StatusBadRequest, errors.Newf("The organization does not own source workflow %s.", sourceId)
+		}
+	}
+
+	workflows, err := workflowReader.GetAllWorkflows(ctx, db)
+	if err != nil {
+		return http.StatusInternalServerError, errors.Wrap(err, "Unable to retrieve workflows.")
+	}
+
+	if workflow.HasCascadeCycle(workflowId, schedule.SourceWorkflowIds, workflows) {
+		return http.StatusBadRequest, workflow.ErrCascadeCycle
+	}
+
+	return http.StatusOK, nil
+}
+
+// validateSensorSchedule checks that a sensor trigger has a valid sensor, and that the S3
+// integration it watches, if any, is owned by the organization.
+func validateSensorSchedule(
+	ctx context.Context,
+	schedule *workflow.Schedule,
+	sensorRoot string,
+	organizationId string,
+	integrationReader integration.Reader,
+	db database.Database,
+) (int, error) {
+	if schedule.Trigger != workflow.SensorUpdateTrigger {
+		return http.StatusOK, nil
+	}
+
+	if schedule.CronSchedule != "" {
+		return http.StatusBadRequest, errors.New("A workflow with a sensor trigger cannot have a cron schedule.")
+	}
+
+	if err := sensor.Validate(schedule.Sensor, sensorRoot); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	if schedule.Sensor.Type != workflow.StorageSensorType || schedule.Sensor.Storage.IntegrationId == nil {
+		return http.StatusOK, nil
+	}
+
+	integrationId := *schedule.Sensor.Storage.IntegrationId
+	ok, err := integrationReader.ValidateIntegrationOwnership(ctx, integrationId, organizationId, db)
+	if err != nil {
+		return http.StatusInternalServerError, errors.Wrap(err, "Unexpected error during integration ownership validation.")
+	}
+	if !ok {
+		return http.StatusBadRequest, errors.Newf("The organization does not own integration %s.", integrationId)
+	}
+
+	integrationObject, err := integrationReader.GetIntegration(ctx, integrationId, db)
+	if err != nil {
+		return http.StatusInternalServerError, errors.Wrap(err, "Unable to retrieve integration.")
+	}
+
+	if integrationObject.Service != integration.S3 {
+		return http.StatusBadRequest, errors.New("A storage sensor can only watch an S3 integration.")
+	}
+
+	return http.StatusOK, nil
+}
+
 func (h *EditWorkflowHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
 	args := interfaceArgs.(*editWorkflowArgs)
 
@@ -120,14 +275,37 @@ func (h *EditWorkflowHandler) Perform(ctx context.Context, interfaceArgs interfa
 	}
 
 	if args.schedule.Trigger != "" {
+		workflowObject, err := h.WorkflowReader.GetWorkflow(ctx, args.workflowId, h.Database)
+		if err != nil {
+			return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to retrieve workflow.")
+		}
+
+		err = seedSensor(
+			ctx,
+			args.workflowId,
+			&workflowObject.Schedule,
+			args.schedule,
+			h.SensorRoot,
+			h.SensorObservationWriter,
+			h.Vault,
+			h.Database,
+		)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+
 		cronjobName := shared_utils.AppendPrefix(args.workflowId.String())
-		err := h.updateWorkflowSchedule(ctx, args.workflowId.String(), cronjobName, args.schedule)
+		err = h.updateWorkflowSchedule(ctx, args.workflowId.String(), cronjobName, args.schedule)
 		if err != nil {
 			return nil, http.StatusInternalServerError, err
 		}
 		changes["schedule"] = args.schedule
 	}
 
+	if args.executionPolicy != nil {
+		changes[workflow.ExecutionPolicyColumn] = args.executionPolicy
+	}
+
 	_, err := h.WorkflowWriter.UpdateWorkflow(ctx, args.workflowId, changes, h.Database)
 	if err != nil {
 		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to update workflow.")
@@ -147,6 +325,11 @@ func (h *EditWorkflowHandler) updateWorkflowSchedule(
 	// schedule, we'll need to create a new cron job.
 	if !h.JobManager.CronJobExists(ctx, cronjobName) {
 		if newSchedule.CronSchedule != "" {
+			schedule, err := cronSchedule(newSchedule)
+			if err != nil {
+				return err
+			}
+
 			spec := job.NewWorkflowSpec(
 				cronjobName,
 				workflowId,
@@ -154,11 +337,15 @@ func (h *EditWorkflowHandler) updateWorkflowSchedule(
 				h.Vault.Config(),
 				h.JobManager.Config(),
 				h.GithubManager.Config(),
+				nil, /* selection */
+				nil, /* resumedFrom */
+				nil, /* parameters */
+				nil, /* workflowDagResultId */
 			)
-			err := h.JobManager.DeployCronJob(
+			err = h.JobManager.DeployCronJob(
 				ctx,
 				cronjobName,
-				string(newSchedule.CronSchedule),
+				schedule,
 				spec,
 			)
 			if err != nil {
@@ -172,14 +359,17 @@ func (h *EditWorkflowHandler) updateWorkflowSchedule(
 		// database by the changes map above, and `prepare` guarantees us that
 		// if `Paused` is true, then the workflow type is `Periodic`, which in
 		// turn means a schedule must be set.
-		newCronSchedule := string(newSchedule.CronSchedule)
+		schedule, err := cronSchedule(newSchedule)
+		if err != nil {
+			return err
+		}
 		if newSchedule.Paused {
 			// The `EditCronJob` helper automatically pauses a workflow when
-			// you set the cron job schedule to an empty string.
-			newCronSchedule = ""
+			// you set the cron job schedule to nil.
+			schedule = nil
 		}
 
-		err := h.JobManager.EditCronJob(ctx, cronjobName, newCronSchedule)
+		err = h.JobManager.EditCronJob(ctx, cronjobName, schedule)
 		if err != nil {
 			return errors.Wrap(err, "Unable to change workflow schedule.")
 		}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/aqueducthq/aqueduct/internal/server/utils"
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_edge"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/aqueducthq/aqueduct/lib/workflow/airflow"
+	workflow_utils "github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// Route: /workflow/{workflowId}/airflow
+// Method: GET
+// Params:
+//	`workflowId`: ID for `workflow` object
+// Request:
+//	Headers:
+//		`api-key`: user's API Key
+// Response:
+//	Body:
+//		an Airflow DAG file that runs the latest version of the workflow.
+//		The file does not contain the credentials of the integrations the workflow uses: its
+//		tasks read them from the Airflow Variables listed at the top of the file.
+
+type exportAirflowDagArgs struct {
+	*CommonArgs
+	workflowId uuid.UUID
+}
+
+type exportAirflowDagResponse struct {
+	fileName string
+	program  *bytes.Buffer
+}
+
+type ExportAirflowDagHandler struct {
+	GetHandler
+
+	Database              database.Database
+	JobManager            job.JobManager
+	ArtifactReader        artifact.Reader
+	OperatorReader        operator.Reader
+	WorkflowReader        workflow.Reader
+	WorkflowDagReader     workflow_dag.Reader
+	WorkflowDagEdgeReader workflow_dag_edge.Reader
+}
+
+func (*ExportAirflowDagHandler) Name() string {
+	return "ExportAirflowDag"
+}
+
+func (h *ExportAirflowDagHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	common, statusCode, err := ParseCommonArgs(r)
+	if err != nil {
+		return nil, statusCode, errors.Wrap(err, "Error when parsing common args.")
+	}
+
+	workflowIdStr := chi.URLParam(r, utils.WorkflowIdUrlParam)
+	workflowId, err := uuid.Parse(workflowIdStr)
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "Malformed workflow ID.")
+	}
+
+	ok, err := h.WorkflowReader.ValidateWorkflowOwnership(
+		r.Context(),
+		workflowId,
+		common.OrganizationId,
+		h.Database,
+	)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error during workflow ownership validation.")
+	}
+	if !ok {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "The organization does not own this workflow.")
+	}
+
+	return &exportAirflowDagArgs{
+		CommonArgs: common,
+		workflowId: workflowId,
+	}, http.StatusOK, nil
+}
+
+func (h *ExportAirflowDagHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*exportAirflowDagArgs)
+
+	emptyResp := exportAirflowDagResponse{}
+
+	// The tasks run the same commands as the jobs of the process job manager.
+	jobManager, ok := h.JobManager.(*job.ProcessJobManager)
+	if !ok {
+		return emptyResp, http.StatusInternalServerError, errors.New("Airflow DAGs can only be generated with the process job manager.")
+	}
+
+	dag, err := workflow_utils.ReadLatestWorkflowDagFromDatabase(
+		ctx,
+		args.workflowId,
+		h.WorkflowReader,
+		h.WorkflowDagReader,
+		h.OperatorReader,
+		h.ArtifactReader,
+		h.WorkflowDagEdgeReader,
+		h.Database,
+	)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to read workflow dag from the database.")
+	}
+
+	program, err := airflow.Compile(ctx, dag, jobManager)
+	if err != nil {
+		return emptyResp, http.StatusBadRequest, errors.Wrap(err, "Unable to generate Airflow DAG.")
+	}
+
+	return &exportAirflowDagResponse{
+		fileName: fmt.Sprintf("%s.py", args.workflowId),
+		program:  bytes.NewBuffer(program),
+	}, http.StatusOK, nil
+}
+
+func (*ExportAirflowDagHandler) SendResponse(w http.ResponseWriter, interfaceResp interface{}) {
+	resp := interfaceResp.(*exportAirflowDagResponse)
+	utils.SendSmallFileResponse(w, resp.fileName, resp.program)
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/aqueducthq/aqueduct/internal/server/utils"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	workflow_utils "github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// Route: /operator_result/{workflowDagResultId}/{operatorId}/logs
+// Method: GET
+// Params:
+//
+//	`workflowDagResultId`: ID for `workflow_dag_result` object
+//	`operatorId`: ID for `operator` object
+//
+// Request:
+//
+//	Headers:
+//		`api-key`: user's API Key
+//		`logs-offset`: (optional) the number of bytes of the logs to skip. Defaults to 0.
+//
+// Response:
+//
+//	Body:
+//		serialized `getOperatorResultLogsResponse`, the stdout and stderr of the operator's job
+//		starting at `logs-offset`. While the operator runs, the logs are streamed to storage
+//		periodically, so a client can tail them by passing the returned `offset` as the next
+//		`logs-offset`. Once the operator finishes, the logs are complete.
+type getOperatorResultLogsArgs struct {
+	*CommonArgs
+	workflowDagResultId uuid.UUID
+	operatorId          uuid.UUID
+	offset              int
+}
+
+type getOperatorResultLogsResponse struct {
+	Status shared.ExecutionStatus `json:"status"`
+	Logs   string                 `json:"logs"`
+	// The offset to request the rest of the logs from.
+	Offset int `json:"offset"`
+	// Whether the operator finished, so no more logs will be written.
+	Complete bool `json:"complete"`
+}
+
+type GetOperatorResultLogsHandler struct {
+	GetHandler
+
+	Database             database.Database
+	OperatorReader       operator.Reader
+	OperatorResultReader operator_result.Reader
+	WorkflowDagReader    workflow_dag.Reader
+}
+
+func (*GetOperatorResultLogsHandler) Name() string {
+	return "GetOperatorResultLogs"
+}
+
+func (*GetOperatorResultLogsHandler) Headers() []string {
+	return []string{utils.LogsOffsetHeader}
+}
+
+func (h *GetOperatorResultLogsHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	common, statusCode, err := ParseCommonArgs(r)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	workflowDagResultIdStr := chi.URLParam(r, utils.WorkflowDagResultIdUrlParam)
+	workflowDagResultId, err := uuid.Parse(workflowDagResultIdStr)
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "Malformed workflow dag result ID.")
+	}
+
+	operatorIdStr := chi.URLParam(r, utils.OperatorIdUrlParam)
+	operatorId, err := uuid.Parse(operatorIdStr)
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "Malformed operator ID.")
+	}
+
+	offset := 0
+	if offsetStr := r.Header.Get(utils.LogsOffsetHeader); offsetStr != "" {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return nil, http.StatusBadRequest, errors.New("Logs offset must be a non-negative integer.")
+		}
+	}
+
+	ok, err := h.OperatorReader.ValidateOperatorOwnership(
+		r.Context(),
+		common.OrganizationId,
+		operatorId,
+		h.Database,
+	)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error during operator ownership validation.")
+	}
+	if !ok {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "The organization does not own this operator.")
+	}
+
+	return &getOperatorResultLogsArgs{
+		CommonArgs:          common,
+		workflowDagResultId: workflowDagResultId,
+		operatorId:          operatorId,
+		offset:              offset,
+	}, http.StatusOK, nil
+}
+
+func (h *GetOperatorResultLogsHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*getOperatorResultLogsArgs)
+
+	emptyResp := getOperatorResultLogsResponse{}
+
+	workflowDag, err := h.WorkflowDagReader.GetWorkflowDagByWorkflowDagResultId(
+		ctx,
+		args.workflowDagResultId,
+		h.Database,
+	)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error occurred when retrieving workflow dag.")
+	}
+
+	dbOperatorResult, err := h.OperatorResultReader.GetOperatorResultByWorkflowDagResultIdAndOperatorId(
+		ctx,
+		args.workflowDagResultId,
+		args.operatorId,
+		h.Database,
+	)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error occurred when retrieving operator result.")
+	}
+
+	response := getOperatorResultLogsResponse{
+		Status: dbOperatorResult.Status,
+		Offset: args.offset,
+		Complete: dbOperatorResult.Status != shared.PendingExecutionStatus &&
+			dbOperatorResult.Status != shared.QueuedExecutionStatus &&
+			dbOperatorResult.Status != shared.AwaitingApprovalExecutionStatus,
+	}
+
+	logs, err := storage.NewStorage(&workflowDag.StorageConfig).Get(
+		ctx,
+		workflow_utils.OperatorResultLogsPath(dbOperatorResult.Id),
+	)
+	if err != nil {
+		// The logs are only written once the operator's job starts.
+		log.Infof("No logs found for operator result %s: %v", dbOperatorResult.Id, err)
+		return response, http.StatusOK, nil
+	}
+
+	if args.offset < len(logs) {
+		response.Logs = string(logs[args.offset:])
+		response.Offset = len(logs)
+	}
+
+	return response, http.StatusOK, nil
+}
@@ -20,6 +20,8 @@ type AuthMethod string
 
 const (
 	ApiKeyAuthMethod AuthMethod = "ApiKey"
+	// The handler authenticates the request itself, e.g. by checking its signature.
+	WebhookAuthMethod AuthMethod = "Webhook"
 )
 
 var ErrUnsupportedAuthMethod = errors.New("Auth method is not supported.")
@@ -68,7 +70,7 @@ type Handler interface {
 	Headers() []string
 	// 'GET' or 'POST'
 	Method() RequestMethod
-	// Auth on this route. For now, we supports APIKey.
+	// Auth on this route. For now, we support APIKey and Webhook.
 	AuthMethod() AuthMethod
 	// Parse the request and returns structured arguments of the request as an `interface{}`
 	Prepare(r *http.Request) (interface{}, int, error)
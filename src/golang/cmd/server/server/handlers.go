@@ -4,11 +4,38 @@ import "github.com/aqueducthq/aqueduct/internal/server/routes"
 
 func (s *AqServer) Handlers() map[string]Handler {
 	return map[string]Handler{
+		routes.ApproveOperatorRoute: &DecideApprovalHandler{
+			Approve:              true,
+			Database:             s.Database,
+			WorkflowReader:       s.WorkflowReader,
+			WorkflowDagReader:    s.WorkflowDagReader,
+			OperatorReader:       s.OperatorReader,
+			OperatorResultReader: s.OperatorResultReader,
+			OperatorResultWriter: s.OperatorResultWriter,
+		},
 		routes.ArchiveNotificationRoute: &ArchiveNotificationHandler{
 			NotificationReader: s.NotificationReader,
 			NotificationWriter: s.NotificationWriter,
 			Database:           s.Database,
 		},
+		routes.BackfillWorkflowRoute: &BackfillWorkflowHandler{
+			Database:          s.Database,
+			WorkflowReader:    s.WorkflowReader,
+			WorkflowDagReader: s.WorkflowDagReader,
+			OperatorReader:    s.OperatorReader,
+
+			WorkflowDagResultWriter: s.WorkflowDagResultWriter,
+			RunRequestWriter:        s.RunRequestWriter,
+		},
+		routes.CancelWorkflowRunRoute: &CancelWorkflowRunHandler{
+			Database:                s.Database,
+			UserReader:              s.UserReader,
+			WorkflowReader:          s.WorkflowReader,
+			WorkflowDagReader:       s.WorkflowDagReader,
+			WorkflowDagResultReader: s.WorkflowDagResultReader,
+			NotificationWriter:      s.NotificationWriter,
+			WorkflowDagResultWriter: s.WorkflowDagResultWriter,
+		},
 		routes.ConnectIntegrationRoute: &ConnectIntegrationHandler{
 			Database:          s.Database,
 			IntegrationWriter: s.IntegrationWriter,
@@ -34,16 +61,32 @@ func (s *AqServer) Handlers() map[string]Handler {
 			WorkflowWatcherWriter:   s.WorkflowWatcherWriter,
 			OperatorWriter:          s.OperatorWriter,
 			OperatorResultWriter:    s.OperatorResultWriter,
+			OperatorCacheWriter:     s.OperatorCacheWriter,
+			SensorObservationWriter: s.SensorObservationWriter,
+			RunRequestWriter:        s.RunRequestWriter,
 			ArtifactWriter:          s.ArtifactWriter,
 			ArtifactResultWriter:    s.ArtifactResultWriter,
 		},
 		routes.EditWorkflowRoute: &EditWorkflowHandler{
-			Database:       s.Database,
-			WorkflowReader: s.WorkflowReader,
-			WorkflowWriter: s.WorkflowWriter,
-			JobManager:     s.JobManager,
-			Vault:          s.Vault,
-			GithubManager:  s.GithubManager,
+			Database:          s.Database,
+			IntegrationReader: s.IntegrationReader,
+			WorkflowReader:    s.WorkflowReader,
+			WorkflowWriter:    s.WorkflowWriter,
+			JobManager:        s.JobManager,
+			Vault:             s.Vault,
+			GithubManager:     s.GithubManager,
+			SensorRoot:        s.SensorRoot,
+
+			SensorObservationWriter: s.SensorObservationWriter,
+		},
+		routes.ExportAirflowDagRoute: &ExportAirflowDagHandler{
+			Database:              s.Database,
+			JobManager:            s.JobManager,
+			ArtifactReader:        s.ArtifactReader,
+			OperatorReader:        s.OperatorReader,
+			WorkflowReader:        s.WorkflowReader,
+			WorkflowDagReader:     s.WorkflowDagReader,
+			WorkflowDagEdgeReader: s.WorkflowDagEdgeReader,
 		},
 		routes.ExportFunctionRoute: &ExportFunctionHandler{
 			Database:          s.Database,
@@ -66,6 +109,12 @@ func (s *AqServer) Handlers() map[string]Handler {
 			OperatorReader:       s.OperatorReader,
 			OperatorResultReader: s.OperatorResultReader,
 		},
+		routes.GetOperatorResultLogsRoute: &GetOperatorResultLogsHandler{
+			Database:             s.Database,
+			OperatorReader:       s.OperatorReader,
+			OperatorResultReader: s.OperatorResultReader,
+			WorkflowDagReader:    s.WorkflowDagReader,
+		},
 		routes.GetUserProfileRoute: &GetUserProfileHandler{},
 		routes.GetWorkflowRoute: &GetWorkflowHandler{
 			Database:                s.Database,
@@ -77,6 +126,11 @@ func (s *AqServer) Handlers() map[string]Handler {
 			WorkflowDagEdgeReader:   s.WorkflowDagEdgeReader,
 			WorkflowDagResultReader: s.WorkflowDagResultReader,
 		},
+		routes.IssueWebhookTokenRoute: &IssueWebhookTokenHandler{
+			Database:       s.Database,
+			Vault:          s.Vault,
+			WorkflowReader: s.WorkflowReader,
+		},
 		routes.ListBuiltinFunctionsRoute: &ListBuiltinFunctionsHandler{
 			StorageConfig: s.StorageConfig,
 		},
@@ -116,11 +170,14 @@ func (s *AqServer) Handlers() map[string]Handler {
 			Vault:             s.Vault,
 		},
 		routes.RefreshWorkflowRoute: &RefreshWorkflowHandler{
-			Database:       s.Database,
-			JobManager:     s.JobManager,
-			GithubManager:  s.GithubManager,
-			Vault:          s.Vault,
-			WorkflowReader: s.WorkflowReader,
+			Database:                s.Database,
+			JobManager:              s.JobManager,
+			GithubManager:           s.GithubManager,
+			Vault:                   s.Vault,
+			WorkflowReader:          s.WorkflowReader,
+			WorkflowDagReader:       s.WorkflowDagReader,
+			WorkflowDagResultReader: s.WorkflowDagResultReader,
+			OperatorReader:          s.OperatorReader,
 		},
 		routes.RegisterWorkflowRoute: &RegisterWorkflowHandler{
 			Database:      s.Database,
@@ -128,6 +185,7 @@ func (s *AqServer) Handlers() map[string]Handler {
 			GithubManager: s.GithubManager,
 			Vault:         s.Vault,
 			StorageConfig: s.StorageConfig,
+			SensorRoot:    s.SensorRoot,
 
 			ArtifactReader:    s.ArtifactReader,
 			IntegrationReader: s.IntegrationReader,
@@ -140,10 +198,45 @@ func (s *AqServer) Handlers() map[string]Handler {
 			WorkflowDagWriter:     s.WorkflowDagWriter,
 			WorkflowDagEdgeWriter: s.WorkflowDagEdgeWriter,
 			WorkflowWatcherWriter: s.WorkflowWatcherWriter,
+
+			SensorObservationWriter: s.SensorObservationWriter,
+		},
+		routes.RejectOperatorRoute: &DecideApprovalHandler{
+			Approve:              false,
+			Database:             s.Database,
+			WorkflowReader:       s.WorkflowReader,
+			WorkflowDagReader:    s.WorkflowDagReader,
+			OperatorReader:       s.OperatorReader,
+			OperatorResultReader: s.OperatorResultReader,
+			OperatorResultWriter: s.OperatorResultWriter,
 		},
 		routes.ResetApiKeyRoute: &ResetApiKeyHandler{
 			Database:   s.Database,
 			UserWriter: s.UserWriter,
 		},
+		routes.ResumeWorkflowRunRoute: &ResumeWorkflowRunHandler{
+			Database:                s.Database,
+			JobManager:              s.JobManager,
+			GithubManager:           s.GithubManager,
+			Vault:                   s.Vault,
+			WorkflowReader:          s.WorkflowReader,
+			WorkflowDagReader:       s.WorkflowDagReader,
+			WorkflowDagResultReader: s.WorkflowDagResultReader,
+			UserReader:              s.UserReader,
+			WorkflowDagResultWriter: s.WorkflowDagResultWriter,
+			NotificationWriter:      s.NotificationWriter,
+		},
+		routes.TriggerWebhookRoute: &TriggerWebhookHandler{
+			Database:                s.Database,
+			JobManager:              s.JobManager,
+			GithubManager:           s.GithubManager,
+			Vault:                   s.Vault,
+			WorkflowReader:          s.WorkflowReader,
+			WorkflowDagReader:       s.WorkflowDagReader,
+			OperatorReader:          s.OperatorReader,
+			UserReader:              s.UserReader,
+			WorkflowDagResultWriter: s.WorkflowDagResultWriter,
+			NotificationWriter:      s.NotificationWriter,
+		},
 	}
 }
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aqueducthq/aqueduct/internal/server/utils"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow/webhook"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+)
+
+// Route: /workflow/{workflowId}/webhook/token
+// Method: POST
+// Params: workflowId
+// Request
+//	Headers:
+//		`api-key`: user's API Key
+// Response:
+//	Body:
+//		`token`: the secret token that signs the requests to the workflow's webhook
+//
+// The workflow must have a webhook trigger. Issuing a token revokes the token issued before it.
+
+type issueWebhookTokenArgs struct {
+	workflowId uuid.UUID
+}
+
+type issueWebhookTokenResponse struct {
+	Token string `json:"token"`
+}
+
+type IssueWebhookTokenHandler struct {
+	PostHandler
+
+	Database       database.Database
+	Vault          vault.Vault
+	WorkflowReader workflow.Reader
+}
+
+func (*IssueWebhookTokenHandler) Name() string {
+	return "IssueWebhookToken"
+}
+
+func (h *IssueWebhookTokenHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	common, statusCode, err := ParseCommonArgs(r)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	workflowIdStr := chi.URLParam(r, utils.WorkflowIdUrlParam)
+	workflowId, err := uuid.Parse(workflowIdStr)
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "Malformed workflow ID.")
+	}
+
+	ok, err := h.WorkflowReader.ValidateWorkflowOwnership(
+		r.Context(),
+		workflowId,
+		common.OrganizationId,
+		h.Database,
+	)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error during workflow ownership validation.")
+	}
+	if !ok {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "The organization does not own this workflow.")
+	}
+
+	return &issueWebhookTokenArgs{
+		workflowId: workflowId,
+	}, http.StatusOK, nil
+}
+
+func (h *IssueWebhookTokenHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*issueWebhookTokenArgs)
+
+	emptyResp := issueWebhookTokenResponse{}
+
+	workflowObject, err := h.WorkflowReader.GetWorkflow(ctx, args.workflowId, h.Database)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to find workflow.")
+	}
+
+	if workflowObject.Schedule.Trigger != workflow.WebhookUpdateTrigger {
+		return emptyResp, http.StatusBadRequest, errors.New("The workflow does not have a webhook trigger.")
+	}
+
+	token, err := webhook.IssueToken(ctx, args.workflowId, h.Vault)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to issue webhook token.")
+	}
+
+	return issueWebhookTokenResponse{Token: token}, http.StatusOK, nil
+}
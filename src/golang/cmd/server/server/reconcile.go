@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/job_record"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const orphanedRunErrMsg = "The workflow run was interrupted because the server restarted while it was running."
+
+// reconcileJobs recovers the jobs launched before the server restarted. Workflow runs that were
+// still pending when the server started and whose executor is no longer running are marked
+// as failed. A run whose executor is still running is marked as failed only if the executor
+// exits without finishing it. Requested runs that were not launched yet are left to be launched.
+func (s *AqServer) reconcileJobs(ctx context.Context, jobManager *job.ProcessJobManager) error {
+	startTime := time.Now()
+
+	runRequests, err := s.RunRequestReader.GetRunRequests(ctx, s.Database)
+	if err != nil {
+		return errors.Wrap(err, "Unable to get requested runs.")
+	}
+
+	skipped := make(map[uuid.UUID]bool, len(runRequests))
+	for _, runRequest := range runRequests {
+		if runRequest.LaunchedAt.IsNull {
+			skipped[runRequest.WorkflowDagResultId] = true
+		}
+	}
+
+	reattached, err := jobManager.Reconcile(ctx, func(record job_record.JobRecord) {
+		// An executor that has not recorded its run yet has not started it either.
+		if record.WorkflowDagResultId.IsNull {
+			return
+		}
+
+		s.failOrphanedWorkflowDagResults(context.Background(), []uuid.UUID{record.WorkflowDagResultId.UUID})
+	})
+	if err != nil {
+		return errors.Wrap(err, "Unable to reconcile job records.")
+	}
+
+	// The runs of reattached executors are still running. The run of an executor that did not
+	// record it is unknown, so none of the runs of its workflow are marked as failed.
+	unknownRunWorkflows := make(map[uuid.UUID]bool, len(reattached))
+	for _, record := range reattached {
+		if record.WorkflowId.IsNull {
+			continue
+		}
+
+		if record.WorkflowDagResultId.IsNull {
+			unknownRunWorkflows[record.WorkflowId.UUID] = true
+			continue
+		}
+
+		skipped[record.WorkflowDagResultId.UUID] = true
+	}
+
+	workflows, err := s.WorkflowReader.GetAllWorkflows(ctx, s.Database)
+	if err != nil {
+		return errors.Wrap(err, "Unable to get workflows from database.")
+	}
+
+	for _, wf := range workflows {
+		if unknownRunWorkflows[wf.Id] {
+			continue
+		}
+
+		workflowDagResults, err := s.WorkflowDagResultReader.GetWorkflowDagResultsByWorkflowId(ctx, wf.Id, s.Database)
+		if err != nil {
+			log.Errorf("Unable to get the runs of workflow %s: %v", wf.Id, err)
+			continue
+		}
+
+		orphanedIds := make([]uuid.UUID, 0, len(workflowDagResults))
+		for _, workflowDagResult := range workflowDagResults {
+			isRunning := workflowDagResult.Status == shared.PendingExecutionStatus ||
+				workflowDagResult.Status == shared.AwaitingApprovalExecutionStatus
+			if isRunning && workflowDagResult.CreatedAt.Before(startTime) && !skipped[workflowDagResult.Id] {
+				orphanedIds = append(orphanedIds, workflowDagResult.Id)
+			}
+		}
+
+		s.failOrphanedWorkflowDagResults(ctx, orphanedIds)
+	}
+
+	return nil
+}
+
+// failOrphanedWorkflowDagResults marks the given runs, along with their pending operator and
+// artifact results, as failed, unless they finished in the meantime.
+func (s *AqServer) failOrphanedWorkflowDagResults(ctx context.Context, workflowDagResultIds []uuid.UUID) {
+	orphanedIds := make([]uuid.UUID, 0, len(workflowDagResultIds))
+	for _, workflowDagResultId := range workflowDagResultIds {
+		log.Infof("Marking orphaned run %s as failed.", workflowDagResultId)
+		_, err := s.WorkflowDagResultWriter.UpdateWorkflowDagResultWithStatus(
+			ctx,
+			workflowDagResultId,
+			workflow_dag_result.RunningStatuses,
+			map[string]interface{}{
+				workflow_dag_result.StatusColumn: shared.FailedExecutionStatus,
+			},
+			s.WorkflowReader,
+			s.NotificationWriter,
+			s.UserReader,
+			s.Database,
+		)
+		if err == database.ErrNoRows {
+			// The run finished or was cancelled in the meantime.
+			continue
+		}
+		if err != nil {
+			log.Errorf("Unable to mark run %s as failed: %v", workflowDagResultId, err)
+			continue
+		}
+
+		orphanedIds = append(orphanedIds, workflowDagResultId)
+	}
+
+	if len(orphanedIds) == 0 {
+		return
+	}
+
+	operatorResults, err := s.OperatorResultReader.GetOperatorResultsByWorkflowDagResultIds(ctx, orphanedIds, s.Database)
+	if err != nil {
+		log.Errorf("Unable to get the operator results of orphaned runs: %v", err)
+	}
+
+	for _, operatorResult := range operatorResults {
+		if operatorResult.Status != shared.PendingExecutionStatus &&
+			operatorResult.Status != shared.QueuedExecutionStatus &&
+			operatorResult.Status != shared.AwaitingApprovalExecutionStatus {
+			continue
+		}
+
+		_, err := s.OperatorResultWriter.UpdateOperatorResult(
+			ctx,
+			operatorResult.Id,
+			map[string]interface{}{
+				operator_result.StatusColumn:   shared.FailedExecutionStatus,
+				operator_result.MetadataColumn: &operator_result.Metadata{Error: orphanedRunErrMsg},
+			},
+			s.Database,
+		)
+		if err != nil {
+			log.Errorf("Unable to mark operator result %s as failed: %v", operatorResult.Id, err)
+		}
+	}
+
+	artifactResults, err := s.ArtifactResultReader.GetArtifactResultsByWorkflowDagResultIds(ctx, orphanedIds, s.Database)
+	if err != nil {
+		log.Errorf("Unable to get the artifact results of orphaned runs: %v", err)
+	}
+
+	for _, artifactResult := range artifactResults {
+		if artifactResult.Status != shared.PendingExecutionStatus {
+			continue
+		}
+
+		_, err := s.ArtifactResultWriter.UpdateArtifactResult(
+			ctx,
+			artifactResult.Id,
+			map[string]interface{}{
+				artifact_result.StatusColumn: shared.FailedExecutionStatus,
+			},
+			s.Database,
+		)
+		if err != nil {
+			log.Errorf("Unable to mark artifact result %s as failed: %v", artifactResult.Id, err)
+		}
+	}
+}
@@ -2,22 +2,53 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/aqueducthq/aqueduct/internal/server/utils"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/job"
 	"github.com/aqueducthq/aqueduct/lib/vault"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/github"
+	"github.com/aqueducthq/aqueduct/lib/workflow/orchestrator"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/go-chi/chi"
 	"github.com/google/uuid"
 )
 
+// Route: /workflow/{workflowId}/refresh
+// Method: POST
+// Params: workflowId
+// Request
+//	Headers:
+//		`api-key`: user's API Key
+//	Body (optional):
+//		`from_operators`: run these operators and everything downstream of them
+//		`only_operators`: run only these operators
+//		`upstream_of`: run these operators and everything upstream of them
+//		At most one of these can be set. The artifacts that the selected operators read from
+//		operators that are not selected are reused from the latest successful run, so such a
+//		selection is rejected if the latest version of the workflow has not run successfully.
+// Response: none
+
 type refreshWorkflowArgs struct {
 	workflowId uuid.UUID
+	// The operators to run, or nil to run the entire workflow.
+	selection *workflow_dag.OperatorSelection
+	// The values that override the registered values of the param operators, if any.
+	parameters shared.Parameters
+	// The workflow dag result that was created for the run when it was requested, if any.
+	workflowDagResultId *uuid.UUID
+	// Whether the run stands in for a run of the workflow's schedule, so that the workflow's
+	// overlap policy applies to it.
+	scheduled bool
 }
 
 // Refresh workflow creates a new workflow version by
@@ -25,11 +56,14 @@ type refreshWorkflowArgs struct {
 type RefreshWorkflowHandler struct {
 	PostHandler
 
-	Database       database.Database
-	JobManager     job.JobManager
-	GithubManager  github.Manager
-	Vault          vault.Vault
-	WorkflowReader workflow.Reader
+	Database                database.Database
+	JobManager              job.JobManager
+	GithubManager           github.Manager
+	Vault                   vault.Vault
+	WorkflowReader          workflow.Reader
+	WorkflowDagReader       workflow_dag.Reader
+	WorkflowDagResultReader workflow_dag_result.Reader
+	OperatorReader          operator.Reader
 }
 
 func (*RefreshWorkflowHandler) Name() string {
@@ -65,11 +99,65 @@ func (h *RefreshWorkflowHandler) Prepare(r *http.Request) (interface{}, int, err
 		return nil, http.StatusBadRequest, errors.Wrap(err, "The organization does not own this workflow.")
 	}
 
+	var selection workflow_dag.OperatorSelection
+	err = json.NewDecoder(r.Body).Decode(&selection)
+	if err != nil && err != io.EOF {
+		return nil, http.StatusBadRequest, errors.New("Unable to parse JSON input.")
+	}
+
+	if selection.IsEmpty() {
+		return &refreshWorkflowArgs{
+			workflowId: workflowId,
+		}, http.StatusOK, nil
+	}
+
+	statusCode, err = h.validateSelection(r.Context(), workflowId, &selection)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
 	return &refreshWorkflowArgs{
 		workflowId: workflowId,
+		selection:  &selection,
 	}, http.StatusOK, nil
 }
 
+// validateSelection checks the operator selection against the latest version of the workflow.
+func (h *RefreshWorkflowHandler) validateSelection(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	selection *workflow_dag.OperatorSelection,
+) (int, error) {
+	dag, err := h.WorkflowDagReader.GetLatestWorkflowDag(ctx, workflowId, h.Database)
+	if err != nil {
+		return http.StatusInternalServerError, errors.Wrap(err, "Unable to retrieve the latest version of the workflow.")
+	}
+
+	operators, err := h.OperatorReader.GetOperatorsByWorkflowDagId(ctx, dag.Id, h.Database)
+	if err != nil {
+		return http.StatusInternalServerError, errors.Wrap(err, "Unable to retrieve the operators of the workflow.")
+	}
+
+	dag.Operators = make(map[uuid.UUID]operator.Operator, len(operators))
+	for _, op := range operators {
+		dag.Operators[op.Id] = op
+	}
+
+	if err := selection.Validate(dag); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	err = orchestrator.CheckReusableRun(ctx, dag, selection, h.WorkflowDagResultReader, h.Database)
+	if err != nil {
+		if err == orchestrator.ErrNoSuccessfulRun {
+			return http.StatusBadRequest, err
+		}
+		return http.StatusInternalServerError, errors.Wrap(err, "Unable to find the runs to reuse artifacts from.")
+	}
+
+	return http.StatusOK, nil
+}
+
 func generateWorkflowJobName() string {
 	return fmt.Sprintf("workflow-adhoc-%s", uuid.New().String())
 }
@@ -98,7 +186,14 @@ func (h *RefreshWorkflowHandler) Perform(ctx context.Context, interfaceArgs inte
 		h.Vault.Config(),
 		h.JobManager.Config(),
 		h.GithubManager.Config(),
+		args.selection,
+		nil, /* resumedFrom */
+		args.parameters,
+		args.workflowDagResultId,
 	)
+	if args.scheduled {
+		jobSpec = job.NewScheduledSpec(jobSpec)
+	}
 
 	err = h.JobManager.Launch(
 		ctx,
@@ -11,6 +11,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/collections/artifact"
 	"github.com/aqueducthq/aqueduct/lib/collections/integration"
 	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/sensor_observation"
 	"github.com/aqueducthq/aqueduct/lib/collections/shared"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
@@ -46,6 +47,8 @@ type RegisterWorkflowHandler struct {
 	GithubManager github.Manager
 	Vault         vault.Vault
 	StorageConfig *shared.StorageConfig
+	// The directory that local directory sensors may watch.
+	SensorRoot string
 
 	ArtifactReader    artifact.Reader
 	IntegrationReader integration.Reader
@@ -58,6 +61,8 @@ type RegisterWorkflowHandler struct {
 	WorkflowDagWriter     workflow_dag.Writer
 	WorkflowDagEdgeWriter workflow_dag_edge.Writer
 	WorkflowWatcherWriter workflow_watcher.Writer
+
+	SensorObservationWriter sensor_observation.Writer
 }
 
 type registerWorkflowArgs struct {
@@ -125,6 +130,39 @@ func (h *RegisterWorkflowHandler) Prepare(r *http.Request) (interface{}, int, er
 		dagSummary.Dag.WorkflowId = collidingWorkflow.Id
 	}
 
+	statusCode, err = validateCascadeSchedule(
+		r.Context(),
+		dagSummary.Dag.WorkflowId,
+		&dagSummary.Dag.Metadata.Schedule,
+		h.SensorRoot,
+		common.getOrganizationId(),
+		h.WorkflowReader,
+		h.Database,
+	)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	statusCode, err = validateSensorSchedule(
+		r.Context(),
+		&dagSummary.Dag.Metadata.Schedule,
+		h.SensorRoot,
+		common.getOrganizationId(),
+		h.IntegrationReader,
+		h.Database,
+	)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	if policy := dagSummary.Dag.Metadata.Schedule.GetOverlapPolicy(); !policy.IsValid() {
+		return nil, http.StatusBadRequest, errors.Newf("Unknown overlap policy %s.", policy)
+	}
+
+	if _, err := cronSchedule(&dagSummary.Dag.Metadata.Schedule); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
 	if err := dag_validation.Validate(
 		dagSummary.Dag,
 	); err != nil {
@@ -184,6 +222,10 @@ func (h *RegisterWorkflowHandler) Perform(ctx context.Context, interfaceArgs int
 			WorkflowReader: h.WorkflowReader,
 			WorkflowWriter: h.WorkflowWriter,
 			JobManager:     h.JobManager,
+			Vault:          h.Vault,
+			GithubManager:  h.GithubManager,
+
+			SensorObservationWriter: h.SensorObservationWriter,
 		}).Perform(
 			ctx,
 			&editWorkflowArgs{
@@ -197,6 +239,20 @@ func (h *RegisterWorkflowHandler) Perform(ctx context.Context, interfaceArgs int
 			return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to update existing workflow.")
 		}
 	} else {
+		err = seedSensor(
+			ctx,
+			workflowId,
+			nil, /* oldSchedule */
+			&args.workflowDag.Metadata.Schedule,
+			h.SensorRoot,
+			h.SensorObservationWriter,
+			h.Vault,
+			txn,
+		)
+		if err != nil {
+			return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to create workflow.")
+		}
+
 		// We should create cron jobs for newly created, non-manually triggered workflows.
 		if string(args.workflowDag.Metadata.Schedule.CronSchedule) != "" {
 			err = createWorkflowCronJob(
@@ -266,7 +322,10 @@ func createWorkflowCronJob(
 ) error {
 	workflowId := workflow.Id.String()
 	name := shared_utils.AppendPrefix(workflowId)
-	period := string(workflow.Schedule.CronSchedule)
+	schedule, err := cronSchedule(&workflow.Schedule)
+	if err != nil {
+		return err
+	}
 
 	spec := job.NewWorkflowSpec(
 		workflow.Name,
@@ -275,12 +334,16 @@ func createWorkflowCronJob(
 		vaultObject.Config(),
 		jobManager.Config(),
 		githubManager.Config(),
+		nil, /* selection */
+		nil, /* resumedFrom */
+		nil, /* parameters */
+		nil, /* workflowDagResultId */
 	)
 
-	err := jobManager.DeployCronJob(
+	err = jobManager.DeployCronJob(
 		ctx,
 		name,
-		period,
+		schedule,
 		spec,
 	)
 	if err != nil {
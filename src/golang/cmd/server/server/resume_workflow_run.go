@@ -0,0 +1,207 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aqueducthq/aqueduct/internal/server/utils"
+	"github.com/aqueducthq/aqueduct/lib/collections/notification"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/user"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/github"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// Route: /workflow/{workflowId}/result/{workflowDagResultId}/resume
+// Method: POST
+// Params:
+//	`workflowId`: ID for `workflow` object
+//	`workflowDagResultId`: ID for the `workflow_dag_result` object of the failed run to resume
+// Request:
+//	Headers:
+//		`api-key`: user's API Key
+// Response:
+//	Body:
+//		{
+//			"workflow_dag_result_id": <ID of the new run>
+//		}
+//
+// A new run of the workflow is started. It copies the results and the artifact contents of the
+// operators that succeeded in the failed run, and only runs the operators that failed or were
+// never reached. Only runs of the latest version of the workflow can be resumed.
+
+type resumeWorkflowRunArgs struct {
+	*CommonArgs
+	workflowId          uuid.UUID
+	workflowDagResultId uuid.UUID
+}
+
+type resumeWorkflowRunResponse struct {
+	WorkflowDagResultId uuid.UUID `json:"workflow_dag_result_id"`
+}
+
+type ResumeWorkflowRunHandler struct {
+	PostHandler
+
+	Database      database.Database
+	JobManager    job.JobManager
+	GithubManager github.Manager
+	Vault         vault.Vault
+
+	WorkflowReader          workflow.Reader
+	WorkflowDagReader       workflow_dag.Reader
+	WorkflowDagResultReader workflow_dag_result.Reader
+	UserReader              user.Reader
+
+	WorkflowDagResultWriter workflow_dag_result.Writer
+	NotificationWriter      notification.Writer
+}
+
+func (*ResumeWorkflowRunHandler) Name() string {
+	return "ResumeWorkflowRun"
+}
+
+func (h *ResumeWorkflowRunHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	common, statusCode, err := ParseCommonArgs(r)
+	if err != nil {
+		return nil, statusCode, err
+	}
+
+	workflowIdStr := chi.URLParam(r, utils.WorkflowIdUrlParam)
+	workflowId, err := uuid.Parse(workflowIdStr)
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "Malformed workflow ID.")
+	}
+
+	workflowDagResultIdStr := chi.URLParam(r, utils.WorkflowDagResultIdUrlParam)
+	workflowDagResultId, err := uuid.Parse(workflowDagResultIdStr)
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "Malformed workflow dag result ID.")
+	}
+
+	ok, err := h.WorkflowReader.ValidateWorkflowOwnership(
+		r.Context(),
+		workflowId,
+		common.OrganizationId,
+		h.Database,
+	)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unexpected error during workflow ownership validation.")
+	}
+	if !ok {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "The organization does not own this workflow.")
+	}
+
+	return &resumeWorkflowRunArgs{
+		CommonArgs:          common,
+		workflowId:          workflowId,
+		workflowDagResultId: workflowDagResultId,
+	}, http.StatusOK, nil
+}
+
+func (h *ResumeWorkflowRunHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*resumeWorkflowRunArgs)
+
+	emptyResp := resumeWorkflowRunResponse{}
+
+	workflowDag, err := h.WorkflowDagReader.GetWorkflowDagByWorkflowDagResultId(
+		ctx,
+		args.workflowDagResultId,
+		h.Database,
+	)
+	if err != nil {
+		if err == database.ErrNoRows {
+			return emptyResp, http.StatusBadRequest, errors.New("Unable to find workflow run.")
+		}
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to find workflow run.")
+	}
+
+	if workflowDag.WorkflowId != args.workflowId {
+		return emptyResp, http.StatusBadRequest, errors.New("The workflow run does not belong to this workflow.")
+	}
+
+	latestWorkflowDag, err := h.WorkflowDagReader.GetLatestWorkflowDag(ctx, args.workflowId, h.Database)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to find the latest version of the workflow.")
+	}
+
+	if latestWorkflowDag.Id != workflowDag.Id {
+		return emptyResp, http.StatusBadRequest, errors.New("Only runs of the latest version of the workflow can be resumed.")
+	}
+
+	workflowDagResult, err := h.WorkflowDagResultReader.GetWorkflowDagResult(
+		ctx,
+		args.workflowDagResultId,
+		h.Database,
+	)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to find workflow run.")
+	}
+
+	if workflowDagResult.Status != shared.FailedExecutionStatus &&
+		workflowDagResult.Status != shared.CanceledExecutionStatus &&
+		workflowDagResult.Status != shared.PartialSuccessExecutionStatus {
+		return emptyResp, http.StatusBadRequest, errors.Newf(
+			"Only failed workflow runs can be resumed, but this run has status %s.",
+			workflowDagResult.Status,
+		)
+	}
+
+	workflowObject, err := h.WorkflowReader.GetWorkflow(ctx, args.workflowId, h.Database)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to find workflow.")
+	}
+
+	// The run is recorded before it is launched, so that the caller can poll its status.
+	resumedRun, err := h.WorkflowDagResultWriter.CreateWorkflowDagResult(
+		ctx,
+		workflowDag.Id,
+		&args.workflowDagResultId,
+		workflowDagResult.Parameters,
+		h.Database,
+	)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to create workflow run.")
+	}
+
+	jobSpec := job.NewWorkflowSpec(
+		workflowObject.Name,
+		workflowObject.Id.String(),
+		h.Database.Config(),
+		h.Vault.Config(),
+		h.JobManager.Config(),
+		h.GithubManager.Config(),
+		nil, /* selection */
+		&args.workflowDagResultId,
+		nil, /* parameters */
+		&resumedRun.Id,
+	)
+
+	err = h.JobManager.Launch(ctx, generateWorkflowJobName(), jobSpec)
+	if err != nil {
+		_, updateErr := h.WorkflowDagResultWriter.UpdateWorkflowDagResult(
+			ctx,
+			resumedRun.Id,
+			map[string]interface{}{workflow_dag_result.StatusColumn: shared.FailedExecutionStatus},
+			h.WorkflowReader,
+			h.NotificationWriter,
+			h.UserReader,
+			h.Database,
+		)
+		if updateErr != nil {
+			log.Errorf("Unable to mark workflow dag result %s as failed: %v", resumedRun.Id, updateErr)
+		}
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to resume workflow run.")
+	}
+
+	return resumeWorkflowRunResponse{WorkflowDagResultId: resumedRun.Id}, http.StatusOK, nil
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/run_request"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// How often the server launches the runs that were requested by other runs.
+const runRequestTickInterval = 5 * time.Second
+
+// StartRunRequests launches the runs requested by other runs in the background, e.g. by cascade
+// triggers and sub-workflow operators, and by backfills. Their executors only record the runs,
+// so that every workflow run is launched by the server's job manager.
+func (s *AqServer) StartRunRequests() {
+	go func() {
+		ticker := time.NewTicker(runRequestTickInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			s.launchRequestedRuns(context.Background())
+		}
+	}()
+}
+
+// launchRequestedRuns launches the requested runs, in the order they were requested. The runs of
+// a backfill are only launched while fewer than its concurrency of them are running. The
+// requests of the runs that finished are deleted.
+func (s *AqServer) launchRequestedRuns(ctx context.Context) {
+	runRequests, err := s.RunRequestReader.GetRunRequests(ctx, s.Database)
+	if err != nil {
+		log.Errorf("Unable to get requested runs: %v", err)
+		return
+	}
+
+	running := map[uuid.UUID]int{}
+	for _, runRequest := range runRequests {
+		if runRequest.LaunchedAt.IsNull {
+			continue
+		}
+
+		isRunning, err := s.isRequestedRunRunning(ctx, &runRequest)
+		if err != nil {
+			log.Errorf("Unable to check whether requested run %s is running: %v", runRequest.WorkflowDagResultId, err)
+			isRunning = true
+		}
+
+		if isRunning {
+			if !runRequest.BackfillId.IsNull {
+				running[runRequest.BackfillId.UUID]++
+			}
+			continue
+		}
+
+		if _, err := s.RunRequestWriter.DeleteRunRequest(ctx, runRequest.WorkflowDagResultId, s.Database); err != nil {
+			log.Errorf("Unable to delete the request of run %s: %v", runRequest.WorkflowDagResultId, err)
+		}
+	}
+
+	for _, runRequest := range runRequests {
+		if !runRequest.LaunchedAt.IsNull {
+			continue
+		}
+
+		if !runRequest.BackfillId.IsNull {
+			if running[runRequest.BackfillId.UUID] >= runRequest.Concurrency {
+				continue
+			}
+			running[runRequest.BackfillId.UUID]++
+		}
+
+		if err := s.launchRequestedRun(ctx, &runRequest); err != nil {
+			log.Errorf("Unable to launch requested run %s: %v", runRequest.WorkflowDagResultId, err)
+		}
+	}
+}
+
+// isRequestedRunRunning returns whether the launched run of the request has not finished yet.
+func (s *AqServer) isRequestedRunRunning(ctx context.Context, runRequest *run_request.RunRequest) (bool, error) {
+	run, err := s.WorkflowDagResultReader.GetWorkflowDagResult(ctx, runRequest.WorkflowDagResultId, s.Database)
+	if err != nil {
+		if err == database.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return run.Status == shared.PendingExecutionStatus || run.Status == shared.AwaitingApprovalExecutionStatus, nil
+}
+
+// launchRequestedRun claims the request and launches its run. A run that was cancelled before
+// it was launched is not launched. A run that fails to launch is marked as failed, since its
+// request was already claimed.
+func (s *AqServer) launchRequestedRun(ctx context.Context, runRequest *run_request.RunRequest) error {
+	claimed, err := s.RunRequestWriter.ClaimRunRequest(ctx, runRequest.WorkflowDagResultId, s.Database)
+	if err != nil {
+		return errors.Wrap(err, "Unable to claim the request.")
+	}
+	if !claimed {
+		return nil
+	}
+
+	run, err := s.WorkflowDagResultReader.GetWorkflowDagResult(ctx, runRequest.WorkflowDagResultId, s.Database)
+	if err != nil {
+		if err == database.ErrNoRows {
+			return nil
+		}
+		return errors.Wrap(err, "Unable to find the requested run.")
+	}
+
+	if run.Status != shared.PendingExecutionStatus {
+		return nil
+	}
+
+	if err := s.launchRun(ctx, runRequest, run); err != nil {
+		_, updateErr := s.WorkflowDagResultWriter.UpdateWorkflowDagResultWithStatus(
+			ctx,
+			run.Id,
+			workflow_dag_result.RunningStatuses,
+			map[string]interface{}{workflow_dag_result.StatusColumn: shared.FailedExecutionStatus},
+			s.WorkflowReader,
+			s.NotificationWriter,
+			s.UserReader,
+			s.Database,
+		)
+		if updateErr != nil && updateErr != database.ErrNoRows {
+			log.Errorf("Unable to mark workflow dag result %s as failed: %v", run.Id, updateErr)
+		}
+		return err
+	}
+
+	log.Infof("Launched run %s of workflow %s.", run.Id, runRequest.WorkflowId)
+	return nil
+}
+
+func (s *AqServer) launchRun(
+	ctx context.Context,
+	runRequest *run_request.RunRequest,
+	run *workflow_dag_result.WorkflowDagResult,
+) error {
+	workflowObject, err := s.WorkflowReader.GetWorkflow(ctx, runRequest.WorkflowId, s.Database)
+	if err != nil {
+		return errors.Wrap(err, "Unable to find workflow.")
+	}
+
+	spec := job.NewWorkflowSpec(
+		workflowObject.Name,
+		workflowObject.Id.String(),
+		s.Database.Config(),
+		s.Vault.Config(),
+		s.JobManager.Config(),
+		s.GithubManager.Config(),
+		nil, /* selection */
+		nil, /* resumedFrom */
+		run.Parameters,
+		&run.Id,
+	)
+	if !runRequest.Parent.IsNull {
+		parent := runRequest.Parent.ParentOperator
+		spec.(*job.WorkflowSpec).Parent = &parent
+	}
+
+	// The runs of a backfill are queued behind scheduled runs and previews.
+	if !runRequest.BackfillId.IsNull {
+		ctx = job.WithPriority(ctx, job.BackfillPriority)
+	}
+
+	return s.JobManager.Launch(ctx, fmt.Sprintf("workflow-requested-%s", uuid.New().String()), spec)
+}
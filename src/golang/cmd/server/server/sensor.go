@@ -0,0 +1,151 @@
+package server
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/sensor_observation"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow/sensor"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// How often the server checks whether any sensor is due to poll.
+const sensorTickInterval = 10 * time.Second
+
+// StartSensors polls the sensors of all workflows with a sensor trigger in the background, each at
+// its own poll interval, and starts a run of the workflow for every new observation. Paused
+// workflows are not polled, so their observations are picked up once they are unpaused.
+func (s *AqServer) StartSensors() {
+	go func() {
+		lastPolledAt := map[uuid.UUID]time.Time{}
+
+		ticker := time.NewTicker(sensorTickInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			s.pollSensors(context.Background(), lastPolledAt)
+		}
+	}()
+}
+
+// pollSensors polls every sensor that is due, and records when it was polled in `lastPolledAt`.
+func (s *AqServer) pollSensors(ctx context.Context, lastPolledAt map[uuid.UUID]time.Time) {
+	workflows, err := s.WorkflowReader.GetAllWorkflows(ctx, s.Database)
+	if err != nil {
+		log.Errorf("Unable to get workflows to poll their sensors: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, wf := range workflows {
+		schedule := wf.Schedule
+		if schedule.Trigger != workflow.SensorUpdateTrigger || schedule.Paused || schedule.Sensor == nil {
+			continue
+		}
+
+		if now.Sub(lastPolledAt[wf.Id]) < sensor.PollInterval(schedule.Sensor) {
+			continue
+		}
+		lastPolledAt[wf.Id] = now
+
+		if err := s.pollSensor(ctx, &wf); err != nil {
+			log.Errorf("Unable to poll the sensor of workflow %s: %v", wf.Id, err)
+		}
+	}
+}
+
+// seedSensor records what the sensor of `newSchedule` observes now, if the schedule enables a
+// sensor or changes it from `oldSchedule`, so that enabling a sensor on existing files does not
+// start a run for each of them. `oldSchedule` is nil for a new workflow.
+func seedSensor(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	oldSchedule *workflow.Schedule,
+	newSchedule *workflow.Schedule,
+	sensorRoot string,
+	sensorObservationWriter sensor_observation.Writer,
+	vaultObject vault.Vault,
+	db database.Database,
+) error {
+	if newSchedule.Trigger != workflow.SensorUpdateTrigger {
+		return nil
+	}
+
+	if oldSchedule != nil &&
+		oldSchedule.Trigger == workflow.SensorUpdateTrigger &&
+		reflect.DeepEqual(oldSchedule.Sensor, newSchedule.Sensor) {
+		return nil
+	}
+
+	err := sensor.Seed(ctx, workflowId, newSchedule.Sensor, sensorRoot, sensorObservationWriter, vaultObject, db)
+	if err != nil {
+		return errors.Wrap(err, "Unable to record what the sensor already observes.")
+	}
+
+	return nil
+}
+
+// pollSensor starts a run of the workflow for every new or changed observation of its sensor.
+// An observation is recorded only once its run is launched, so that a failed launch is retried
+// on the next poll.
+func (s *AqServer) pollSensor(ctx context.Context, wf *workflow.Workflow) error {
+	observations, err := sensor.Poll(
+		ctx,
+		wf.Id,
+		wf.Schedule.Sensor,
+		s.SensorRoot,
+		time.Now(),
+		s.SensorObservationReader,
+		s.SensorObservationWriter,
+		s.Vault,
+		s.Database,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, observation := range observations {
+		parameters, err := sensor.Parameters(wf.Schedule.Sensor, observation.Value)
+		if err != nil {
+			return err
+		}
+
+		_, _, err = (&RefreshWorkflowHandler{
+			Database:       s.Database,
+			JobManager:     s.JobManager,
+			GithubManager:  s.GithubManager,
+			Vault:          s.Vault,
+			WorkflowReader: s.WorkflowReader,
+		}).Perform(
+			ctx,
+			&refreshWorkflowArgs{
+				workflowId: wf.Id,
+				parameters: parameters,
+			},
+		)
+		if err != nil {
+			return errors.Wrapf(err, "Unable to start a run for %s.", observation.Value)
+		}
+
+		_, err = s.SensorObservationWriter.RecordObservation(
+			ctx,
+			wf.Id,
+			observation.Value,
+			observation.Version,
+			s.Database,
+		)
+		if err != nil {
+			return errors.Wrapf(err, "Unable to record observation %s.", observation.Value)
+		}
+
+		log.Infof("Workflow %s was triggered by its sensor observing %s.", wf.Id, observation.Value)
+	}
+
+	return nil
+}
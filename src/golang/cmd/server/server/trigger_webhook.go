@@ -0,0 +1,219 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/internal/server/utils"
+	"github.com/aqueducthq/aqueduct/lib/collections/notification"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/user"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/github"
+	"github.com/aqueducthq/aqueduct/lib/workflow/webhook"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// Route: /workflow/{workflowId}/webhook
+// Method: POST
+// Params: workflowId
+// Request
+//	Headers:
+//		`webhook-timestamp`: the time of the request in Unix seconds, which must be within a few
+//			minutes of the server's clock
+//		`webhook-signature`: `sha256=` followed by the hex encoded HMAC-SHA256 of
+//			`<webhook-timestamp>.<request body>`, keyed by the workflow's webhook token
+//	Body (optional):
+//		`parameters`: a map from the names of param operators to the JSON values they take
+//			in this run
+// Response:
+//	Body:
+//		`workflow_dag_result_id`: the ID of the new `workflow_dag_result`, which can be polled
+//			for the status of the run
+//
+// This route does not take an API key, so external systems can trigger the workflow with only
+// its webhook token. The workflow must have a webhook trigger and must not be paused.
+
+const maxWebhookBodyBytes = 1 << 20
+
+type triggerWebhookRequest struct {
+	Parameters map[string]json.RawMessage `json:"parameters"`
+}
+
+type triggerWebhookArgs struct {
+	workflowId    uuid.UUID
+	workflowDagId uuid.UUID
+	parameters    shared.Parameters
+}
+
+type triggerWebhookResponse struct {
+	WorkflowDagResultId uuid.UUID `json:"workflow_dag_result_id"`
+}
+
+type TriggerWebhookHandler struct {
+	PostHandler
+
+	Database      database.Database
+	JobManager    job.JobManager
+	GithubManager github.Manager
+	Vault         vault.Vault
+
+	WorkflowReader          workflow.Reader
+	WorkflowDagReader       workflow_dag.Reader
+	OperatorReader          operator.Reader
+	UserReader              user.Reader
+	WorkflowDagResultWriter workflow_dag_result.Writer
+	NotificationWriter      notification.Writer
+}
+
+func (*TriggerWebhookHandler) Name() string {
+	return "TriggerWebhook"
+}
+
+func (*TriggerWebhookHandler) AuthMethod() AuthMethod {
+	return WebhookAuthMethod
+}
+
+func (*TriggerWebhookHandler) Headers() []string {
+	return []string{utils.WebhookSignatureHeader, utils.WebhookTimestampHeader}
+}
+
+func (h *TriggerWebhookHandler) Prepare(r *http.Request) (interface{}, int, error) {
+	workflowIdStr := chi.URLParam(r, utils.WorkflowIdUrlParam)
+	workflowId, err := uuid.Parse(workflowIdStr)
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "Malformed workflow ID.")
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodyBytes))
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.Wrap(err, "Unable to read request body.")
+	}
+
+	err = webhook.VerifySignature(
+		r.Context(),
+		workflowId,
+		body,
+		r.Header.Get(utils.WebhookTimestampHeader),
+		r.Header.Get(utils.WebhookSignatureHeader),
+		time.Now(),
+		h.Vault,
+	)
+	if err != nil {
+		return nil, http.StatusUnauthorized, err
+	}
+
+	workflowObject, err := h.WorkflowReader.GetWorkflow(r.Context(), workflowId, h.Database)
+	if err != nil {
+		if err == database.ErrNoRows {
+			return nil, http.StatusBadRequest, errors.New("Unable to find workflow.")
+		}
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to find workflow.")
+	}
+
+	if workflowObject.Schedule.Trigger != workflow.WebhookUpdateTrigger {
+		return nil, http.StatusBadRequest, errors.New("The workflow does not have a webhook trigger.")
+	}
+
+	if workflowObject.Schedule.Paused {
+		return nil, http.StatusBadRequest, errors.New("The workflow is paused.")
+	}
+
+	var request triggerWebhookRequest
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&request); err != nil && err != io.EOF {
+		return nil, http.StatusBadRequest, errors.New("Unable to parse JSON input.")
+	}
+
+	parameters := make(shared.Parameters, len(request.Parameters))
+	for name, val := range request.Parameters {
+		parameters[name] = string(val)
+	}
+
+	dag, err := h.WorkflowDagReader.GetLatestWorkflowDag(r.Context(), workflowId, h.Database)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to retrieve the latest version of the workflow.")
+	}
+
+	operators, err := h.OperatorReader.GetOperatorsByWorkflowDagId(r.Context(), dag.Id, h.Database)
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.Wrap(err, "Unable to retrieve the operators of the workflow.")
+	}
+
+	dag.Operators = make(map[uuid.UUID]operator.Operator, len(operators))
+	for _, op := range operators {
+		dag.Operators[op.Id] = op
+	}
+
+	if err := dag.OverrideParameters(parameters); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	return &triggerWebhookArgs{
+		workflowId:    workflowId,
+		workflowDagId: dag.Id,
+		parameters:    parameters,
+	}, http.StatusOK, nil
+}
+
+func (h *TriggerWebhookHandler) Perform(ctx context.Context, interfaceArgs interface{}) (interface{}, int, error) {
+	args := interfaceArgs.(*triggerWebhookArgs)
+
+	emptyResp := triggerWebhookResponse{}
+
+	// The run is recorded before it is launched, so that the caller can poll its status.
+	workflowDagResult, err := h.WorkflowDagResultWriter.CreateWorkflowDagResult(
+		ctx,
+		args.workflowDagId,
+		nil, /* resumedFrom */
+		args.parameters,
+		h.Database,
+	)
+	if err != nil {
+		return emptyResp, http.StatusInternalServerError, errors.Wrap(err, "Unable to create workflow run.")
+	}
+
+	_, statusCode, err := (&RefreshWorkflowHandler{
+		Database:       h.Database,
+		JobManager:     h.JobManager,
+		GithubManager:  h.GithubManager,
+		Vault:          h.Vault,
+		WorkflowReader: h.WorkflowReader,
+	}).Perform(
+		ctx,
+		&refreshWorkflowArgs{
+			workflowId:          args.workflowId,
+			parameters:          args.parameters,
+			workflowDagResultId: &workflowDagResult.Id,
+		},
+	)
+	if err != nil {
+		_, updateErr := h.WorkflowDagResultWriter.UpdateWorkflowDagResult(
+			ctx,
+			workflowDagResult.Id,
+			map[string]interface{}{workflow_dag_result.StatusColumn: shared.FailedExecutionStatus},
+			h.WorkflowReader,
+			h.NotificationWriter,
+			h.UserReader,
+			h.Database,
+		)
+		if updateErr != nil {
+			log.Errorf("Unable to mark workflow dag result %s as failed: %v", workflowDagResult.Id, updateErr)
+		}
+		return emptyResp, statusCode, err
+	}
+
+	return triggerWebhookResponse{WorkflowDagResultId: workflowDagResult.Id}, http.StatusOK, nil
+}
@@ -13,6 +13,14 @@ type ServerConfiguration struct {
 	EncryptionKey      string `yaml:"encryptionKey" json:"encryption_key"`
 	RetentionJobPeriod string `yaml:"retentionJobPeriod"`
 	ApiKey             string `yaml:"apiKey"`
+	// The maximum number of operator jobs that run at the same time, in total and per workflow,
+	// across the server and every workflow executor. Jobs beyond these limits are queued.
+	// 0 means there is no limit.
+	MaxConcurrentJobs            int `yaml:"maxConcurrentJobs"`
+	MaxConcurrentJobsPerWorkflow int `yaml:"maxConcurrentJobsPerWorkflow"`
+	// The directory, relative to the server's storage, that local directory sensors may watch.
+	// It defaults to "sensors/".
+	SensorDirectory string `yaml:"sensorDirectory"`
 }
 
 func ParseServerConfiguration(confPath string) *ServerConfiguration {
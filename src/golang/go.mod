@@ -8,7 +8,6 @@ require (
 	github.com/dropbox/godropbox v0.0.0-20200228041828-52ad444d3502
 	github.com/go-chi/chi v1.5.4
 	github.com/go-chi/cors v1.2.0
-	github.com/go-co-op/gocron v1.13.0
 	github.com/golang/protobuf v1.5.2 // indirect
 	github.com/google/go-github/v40 v40.0.0
 	github.com/google/uuid v1.3.0
@@ -0,0 +1,5 @@
+package _000009_add_job_record_table
+
+const downPostgresScript = `
+DROP TABLE IF EXISTS job_record;
+`
@@ -0,0 +1,12 @@
+package _000009_add_job_record_table
+
+const upPostgresScript = `
+CREATE TABLE IF NOT EXISTS job_record (
+    name VARCHAR PRIMARY KEY,
+    pid INTEGER NOT NULL,
+    spec_type VARCHAR NOT NULL,
+    workflow_id UUID,
+    log_path VARCHAR NOT NULL,
+    start_time TIMESTAMPTZ NOT NULL
+);
+`
@@ -0,0 +1,12 @@
+package _000009_add_job_record_table
+
+const sqliteScript = `
+CREATE TABLE IF NOT EXISTS job_record (
+    name TEXT NOT NULL PRIMARY KEY,
+    pid INTEGER NOT NULL,
+    spec_type TEXT NOT NULL,
+    workflow_id BLOB,
+    log_path TEXT NOT NULL,
+    start_time DATETIME NOT NULL
+);
+`
@@ -0,0 +1,5 @@
+package _000010_add_workflow_execution_policy
+
+const downPostgresScript = `
+ALTER TABLE workflow DROP COLUMN IF EXISTS execution_policy;
+`
@@ -0,0 +1,6 @@
+package _000010_add_workflow_execution_policy
+
+const upPostgresScript = `
+ALTER TABLE workflow
+ADD COLUMN execution_policy JSONB NOT NULL DEFAULT '{}'::json;
+`
@@ -0,0 +1,5 @@
+package _000011_add_operator_cache_table
+
+const downPostgresScript = `
+DROP TABLE IF EXISTS operator_cache;
+`
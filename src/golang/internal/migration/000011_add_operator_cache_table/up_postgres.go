@@ -0,0 +1,11 @@
+package _000011_add_operator_cache_table
+
+const upPostgresScript = `
+CREATE TABLE IF NOT EXISTS operator_cache (
+    cache_key VARCHAR PRIMARY KEY,
+    workflow_id UUID NOT NULL REFERENCES workflow (id),
+    operator_result_id UUID NOT NULL,
+    outputs JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+`
@@ -0,0 +1,11 @@
+package _000011_add_operator_cache_table
+
+const sqliteScript = `
+CREATE TABLE IF NOT EXISTS operator_cache (
+    cache_key TEXT NOT NULL PRIMARY KEY,
+    workflow_id BLOB NOT NULL REFERENCES workflow (id),
+    operator_result_id BLOB NOT NULL,
+    outputs BLOB NOT NULL,
+    created_at DATETIME NOT NULL
+);
+`
@@ -0,0 +1,5 @@
+package _000012_add_workflow_dag_result_resumed_from
+
+const downPostgresScript = `
+ALTER TABLE workflow_dag_result DROP COLUMN IF EXISTS resumed_from;
+`
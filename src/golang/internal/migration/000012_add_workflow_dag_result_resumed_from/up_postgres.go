@@ -0,0 +1,6 @@
+package _000012_add_workflow_dag_result_resumed_from
+
+const upPostgresScript = `
+ALTER TABLE workflow_dag_result
+ADD COLUMN resumed_from UUID REFERENCES workflow_dag_result (id) ON DELETE SET NULL;
+`
@@ -0,0 +1,5 @@
+package _000013_add_workflow_dag_result_parameters
+
+const downPostgresScript = `
+ALTER TABLE workflow_dag_result DROP COLUMN IF EXISTS parameters;
+`
@@ -0,0 +1,6 @@
+package _000013_add_workflow_dag_result_parameters
+
+const upPostgresScript = `
+ALTER TABLE workflow_dag_result
+ADD COLUMN parameters JSONB NOT NULL DEFAULT '{}'::json;
+`
@@ -0,0 +1,6 @@
+package _000013_add_workflow_dag_result_parameters
+
+const sqliteScript = `
+ALTER TABLE workflow_dag_result
+ADD COLUMN parameters BLOB NOT NULL DEFAULT '{}';
+`
@@ -0,0 +1,5 @@
+package _000014_add_sensor_observation_table
+
+const downPostgresScript = `
+DROP TABLE IF EXISTS sensor_observation;
+`
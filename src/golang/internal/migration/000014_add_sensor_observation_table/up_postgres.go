@@ -0,0 +1,10 @@
+package _000014_add_sensor_observation_table
+
+const upPostgresScript = `
+CREATE TABLE IF NOT EXISTS sensor_observation (
+    workflow_id UUID NOT NULL REFERENCES workflow (id),
+    observation VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (workflow_id, observation)
+);
+`
@@ -0,0 +1,10 @@
+package _000014_add_sensor_observation_table
+
+const sqliteScript = `
+CREATE TABLE IF NOT EXISTS sensor_observation (
+    workflow_id BLOB NOT NULL REFERENCES workflow (id),
+    observation TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (workflow_id, observation)
+);
+`
@@ -0,0 +1,5 @@
+package _000015_add_workflow_dag_result_parent_id
+
+const downPostgresScript = `
+ALTER TABLE workflow_dag_result DROP COLUMN IF EXISTS parent_id;
+`
@@ -0,0 +1,6 @@
+package _000015_add_workflow_dag_result_parent_id
+
+const upPostgresScript = `
+ALTER TABLE workflow_dag_result
+ADD COLUMN parent_id UUID REFERENCES workflow_dag_result (id) ON DELETE SET NULL;
+`
@@ -0,0 +1,6 @@
+package _000015_add_workflow_dag_result_parent_id
+
+const sqliteScript = `
+ALTER TABLE workflow_dag_result
+ADD COLUMN parent_id BLOB REFERENCES workflow_dag_result (id) ON DELETE SET NULL;
+`
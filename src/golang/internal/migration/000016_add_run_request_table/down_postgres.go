@@ -0,0 +1,5 @@
+package _000016_add_run_request_table
+
+const downPostgresScript = `
+DROP TABLE IF EXISTS run_request;
+`
@@ -0,0 +1,10 @@
+package _000016_add_run_request_table
+
+const upPostgresScript = `
+CREATE TABLE IF NOT EXISTS run_request (
+    workflow_dag_result_id UUID NOT NULL PRIMARY KEY REFERENCES workflow_dag_result (id),
+    workflow_id UUID NOT NULL REFERENCES workflow (id),
+    parent JSONB,
+    created_at TIMESTAMPTZ NOT NULL
+);
+`
@@ -0,0 +1,10 @@
+package _000016_add_run_request_table
+
+const sqliteScript = `
+CREATE TABLE IF NOT EXISTS run_request (
+    workflow_dag_result_id BLOB NOT NULL PRIMARY KEY REFERENCES workflow_dag_result (id),
+    workflow_id BLOB NOT NULL REFERENCES workflow (id),
+    parent BLOB,
+    created_at DATETIME NOT NULL
+);
+`
@@ -0,0 +1,5 @@
+package _000017_add_sensor_observation_version
+
+const downPostgresScript = `
+ALTER TABLE sensor_observation DROP COLUMN IF EXISTS version;
+`
@@ -0,0 +1,6 @@
+package _000017_add_sensor_observation_version
+
+const upPostgresScript = `
+ALTER TABLE sensor_observation
+ADD COLUMN version VARCHAR NOT NULL DEFAULT '';
+`
@@ -0,0 +1,5 @@
+package _000018_add_job_record_workflow_dag_result_id
+
+const downPostgresScript = `
+ALTER TABLE job_record DROP COLUMN IF EXISTS workflow_dag_result_id;
+`
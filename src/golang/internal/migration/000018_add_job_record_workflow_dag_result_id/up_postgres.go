@@ -0,0 +1,6 @@
+package _000018_add_job_record_workflow_dag_result_id
+
+const upPostgresScript = `
+ALTER TABLE job_record
+ADD COLUMN workflow_dag_result_id UUID;
+`
@@ -0,0 +1,7 @@
+package _000019_add_run_request_backfill
+
+const downPostgresScript = `
+ALTER TABLE run_request DROP COLUMN IF EXISTS backfill_id;
+ALTER TABLE run_request DROP COLUMN IF EXISTS concurrency;
+ALTER TABLE run_request DROP COLUMN IF EXISTS launched_at;
+`
@@ -0,0 +1,12 @@
+package _000019_add_run_request_backfill
+
+const upPostgresScript = `
+ALTER TABLE run_request
+ADD COLUMN backfill_id UUID;
+
+ALTER TABLE run_request
+ADD COLUMN concurrency INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE run_request
+ADD COLUMN launched_at TIMESTAMPTZ;
+`
@@ -0,0 +1,5 @@
+package _000020_add_job_slot_table
+
+const downPostgresScript = `
+DROP TABLE IF EXISTS job_slot;
+`
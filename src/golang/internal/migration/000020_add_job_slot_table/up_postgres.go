@@ -0,0 +1,13 @@
+package _000020_add_job_slot_table
+
+const upPostgresScript = `
+CREATE TABLE IF NOT EXISTS job_slot (
+    name VARCHAR PRIMARY KEY,
+    workflow_id UUID,
+    priority INTEGER NOT NULL,
+    requested_at TIMESTAMPTZ NOT NULL,
+    acquired BOOLEAN NOT NULL,
+    pid INTEGER NOT NULL,
+    start_time TIMESTAMPTZ NOT NULL
+);
+`
@@ -0,0 +1,13 @@
+package _000020_add_job_slot_table
+
+const sqliteScript = `
+CREATE TABLE IF NOT EXISTS job_slot (
+    name TEXT NOT NULL PRIMARY KEY,
+    workflow_id BLOB,
+    priority INTEGER NOT NULL,
+    requested_at DATETIME NOT NULL,
+    acquired BOOL NOT NULL,
+    pid INTEGER NOT NULL,
+    start_time DATETIME NOT NULL
+);
+`
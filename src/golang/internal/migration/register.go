@@ -11,6 +11,18 @@ import (
 	_000006 "github.com/aqueducthq/aqueduct/internal/migration/000006_add_retention_policy_column"
 	_000007 "github.com/aqueducthq/aqueduct/internal/migration/000007_workflow_dag_edge_pk"
 	_000008 "github.com/aqueducthq/aqueduct/internal/migration/000008_delete_s3_config"
+	_000009 "github.com/aqueducthq/aqueduct/internal/migration/000009_add_job_record_table"
+	_000010 "github.com/aqueducthq/aqueduct/internal/migration/000010_add_workflow_execution_policy"
+	_000011 "github.com/aqueducthq/aqueduct/internal/migration/000011_add_operator_cache_table"
+	_000012 "github.com/aqueducthq/aqueduct/internal/migration/000012_add_workflow_dag_result_resumed_from"
+	_000013 "github.com/aqueducthq/aqueduct/internal/migration/000013_add_workflow_dag_result_parameters"
+	_000014 "github.com/aqueducthq/aqueduct/internal/migration/000014_add_sensor_observation_table"
+	_000015 "github.com/aqueducthq/aqueduct/internal/migration/000015_add_workflow_dag_result_parent_id"
+	_000016 "github.com/aqueducthq/aqueduct/internal/migration/000016_add_run_request_table"
+	_000017 "github.com/aqueducthq/aqueduct/internal/migration/000017_add_sensor_observation_version"
+	_000018 "github.com/aqueducthq/aqueduct/internal/migration/000018_add_job_record_workflow_dag_result_id"
+	_000019 "github.com/aqueducthq/aqueduct/internal/migration/000019_add_run_request_backfill"
+	_000020 "github.com/aqueducthq/aqueduct/internal/migration/000020_add_job_slot_table"
 	"github.com/aqueducthq/aqueduct/lib/database"
 )
 
@@ -66,4 +78,76 @@ func init() {
 		downPostgres: _000008.DownPostgres,
 		name:         "delete outdated s3_config column",
 	}
+
+	registeredMigrations[9] = &migration{
+		upPostgres: _000009.UpPostgres, upSqlite: _000009.UpSqlite,
+		downPostgres: _000009.DownPostgres,
+		name:         "add job_record table",
+	}
+
+	registeredMigrations[10] = &migration{
+		upPostgres: _000010.UpPostgres, upSqlite: _000010.UpSqlite,
+		downPostgres: _000010.DownPostgres,
+		name:         "add execution_policy column to workflow",
+	}
+
+	registeredMigrations[11] = &migration{
+		upPostgres: _000011.UpPostgres, upSqlite: _000011.UpSqlite,
+		downPostgres: _000011.DownPostgres,
+		name:         "add operator_cache table",
+	}
+
+	registeredMigrations[12] = &migration{
+		upPostgres: _000012.UpPostgres, upSqlite: _000012.UpSqlite,
+		downPostgres: _000012.DownPostgres,
+		name:         "add resumed_from column to workflow_dag_result",
+	}
+
+	registeredMigrations[13] = &migration{
+		upPostgres: _000013.UpPostgres, upSqlite: _000013.UpSqlite,
+		downPostgres: _000013.DownPostgres,
+		name:         "add parameters column to workflow_dag_result",
+	}
+
+	registeredMigrations[14] = &migration{
+		upPostgres: _000014.UpPostgres, upSqlite: _000014.UpSqlite,
+		downPostgres: _000014.DownPostgres,
+		name:         "add sensor_observation table",
+	}
+
+	registeredMigrations[15] = &migration{
+		upPostgres: _000015.UpPostgres, upSqlite: _000015.UpSqlite,
+		downPostgres: _000015.DownPostgres,
+		name:         "add parent_id column to workflow_dag_result",
+	}
+
+	registeredMigrations[16] = &migration{
+		upPostgres: _000016.UpPostgres, upSqlite: _000016.UpSqlite,
+		downPostgres: _000016.DownPostgres,
+		name:         "add run_request table",
+	}
+
+	registeredMigrations[17] = &migration{
+		upPostgres: _000017.UpPostgres, upSqlite: _000017.UpSqlite,
+		downPostgres: _000017.DownPostgres,
+		name:         "add version column to sensor_observation",
+	}
+
+	registeredMigrations[18] = &migration{
+		upPostgres: _000018.UpPostgres, upSqlite: _000018.UpSqlite,
+		downPostgres: _000018.DownPostgres,
+		name:         "add workflow_dag_result_id column to job_record",
+	}
+
+	registeredMigrations[19] = &migration{
+		upPostgres: _000019.UpPostgres, upSqlite: _000019.UpSqlite,
+		downPostgres: _000019.DownPostgres,
+		name:         "add backfill columns to run_request",
+	}
+
+	registeredMigrations[20] = &migration{
+		upPostgres: _000020.UpPostgres, upSqlite: _000020.UpSqlite,
+		downPostgres: _000020.DownPostgres,
+		name:         "add job_slot table",
+	}
 }
@@ -1,7 +1,10 @@
 package dag_validation
 
 import (
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/function"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/google/uuid"
 )
@@ -12,6 +15,15 @@ var (
 	ErrUnreachableArtifact     = errors.New("The DAG has an unreachable artifact")
 	ErrUnDefinedArtifact       = errors.New("The DAG's operator edge contains an undefined artifact.")
 	ErrUnexecutableOperator    = errors.New("The DAG contains an operator whose dependencies will never be met.")
+	ErrInvalidRetryPolicy      = errors.New("The DAG contains an operator with an invalid retry policy.")
+	ErrInvalidTimeout          = errors.New("The DAG contains a negative timeout.")
+	ErrInvalidFailurePolicy    = errors.New("The DAG has an unknown failure policy.")
+	ErrInvalidSensor           = errors.New("The DAG contains an invalid sensor.")
+	ErrInvalidRunCondition     = errors.New("The DAG contains an operator whose run condition does not refer to one of its boolean inputs.")
+	ErrInvalidMap              = errors.New("The DAG contains a map operator that is not a function mapping one of its JSON inputs to a single table.")
+	ErrInvalidSubWorkflow      = errors.New("The DAG contains a sub-workflow that does not map its JSON and float inputs to params, and its outputs to artifacts, of a single workflow.")
+	ErrInvalidApproval         = errors.New("The DAG contains an approval that does not output a single boolean.")
+	ErrInvalidArtifactType     = errors.New("The DAG contains a function that takes or outputs an artifact of a type it does not support.")
 
 	ValidationErrors = map[error]bool{
 		ErrNoOperator:              true,
@@ -19,6 +31,15 @@ var (
 		ErrUnreachableArtifact:     true,
 		ErrUnDefinedArtifact:       true,
 		ErrUnexecutableOperator:    true,
+		ErrInvalidRetryPolicy:      true,
+		ErrInvalidTimeout:          true,
+		ErrInvalidFailurePolicy:    true,
+		ErrInvalidSensor:           true,
+		ErrInvalidRunCondition:     true,
+		ErrInvalidMap:              true,
+		ErrInvalidSubWorkflow:      true,
+		ErrInvalidApproval:         true,
+		ErrInvalidArtifactType:     true,
 	}
 )
 
@@ -39,7 +60,65 @@ func Validate(
 	// parent operator pointing to it.
 	artifactParents := make(map[uuid.UUID]bool)
 
+	if dag.Metadata != nil {
+		if dag.Metadata.ExecutionPolicy.TimeoutSeconds < 0 {
+			return ErrInvalidTimeout
+		}
+
+		if !dag.Metadata.ExecutionPolicy.FailurePolicy.IsValid() {
+			return ErrInvalidFailurePolicy
+		}
+	}
+
 	for _, op := range dag.Operators {
+		if op.Spec.Timeout() < 0 {
+			return ErrInvalidTimeout
+		}
+
+		if retryPolicy := op.Spec.RetryPolicy(); retryPolicy != nil {
+			if err := retryPolicy.Validate(); err != nil {
+				return ErrInvalidRetryPolicy
+			}
+		}
+
+		if op.Spec.IsSensor() {
+			if err := op.Spec.Sensor().Validate(); err != nil {
+				return ErrInvalidSensor
+			}
+		}
+
+		if runCondition := op.Spec.RunCondition(); runCondition != nil {
+			if !isInputOfType(dag, &op, runCondition.ArtifactId, artifact.BoolType) {
+				return ErrInvalidRunCondition
+			}
+		}
+
+		if opMap := op.Spec.Map(); opMap != nil {
+			if !op.Spec.IsFunction() ||
+				opMap.MaxConcurrency < 0 ||
+				!isInputOfType(dag, &op, opMap.ArtifactId, artifact.JsonType) ||
+				len(op.Outputs) != 1 ||
+				!dag.Artifacts[op.Outputs[0]].Spec.IsTable() {
+				return ErrInvalidMap
+			}
+		}
+
+		if op.Spec.IsApproval() {
+			if err := op.Spec.Approval().Validate(); err != nil ||
+				len(op.Outputs) != 1 ||
+				!dag.Artifacts[op.Outputs[0]].Spec.IsBool() {
+				return ErrInvalidApproval
+			}
+		}
+
+		if op.Spec.IsSubWorkflow() && !isValidSubWorkflow(dag, &op) {
+			return ErrInvalidSubWorkflow
+		}
+
+		if !hasValidArtifactTypes(dag, &op) {
+			return ErrInvalidArtifactType
+		}
+
 		for _, inputArtifactId := range op.Inputs {
 			artifactIdsInEdges[inputArtifactId] = false
 		}
@@ -77,6 +156,120 @@ func Validate(
 	return checkUnexecutableOperator(dag)
 }
 
+// isInputOfType returns whether the artifact is an artifact of the dag with the given type that
+// the operator takes as an input.
+func isInputOfType(
+	dag *workflow_dag.WorkflowDag,
+	op *operator.Operator,
+	artifactId uuid.UUID,
+	artifactType artifact.Type,
+) bool {
+	inputArtifact, ok := dag.Artifacts[artifactId]
+	if !ok || inputArtifact.Spec.Type() != artifactType {
+		return false
+	}
+
+	for _, inputArtifactId := range op.Inputs {
+		if inputArtifactId == artifactId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isValidSubWorkflow returns whether the sub-workflow operator passes each of its data inputs to
+// a param of the workflow it runs, and takes each of its outputs from an artifact of the workflow.
+// The data inputs must be JSON or floats, since their contents become the values of params.
+func isValidSubWorkflow(dag *workflow_dag.WorkflowDag, op *operator.Operator) bool {
+	inputArtifactIds := dataInputs(dag, op)
+	for _, inputArtifactId := range inputArtifactIds {
+		inputArtifact, ok := dag.Artifacts[inputArtifactId]
+		if !ok || !(inputArtifact.Spec.IsJson() || inputArtifact.Spec.IsFloat()) {
+			return false
+		}
+	}
+
+	return op.Spec.SubWorkflow().Validate(len(inputArtifactIds), len(op.Outputs)) == nil
+}
+
+// hasValidArtifactTypes returns whether the operator, if it is a function, takes and outputs
+// artifacts of the types it supports. General functions take and output tables, floats, booleans
+// and JSON. Metrics and checks take the same types, but output a single float and a single boolean
+// respectively. Undefined artifacts are reported separately.
+func hasValidArtifactTypes(dag *workflow_dag.WorkflowDag, op *operator.Operator) bool {
+	if !op.Spec.IsFunction() && !op.Spec.IsMetric() && !op.Spec.IsCheck() {
+		return true
+	}
+
+	for _, inputArtifactId := range dataInputs(dag, op) {
+		inputArtifact, ok := dag.Artifacts[inputArtifactId]
+		if ok && !function.IsSupportedArtifactType(inputArtifact.Spec.Type()) {
+			return false
+		}
+	}
+
+	if op.Spec.IsMetric() || op.Spec.IsCheck() {
+		if len(op.Outputs) != 1 {
+			return false
+		}
+
+		outputArtifact, ok := dag.Artifacts[op.Outputs[0]]
+		if !ok {
+			return true
+		}
+
+		if op.Spec.IsMetric() {
+			return outputArtifact.Spec.IsFloat()
+		}
+		return outputArtifact.Spec.IsBool()
+	}
+
+	for _, outputArtifactId := range op.Outputs {
+		outputArtifact, ok := dag.Artifacts[outputArtifactId]
+		if ok && !function.IsSupportedArtifactType(outputArtifact.Spec.Type()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// dataInputs returns the inputs whose contents the operator reads. The artifact its run condition
+// refers to and the outputs of gates are only waited for.
+func dataInputs(dag *workflow_dag.WorkflowDag, op *operator.Operator) []uuid.UUID {
+	inputArtifactIds := make([]uuid.UUID, 0, len(op.Inputs))
+	for _, inputArtifactId := range op.Inputs {
+		if runCondition := op.Spec.RunCondition(); runCondition != nil && runCondition.ArtifactId == inputArtifactId {
+			continue
+		}
+		if isGateOutput(dag, inputArtifactId) {
+			continue
+		}
+
+		inputArtifactIds = append(inputArtifactIds, inputArtifactId)
+	}
+
+	return inputArtifactIds
+}
+
+// isGateOutput returns whether the artifact is output by a sensor or an approval of the dag.
+func isGateOutput(dag *workflow_dag.WorkflowDag, artifactId uuid.UUID) bool {
+	for _, op := range dag.Operators {
+		if !op.Spec.IsGate() {
+			continue
+		}
+
+		for _, outputArtifactId := range op.Outputs {
+			if outputArtifactId == artifactId {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func checkUnexecutableOperator(dag *workflow_dag.WorkflowDag) error {
 	numOperators := len(dag.Operators)
 	operatorsExecuted := make(map[uuid.UUID]bool, numOperators)
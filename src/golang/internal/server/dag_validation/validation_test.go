@@ -1,12 +1,18 @@
 package dag_validation_test
 
 import (
+	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/aqueducthq/aqueduct/internal/server/dag_validation"
 	"github.com/aqueducthq/aqueduct/lib/collections/artifact"
 	"github.com/aqueducthq/aqueduct/lib/collections/operator"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/workflow/artifact/boolean"
+	"github.com/aqueducthq/aqueduct/lib/workflow/artifact/float"
+	"github.com/aqueducthq/aqueduct/lib/workflow/artifact/jsonable"
+	"github.com/aqueducthq/aqueduct/lib/workflow/artifact/table"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
@@ -194,6 +200,203 @@ func generateUndefinedArtifactDag(t *testing.T) *workflow_dag.WorkflowDag {
 	}
 }
 
+// This manually creates a DAG where the load only runs if the check's artifact has the given
+// value, and the load's run condition refers to `conditionArtifactId`:
+// check_0 -> artifact_0 (bool) --> load_0
+func generateRunConditionDag(t *testing.T, conditionArtifactId *uuid.UUID) *workflow_dag.WorkflowDag {
+	artifactZero := artifact.Artifact{
+		Id:   uuid.New(),
+		Spec: *artifact.NewSpecFromBool(boolean.Bool{}),
+	}
+
+	if conditionArtifactId == nil {
+		conditionArtifactId = &artifactZero.Id
+	}
+
+	var loadSpec operator.Spec
+	err := json.Unmarshal([]byte(fmt.Sprintf(
+		`{"function": {"type": "file"}, "run_condition": {"artifact_id": "%s", "run_if": true}}`,
+		*conditionArtifactId,
+	)), &loadSpec)
+	require.Nil(t, err)
+
+	checkZero := operator.Operator{
+		Id:      uuid.New(),
+		Outputs: []uuid.UUID{artifactZero.Id},
+	}
+
+	loadZero := operator.Operator{
+		Id:     uuid.New(),
+		Spec:   loadSpec,
+		Inputs: []uuid.UUID{artifactZero.Id},
+	}
+
+	return &workflow_dag.WorkflowDag{
+		Operators: map[uuid.UUID]operator.Operator{checkZero.Id: checkZero, loadZero.Id: loadZero},
+		Artifacts: map[uuid.UUID]artifact.Artifact{artifactZero.Id: artifactZero},
+	}
+}
+
+// This manually creates a DAG where a function is mapped over the elements of a parameter:
+// param_0 -> artifact_0 (json) -> func_0 -> artifact_1
+func generateMapDag(t *testing.T, outputSpec artifact.Spec) *workflow_dag.WorkflowDag {
+	artifactZero := artifact.Artifact{
+		Id:   uuid.New(),
+		Spec: *artifact.NewSpecFromJson(jsonable.Json{}),
+	}
+
+	artifactOne := artifact.Artifact{
+		Id:   uuid.New(),
+		Spec: outputSpec,
+	}
+
+	var functionSpec operator.Spec
+	err := json.Unmarshal([]byte(fmt.Sprintf(
+		`{"function": {"type": "file"}, "map": {"artifact_id": "%s", "max_concurrency": 4}}`,
+		artifactZero.Id,
+	)), &functionSpec)
+	require.Nil(t, err)
+
+	paramZero := operator.Operator{
+		Id:      uuid.New(),
+		Outputs: []uuid.UUID{artifactZero.Id},
+	}
+
+	functionZero := operator.Operator{
+		Id:      uuid.New(),
+		Spec:    functionSpec,
+		Inputs:  []uuid.UUID{artifactZero.Id},
+		Outputs: []uuid.UUID{artifactOne.Id},
+	}
+
+	return &workflow_dag.WorkflowDag{
+		Operators: map[uuid.UUID]operator.Operator{paramZero.Id: paramZero, functionZero.Id: functionZero},
+		Artifacts: map[uuid.UUID]artifact.Artifact{artifactZero.Id: artifactZero, artifactOne.Id: artifactOne},
+	}
+}
+
+func generateSubWorkflowDag(t *testing.T, inputSpec artifact.Spec) *workflow_dag.WorkflowDag {
+	artifactZero := artifact.Artifact{
+		Id:   uuid.New(),
+		Spec: inputSpec,
+	}
+
+	artifactOne := artifact.Artifact{
+		Id:   uuid.New(),
+		Spec: *artifact.NewSpecFromTable(table.Table{}),
+	}
+
+	var subWorkflowSpec operator.Spec
+	err := json.Unmarshal([]byte(
+		`{"sub_workflow": {"workflow_name": "daily_report", "inputs": ["region"], "outputs": ["report"]}}`,
+	), &subWorkflowSpec)
+	require.Nil(t, err)
+
+	paramZero := operator.Operator{
+		Id:      uuid.New(),
+		Outputs: []uuid.UUID{artifactZero.Id},
+	}
+
+	subWorkflowZero := operator.Operator{
+		Id:      uuid.New(),
+		Spec:    subWorkflowSpec,
+		Inputs:  []uuid.UUID{artifactZero.Id},
+		Outputs: []uuid.UUID{artifactOne.Id},
+	}
+
+	return &workflow_dag.WorkflowDag{
+		Operators: map[uuid.UUID]operator.Operator{paramZero.Id: paramZero, subWorkflowZero.Id: subWorkflowZero},
+		Artifacts: map[uuid.UUID]artifact.Artifact{artifactZero.Id: artifactZero, artifactOne.Id: artifactOne},
+	}
+}
+
+func generateApprovalDag(t *testing.T, outputSpec artifact.Spec) *workflow_dag.WorkflowDag {
+	artifactZero := artifact.Artifact{
+		Id:   uuid.New(),
+		Spec: *artifact.NewSpecFromTable(table.Table{}),
+	}
+
+	artifactOne := artifact.Artifact{
+		Id:   uuid.New(),
+		Spec: outputSpec,
+	}
+
+	var approvalSpec operator.Spec
+	err := json.Unmarshal([]byte(
+		`{"approval": {"approvers": ["finance@example.com"], "timeout_seconds": 3600}}`,
+	), &approvalSpec)
+	require.Nil(t, err)
+
+	extractZero := operator.Operator{
+		Id:      uuid.New(),
+		Outputs: []uuid.UUID{artifactZero.Id},
+	}
+
+	approvalZero := operator.Operator{
+		Id:      uuid.New(),
+		Spec:    approvalSpec,
+		Inputs:  []uuid.UUID{artifactZero.Id},
+		Outputs: []uuid.UUID{artifactOne.Id},
+	}
+
+	return &workflow_dag.WorkflowDag{
+		Operators: map[uuid.UUID]operator.Operator{extractZero.Id: extractZero, approvalZero.Id: approvalZero},
+		Artifacts: map[uuid.UUID]artifact.Artifact{artifactZero.Id: artifactZero, artifactOne.Id: artifactOne},
+	}
+}
+
+// This manually creates a DAG where an operator with spec `rawSpec` computes an artifact of
+// `outputSpec` from a float and a boolean:
+// metric_0 -> artifact_0 (float) --
+//                                  |--> op_0 -> artifact_2
+// check_0 -> artifact_1 (bool)   --
+func generateFunctionDag(t *testing.T, rawSpec string, outputSpec artifact.Spec) *workflow_dag.WorkflowDag {
+	artifactZero := artifact.Artifact{
+		Id:   uuid.New(),
+		Spec: *artifact.NewSpecFromFloat(float.Float{}),
+	}
+
+	artifactOne := artifact.Artifact{
+		Id:   uuid.New(),
+		Spec: *artifact.NewSpecFromBool(boolean.Bool{}),
+	}
+
+	artifactTwo := artifact.Artifact{
+		Id:   uuid.New(),
+		Spec: outputSpec,
+	}
+
+	var opSpec operator.Spec
+	err := json.Unmarshal([]byte(rawSpec), &opSpec)
+	require.Nil(t, err)
+
+	metricZero := operator.Operator{
+		Id:      uuid.New(),
+		Outputs: []uuid.UUID{artifactZero.Id},
+	}
+
+	checkZero := operator.Operator{
+		Id:      uuid.New(),
+		Outputs: []uuid.UUID{artifactOne.Id},
+	}
+
+	opZero := operator.Operator{
+		Id:      uuid.New(),
+		Spec:    opSpec,
+		Inputs:  []uuid.UUID{artifactZero.Id, artifactOne.Id},
+		Outputs: []uuid.UUID{artifactTwo.Id},
+	}
+
+	return &workflow_dag.WorkflowDag{
+		Operators: map[uuid.UUID]operator.Operator{metricZero.Id: metricZero, checkZero.Id: checkZero, opZero.Id: opZero},
+		Artifacts: map[uuid.UUID]artifact.Artifact{
+			artifactZero.Id: artifactZero,
+			artifactOne.Id:  artifactOne,
+			artifactTwo.Id:  artifactTwo,
+		},
+	}
+}
+
 func TestValidate(t *testing.T) {
 	basicDag := generateBasicDag(t)
 	err := dag_validation.Validate(
@@ -230,4 +433,80 @@ func TestValidate(t *testing.T) {
 		undefinedArtifactDag,
 	)
 	require.Equal(t, err, dag_validation.ErrUnDefinedArtifact)
+
+	runConditionDag := generateRunConditionDag(t, nil)
+	err = dag_validation.Validate(
+		runConditionDag,
+	)
+	require.Nil(t, err)
+
+	// The run condition refers to an artifact that is not in the DAG.
+	undefinedArtifactId := uuid.New()
+	undefinedRunConditionDag := generateRunConditionDag(t, &undefinedArtifactId)
+	err = dag_validation.Validate(
+		undefinedRunConditionDag,
+	)
+	require.Equal(t, err, dag_validation.ErrInvalidRunCondition)
+
+	// The run condition refers to an artifact that is not boolean.
+	for id, conditionArtifact := range runConditionDag.Artifacts {
+		conditionArtifact.Spec = *artifact.NewSpecFromTable(table.Table{})
+		runConditionDag.Artifacts[id] = conditionArtifact
+	}
+	err = dag_validation.Validate(
+		runConditionDag,
+	)
+	require.Equal(t, err, dag_validation.ErrInvalidRunCondition)
+
+	mapDag := generateMapDag(t, *artifact.NewSpecFromTable(table.Table{}))
+	err = dag_validation.Validate(
+		mapDag,
+	)
+	require.Nil(t, err)
+
+	// The outputs of the elements can only be combined if they are tables.
+	mapToBoolDag := generateMapDag(t, *artifact.NewSpecFromBool(boolean.Bool{}))
+	err = dag_validation.Validate(
+		mapToBoolDag,
+	)
+	require.Equal(t, err, dag_validation.ErrInvalidMap)
+
+	subWorkflowDag := generateSubWorkflowDag(t, *artifact.NewSpecFromJson(jsonable.Json{}))
+	err = dag_validation.Validate(
+		subWorkflowDag,
+	)
+	require.Nil(t, err)
+
+	// The content of a table cannot be the value of a param.
+	tableSubWorkflowDag := generateSubWorkflowDag(t, *artifact.NewSpecFromTable(table.Table{}))
+	err = dag_validation.Validate(
+		tableSubWorkflowDag,
+	)
+	require.Equal(t, err, dag_validation.ErrInvalidSubWorkflow)
+
+	approvalDag := generateApprovalDag(t, *artifact.NewSpecFromBool(boolean.Bool{}))
+	err = dag_validation.Validate(
+		approvalDag,
+	)
+	require.Nil(t, err)
+
+	// An approval only outputs whether the run was approved.
+	tableApprovalDag := generateApprovalDag(t, *artifact.NewSpecFromTable(table.Table{}))
+	err = dag_validation.Validate(
+		tableApprovalDag,
+	)
+	require.Equal(t, err, dag_validation.ErrInvalidApproval)
+
+	functionDag := generateFunctionDag(t, `{"function": {"type": "file"}}`, *artifact.NewSpecFromJson(jsonable.Json{}))
+	err = dag_validation.Validate(
+		functionDag,
+	)
+	require.Nil(t, err)
+
+	// A metric only outputs a float.
+	metricToJsonDag := generateFunctionDag(t, `{"metric": {"function": {"type": "file"}}}`, *artifact.NewSpecFromJson(jsonable.Json{}))
+	err = dag_validation.Validate(
+		metricToJsonDag,
+	)
+	require.Equal(t, err, dag_validation.ErrInvalidArtifactType)
 }
@@ -20,19 +20,28 @@ const (
 	ListNotificationsRoute   = "/notifications"
 	ArchiveNotificationRoute = "/notifications/{notificationId}/archive"
 
-	GetOperatorResultRoute = "/operator_result/{workflowDagResultId}/{operatorId}"
+	GetOperatorResultRoute     = "/operator_result/{workflowDagResultId}/{operatorId}"
+	GetOperatorResultLogsRoute = "/operator_result/{workflowDagResultId}/{operatorId}/logs"
 
 	GetNodePositionsRoute = "/positioning"
 	PreviewRoute          = "/preview"
 
 	GetUserProfileRoute = "/user"
 
-	ListWorkflowsRoute    = "/workflows"
-	RegisterWorkflowRoute = "/workflow/register"
-	GetWorkflowRoute      = "/workflow/{workflowId}"
-	DeleteWorkflowRoute   = "/workflow/{workflowId}/delete"
-	EditWorkflowRoute     = "/workflow/{workflowId}/edit"
-	RefreshWorkflowRoute  = "/workflow/{workflowId}/refresh"
-	UnwatchWorkflowRoute  = "/workflow/{workflowId}/unwatch"
-	WatchWorkflowRoute    = "/workflow/{workflowId}/watch"
+	ListWorkflowsRoute     = "/workflows"
+	RegisterWorkflowRoute  = "/workflow/register"
+	GetWorkflowRoute       = "/workflow/{workflowId}"
+	ExportAirflowDagRoute  = "/workflow/{workflowId}/airflow"
+	BackfillWorkflowRoute  = "/workflow/{workflowId}/backfill"
+	DeleteWorkflowRoute    = "/workflow/{workflowId}/delete"
+	EditWorkflowRoute      = "/workflow/{workflowId}/edit"
+	RefreshWorkflowRoute   = "/workflow/{workflowId}/refresh"
+	CancelWorkflowRunRoute = "/workflow/{workflowId}/result/{workflowDagResultId}/cancel"
+	ApproveOperatorRoute   = "/workflow/{workflowId}/result/{workflowDagResultId}/operator/{operatorId}/approve"
+	RejectOperatorRoute    = "/workflow/{workflowId}/result/{workflowDagResultId}/operator/{operatorId}/reject"
+	ResumeWorkflowRunRoute = "/workflow/{workflowId}/result/{workflowDagResultId}/resume"
+	UnwatchWorkflowRoute   = "/workflow/{workflowId}/unwatch"
+	WatchWorkflowRoute     = "/workflow/{workflowId}/watch"
+	TriggerWebhookRoute    = "/workflow/{workflowId}/webhook"
+	IssueWebhookTokenRoute = "/workflow/{workflowId}/webhook/token"
 )
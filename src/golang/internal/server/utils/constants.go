@@ -15,6 +15,7 @@ const (
 	ContentTypeHeader      = "content-type"
 	ApiKeyHeader           = "api-key"
 	LogsHeader             = "logs"
+	LogsOffsetHeader       = "logs-offset"
 	SdkClientVersionHeader = "sdk-client-version"
 
 	// Integration headers
@@ -24,6 +25,9 @@ const (
 
 	TableNameHeader = "table-name"
 
+	WebhookSignatureHeader = "webhook-signature"
+	WebhookTimestampHeader = "webhook-timestamp"
+
 	WorkflowIdUrlParam          = "workflowId"
 	WorkflowDagResultIdUrlParam = "workflowDagResultId"
 	OperatorIdUrlParam          = "operatorId"
@@ -114,6 +114,8 @@ func ValidateDagOperatorIntegrationOwnership(
 			integrationId = operator.Spec.Extract().IntegrationId
 		} else if operator.Spec.IsLoad() {
 			integrationId = operator.Spec.Load().IntegrationId
+		} else if operator.Spec.IsSensor() {
+			integrationId = operator.Spec.Sensor().IntegrationId
 		} else {
 			continue
 		}
@@ -63,6 +63,12 @@ func NewSpecFromBool(b boolean.Bool) *Spec {
 	}
 }
 
+func NewSpecFromJson(j jsonable.Json) *Spec {
+	return &Spec{
+		spec: specUnion{Type: JsonType, Json: &j},
+	}
+}
+
 func (s Spec) Type() Type {
 	return s.spec.Type
 }
@@ -108,7 +114,7 @@ func (s Spec) IsJson() bool {
 }
 
 func (s Spec) Json() *jsonable.Json {
-	if !s.IsBool() {
+	if !s.IsJson() {
 		return nil
 	}
 
@@ -0,0 +1,32 @@
+package job_record
+
+import "strings"
+
+const (
+	tableName = "job_record"
+
+	// JobRecord table column names
+	NameColumn                = "name"
+	PidColumn                 = "pid"
+	SpecTypeColumn            = "spec_type"
+	WorkflowIdColumn          = "workflow_id"
+	LogPathColumn             = "log_path"
+	StartTimeColumn           = "start_time"
+	WorkflowDagResultIdColumn = "workflow_dag_result_id"
+)
+
+// Returns a joined string of all JobRecord columns.
+func allColumns() string {
+	return strings.Join(
+		[]string{
+			NameColumn,
+			PidColumn,
+			SpecTypeColumn,
+			WorkflowIdColumn,
+			LogPathColumn,
+			StartTimeColumn,
+			WorkflowDagResultIdColumn,
+		},
+		",",
+	)
+}
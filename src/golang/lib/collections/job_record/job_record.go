@@ -0,0 +1,81 @@
+package job_record
+
+import (
+	"context"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/utils"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/google/uuid"
+)
+
+// JobRecord is the durable state of a job launched by a job manager. It allows the
+// job manager to find its jobs again after the server restarts.
+type JobRecord struct {
+	Name     string `db:"name"`
+	Pid      int    `db:"pid"`
+	SpecType string `db:"spec_type"`
+	// Only set for workflow jobs.
+	WorkflowId utils.NullUUID `db:"workflow_id"`
+	LogPath    string         `db:"log_path"`
+	StartTime  time.Time      `db:"start_time"`
+	// The run of a workflow job, once its executor has recorded it.
+	WorkflowDagResultId utils.NullUUID `db:"workflow_dag_result_id"`
+}
+
+type Reader interface {
+	GetJobRecords(ctx context.Context, db database.Database) ([]JobRecord, error)
+}
+
+type Writer interface {
+	// CreateJobRecord records the job, or updates the record of the job with the same name.
+	// The run recorded by `RecordWorkflowDagResult` is kept.
+	CreateJobRecord(
+		ctx context.Context,
+		name string,
+		pid int,
+		specType string,
+		workflowId *uuid.UUID,
+		logPath string,
+		startTime time.Time,
+		db database.Database,
+	) (*JobRecord, error)
+	// RecordWorkflowDagResult records the run of the workflow job with the given name. Since the
+	// job may be running before its record is created, the record is created if it does not
+	// exist yet, and `CreateJobRecord` fills in the remaining columns later.
+	RecordWorkflowDagResult(
+		ctx context.Context,
+		name string,
+		pid int,
+		specType string,
+		workflowId uuid.UUID,
+		workflowDagResultId uuid.UUID,
+		startTime time.Time,
+		db database.Database,
+	) error
+	DeleteJobRecord(ctx context.Context, name string, db database.Database) error
+}
+
+func NewReader(dbConf *database.DatabaseConfig) (Reader, error) {
+	if dbConf.Type == database.PostgresType {
+		return newPostgresReader(), nil
+	}
+
+	if dbConf.Type == database.SqliteType {
+		return newSqliteReader(), nil
+	}
+
+	return nil, database.ErrUnsupportedDbType
+}
+
+func NewWriter(dbConf *database.DatabaseConfig) (Writer, error) {
+	if dbConf.Type == database.PostgresType {
+		return newPostgresWriter(), nil
+	}
+
+	if dbConf.Type == database.SqliteType {
+		return newSqliteWriter(), nil
+	}
+
+	return nil, database.ErrUnsupportedDbType
+}
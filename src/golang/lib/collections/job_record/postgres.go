@@ -0,0 +1,17 @@
+package job_record
+
+type postgresReaderImpl struct {
+	standardReaderImpl
+}
+
+type postgresWriterImpl struct {
+	standardWriterImpl
+}
+
+func newPostgresReader() Reader {
+	return &postgresReaderImpl{standardReaderImpl{}}
+}
+
+func newPostgresWriter() Writer {
+	return &postgresWriterImpl{standardWriterImpl{}}
+}
@@ -0,0 +1,102 @@
+package job_record
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/google/uuid"
+)
+
+type standardReaderImpl struct{}
+
+type standardWriterImpl struct{}
+
+func (w *standardWriterImpl) CreateJobRecord(
+	ctx context.Context,
+	name string,
+	pid int,
+	specType string,
+	workflowId *uuid.UUID,
+	logPath string,
+	startTime time.Time,
+	db database.Database,
+) (*JobRecord, error) {
+	var workflowIdArg interface{}
+	if workflowId != nil {
+		workflowIdArg = *workflowId
+	}
+
+	createJobRecordStmt := fmt.Sprintf(
+		`INSERT INTO job_record (%s, %s, %s, %s, %s, %s) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (%s) DO UPDATE SET
+		%s = excluded.%s, %s = excluded.%s, %s = excluded.%s, %s = excluded.%s, %s = excluded.%s
+		RETURNING %s;`,
+		NameColumn, PidColumn, SpecTypeColumn, WorkflowIdColumn, LogPathColumn, StartTimeColumn,
+		NameColumn,
+		PidColumn, PidColumn,
+		SpecTypeColumn, SpecTypeColumn,
+		WorkflowIdColumn, WorkflowIdColumn,
+		LogPathColumn, LogPathColumn,
+		StartTimeColumn, StartTimeColumn,
+		allColumns(),
+	)
+
+	args := []interface{}{
+		name, pid, specType, workflowIdArg, logPath, startTime,
+	}
+
+	var jobRecord JobRecord
+	err := db.Query(ctx, &jobRecord, createJobRecordStmt, args...)
+	return &jobRecord, err
+}
+
+func (w *standardWriterImpl) RecordWorkflowDagResult(
+	ctx context.Context,
+	name string,
+	pid int,
+	specType string,
+	workflowId uuid.UUID,
+	workflowDagResultId uuid.UUID,
+	startTime time.Time,
+	db database.Database,
+) error {
+	// The log path is only known to the job manager, which sets it in `CreateJobRecord`.
+	recordWorkflowDagResultStmt := fmt.Sprintf(
+		`INSERT INTO job_record (%s, %s, %s, %s, %s, %s, %s) VALUES ($1, $2, $3, $4, '', $5, $6)
+		ON CONFLICT (%s) DO UPDATE SET %s = excluded.%s;`,
+		NameColumn, PidColumn, SpecTypeColumn, WorkflowIdColumn, LogPathColumn, StartTimeColumn, WorkflowDagResultIdColumn,
+		NameColumn,
+		WorkflowDagResultIdColumn, WorkflowDagResultIdColumn,
+	)
+
+	return db.Execute(
+		ctx,
+		recordWorkflowDagResultStmt,
+		name, pid, specType, workflowId, startTime, workflowDagResultId,
+	)
+}
+
+func (r *standardReaderImpl) GetJobRecords(
+	ctx context.Context,
+	db database.Database,
+) ([]JobRecord, error) {
+	getJobRecordsQuery := fmt.Sprintf(
+		"SELECT %s FROM job_record;",
+		allColumns(),
+	)
+	var jobRecords []JobRecord
+
+	err := db.Query(ctx, &jobRecords, getJobRecordsQuery)
+	return jobRecords, err
+}
+
+func (w *standardWriterImpl) DeleteJobRecord(
+	ctx context.Context,
+	name string,
+	db database.Database,
+) error {
+	deleteJobRecordStmt := `DELETE FROM job_record WHERE name = $1;`
+	return db.Execute(ctx, deleteJobRecordStmt, name)
+}
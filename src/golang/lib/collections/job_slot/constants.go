@@ -0,0 +1,32 @@
+package job_slot
+
+import "strings"
+
+const (
+	tableName = "job_slot"
+
+	// JobSlot table column names
+	NameColumn        = "name"
+	WorkflowIdColumn  = "workflow_id"
+	PriorityColumn    = "priority"
+	RequestedAtColumn = "requested_at"
+	AcquiredColumn    = "acquired"
+	PidColumn         = "pid"
+	StartTimeColumn   = "start_time"
+)
+
+// Returns a joined string of all JobSlot columns.
+func allColumns() string {
+	return strings.Join(
+		[]string{
+			NameColumn,
+			WorkflowIdColumn,
+			PriorityColumn,
+			RequestedAtColumn,
+			AcquiredColumn,
+			PidColumn,
+			StartTimeColumn,
+		},
+		",",
+	)
+}
@@ -0,0 +1,88 @@
+package job_slot
+
+import (
+	"context"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/utils"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/google/uuid"
+)
+
+// JobSlot is a job that waits for, or holds, one of the slots shared by the job managers of
+// every process that uses the same metadata database. A slot is held once it is acquired, and
+// freed by deleting the job's row.
+type JobSlot struct {
+	Name string `db:"name"`
+	// The workflow whose per-workflow limit the job counts against, if any.
+	WorkflowId  utils.NullUUID `db:"workflow_id"`
+	Priority    int            `db:"priority"`
+	RequestedAt time.Time      `db:"requested_at"`
+	Acquired    bool           `db:"acquired"`
+	// The process that owns the row: the job manager while the job waits, and the job once it
+	// has started. The row is stale once this process is gone.
+	Pid       int       `db:"pid"`
+	StartTime time.Time `db:"start_time"`
+}
+
+type Reader interface {
+	GetJobSlots(ctx context.Context, db database.Database) ([]JobSlot, error)
+}
+
+type Writer interface {
+	// CreateJobSlot records that the job waits for a slot.
+	CreateJobSlot(
+		ctx context.Context,
+		name string,
+		workflowId *uuid.UUID,
+		priority int,
+		pid int,
+		startTime time.Time,
+		db database.Database,
+	) (*JobSlot, error)
+	// AcquireJobSlot acquires a slot for the waiting job, and returns whether it did. A slot is
+	// only acquired if fewer than `maxSlots` slots, and fewer than `maxSlotsPerWorkflow` slots of
+	// the job's workflow, are held, and no waiting job that could acquire a slot has a higher
+	// priority or, with the same priority, was requested earlier. A limit of 0 means there is
+	// no limit.
+	AcquireJobSlot(
+		ctx context.Context,
+		name string,
+		maxSlots int,
+		maxSlotsPerWorkflow int,
+		db database.Database,
+	) (bool, error)
+	// UpdateJobSlotOwner sets the process that owns the job's row.
+	UpdateJobSlotOwner(
+		ctx context.Context,
+		name string,
+		pid int,
+		startTime time.Time,
+		db database.Database,
+	) error
+	DeleteJobSlot(ctx context.Context, name string, db database.Database) error
+}
+
+func NewReader(dbConf *database.DatabaseConfig) (Reader, error) {
+	if dbConf.Type == database.PostgresType {
+		return newPostgresReader(), nil
+	}
+
+	if dbConf.Type == database.SqliteType {
+		return newSqliteReader(), nil
+	}
+
+	return nil, database.ErrUnsupportedDbType
+}
+
+func NewWriter(dbConf *database.DatabaseConfig) (Writer, error) {
+	if dbConf.Type == database.PostgresType {
+		return newPostgresWriter(), nil
+	}
+
+	if dbConf.Type == database.SqliteType {
+		return newSqliteWriter(), nil
+	}
+
+	return nil, database.ErrUnsupportedDbType
+}
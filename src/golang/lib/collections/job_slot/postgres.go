@@ -0,0 +1,55 @@
+package job_slot
+
+import (
+	"context"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+)
+
+type postgresReaderImpl struct {
+	standardReaderImpl
+}
+
+type postgresWriterImpl struct {
+	standardWriterImpl
+}
+
+func newPostgresReader() Reader {
+	return &postgresReaderImpl{standardReaderImpl{}}
+}
+
+func newPostgresWriter() Writer {
+	return &postgresWriterImpl{standardWriterImpl{}}
+}
+
+func (w *postgresWriterImpl) AcquireJobSlot(
+	ctx context.Context,
+	name string,
+	maxSlots int,
+	maxSlotsPerWorkflow int,
+	db database.Database,
+) (bool, error) {
+	// Concurrent statements do not see each other's acquired slots under read committed, so
+	// the table is locked against other writers until the slot is acquired.
+	txn, err := db.BeginTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer txn.Rollback(ctx)
+
+	if err := txn.Execute(ctx, "LOCK TABLE job_slot IN SHARE ROW EXCLUSIVE MODE;"); err != nil {
+		return false, err
+	}
+
+	var acquired []JobSlot
+	err = txn.Query(ctx, &acquired, acquireJobSlotStmt, true, name, maxSlots, maxSlotsPerWorkflow)
+	if err != nil {
+		return false, err
+	}
+
+	if err := txn.Commit(ctx); err != nil {
+		return false, err
+	}
+
+	return len(acquired) > 0, nil
+}
@@ -0,0 +1,118 @@
+package job_slot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/google/uuid"
+)
+
+// acquireJobSlotStmt acquires a slot for a waiting job. A waiting job only blocks the jobs
+// behind it if it is not held back by the limit of its own workflow, so a workflow at its limit
+// does not block the other workflows.
+var acquireJobSlotStmt = fmt.Sprintf(
+	`UPDATE job_slot SET acquired = $1
+	WHERE name = $2 AND NOT acquired
+	AND ($3 <= 0 OR (SELECT COUNT(*) FROM job_slot AS held WHERE held.acquired) < $3)
+	AND ($4 <= 0 OR job_slot.workflow_id IS NULL OR (
+		SELECT COUNT(*) FROM job_slot AS held
+		WHERE held.acquired AND held.workflow_id = job_slot.workflow_id
+	) < $4)
+	AND NOT EXISTS (
+		SELECT 1 FROM job_slot AS waiting
+		WHERE NOT waiting.acquired
+		AND (
+			waiting.priority > job_slot.priority
+			OR (waiting.priority = job_slot.priority AND waiting.requested_at < job_slot.requested_at)
+		)
+		AND ($4 <= 0 OR waiting.workflow_id IS NULL OR (
+			SELECT COUNT(*) FROM job_slot AS held
+			WHERE held.acquired AND held.workflow_id = waiting.workflow_id
+		) < $4)
+	)
+	RETURNING %s;`,
+	allColumns(),
+)
+
+type standardReaderImpl struct{}
+
+type standardWriterImpl struct{}
+
+func (w *standardWriterImpl) CreateJobSlot(
+	ctx context.Context,
+	name string,
+	workflowId *uuid.UUID,
+	priority int,
+	pid int,
+	startTime time.Time,
+	db database.Database,
+) (*JobSlot, error) {
+	var workflowIdArg interface{}
+	if workflowId != nil {
+		workflowIdArg = *workflowId
+	}
+
+	insertColumns := []string{
+		NameColumn, WorkflowIdColumn, PriorityColumn, RequestedAtColumn, AcquiredColumn, PidColumn, StartTimeColumn,
+	}
+	insertJobSlotStmt := db.PrepareInsertWithReturnAllStmt(tableName, insertColumns, allColumns())
+
+	args := []interface{}{name, workflowIdArg, priority, time.Now(), false, pid, startTime}
+
+	var jobSlot JobSlot
+	err := db.Query(ctx, &jobSlot, insertJobSlotStmt, args...)
+	return &jobSlot, err
+}
+
+func (w *standardWriterImpl) AcquireJobSlot(
+	ctx context.Context,
+	name string,
+	maxSlots int,
+	maxSlotsPerWorkflow int,
+	db database.Database,
+) (bool, error) {
+	// The statement reads the held slots and acquires one at once, which is atomic since sqlite
+	// serializes writes.
+	var acquired []JobSlot
+	err := db.Query(ctx, &acquired, acquireJobSlotStmt, true, name, maxSlots, maxSlotsPerWorkflow)
+	return len(acquired) > 0, err
+}
+
+func (w *standardWriterImpl) UpdateJobSlotOwner(
+	ctx context.Context,
+	name string,
+	pid int,
+	startTime time.Time,
+	db database.Database,
+) error {
+	updateJobSlotOwnerStmt := fmt.Sprintf(
+		"UPDATE job_slot SET %s = $1, %s = $2 WHERE %s = $3;",
+		PidColumn, StartTimeColumn, NameColumn,
+	)
+	return db.Execute(ctx, updateJobSlotOwnerStmt, pid, startTime, name)
+}
+
+func (r *standardReaderImpl) GetJobSlots(
+	ctx context.Context,
+	db database.Database,
+) ([]JobSlot, error) {
+	getJobSlotsQuery := fmt.Sprintf(
+		"SELECT %s FROM job_slot;",
+		allColumns(),
+	)
+	var jobSlots []JobSlot
+
+	err := db.Query(ctx, &jobSlots, getJobSlotsQuery)
+	return jobSlots, err
+}
+
+func (w *standardWriterImpl) DeleteJobSlot(
+	ctx context.Context,
+	name string,
+	db database.Database,
+) error {
+	deleteJobSlotStmt := `DELETE FROM job_slot WHERE name = $1;`
+	return db.Execute(ctx, deleteJobSlotStmt, name)
+}
@@ -2,7 +2,9 @@ package operator_test
 
 import (
 	"encoding/json"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/collections/operator"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/function"
@@ -34,3 +36,105 @@ func TestSerializingAndDeserializingOperator(t *testing.T) {
 	require.True(t, reconstructedOp.Spec.IsFunction())
 	require.NotNil(t, reconstructedOp.Spec.Function())
 }
+
+func TestRetryPolicy(t *testing.T) {
+	var spec operator.Spec
+	err := json.Unmarshal([]byte(`{
+		"function": {"type": "file"},
+		"retry_policy": {
+			"max_attempts": 4,
+			"initial_backoff_seconds": 2,
+			"backoff_multiplier": 3,
+			"retry_on_user_failure": true
+		}
+	}`), &spec)
+	require.Nil(t, err)
+	require.True(t, spec.IsFunction())
+
+	retryPolicy := spec.RetryPolicy()
+	require.NotNil(t, retryPolicy)
+	require.Equal(t, 4, retryPolicy.MaxAttempts)
+	require.True(t, retryPolicy.RetryOnUserFailure)
+
+	require.Equal(t, 2*time.Second, retryPolicy.Backoff(1))
+	require.Equal(t, 6*time.Second, retryPolicy.Backoff(2))
+	require.Equal(t, 18*time.Second, retryPolicy.Backoff(3))
+
+	rawSpec, err := json.Marshal(spec)
+	require.Nil(t, err)
+
+	var reconstructedSpec operator.Spec
+	err = json.Unmarshal(rawSpec, &reconstructedSpec)
+	require.Nil(t, err)
+	require.Equal(t, retryPolicy, reconstructedSpec.RetryPolicy())
+
+	// Without a multiplier, the backoff stays the same.
+	constantRetryPolicy := &operator.RetryPolicy{MaxAttempts: 3, InitialBackoffSeconds: 1}
+	require.Equal(t, time.Second, constantRetryPolicy.Backoff(1))
+	require.Equal(t, time.Second, constantRetryPolicy.Backoff(2))
+
+	// The backoff is capped, even when it grows beyond what a duration can hold.
+	require.Equal(t, operator.MaxBackoff, retryPolicy.Backoff(20))
+	require.Equal(t, operator.MaxBackoff, retryPolicy.Backoff(10000))
+	require.Nil(t, retryPolicy.Validate())
+
+	invalidRetryPolicies := []operator.RetryPolicy{
+		{MaxAttempts: 0},
+		{MaxAttempts: 2, InitialBackoffSeconds: -1},
+		{MaxAttempts: 2, InitialBackoffSeconds: math.Inf(1)},
+		{MaxAttempts: 2, InitialBackoffSeconds: 1, BackoffMultiplier: math.NaN()},
+	}
+	for _, invalidRetryPolicy := range invalidRetryPolicies {
+		require.NotNil(t, invalidRetryPolicy.Validate())
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	var spec operator.Spec
+	err := json.Unmarshal([]byte(`{"function": {"type": "file"}, "timeout_seconds": 90}`), &spec)
+	require.Nil(t, err)
+	require.Equal(t, 90*time.Second, spec.Timeout())
+
+	var specWithoutTimeout operator.Spec
+	err = json.Unmarshal([]byte(`{"function": {"type": "file"}}`), &specWithoutTimeout)
+	require.Nil(t, err)
+	require.Equal(t, time.Duration(0), specWithoutTimeout.Timeout())
+}
+
+func TestSensorSpec(t *testing.T) {
+	var spec operator.Spec
+	err := json.Unmarshal([]byte(`{
+		"sensor": {
+			"service": "Postgres",
+			"integration_id": "8a1a9d4e-3f3c-4f0e-9d6b-2f4f0f6c1b2a",
+			"query": "SELECT COUNT(*) > 0 FROM events WHERE ds = CURRENT_DATE;",
+			"poll_interval_seconds": 30
+		},
+		"enable_cache": true
+	}`), &spec)
+	require.Nil(t, err)
+	require.True(t, spec.IsSensor())
+	require.Equal(t, 30*time.Second, spec.Sensor().PollInterval())
+
+	// Sensors are never cached.
+	require.False(t, spec.CacheEnabled())
+}
+
+func TestRunCondition(t *testing.T) {
+	var spec operator.Spec
+	err := json.Unmarshal([]byte(`{
+		"function": {"type": "file"},
+		"run_condition": {"artifact_id": "8a1a9d4e-3f3c-4f0e-9d6b-2f4f0f6c1b2a", "run_if": false}
+	}`), &spec)
+	require.Nil(t, err)
+	runCondition := spec.RunCondition()
+	require.NotNil(t, runCondition)
+	require.Equal(t, uuid.MustParse("8a1a9d4e-3f3c-4f0e-9d6b-2f4f0f6c1b2a"), runCondition.ArtifactId)
+	require.True(t, runCondition.IsMet(false))
+	require.False(t, runCondition.IsMet(true))
+
+	var unconditionalSpec operator.Spec
+	err = json.Unmarshal([]byte(`{"function": {"type": "file"}}`), &unconditionalSpec)
+	require.Nil(t, err)
+	require.Nil(t, unconditionalSpec.RunCondition())
+}
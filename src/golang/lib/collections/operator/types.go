@@ -4,14 +4,19 @@ package operator
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"math"
+	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/collections/utils"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/approval"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/check"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/function"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/metric"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/param"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/subworkflow"
 	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
 )
 
 // This file covers all operator specs.
@@ -27,22 +32,114 @@ import (
 type Type string
 
 const (
-	FunctionType Type = "function"
-	MetricType   Type = "metric"
-	CheckType    Type = "check"
-	ExtractType  Type = "extract"
-	LoadType     Type = "load"
-	ParamType    Type = "param"
+	FunctionType    Type = "function"
+	MetricType      Type = "metric"
+	CheckType       Type = "check"
+	ExtractType     Type = "extract"
+	LoadType        Type = "load"
+	ParamType       Type = "param"
+	SensorType      Type = "sensor"
+	SubWorkflowType Type = "sub_workflow"
+	ApprovalType    Type = "approval"
 )
 
 type specUnion struct {
-	Type     Type               `json:"type"`
-	Function *function.Function `json:"function,omitempty"`
-	Check    *check.Check       `json:"check,omitempty"`
-	Metric   *metric.Metric     `json:"metric,omitempty"`
-	Extract  *connector.Extract `json:"extract,omitempty"`
-	Load     *connector.Load    `json:"load,omitempty"`
-	Param    *param.Param       `json:"param,omitempty"`
+	Type        Type                     `json:"type"`
+	Function    *function.Function       `json:"function,omitempty"`
+	Check       *check.Check             `json:"check,omitempty"`
+	Metric      *metric.Metric           `json:"metric,omitempty"`
+	Extract     *connector.Extract       `json:"extract,omitempty"`
+	Load        *connector.Load          `json:"load,omitempty"`
+	Param       *param.Param             `json:"param,omitempty"`
+	Sensor      *connector.Sensor        `json:"sensor,omitempty"`
+	SubWorkflow *subworkflow.SubWorkflow `json:"sub_workflow,omitempty"`
+	Approval    *approval.Approval       `json:"approval,omitempty"`
+
+	// These apply to operators of any type.
+	RetryPolicy *RetryPolicy `json:"retry_policy,omitempty"`
+	// The maximum time each attempt of the operator may run. 0 means the operator has no timeout
+	// of its own, and is only bound by the workflow's timeout.
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+	// Whether later runs may reuse the outputs of an earlier run of the operator, as long as
+	// its spec, its function code and its inputs are unchanged.
+	EnableCache bool `json:"enable_cache,omitempty"`
+	// If set, the operator only runs if the condition is met, and is skipped otherwise.
+	RunCondition *RunCondition `json:"run_condition,omitempty"`
+	// If set, the function operator is run once per element of a JSON list artifact.
+	Map *Map `json:"map,omitempty"`
+}
+
+// RetryPolicy determines how an operator that failed is retried within the same workflow run.
+type RetryPolicy struct {
+	// The maximum number of times the operator is run, including the first attempt.
+	MaxAttempts int `json:"max_attempts"`
+	// How long to wait before the first retry.
+	InitialBackoffSeconds float64 `json:"initial_backoff_seconds"`
+	// The factor the backoff grows by with each retry. Values below 1 are treated as 1.
+	BackoffMultiplier float64 `json:"backoff_multiplier"`
+	// Failures caused by the system, e.g. a warehouse timeout, are always retried.
+	// Failures caused by the user's code are only retried if this is set.
+	RetryOnUserFailure bool `json:"retry_on_user_failure"`
+}
+
+// MaxBackoff bounds how long an operator waits before it is retried.
+const MaxBackoff = time.Hour
+
+// Validate checks that the retry policy runs the operator at least once, and that its backoff
+// is finite and not negative.
+func (p *RetryPolicy) Validate() error {
+	if p.MaxAttempts < 1 {
+		return errors.New("A retry policy must allow at least one attempt.")
+	}
+
+	if math.IsNaN(p.InitialBackoffSeconds) || p.InitialBackoffSeconds < 0 ||
+		p.InitialBackoffSeconds > MaxBackoff.Seconds() {
+		return errors.Newf("The initial backoff must be between 0 and %s.", MaxBackoff)
+	}
+
+	if math.IsNaN(p.BackoffMultiplier) || math.IsInf(p.BackoffMultiplier, 0) || p.BackoffMultiplier < 0 {
+		return errors.New("The backoff multiplier must be a finite number that is not negative.")
+	}
+
+	return nil
+}
+
+// Backoff returns how long to wait before running the operator again after `failedAttempts`
+// failures. It is at most `MaxBackoff`.
+func (p *RetryPolicy) Backoff(failedAttempts int) time.Duration {
+	multiplier := math.Max(p.BackoffMultiplier, 1)
+	backoffSeconds := p.InitialBackoffSeconds * math.Pow(multiplier, float64(failedAttempts-1))
+
+	// This also catches a backoff that overflowed to +Inf, or is NaN.
+	if !(backoffSeconds <= MaxBackoff.Seconds()) {
+		return MaxBackoff
+	}
+
+	return time.Duration(math.Max(backoffSeconds, 0) * float64(time.Second))
+}
+
+// RunCondition gates an operator on the value of a boolean artifact, e.g. the result of a check.
+// The artifact must be one of the operator's inputs, so that the operator only becomes ready
+// once the artifact is produced. It is not passed on to the operator's job.
+type RunCondition struct {
+	ArtifactId uuid.UUID `json:"artifact_id"`
+	// The operator runs if the artifact has this value.
+	RunIf bool `json:"run_if"`
+}
+
+// IsMet returns whether an operator with this condition runs when its artifact has the given value.
+func (c *RunCondition) IsMet(value bool) bool {
+	return value == c.RunIf
+}
+
+// Map fans a function operator out over the elements of a JSON list artifact. The list must be
+// one of the operator's inputs. Each element is run as its own job, which takes the element in
+// place of the list, and the tables output by the jobs are combined into the operator's single
+// table output.
+type Map struct {
+	ArtifactId uuid.UUID `json:"artifact_id"`
+	// The maximum number of elements that run at the same time. 0 means there is no limit.
+	MaxConcurrency int `json:"max_concurrency,omitempty"`
 }
 
 type Spec struct {
@@ -84,6 +181,27 @@ func NewSpecFromLoad(l connector.Load) *Spec {
 	}}
 }
 
+func NewSpecFromSensor(sensor connector.Sensor) *Spec {
+	return &Spec{spec: specUnion{
+		Type:   SensorType,
+		Sensor: &sensor,
+	}}
+}
+
+func NewSpecFromSubWorkflow(w subworkflow.SubWorkflow) *Spec {
+	return &Spec{spec: specUnion{
+		Type:        SubWorkflowType,
+		SubWorkflow: &w,
+	}}
+}
+
+func NewSpecFromApproval(a approval.Approval) *Spec {
+	return &Spec{spec: specUnion{
+		Type:     ApprovalType,
+		Approval: &a,
+	}}
+}
+
 func (s Spec) Type() Type {
 	return s.spec.Type
 }
@@ -176,6 +294,78 @@ func (s Spec) Param() *param.Param {
 	return s.spec.Param
 }
 
+func (s Spec) IsSensor() bool {
+	return s.Type() == SensorType
+}
+
+func (s Spec) Sensor() *connector.Sensor {
+	if !s.IsSensor() {
+		return nil
+	}
+
+	return s.spec.Sensor
+}
+
+func (s Spec) IsSubWorkflow() bool {
+	return s.Type() == SubWorkflowType
+}
+
+func (s Spec) SubWorkflow() *subworkflow.SubWorkflow {
+	if !s.IsSubWorkflow() {
+		return nil
+	}
+
+	return s.spec.SubWorkflow
+}
+
+func (s Spec) IsApproval() bool {
+	return s.Type() == ApprovalType
+}
+
+func (s Spec) Approval() *approval.Approval {
+	if !s.IsApproval() {
+		return nil
+	}
+
+	return s.spec.Approval
+}
+
+// IsGate returns whether the operator only gates the operators downstream of it, i.e. it is a
+// sensor or an approval. Operators take the outputs of gates as inputs only so that they become
+// ready once the gate succeeds, so these artifacts are not passed on to the operators' jobs.
+func (s Spec) IsGate() bool {
+	return s.IsSensor() || s.IsApproval()
+}
+
+// RetryPolicy returns the operator's retry policy, or nil if the operator is not retried.
+func (s Spec) RetryPolicy() *RetryPolicy {
+	return s.spec.RetryPolicy
+}
+
+// CacheEnabled returns whether the operator's outputs may be reused across runs. Load operators
+// are never cached, since they are run for their side effects, and neither are sensors, since
+// they check the state of the world at the time of the run. Sub-workflows are not cached either,
+// since the workflow they run may change without their spec changing, and neither are approvals,
+// since each run has to be approved.
+func (s Spec) CacheEnabled() bool {
+	return s.spec.EnableCache && !s.IsLoad() && !s.IsSensor() && !s.IsSubWorkflow() && !s.IsApproval()
+}
+
+// RunCondition returns the condition the operator runs on, or nil if the operator always runs.
+func (s Spec) RunCondition() *RunCondition {
+	return s.spec.RunCondition
+}
+
+// Map returns how the operator fans out over a list artifact, or nil if the operator runs once.
+func (s Spec) Map() *Map {
+	return s.spec.Map
+}
+
+// Timeout returns how long each attempt of the operator may run, or 0 if the operator has no timeout.
+func (s Spec) Timeout() time.Duration {
+	return time.Duration(s.spec.TimeoutSeconds) * time.Second
+}
+
 func (s Spec) MarshalJSON() ([]byte, error) {
 	return json.Marshal(s.spec)
 }
@@ -207,6 +397,15 @@ func (s *Spec) UnmarshalJSON(rawMessage []byte) error {
 	} else if spec.Param != nil {
 		spec.Type = ParamType
 		typeCount++
+	} else if spec.Sensor != nil {
+		spec.Type = SensorType
+		typeCount++
+	} else if spec.SubWorkflow != nil {
+		spec.Type = SubWorkflowType
+		typeCount++
+	} else if spec.Approval != nil {
+		spec.Type = ApprovalType
+		typeCount++
 	}
 	if typeCount != 1 {
 		return errors.Newf("Operator Spec can only be of one type. Number of types: %d", typeCount)
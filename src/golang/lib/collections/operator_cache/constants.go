@@ -0,0 +1,28 @@
+package operator_cache
+
+import "strings"
+
+const (
+	tableName = "operator_cache"
+
+	// OperatorCache table column names
+	CacheKeyColumn         = "cache_key"
+	WorkflowIdColumn       = "workflow_id"
+	OperatorResultIdColumn = "operator_result_id"
+	OutputsColumn          = "outputs"
+	CreatedAtColumn        = "created_at"
+)
+
+// Returns a joined string of all OperatorCache columns.
+func allColumns() string {
+	return strings.Join(
+		[]string{
+			CacheKeyColumn,
+			WorkflowIdColumn,
+			OperatorResultIdColumn,
+			OutputsColumn,
+			CreatedAtColumn,
+		},
+		",",
+	)
+}
@@ -0,0 +1,60 @@
+package operator_cache
+
+import (
+	"context"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/utils"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/google/uuid"
+)
+
+type noopReaderImpl struct {
+	throwError bool
+}
+
+type noopWriterImpl struct {
+	throwError bool
+}
+
+func NewNoopReader(throwError bool) Reader {
+	return &noopReaderImpl{throwError: throwError}
+}
+
+func NewNoopWriter(throwError bool) Writer {
+	return &noopWriterImpl{throwError: throwError}
+}
+
+func (r *noopReaderImpl) GetCacheEntry(
+	ctx context.Context,
+	cacheKey string,
+	db database.Database,
+) (*CacheEntry, error) {
+	return nil, utils.NoopInterfaceErrorHandling(r.throwError)
+}
+
+func (w *noopWriterImpl) CreateCacheEntry(
+	ctx context.Context,
+	cacheKey string,
+	workflowId uuid.UUID,
+	operatorResultId uuid.UUID,
+	outputs *CachedOutputs,
+	db database.Database,
+) (*CacheEntry, error) {
+	return nil, utils.NoopInterfaceErrorHandling(w.throwError)
+}
+
+func (w *noopWriterImpl) DeleteCacheEntry(
+	ctx context.Context,
+	cacheKey string,
+	db database.Database,
+) error {
+	return utils.NoopInterfaceErrorHandling(w.throwError)
+}
+
+func (w *noopWriterImpl) DeleteCacheEntriesByWorkflowId(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	db database.Database,
+) error {
+	return utils.NoopInterfaceErrorHandling(w.throwError)
+}
@@ -0,0 +1,63 @@
+package operator_cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/google/uuid"
+)
+
+// CacheEntry records the outputs of a successful operator run, so that later runs of the same
+// workflow can reuse them instead of running the operator again. The cache key is a hash of
+// everything that determines the operator's outputs.
+type CacheEntry struct {
+	CacheKey   string    `db:"cache_key" json:"cache_key"`
+	WorkflowId uuid.UUID `db:"workflow_id" json:"workflow_id"`
+	// The operator result that produced the outputs.
+	OperatorResultId uuid.UUID     `db:"operator_result_id" json:"operator_result_id"`
+	Outputs          CachedOutputs `db:"outputs" json:"outputs"`
+	CreatedAt        time.Time     `db:"created_at" json:"created_at"`
+}
+
+type Reader interface {
+	// GetCacheEntry returns database.ErrNoRows if there is no entry for the cache key.
+	GetCacheEntry(ctx context.Context, cacheKey string, db database.Database) (*CacheEntry, error)
+}
+
+type Writer interface {
+	CreateCacheEntry(
+		ctx context.Context,
+		cacheKey string,
+		workflowId uuid.UUID,
+		operatorResultId uuid.UUID,
+		outputs *CachedOutputs,
+		db database.Database,
+	) (*CacheEntry, error)
+	DeleteCacheEntry(ctx context.Context, cacheKey string, db database.Database) error
+	DeleteCacheEntriesByWorkflowId(ctx context.Context, workflowId uuid.UUID, db database.Database) error
+}
+
+func NewReader(dbConf *database.DatabaseConfig) (Reader, error) {
+	if dbConf.Type == database.PostgresType {
+		return newPostgresReader(), nil
+	}
+
+	if dbConf.Type == database.SqliteType {
+		return newSqliteReader(), nil
+	}
+
+	return nil, database.ErrUnsupportedDbType
+}
+
+func NewWriter(dbConf *database.DatabaseConfig) (Writer, error) {
+	if dbConf.Type == database.PostgresType {
+		return newPostgresWriter(), nil
+	}
+
+	if dbConf.Type == database.SqliteType {
+		return newSqliteWriter(), nil
+	}
+
+	return nil, database.ErrUnsupportedDbType
+}
@@ -0,0 +1,17 @@
+package operator_cache
+
+type postgresReaderImpl struct {
+	standardReaderImpl
+}
+
+type postgresWriterImpl struct {
+	standardWriterImpl
+}
+
+func newPostgresReader() Reader {
+	return &postgresReaderImpl{standardReaderImpl{}}
+}
+
+func newPostgresWriter() Writer {
+	return &postgresWriterImpl{standardWriterImpl{}}
+}
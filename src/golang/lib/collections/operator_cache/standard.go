@@ -0,0 +1,69 @@
+package operator_cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/google/uuid"
+)
+
+type standardReaderImpl struct{}
+
+type standardWriterImpl struct{}
+
+func (w *standardWriterImpl) CreateCacheEntry(
+	ctx context.Context,
+	cacheKey string,
+	workflowId uuid.UUID,
+	operatorResultId uuid.UUID,
+	outputs *CachedOutputs,
+	db database.Database,
+) (*CacheEntry, error) {
+	insertColumns := []string{
+		CacheKeyColumn, WorkflowIdColumn, OperatorResultIdColumn, OutputsColumn, CreatedAtColumn,
+	}
+	insertCacheEntryStmt := db.PrepareInsertWithReturnAllStmt(tableName, insertColumns, allColumns())
+
+	args := []interface{}{
+		cacheKey, workflowId, operatorResultId, outputs, time.Now(),
+	}
+
+	var cacheEntry CacheEntry
+	err := db.Query(ctx, &cacheEntry, insertCacheEntryStmt, args...)
+	return &cacheEntry, err
+}
+
+func (r *standardReaderImpl) GetCacheEntry(
+	ctx context.Context,
+	cacheKey string,
+	db database.Database,
+) (*CacheEntry, error) {
+	getCacheEntryQuery := fmt.Sprintf(
+		"SELECT %s FROM operator_cache WHERE cache_key = $1;",
+		allColumns(),
+	)
+	var cacheEntry CacheEntry
+
+	err := db.Query(ctx, &cacheEntry, getCacheEntryQuery, cacheKey)
+	return &cacheEntry, err
+}
+
+func (w *standardWriterImpl) DeleteCacheEntry(
+	ctx context.Context,
+	cacheKey string,
+	db database.Database,
+) error {
+	deleteCacheEntryStmt := `DELETE FROM operator_cache WHERE cache_key = $1;`
+	return db.Execute(ctx, deleteCacheEntryStmt, cacheKey)
+}
+
+func (w *standardWriterImpl) DeleteCacheEntriesByWorkflowId(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	db database.Database,
+) error {
+	deleteCacheEntriesStmt := `DELETE FROM operator_cache WHERE workflow_id = $1;`
+	return db.Execute(ctx, deleteCacheEntriesStmt, workflowId)
+}
@@ -0,0 +1,25 @@
+package operator_cache
+
+import (
+	"database/sql/driver"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/utils"
+)
+
+// CachedOutput is an output artifact of a cached operator run.
+type CachedOutput struct {
+	ContentPath string                   `json:"content_path"`
+	Metadata    artifact_result.Metadata `json:"metadata"`
+}
+
+// CachedOutputs are ordered the same way as the outputs of the operator.
+type CachedOutputs []CachedOutput
+
+func (o *CachedOutputs) Value() (driver.Value, error) {
+	return utils.ValueJsonB(*o)
+}
+
+func (o *CachedOutputs) Scan(value interface{}) error {
+	return utils.ScanJsonB(value, o)
+}
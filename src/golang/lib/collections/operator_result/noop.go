@@ -3,6 +3,7 @@ package operator_result
 import (
 	"context"
 
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
 	"github.com/aqueducthq/aqueduct/lib/collections/utils"
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/google/uuid"
@@ -75,6 +76,16 @@ func (w *noopWriterImpl) UpdateOperatorResult(
 	return nil, utils.NoopInterfaceErrorHandling(w.throwError)
 }
 
+func (w *noopWriterImpl) UpdateOperatorResultWithStatus(
+	ctx context.Context,
+	id uuid.UUID,
+	status shared.ExecutionStatus,
+	changes map[string]interface{},
+	db database.Database,
+) (*OperatorResult, error) {
+	return nil, utils.NoopInterfaceErrorHandling(w.throwError)
+}
+
 func (w *noopWriterImpl) DeleteOperatorResult(
 	ctx context.Context,
 	id uuid.UUID,
@@ -45,6 +45,15 @@ type Writer interface {
 		changes map[string]interface{},
 		db database.Database,
 	) (*OperatorResult, error)
+	// UpdateOperatorResultWithStatus only applies `changes` if the operator result still has
+	// status `status`. It returns database.ErrNoRows otherwise.
+	UpdateOperatorResultWithStatus(
+		ctx context.Context,
+		id uuid.UUID,
+		status shared.ExecutionStatus,
+		changes map[string]interface{},
+		db database.Database,
+	) (*OperatorResult, error)
 	DeleteOperatorResult(ctx context.Context, id uuid.UUID, db database.Database) error
 	DeleteOperatorResults(ctx context.Context, ids []uuid.UUID, db database.Database) error
 }
@@ -120,6 +120,29 @@ func (w *standardWriterImpl) UpdateOperatorResult(
 	return &operatorResult, err
 }
 
+func (w *standardWriterImpl) UpdateOperatorResultWithStatus(
+	ctx context.Context,
+	id uuid.UUID,
+	status shared.ExecutionStatus,
+	changes map[string]interface{},
+	db database.Database,
+) (*OperatorResult, error) {
+	var operatorResult OperatorResult
+	err := utils.UpdateRecordToDestIfIn(
+		ctx,
+		&operatorResult,
+		changes,
+		tableName,
+		IdColumn,
+		id,
+		StatusColumn,
+		[]interface{}{status},
+		allColumns(),
+		db,
+	)
+	return &operatorResult, err
+}
+
 func (w *standardWriterImpl) DeleteOperatorResult(
 	ctx context.Context,
 	id uuid.UUID,
@@ -2,13 +2,42 @@ package operator_result
 
 import (
 	"database/sql/driver"
+	"time"
 
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
 	"github.com/aqueducthq/aqueduct/lib/collections/utils"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/function"
+	"github.com/google/uuid"
 )
 
 type Metadata struct {
 	Error string            `json:"error"`
 	Logs  map[string]string `json:"logs"`
+	// The resource limit that stopped the operator, if any.
+	ExceededLimit function.ResourceLimit `json:"exceeded_limit,omitempty"`
+	// The number of times the operator was run, and the failures of the attempts that
+	// were retried according to the operator's retry policy.
+	Attempts       int             `json:"attempts,omitempty"`
+	FailedAttempts []FailedAttempt `json:"failed_attempts,omitempty"`
+	// Whether the outputs of an earlier run were reused instead of running the operator,
+	// and the operator result of that run.
+	Cached     bool       `json:"cached,omitempty"`
+	CachedFrom *uuid.UUID `json:"cached_from,omitempty"`
+	// The results of the elements of a map operator, in the order of its list artifact.
+	Elements []ElementResult `json:"elements,omitempty"`
+}
+
+// ElementResult records how a single element of a map operator ran.
+type ElementResult struct {
+	Status shared.ExecutionStatus `json:"status"`
+	Error  string                 `json:"error,omitempty"`
+}
+
+// FailedAttempt records an attempt at running an operator that failed and was retried.
+type FailedAttempt struct {
+	Error         string                 `json:"error"`
+	ExceededLimit function.ResourceLimit `json:"exceeded_limit,omitempty"`
+	FinishedAt    time.Time              `json:"finished_at"`
 }
 
 type NullMetadata struct {
@@ -0,0 +1,32 @@
+package run_request
+
+import "strings"
+
+const (
+	tableName = "run_request"
+
+	// RunRequest table column names
+	WorkflowDagResultIdColumn = "workflow_dag_result_id"
+	WorkflowIdColumn          = "workflow_id"
+	ParentColumn              = "parent"
+	CreatedAtColumn           = "created_at"
+	BackfillIdColumn          = "backfill_id"
+	ConcurrencyColumn         = "concurrency"
+	LaunchedAtColumn          = "launched_at"
+)
+
+// Returns a joined string of all RunRequest columns.
+func allColumns() string {
+	return strings.Join(
+		[]string{
+			WorkflowDagResultIdColumn,
+			WorkflowIdColumn,
+			ParentColumn,
+			CreatedAtColumn,
+			BackfillIdColumn,
+			ConcurrencyColumn,
+			LaunchedAtColumn,
+		},
+		",",
+	)
+}
@@ -0,0 +1,75 @@
+package run_request
+
+import (
+	"context"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/utils"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/google/uuid"
+)
+
+type noopReaderImpl struct {
+	throwError bool
+}
+
+type noopWriterImpl struct {
+	throwError bool
+}
+
+func NewNoopReader(throwError bool) Reader {
+	return &noopReaderImpl{throwError: throwError}
+}
+
+func NewNoopWriter(throwError bool) Writer {
+	return &noopWriterImpl{throwError: throwError}
+}
+
+func (r *noopReaderImpl) GetRunRequests(ctx context.Context, db database.Database) ([]RunRequest, error) {
+	return nil, utils.NoopInterfaceErrorHandling(r.throwError)
+}
+
+func (w *noopWriterImpl) CreateRunRequest(
+	ctx context.Context,
+	workflowDagResultId uuid.UUID,
+	workflowId uuid.UUID,
+	parent *job.ParentOperator,
+	db database.Database,
+) (*RunRequest, error) {
+	return nil, utils.NoopInterfaceErrorHandling(w.throwError)
+}
+
+func (w *noopWriterImpl) CreateBackfillRunRequest(
+	ctx context.Context,
+	workflowDagResultId uuid.UUID,
+	workflowId uuid.UUID,
+	backfillId uuid.UUID,
+	concurrency int,
+	db database.Database,
+) (*RunRequest, error) {
+	return nil, utils.NoopInterfaceErrorHandling(w.throwError)
+}
+
+func (w *noopWriterImpl) ClaimRunRequest(
+	ctx context.Context,
+	workflowDagResultId uuid.UUID,
+	db database.Database,
+) (bool, error) {
+	return false, utils.NoopInterfaceErrorHandling(w.throwError)
+}
+
+func (w *noopWriterImpl) DeleteRunRequest(
+	ctx context.Context,
+	workflowDagResultId uuid.UUID,
+	db database.Database,
+) (bool, error) {
+	return false, utils.NoopInterfaceErrorHandling(w.throwError)
+}
+
+func (w *noopWriterImpl) DeleteRunRequestsByWorkflowId(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	db database.Database,
+) error {
+	return utils.NoopInterfaceErrorHandling(w.throwError)
+}
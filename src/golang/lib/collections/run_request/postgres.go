@@ -0,0 +1,17 @@
+package run_request
+
+type postgresReaderImpl struct {
+	standardReaderImpl
+}
+
+type postgresWriterImpl struct {
+	standardWriterImpl
+}
+
+func newPostgresReader() Reader {
+	return &postgresReaderImpl{standardReaderImpl{}}
+}
+
+func newPostgresWriter() Writer {
+	return &postgresWriterImpl{standardWriterImpl{}}
+}
@@ -0,0 +1,83 @@
+package run_request
+
+import (
+	"context"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/utils"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/google/uuid"
+)
+
+// RunRequest records a workflow run that was requested by another run, e.g. by a cascade trigger
+// or a sub-workflow operator, or by a backfill. Executors do not launch jobs on the server's
+// behalf, so the run is created up front and the server launches it. The request is kept
+// until its run finishes.
+type RunRequest struct {
+	WorkflowDagResultId uuid.UUID `db:"workflow_dag_result_id" json:"workflow_dag_result_id"`
+	WorkflowId          uuid.UUID `db:"workflow_id" json:"workflow_id"`
+	// The sub-workflow operator that requested the run, if any.
+	Parent    NullParent `db:"parent" json:"parent"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	// The backfill that requested the run, if any, and the maximum number of its runs that
+	// may run at once.
+	BackfillId  utils.NullUUID `db:"backfill_id" json:"backfill_id"`
+	Concurrency int            `db:"concurrency" json:"concurrency"`
+	// When the server launched the run. It is unset until then.
+	LaunchedAt utils.NullTime `db:"launched_at" json:"launched_at"`
+}
+
+type Reader interface {
+	GetRunRequests(ctx context.Context, db database.Database) ([]RunRequest, error)
+}
+
+type Writer interface {
+	CreateRunRequest(
+		ctx context.Context,
+		workflowDagResultId uuid.UUID,
+		workflowId uuid.UUID,
+		parent *job.ParentOperator,
+		db database.Database,
+	) (*RunRequest, error)
+	// CreateBackfillRunRequest records a run requested by the backfill, such that at most
+	// `concurrency` of the backfill's runs are launched at once.
+	CreateBackfillRunRequest(
+		ctx context.Context,
+		workflowDagResultId uuid.UUID,
+		workflowId uuid.UUID,
+		backfillId uuid.UUID,
+		concurrency int,
+		db database.Database,
+	) (*RunRequest, error)
+	// ClaimRunRequest records that the run is being launched, and returns whether its request
+	// was not claimed before. It lets the server claim a request before launching its run.
+	ClaimRunRequest(ctx context.Context, workflowDagResultId uuid.UUID, db database.Database) (bool, error)
+	// DeleteRunRequest deletes the request of the run, and returns whether there was one.
+	DeleteRunRequest(ctx context.Context, workflowDagResultId uuid.UUID, db database.Database) (bool, error)
+	DeleteRunRequestsByWorkflowId(ctx context.Context, workflowId uuid.UUID, db database.Database) error
+}
+
+func NewReader(dbConf *database.DatabaseConfig) (Reader, error) {
+	if dbConf.Type == database.PostgresType {
+		return newPostgresReader(), nil
+	}
+
+	if dbConf.Type == database.SqliteType {
+		return newSqliteReader(), nil
+	}
+
+	return nil, database.ErrUnsupportedDbType
+}
+
+func NewWriter(dbConf *database.DatabaseConfig) (Writer, error) {
+	if dbConf.Type == database.PostgresType {
+		return newPostgresWriter(), nil
+	}
+
+	if dbConf.Type == database.SqliteType {
+		return newSqliteWriter(), nil
+	}
+
+	return nil, database.ErrUnsupportedDbType
+}
@@ -0,0 +1,17 @@
+package run_request
+
+type sqliteReaderImpl struct {
+	standardReaderImpl
+}
+
+type sqliteWriterImpl struct {
+	standardWriterImpl
+}
+
+func newSqliteReader() Reader {
+	return &sqliteReaderImpl{standardReaderImpl{}}
+}
+
+func newSqliteWriter() Writer {
+	return &sqliteWriterImpl{standardWriterImpl{}}
+}
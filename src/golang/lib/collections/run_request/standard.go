@@ -0,0 +1,109 @@
+package run_request
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/google/uuid"
+)
+
+type standardReaderImpl struct{}
+
+type standardWriterImpl struct{}
+
+func (w *standardWriterImpl) CreateRunRequest(
+	ctx context.Context,
+	workflowDagResultId uuid.UUID,
+	workflowId uuid.UUID,
+	parent *job.ParentOperator,
+	db database.Database,
+) (*RunRequest, error) {
+	insertColumns := []string{WorkflowDagResultIdColumn, WorkflowIdColumn, ParentColumn, CreatedAtColumn}
+	insertRunRequestStmt := db.PrepareInsertWithReturnAllStmt(tableName, insertColumns, allColumns())
+
+	nullParent := &NullParent{IsNull: true}
+	if parent != nil {
+		nullParent = &NullParent{ParentOperator: *parent}
+	}
+
+	args := []interface{}{workflowDagResultId, workflowId, nullParent, time.Now()}
+
+	var runRequest RunRequest
+	err := db.Query(ctx, &runRequest, insertRunRequestStmt, args...)
+	return &runRequest, err
+}
+
+func (w *standardWriterImpl) CreateBackfillRunRequest(
+	ctx context.Context,
+	workflowDagResultId uuid.UUID,
+	workflowId uuid.UUID,
+	backfillId uuid.UUID,
+	concurrency int,
+	db database.Database,
+) (*RunRequest, error) {
+	insertColumns := []string{
+		WorkflowDagResultIdColumn, WorkflowIdColumn, ParentColumn, CreatedAtColumn, BackfillIdColumn, ConcurrencyColumn,
+	}
+	insertRunRequestStmt := db.PrepareInsertWithReturnAllStmt(tableName, insertColumns, allColumns())
+
+	args := []interface{}{
+		workflowDagResultId, workflowId, &NullParent{IsNull: true}, time.Now(), backfillId, concurrency,
+	}
+
+	var runRequest RunRequest
+	err := db.Query(ctx, &runRequest, insertRunRequestStmt, args...)
+	return &runRequest, err
+}
+
+func (r *standardReaderImpl) GetRunRequests(ctx context.Context, db database.Database) ([]RunRequest, error) {
+	getRunRequestsQuery := fmt.Sprintf(
+		"SELECT %s FROM run_request ORDER BY created_at;",
+		allColumns(),
+	)
+	var runRequests []RunRequest
+
+	err := db.Query(ctx, &runRequests, getRunRequestsQuery)
+	return runRequests, err
+}
+
+func (w *standardWriterImpl) ClaimRunRequest(
+	ctx context.Context,
+	workflowDagResultId uuid.UUID,
+	db database.Database,
+) (bool, error) {
+	claimRunRequestStmt := fmt.Sprintf(
+		"UPDATE run_request SET launched_at = $1 WHERE workflow_dag_result_id = $2 AND launched_at IS NULL RETURNING %s;",
+		allColumns(),
+	)
+	var claimed []RunRequest
+
+	err := db.Query(ctx, &claimed, claimRunRequestStmt, time.Now(), workflowDagResultId)
+	return len(claimed) > 0, err
+}
+
+func (w *standardWriterImpl) DeleteRunRequest(
+	ctx context.Context,
+	workflowDagResultId uuid.UUID,
+	db database.Database,
+) (bool, error) {
+	deleteRunRequestStmt := fmt.Sprintf(
+		"DELETE FROM run_request WHERE workflow_dag_result_id = $1 RETURNING %s;",
+		allColumns(),
+	)
+	var deleted []RunRequest
+
+	err := db.Query(ctx, &deleted, deleteRunRequestStmt, workflowDagResultId)
+	return len(deleted) > 0, err
+}
+
+func (w *standardWriterImpl) DeleteRunRequestsByWorkflowId(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	db database.Database,
+) error {
+	deleteRunRequestsStmt := `DELETE FROM run_request WHERE workflow_id = $1;`
+	return db.Execute(ctx, deleteRunRequestsStmt, workflowId)
+}
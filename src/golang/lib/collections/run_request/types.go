@@ -0,0 +1,36 @@
+package run_request
+
+import (
+	"database/sql/driver"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/utils"
+	"github.com/aqueducthq/aqueduct/lib/job"
+)
+
+type NullParent struct {
+	job.ParentOperator
+	IsNull bool
+}
+
+func (n *NullParent) Value() (driver.Value, error) {
+	if n.IsNull {
+		return nil, nil
+	}
+
+	return utils.ValueJsonB(n.ParentOperator)
+}
+
+func (n *NullParent) Scan(value interface{}) error {
+	if value == nil {
+		n.IsNull = true
+		return nil
+	}
+
+	parent := &job.ParentOperator{}
+	if err := utils.ScanJsonB(value, parent); err != nil {
+		return err
+	}
+
+	n.ParentOperator, n.IsNull = *parent, false
+	return nil
+}
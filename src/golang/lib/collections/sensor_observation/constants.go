@@ -0,0 +1,26 @@
+package sensor_observation
+
+import "strings"
+
+const (
+	tableName = "sensor_observation"
+
+	// SensorObservation table column names
+	WorkflowIdColumn  = "workflow_id"
+	ObservationColumn = "observation"
+	VersionColumn     = "version"
+	CreatedAtColumn   = "created_at"
+)
+
+// Returns a joined string of all SensorObservation columns.
+func allColumns() string {
+	return strings.Join(
+		[]string{
+			WorkflowIdColumn,
+			ObservationColumn,
+			VersionColumn,
+			CreatedAtColumn,
+		},
+		",",
+	)
+}
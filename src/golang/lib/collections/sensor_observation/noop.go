@@ -0,0 +1,60 @@
+package sensor_observation
+
+import (
+	"context"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/utils"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/google/uuid"
+)
+
+type noopReaderImpl struct {
+	throwError bool
+}
+
+type noopWriterImpl struct {
+	throwError bool
+}
+
+func NewNoopReader(throwError bool) Reader {
+	return &noopReaderImpl{throwError: throwError}
+}
+
+func NewNoopWriter(throwError bool) Writer {
+	return &noopWriterImpl{throwError: throwError}
+}
+
+func (r *noopReaderImpl) GetObservationsByWorkflowId(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	db database.Database,
+) ([]SensorObservation, error) {
+	return nil, utils.NoopInterfaceErrorHandling(r.throwError)
+}
+
+func (w *noopWriterImpl) RecordObservation(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	observation string,
+	version string,
+	db database.Database,
+) (*SensorObservation, error) {
+	return nil, utils.NoopInterfaceErrorHandling(w.throwError)
+}
+
+func (w *noopWriterImpl) DeleteObservations(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	observations []string,
+	db database.Database,
+) error {
+	return utils.NoopInterfaceErrorHandling(w.throwError)
+}
+
+func (w *noopWriterImpl) DeleteObservationsByWorkflowId(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	db database.Database,
+) error {
+	return utils.NoopInterfaceErrorHandling(w.throwError)
+}
@@ -0,0 +1,72 @@
+package sensor_observation
+
+import (
+	"context"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/google/uuid"
+)
+
+// SensorObservation records the version of something that the sensor of a workflow has already
+// observed, so that it starts a run for each version only once. For a storage sensor, the
+// observation is a file path and the version identifies the file's content. A workflow has at
+// most one observation per path, and observations of paths that are gone are deleted, so a
+// workflow has at most as many observations as its sensor currently sees.
+type SensorObservation struct {
+	WorkflowId  uuid.UUID `db:"workflow_id" json:"workflow_id"`
+	Observation string    `db:"observation" json:"observation"`
+	Version     string    `db:"version" json:"version"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+type Reader interface {
+	GetObservationsByWorkflowId(
+		ctx context.Context,
+		workflowId uuid.UUID,
+		db database.Database,
+	) ([]SensorObservation, error)
+}
+
+type Writer interface {
+	// RecordObservation records the version of the observation, replacing any version recorded
+	// before.
+	RecordObservation(
+		ctx context.Context,
+		workflowId uuid.UUID,
+		observation string,
+		version string,
+		db database.Database,
+	) (*SensorObservation, error)
+	DeleteObservations(
+		ctx context.Context,
+		workflowId uuid.UUID,
+		observations []string,
+		db database.Database,
+	) error
+	DeleteObservationsByWorkflowId(ctx context.Context, workflowId uuid.UUID, db database.Database) error
+}
+
+func NewReader(dbConf *database.DatabaseConfig) (Reader, error) {
+	if dbConf.Type == database.PostgresType {
+		return newPostgresReader(), nil
+	}
+
+	if dbConf.Type == database.SqliteType {
+		return newSqliteReader(), nil
+	}
+
+	return nil, database.ErrUnsupportedDbType
+}
+
+func NewWriter(dbConf *database.DatabaseConfig) (Writer, error) {
+	if dbConf.Type == database.PostgresType {
+		return newPostgresWriter(), nil
+	}
+
+	if dbConf.Type == database.SqliteType {
+		return newSqliteWriter(), nil
+	}
+
+	return nil, database.ErrUnsupportedDbType
+}
@@ -0,0 +1,17 @@
+package sensor_observation
+
+type sqliteReaderImpl struct {
+	standardReaderImpl
+}
+
+type sqliteWriterImpl struct {
+	standardWriterImpl
+}
+
+func newSqliteReader() Reader {
+	return &sqliteReaderImpl{standardReaderImpl{}}
+}
+
+func newSqliteWriter() Writer {
+	return &sqliteWriterImpl{standardWriterImpl{}}
+}
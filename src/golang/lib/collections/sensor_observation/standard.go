@@ -0,0 +1,85 @@
+package sensor_observation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/database/stmt_preparers"
+	"github.com/google/uuid"
+)
+
+type standardReaderImpl struct{}
+
+type standardWriterImpl struct{}
+
+func (w *standardWriterImpl) RecordObservation(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	observation string,
+	version string,
+	db database.Database,
+) (*SensorObservation, error) {
+	recordObservationStmt := fmt.Sprintf(
+		`INSERT INTO sensor_observation (%s) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (workflow_id, observation)
+		DO UPDATE SET version = excluded.version, created_at = excluded.created_at
+		RETURNING %s;`,
+		allColumns(),
+		allColumns(),
+	)
+
+	args := []interface{}{workflowId, observation, version, time.Now()}
+
+	var sensorObservation SensorObservation
+	err := db.Query(ctx, &sensorObservation, recordObservationStmt, args...)
+	return &sensorObservation, err
+}
+
+func (r *standardReaderImpl) GetObservationsByWorkflowId(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	db database.Database,
+) ([]SensorObservation, error) {
+	getObservationsQuery := fmt.Sprintf(
+		"SELECT %s FROM sensor_observation WHERE workflow_id = $1;",
+		allColumns(),
+	)
+	var sensorObservations []SensorObservation
+
+	err := db.Query(ctx, &sensorObservations, getObservationsQuery, workflowId)
+	return sensorObservations, err
+}
+
+func (w *standardWriterImpl) DeleteObservations(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	observations []string,
+	db database.Database,
+) error {
+	if len(observations) == 0 {
+		return nil
+	}
+
+	deleteObservationsStmt := fmt.Sprintf(
+		"DELETE FROM sensor_observation WHERE workflow_id = $1 AND observation IN (%s);",
+		stmt_preparers.GenerateArgsList(len(observations), 2),
+	)
+
+	args := make([]interface{}, 0, len(observations)+1)
+	args = append(args, workflowId)
+	for _, observation := range observations {
+		args = append(args, observation)
+	}
+	return db.Execute(ctx, deleteObservationsStmt, args...)
+}
+
+func (w *standardWriterImpl) DeleteObservationsByWorkflowId(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	db database.Database,
+) error {
+	deleteObservationsStmt := `DELETE FROM sensor_observation WHERE workflow_id = $1;`
+	return db.Execute(ctx, deleteObservationsStmt, workflowId)
+}
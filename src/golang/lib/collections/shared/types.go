@@ -1,6 +1,9 @@
 package shared
 
 import (
+	"database/sql/driver"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/utils"
 	"github.com/dropbox/godropbox/errors"
 )
 
@@ -12,5 +15,30 @@ const (
 	SucceededExecutionStatus ExecutionStatus = "succeeded"
 	FailedExecutionStatus    ExecutionStatus = "failed"
 	PendingExecutionStatus   ExecutionStatus = "pending"
+	QueuedExecutionStatus    ExecutionStatus = "queued"
+	CanceledExecutionStatus  ExecutionStatus = "canceled"
 	UnknownExecutionStatus   ExecutionStatus = "unknown"
+	// An operator is skipped when it does not run: one of its upstream operators failed, its run
+	// condition was not met, it was not selected in a partial refresh, or it is a fan-out element
+	// that never launched. A workflow run is skipped when it overlaps with an earlier run and the
+	// workflow's overlap policy is to skip it.
+	SkippedExecutionStatus ExecutionStatus = "skipped"
+	// A workflow run partially succeeds when some of its operators failed, but
+	// the operators that do not depend on them were run.
+	PartialSuccessExecutionStatus ExecutionStatus = "partial_success"
+	// An approval operator, and the run it is part of, await approval until one of the
+	// operator's approvers approves or rejects the run.
+	AwaitingApprovalExecutionStatus ExecutionStatus = "awaiting_approval"
 )
+
+// Parameters maps the names of param operators to the serialized values they take in a single
+// workflow run, instead of the values they were registered with.
+type Parameters map[string]string
+
+func (p *Parameters) Value() (driver.Value, error) {
+	return utils.ValueJsonB(*p)
+}
+
+func (p *Parameters) Scan(value interface{}) error {
+	return utils.ScanJsonB(value, p)
+}
@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/job_record"
+	"github.com/aqueducthq/aqueduct/lib/collections/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateJobRecord(t *testing.T) {
+	defer resetDatabase(t)
+
+	workflowId := uuid.New()
+	expectedJobRecord := &job_record.JobRecord{
+		Name:       "workflow-adhoc-job",
+		Pid:        1234,
+		SpecType:   "workflow",
+		WorkflowId: utils.NullUUID{UUID: workflowId},
+		LogPath:    "/tmp/workflow-adhoc-job.log",
+		StartTime:  time.Now().UTC().Truncate(time.Second),
+
+		WorkflowDagResultId: utils.NullUUID{IsNull: true},
+	}
+
+	actualJobRecord, err := writers.jobRecordWriter.CreateJobRecord(
+		context.Background(),
+		expectedJobRecord.Name,
+		expectedJobRecord.Pid,
+		expectedJobRecord.SpecType,
+		&workflowId,
+		expectedJobRecord.LogPath,
+		expectedJobRecord.StartTime,
+		db,
+	)
+	require.Nil(t, err)
+	require.True(t, expectedJobRecord.StartTime.Equal(actualJobRecord.StartTime))
+
+	expectedJobRecord.StartTime = actualJobRecord.StartTime
+	requireDeepEqual(t, expectedJobRecord, actualJobRecord)
+}
+
+func TestGetAndDeleteJobRecords(t *testing.T) {
+	defer resetDatabase(t)
+
+	names := []string{"function-operator-a", "function-operator-b"}
+	for i, name := range names {
+		_, err := writers.jobRecordWriter.CreateJobRecord(
+			context.Background(),
+			name,
+			i+1,
+			"function",
+			nil,
+			"",
+			time.Now(),
+			db,
+		)
+		require.Nil(t, err)
+	}
+
+	jobRecords, err := readers.jobRecordReader.GetJobRecords(context.Background(), db)
+	require.Nil(t, err)
+	require.Len(t, jobRecords, len(names))
+	for _, jobRecord := range jobRecords {
+		require.True(t, jobRecord.WorkflowId.IsNull)
+	}
+
+	err = writers.jobRecordWriter.DeleteJobRecord(context.Background(), names[0], db)
+	require.Nil(t, err)
+
+	jobRecords, err = readers.jobRecordReader.GetJobRecords(context.Background(), db)
+	require.Nil(t, err)
+	require.Len(t, jobRecords, 1)
+	require.Equal(t, names[1], jobRecords[0].Name)
+}
+
+func TestRecordWorkflowDagResult(t *testing.T) {
+	defer resetDatabase(t)
+
+	workflowId := uuid.New()
+	startTime := time.Now().UTC().Truncate(time.Second)
+	workflowDagResultIds := []uuid.UUID{uuid.New(), uuid.New()}
+
+	// The executor records its run after the job manager recorded the job.
+	_, err := writers.jobRecordWriter.CreateJobRecord(
+		context.Background(),
+		"workflow-a",
+		1,
+		"workflow",
+		&workflowId,
+		"/tmp/workflow-a.log",
+		startTime,
+		db,
+	)
+	require.Nil(t, err)
+
+	err = writers.jobRecordWriter.RecordWorkflowDagResult(
+		context.Background(),
+		"workflow-a",
+		1,
+		"workflow",
+		workflowId,
+		workflowDagResultIds[0],
+		startTime,
+		db,
+	)
+	require.Nil(t, err)
+
+	// The executor records its run before the job manager recorded the job.
+	err = writers.jobRecordWriter.RecordWorkflowDagResult(
+		context.Background(),
+		"workflow-b",
+		2,
+		"workflow",
+		workflowId,
+		workflowDagResultIds[1],
+		startTime,
+		db,
+	)
+	require.Nil(t, err)
+
+	jobRecord, err := writers.jobRecordWriter.CreateJobRecord(
+		context.Background(),
+		"workflow-b",
+		2,
+		"workflow",
+		&workflowId,
+		"/tmp/workflow-b.log",
+		startTime,
+		db,
+	)
+	require.Nil(t, err)
+	require.Equal(t, "/tmp/workflow-b.log", jobRecord.LogPath)
+	require.Equal(t, utils.NullUUID{UUID: workflowDagResultIds[1]}, jobRecord.WorkflowDagResultId)
+
+	jobRecords, err := readers.jobRecordReader.GetJobRecords(context.Background(), db)
+	require.Nil(t, err)
+	require.Len(t, jobRecords, 2)
+	for _, jobRecord := range jobRecords {
+		expectedId := workflowDagResultIds[0]
+		if jobRecord.Name == "workflow-b" {
+			expectedId = workflowDagResultIds[1]
+		}
+
+		require.Equal(t, utils.NullUUID{UUID: expectedId}, jobRecord.WorkflowDagResultId)
+		require.Equal(t, fmt.Sprintf("/tmp/%s.log", jobRecord.Name), jobRecord.LogPath)
+	}
+}
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestJobSlot(t *testing.T, name string, workflowId *uuid.UUID, priority int) {
+	_, err := writers.jobSlotWriter.CreateJobSlot(
+		context.Background(),
+		name,
+		workflowId,
+		priority,
+		1,
+		time.Now(),
+		db,
+	)
+	require.Nil(t, err)
+}
+
+func requireAcquireJobSlot(t *testing.T, name string, maxSlots int, maxSlotsPerWorkflow int, expected bool) {
+	acquired, err := writers.jobSlotWriter.AcquireJobSlot(
+		context.Background(),
+		name,
+		maxSlots,
+		maxSlotsPerWorkflow,
+		db,
+	)
+	require.Nil(t, err)
+	require.Equal(t, expected, acquired)
+}
+
+func TestAcquireJobSlot(t *testing.T) {
+	defer resetDatabase(t)
+
+	createTestJobSlot(t, "function-operator-a", nil, 1)
+	createTestJobSlot(t, "function-operator-b", nil, 1)
+
+	requireAcquireJobSlot(t, "function-operator-a", 1, 0, true)
+	// A slot is only acquired once.
+	requireAcquireJobSlot(t, "function-operator-a", 1, 0, false)
+	requireAcquireJobSlot(t, "function-operator-b", 1, 0, false)
+
+	err := writers.jobSlotWriter.DeleteJobSlot(context.Background(), "function-operator-a", db)
+	require.Nil(t, err)
+
+	requireAcquireJobSlot(t, "function-operator-b", 1, 0, true)
+}
+
+func TestAcquireJobSlotPriority(t *testing.T) {
+	defer resetDatabase(t)
+
+	createTestJobSlot(t, "backfill", nil, 0)
+	createTestJobSlot(t, "scheduled-1", nil, 1)
+	createTestJobSlot(t, "preview", nil, 2)
+	createTestJobSlot(t, "scheduled-2", nil, 1)
+
+	// Each job only acquires a slot once the jobs ahead of it have.
+	for _, name := range []string{"backfill", "scheduled-1", "scheduled-2"} {
+		requireAcquireJobSlot(t, name, 0, 0, false)
+	}
+
+	for _, name := range []string{"preview", "scheduled-1", "scheduled-2", "backfill"} {
+		requireAcquireJobSlot(t, name, 0, 0, true)
+	}
+}
+
+func TestAcquireJobSlotPerWorkflow(t *testing.T) {
+	defer resetDatabase(t)
+
+	workflowId := uuid.New()
+	otherWorkflowId := uuid.New()
+
+	createTestJobSlot(t, "workflow-running", &workflowId, 1)
+	createTestJobSlot(t, "workflow-queued", &workflowId, 1)
+	createTestJobSlot(t, "other-workflow", &otherWorkflowId, 1)
+
+	requireAcquireJobSlot(t, "workflow-running", 0, 1, true)
+	requireAcquireJobSlot(t, "workflow-queued", 0, 1, false)
+	// The job that waits for its workflow's limit does not block the other workflow.
+	requireAcquireJobSlot(t, "other-workflow", 0, 1, true)
+}
+
+func TestUpdateJobSlotOwner(t *testing.T) {
+	defer resetDatabase(t)
+
+	createTestJobSlot(t, "function-operator", nil, 1)
+	requireAcquireJobSlot(t, "function-operator", 1, 0, true)
+
+	startTime := time.Now().UTC().Truncate(time.Second)
+	err := writers.jobSlotWriter.UpdateJobSlotOwner(context.Background(), "function-operator", 1234, startTime, db)
+	require.Nil(t, err)
+
+	jobSlots, err := readers.jobSlotReader.GetJobSlots(context.Background(), db)
+	require.Nil(t, err)
+	require.Len(t, jobSlots, 1)
+	require.True(t, jobSlots[0].Acquired)
+	require.True(t, jobSlots[0].WorkflowId.IsNull)
+	require.Equal(t, 1234, jobSlots[0].Pid)
+	require.True(t, startTime.Equal(jobSlots[0].StartTime))
+}
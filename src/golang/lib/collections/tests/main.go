@@ -5,10 +5,15 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/collections/artifact"
 	"github.com/aqueducthq/aqueduct/lib/collections/artifact_result"
 	"github.com/aqueducthq/aqueduct/lib/collections/integration"
+	"github.com/aqueducthq/aqueduct/lib/collections/job_record"
+	"github.com/aqueducthq/aqueduct/lib/collections/job_slot"
 	"github.com/aqueducthq/aqueduct/lib/collections/notification"
 	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_cache"
 	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/run_request"
 	"github.com/aqueducthq/aqueduct/lib/collections/schema_version"
+	"github.com/aqueducthq/aqueduct/lib/collections/sensor_observation"
 	"github.com/aqueducthq/aqueduct/lib/collections/user"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
@@ -28,10 +33,15 @@ type dbReaders struct {
 	artifactReader          artifact.Reader
 	artifactResultReader    artifact_result.Reader
 	integrationReader       integration.Reader
+	jobRecordReader         job_record.Reader
+	jobSlotReader           job_slot.Reader
 	notificationReader      notification.Reader
 	operatorReader          operator.Reader
+	operatorCacheReader     operator_cache.Reader
 	operatorResultReader    operator_result.Reader
 	schemaVersionReader     schema_version.Reader
+	sensorObservationReader sensor_observation.Reader
+	runRequestReader        run_request.Reader
 	userReader              user.Reader
 	workflowReader          workflow.Reader
 	workflowDagReader       workflow_dag.Reader
@@ -45,10 +55,15 @@ type dbWriters struct {
 	artifactWriter          artifact.Writer
 	artifactResultWriter    artifact_result.Writer
 	integrationWriter       integration.Writer
+	jobRecordWriter         job_record.Writer
+	jobSlotWriter           job_slot.Writer
 	notificationWriter      notification.Writer
 	operatorWriter          operator.Writer
+	operatorCacheWriter     operator_cache.Writer
 	operatorResultWriter    operator_result.Writer
 	schemaVersionWriter     schema_version.Writer
+	sensorObservationWriter sensor_observation.Writer
+	runRequestWriter        run_request.Writer
 	userWriter              user.Writer
 	workflowWriter          workflow.Writer
 	workflowDagWriter       workflow_dag.Writer
@@ -68,6 +83,16 @@ func createReaders(dbConfig *database.DatabaseConfig) (*dbReaders, error) {
 		return nil, err
 	}
 
+	jobRecordReader, err := job_record.NewReader(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	jobSlotReader, err := job_slot.NewReader(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	notificationReader, err := notification.NewReader(dbConfig)
 	if err != nil {
 		return nil, err
@@ -88,6 +113,21 @@ func createReaders(dbConfig *database.DatabaseConfig) (*dbReaders, error) {
 		return nil, err
 	}
 
+	operatorCacheReader, err := operator_cache.NewReader(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	sensorObservationReader, err := sensor_observation.NewReader(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	runRequestReader, err := run_request.NewReader(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	operatorResultReader, err := operator_result.NewReader(dbConfig)
 	if err != nil {
 		return nil, err
@@ -131,10 +171,13 @@ func createReaders(dbConfig *database.DatabaseConfig) (*dbReaders, error) {
 	return &dbReaders{
 		userReader:              userReader,
 		integrationReader:       integrationReader,
+		jobRecordReader:         jobRecordReader,
+		jobSlotReader:           jobSlotReader,
 		notificationReader:      notificationReader,
 		artifactReader:          artifactReader,
 		artifactResultReader:    artifactResultReader,
 		operatorReader:          operatorReader,
+		operatorCacheReader:     operatorCacheReader,
 		operatorResultReader:    operatorResultReader,
 		workflowReader:          workflowReader,
 		workflowDagReader:       workflowDagReader,
@@ -142,6 +185,8 @@ func createReaders(dbConfig *database.DatabaseConfig) (*dbReaders, error) {
 		workflowWatcherReader:   workflowWatcherReader,
 		workflowDagResultReader: workflowDagResultReader,
 		schemaVersionReader:     schemaVersionReader,
+		sensorObservationReader: sensorObservationReader,
+		runRequestReader:        runRequestReader,
 		serverReader:            queriesReader,
 	}, nil
 }
@@ -157,6 +202,16 @@ func createWriters(dbConfig *database.DatabaseConfig) (*dbWriters, error) {
 		return nil, err
 	}
 
+	jobRecordWriter, err := job_record.NewWriter(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	jobSlotWriter, err := job_slot.NewWriter(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	notificationWriter, err := notification.NewWriter(dbConfig)
 	if err != nil {
 		return nil, err
@@ -177,6 +232,21 @@ func createWriters(dbConfig *database.DatabaseConfig) (*dbWriters, error) {
 		return nil, err
 	}
 
+	operatorCacheWriter, err := operator_cache.NewWriter(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	sensorObservationWriter, err := sensor_observation.NewWriter(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	runRequestWriter, err := run_request.NewWriter(dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	operatorResultWriter, err := operator_result.NewWriter(dbConfig)
 	if err != nil {
 		return nil, err
@@ -215,10 +285,13 @@ func createWriters(dbConfig *database.DatabaseConfig) (*dbWriters, error) {
 	return &dbWriters{
 		userWriter:              userWriter,
 		integrationWriter:       integrationWriter,
+		jobRecordWriter:         jobRecordWriter,
+		jobSlotWriter:           jobSlotWriter,
 		notificationWriter:      notificationWriter,
 		artifactWriter:          artifactWriter,
 		artifactResultWriter:    artifactResultWriter,
 		operatorWriter:          operatorWriter,
+		operatorCacheWriter:     operatorCacheWriter,
 		operatorResultWriter:    operatorResultWriter,
 		workflowWriter:          workflowWriter,
 		workflowDagWriter:       workflowDagWriter,
@@ -226,5 +299,7 @@ func createWriters(dbConfig *database.DatabaseConfig) (*dbWriters, error) {
 		workflowWatcherWriter:   workflowWatcherWriter,
 		workflowDagResultWriter: workflowDagResultWriter,
 		schemaVersionWriter:     schemaVersionWriter,
+		sensorObservationWriter: sensorObservationWriter,
+		runRequestWriter:        runRequestWriter,
 	}, nil
 }
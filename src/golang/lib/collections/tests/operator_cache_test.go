@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_cache"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAndGetCacheEntry(t *testing.T) {
+	defer resetDatabase(t)
+
+	workflows := seedWorkflow(t, 1)
+	operatorResultId := uuid.New()
+	outputs := operator_cache.CachedOutputs{
+		{
+			ContentPath: "artifact-content",
+			Metadata:    artifact_result.Metadata{{"col": "int64"}},
+		},
+	}
+
+	createdEntry, err := writers.operatorCacheWriter.CreateCacheEntry(
+		context.Background(),
+		"cache-key",
+		workflows[0].Id,
+		operatorResultId,
+		&outputs,
+		db,
+	)
+	require.Nil(t, err)
+	require.Equal(t, "cache-key", createdEntry.CacheKey)
+	require.Equal(t, workflows[0].Id, createdEntry.WorkflowId)
+	require.Equal(t, operatorResultId, createdEntry.OperatorResultId)
+
+	entry, err := readers.operatorCacheReader.GetCacheEntry(context.Background(), "cache-key", db)
+	require.Nil(t, err)
+	require.Equal(t, outputs, entry.Outputs)
+
+	_, err = readers.operatorCacheReader.GetCacheEntry(context.Background(), "missing-key", db)
+	require.Equal(t, database.ErrNoRows, err)
+}
+
+func TestDeleteCacheEntries(t *testing.T) {
+	defer resetDatabase(t)
+
+	workflows := seedWorkflow(t, 2)
+	outputs := operator_cache.CachedOutputs{}
+	for i, key := range []string{"key-a", "key-b", "key-c"} {
+		_, err := writers.operatorCacheWriter.CreateCacheEntry(
+			context.Background(),
+			key,
+			workflows[i%2].Id,
+			uuid.New(),
+			&outputs,
+			db,
+		)
+		require.Nil(t, err)
+	}
+
+	err := writers.operatorCacheWriter.DeleteCacheEntry(context.Background(), "key-b", db)
+	require.Nil(t, err)
+
+	_, err = readers.operatorCacheReader.GetCacheEntry(context.Background(), "key-b", db)
+	require.Equal(t, database.ErrNoRows, err)
+
+	err = writers.operatorCacheWriter.DeleteCacheEntriesByWorkflowId(context.Background(), workflows[0].Id, db)
+	require.Nil(t, err)
+
+	for _, key := range []string{"key-a", "key-c"} {
+		_, err = readers.operatorCacheReader.GetCacheEntry(context.Background(), key, db)
+		require.Equal(t, database.ErrNoRows, err)
+	}
+}
@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateOperatorResultWithStatus(t *testing.T) {
+	defer resetDatabase(t)
+
+	dags := seedWorkflowDag(t, 1)
+	dagResults := seedWorkflowDagResultWithDags(t, 1, []uuid.UUID{dags[0].Id})
+	operators := seedOperator(t, 1)
+
+	operatorResult, err := writers.operatorResultWriter.CreateOperatorResult(
+		context.Background(),
+		dagResults[0].Id,
+		operators[0].Id,
+		db,
+	)
+	require.Nil(t, err)
+
+	// The operator result is not updated while it does not have the expected status.
+	_, err = writers.operatorResultWriter.UpdateOperatorResultWithStatus(
+		context.Background(),
+		operatorResult.Id,
+		shared.AwaitingApprovalExecutionStatus,
+		map[string]interface{}{operator_result.StatusColumn: shared.SucceededExecutionStatus},
+		db,
+	)
+	require.Equal(t, database.ErrNoRows, err)
+
+	_, err = writers.operatorResultWriter.UpdateOperatorResult(
+		context.Background(),
+		operatorResult.Id,
+		map[string]interface{}{operator_result.StatusColumn: shared.AwaitingApprovalExecutionStatus},
+		db,
+	)
+	require.Nil(t, err)
+
+	updated, err := writers.operatorResultWriter.UpdateOperatorResultWithStatus(
+		context.Background(),
+		operatorResult.Id,
+		shared.AwaitingApprovalExecutionStatus,
+		map[string]interface{}{
+			operator_result.StatusColumn:   shared.SucceededExecutionStatus,
+			operator_result.MetadataColumn: &operator_result.Metadata{Logs: map[string]string{"stdout": "Approved."}},
+		},
+		db,
+	)
+	require.Nil(t, err)
+	require.Equal(t, shared.SucceededExecutionStatus, updated.Status)
+	require.Equal(t, "Approved.", updated.Metadata.Metadata.Logs["stdout"])
+
+	// Only one of two concurrent decisions is recorded.
+	_, err = writers.operatorResultWriter.UpdateOperatorResultWithStatus(
+		context.Background(),
+		operatorResult.Id,
+		shared.AwaitingApprovalExecutionStatus,
+		map[string]interface{}{operator_result.StatusColumn: shared.FailedExecutionStatus},
+		db,
+	)
+	require.Equal(t, database.ErrNoRows, err)
+
+	actual, err := readers.operatorResultReader.GetOperatorResult(context.Background(), operatorResult.Id, db)
+	require.Nil(t, err)
+	require.Equal(t, shared.SucceededExecutionStatus, actual.Status)
+}
@@ -7,13 +7,18 @@ import (
 
 // resetDatabase wipes all rows from the database
 func resetDatabase(t *testing.T) {
+	resetRunRequest(t)
 	resetWorkflowDagResult(t)
 	resetWorkflowDagEdge(t)
 	resetOperator(t)
 	resetWorkflowDag(t)
+	resetOperatorCache(t)
+	resetSensorObservation(t)
 	resetWorkflow(t)
 	resetIntegration(t)
 	resetUser(t)
+	resetJobRecord(t)
+	resetJobSlot(t)
 }
 
 func resetUser(t *testing.T) {
@@ -64,3 +69,38 @@ func resetWorkflowDagResult(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func resetJobRecord(t *testing.T) {
+	if err := db.Execute(context.Background(), "DELETE FROM job_record;"); err != nil {
+		t.Errorf("Unable to reset job_record table: %v", err)
+		t.FailNow()
+	}
+}
+
+func resetJobSlot(t *testing.T) {
+	if err := db.Execute(context.Background(), "DELETE FROM job_slot;"); err != nil {
+		t.Errorf("Unable to reset job_slot table: %v", err)
+		t.FailNow()
+	}
+}
+
+func resetRunRequest(t *testing.T) {
+	if err := db.Execute(context.Background(), "DELETE FROM run_request;"); err != nil {
+		t.Errorf("Unable to reset run_request table: %v", err)
+		t.FailNow()
+	}
+}
+
+func resetSensorObservation(t *testing.T) {
+	if err := db.Execute(context.Background(), "DELETE FROM sensor_observation;"); err != nil {
+		t.Errorf("Unable to reset sensor_observation table: %v", err)
+		t.FailNow()
+	}
+}
+
+func resetOperatorCache(t *testing.T) {
+	if err := db.Execute(context.Background(), "DELETE FROM operator_cache;"); err != nil {
+		t.Errorf("Unable to reset operator_cache table: %v", err)
+		t.FailNow()
+	}
+}
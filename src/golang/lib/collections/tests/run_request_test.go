@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAndClaimRunRequests(t *testing.T) {
+	defer resetDatabase(t)
+
+	dags := seedWorkflowDag(t, 2)
+	runs := seedWorkflowDagResultWithDags(t, 2, []uuid.UUID{dags[0].Id, dags[1].Id})
+
+	parent := &job.ParentOperator{
+		WorkflowDagResultId: uuid.New(),
+		StorageConfig:       shared.StorageConfig{Type: shared.FileStorageType},
+		MetadataPath:        "metadata",
+		OutputArtifactNames: []string{"report"},
+		OutputContentPaths:  []string{"output"},
+		OutputMetadataPaths: []string{"output-metadata"},
+	}
+	_, err := writers.runRequestWriter.CreateRunRequest(context.Background(), runs[0].Id, dags[0].WorkflowId, nil, db)
+	require.Nil(t, err)
+	_, err = writers.runRequestWriter.CreateRunRequest(context.Background(), runs[1].Id, dags[1].WorkflowId, parent, db)
+	require.Nil(t, err)
+
+	// The requests are returned in the order they were made.
+	runRequests, err := readers.runRequestReader.GetRunRequests(context.Background(), db)
+	require.Nil(t, err)
+	require.Len(t, runRequests, 2)
+	require.Equal(t, runs[0].Id, runRequests[0].WorkflowDagResultId)
+	require.True(t, runRequests[0].Parent.IsNull)
+	require.Equal(t, runs[1].Id, runRequests[1].WorkflowDagResultId)
+	require.False(t, runRequests[1].Parent.IsNull)
+	require.Equal(t, *parent, runRequests[1].Parent.ParentOperator)
+
+	require.True(t, runRequests[0].BackfillId.IsNull)
+	require.True(t, runRequests[0].LaunchedAt.IsNull)
+
+	// A request can only be claimed once.
+	claimed, err := writers.runRequestWriter.ClaimRunRequest(context.Background(), runs[0].Id, db)
+	require.Nil(t, err)
+	require.True(t, claimed)
+
+	claimed, err = writers.runRequestWriter.ClaimRunRequest(context.Background(), runs[0].Id, db)
+	require.Nil(t, err)
+	require.False(t, claimed)
+
+	// The request is kept until it is deleted once its run finishes.
+	runRequests, err = readers.runRequestReader.GetRunRequests(context.Background(), db)
+	require.Nil(t, err)
+	require.Len(t, runRequests, 2)
+	require.False(t, runRequests[0].LaunchedAt.IsNull)
+	require.True(t, runRequests[1].LaunchedAt.IsNull)
+
+	deleted, err := writers.runRequestWriter.DeleteRunRequest(context.Background(), runs[0].Id, db)
+	require.Nil(t, err)
+	require.True(t, deleted)
+
+	deleted, err = writers.runRequestWriter.DeleteRunRequest(context.Background(), runs[0].Id, db)
+	require.Nil(t, err)
+	require.False(t, deleted)
+
+	err = writers.runRequestWriter.DeleteRunRequestsByWorkflowId(context.Background(), dags[1].WorkflowId, db)
+	require.Nil(t, err)
+
+	runRequests, err = readers.runRequestReader.GetRunRequests(context.Background(), db)
+	require.Nil(t, err)
+	require.Len(t, runRequests, 0)
+}
+
+func TestCreateBackfillRunRequests(t *testing.T) {
+	defer resetDatabase(t)
+
+	dags := seedWorkflowDag(t, 1)
+	runs := seedWorkflowDagResultWithDags(t, 2, []uuid.UUID{dags[0].Id, dags[0].Id})
+
+	backfillId := uuid.New()
+	for _, run := range runs {
+		_, err := writers.runRequestWriter.CreateBackfillRunRequest(
+			context.Background(),
+			run.Id,
+			dags[0].WorkflowId,
+			backfillId,
+			2, /* concurrency */
+			db,
+		)
+		require.Nil(t, err)
+	}
+
+	runRequests, err := readers.runRequestReader.GetRunRequests(context.Background(), db)
+	require.Nil(t, err)
+	require.Len(t, runRequests, 2)
+	for i, runRequest := range runRequests {
+		require.Equal(t, runs[i].Id, runRequest.WorkflowDagResultId)
+		require.True(t, runRequest.Parent.IsNull)
+		require.Equal(t, backfillId, runRequest.BackfillId.UUID)
+		require.False(t, runRequest.BackfillId.IsNull)
+		require.Equal(t, 2, runRequest.Concurrency)
+		require.True(t, runRequest.LaunchedAt.IsNull)
+	}
+}
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndGetObservations(t *testing.T) {
+	defer resetDatabase(t)
+
+	workflows := seedWorkflow(t, 2)
+	for i, observation := range []string{"drop/a.csv", "drop/b.csv", "drop/a.csv"} {
+		recordedObservation, err := writers.sensorObservationWriter.RecordObservation(
+			context.Background(),
+			workflows[i/2].Id,
+			observation,
+			"v1",
+			db,
+		)
+		require.Nil(t, err)
+		require.Equal(t, workflows[i/2].Id, recordedObservation.WorkflowId)
+		require.Equal(t, observation, recordedObservation.Observation)
+		require.Equal(t, "v1", recordedObservation.Version)
+	}
+
+	// A workflow has one observation per path, at the version recorded last.
+	_, err := writers.sensorObservationWriter.RecordObservation(
+		context.Background(),
+		workflows[0].Id,
+		"drop/a.csv",
+		"v2",
+		db,
+	)
+	require.Nil(t, err)
+
+	observations, err := readers.sensorObservationReader.GetObservationsByWorkflowId(
+		context.Background(),
+		workflows[0].Id,
+		db,
+	)
+	require.Nil(t, err)
+
+	versions := make(map[string]string, len(observations))
+	for _, observation := range observations {
+		versions[observation.Observation] = observation.Version
+	}
+	require.Equal(t, map[string]string{"drop/a.csv": "v2", "drop/b.csv": "v1"}, versions)
+}
+
+func TestDeleteObservations(t *testing.T) {
+	defer resetDatabase(t)
+
+	workflows := seedWorkflow(t, 2)
+	for _, wf := range workflows {
+		for _, observation := range []string{"drop/a.csv", "drop/b.csv", "drop/c.csv"} {
+			_, err := writers.sensorObservationWriter.RecordObservation(context.Background(), wf.Id, observation, "v1", db)
+			require.Nil(t, err)
+		}
+	}
+
+	err := writers.sensorObservationWriter.DeleteObservations(
+		context.Background(),
+		workflows[0].Id,
+		[]string{"drop/a.csv", "drop/c.csv"},
+		db,
+	)
+	require.Nil(t, err)
+
+	observations, err := readers.sensorObservationReader.GetObservationsByWorkflowId(context.Background(), workflows[0].Id, db)
+	require.Nil(t, err)
+	require.Len(t, observations, 1)
+	require.Equal(t, "drop/b.csv", observations[0].Observation)
+
+	observations, err = readers.sensorObservationReader.GetObservationsByWorkflowId(context.Background(), workflows[1].Id, db)
+	require.Nil(t, err)
+	require.Len(t, observations, 3)
+}
+
+func TestDeleteObservationsByWorkflowId(t *testing.T) {
+	defer resetDatabase(t)
+
+	workflows := seedWorkflow(t, 2)
+	for _, wf := range workflows {
+		_, err := writers.sensorObservationWriter.RecordObservation(context.Background(), wf.Id, "drop/a.csv", "v1", db)
+		require.Nil(t, err)
+	}
+	err := writers.sensorObservationWriter.DeleteObservationsByWorkflowId(context.Background(), workflows[0].Id, db)
+	require.Nil(t, err)
+
+	observations, err := readers.sensorObservationReader.GetObservationsByWorkflowId(context.Background(), workflows[0].Id, db)
+	require.Nil(t, err)
+	require.Len(t, observations, 0)
+
+	observations, err = readers.sensorObservationReader.GetObservationsByWorkflowId(context.Background(), workflows[1].Id, db)
+	require.Nil(t, err)
+	require.Len(t, observations, 1)
+}
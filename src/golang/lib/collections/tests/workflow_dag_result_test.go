@@ -5,7 +5,9 @@ import (
 	"testing"
 
 	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/utils"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
@@ -21,6 +23,8 @@ func seedWorkflowDagResultWithDags(t *testing.T, count int, workflowDagIds []uui
 		testDagResult, err := writers.workflowDagResultWriter.CreateWorkflowDagResult(
 			context.Background(),
 			workflowDagIds[i],
+			nil, /* resumedFrom */
+			nil, /* parameters */
 			db,
 		)
 		require.Nil(t, err)
@@ -41,11 +45,16 @@ func TestCreateWorkflowDagResult(t *testing.T) {
 	expectedDagResult := &workflow_dag_result.WorkflowDagResult{
 		WorkflowDagId: dags[0].Id,
 		Status:        shared.PendingExecutionStatus,
+		ResumedFrom:   utils.NullUUID{IsNull: true},
+		Parameters:    shared.Parameters{},
+		ParentId:      utils.NullUUID{IsNull: true},
 	}
 
 	actualDagResult, err := writers.workflowDagResultWriter.CreateWorkflowDagResult(
 		context.Background(),
 		expectedDagResult.WorkflowDagId,
+		nil, /* resumedFrom */
+		nil, /* parameters */
 		db,
 	)
 	require.Nil(t, err)
@@ -57,6 +66,136 @@ func TestCreateWorkflowDagResult(t *testing.T) {
 	requireDeepEqual(t, expectedDagResult, actualDagResult)
 }
 
+func TestCreateResumedWorkflowDagResult(t *testing.T) {
+	defer resetDatabase(t)
+
+	dags := seedWorkflowDag(t, 1)
+	failedDagResults := seedWorkflowDagResultWithDags(t, 1, []uuid.UUID{dags[0].Id})
+
+	actualDagResult, err := writers.workflowDagResultWriter.CreateWorkflowDagResult(
+		context.Background(),
+		dags[0].Id,
+		&failedDagResults[0].Id,
+		nil, /* parameters */
+		db,
+	)
+	require.Nil(t, err)
+	require.False(t, actualDagResult.ResumedFrom.IsNull)
+	require.Equal(t, failedDagResults[0].Id, actualDagResult.ResumedFrom.UUID)
+
+	// Deleting the resumed run keeps the run that resumed it.
+	err = writers.workflowDagResultWriter.DeleteWorkflowDagResult(context.Background(), failedDagResults[0].Id, db)
+	require.Nil(t, err)
+
+	reloadedDagResult, err := readers.workflowDagResultReader.GetWorkflowDagResult(
+		context.Background(),
+		actualDagResult.Id,
+		db,
+	)
+	require.Nil(t, err)
+	require.True(t, reloadedDagResult.ResumedFrom.IsNull)
+}
+
+func TestChildWorkflowDagResult(t *testing.T) {
+	defer resetDatabase(t)
+
+	dags := seedWorkflowDag(t, 2)
+	dagResults := seedWorkflowDagResultWithDags(t, 2, []uuid.UUID{dags[0].Id, dags[1].Id})
+	parent, child := dagResults[0], dagResults[1]
+
+	linkedDagResult, err := writers.workflowDagResultWriter.UpdateWorkflowDagResult(
+		context.Background(),
+		child.Id,
+		map[string]interface{}{workflow_dag_result.ParentIdColumn: parent.Id},
+		readers.workflowReader,
+		writers.notificationWriter,
+		readers.userReader,
+		db,
+	)
+	require.Nil(t, err)
+	require.False(t, linkedDagResult.ParentId.IsNull)
+	require.Equal(t, parent.Id, linkedDagResult.ParentId.UUID)
+
+	// Deleting the parent run keeps the child run.
+	err = writers.workflowDagResultWriter.DeleteWorkflowDagResult(context.Background(), parent.Id, db)
+	require.Nil(t, err)
+
+	reloadedDagResult, err := readers.workflowDagResultReader.GetWorkflowDagResult(
+		context.Background(),
+		child.Id,
+		db,
+	)
+	require.Nil(t, err)
+	require.True(t, reloadedDagResult.ParentId.IsNull)
+}
+
+func TestCreateWorkflowDagResultWithParameters(t *testing.T) {
+	defer resetDatabase(t)
+
+	dags := seedWorkflowDag(t, 1)
+	parameters := shared.Parameters{"date": `"2022-06-01"`}
+
+	actualDagResult, err := writers.workflowDagResultWriter.CreateWorkflowDagResult(
+		context.Background(),
+		dags[0].Id,
+		nil, /* resumedFrom */
+		parameters,
+		db,
+	)
+	require.Nil(t, err)
+	require.Equal(t, parameters, actualDagResult.Parameters)
+
+	reloadedDagResult, err := readers.workflowDagResultReader.GetWorkflowDagResult(
+		context.Background(),
+		actualDagResult.Id,
+		db,
+	)
+	require.Nil(t, err)
+	require.Equal(t, parameters, reloadedDagResult.Parameters)
+}
+
+func TestUpdateWorkflowDagResultWithStatus(t *testing.T) {
+	defer resetDatabase(t)
+
+	dags := seedWorkflowDag(t, 1)
+	dagResults := seedWorkflowDagResultWithDags(t, 1, []uuid.UUID{dags[0].Id})
+
+	// The run is cancelled while it is pending.
+	canceled, err := writers.workflowDagResultWriter.UpdateWorkflowDagResultWithStatus(
+		context.Background(),
+		dagResults[0].Id,
+		workflow_dag_result.RunningStatuses,
+		map[string]interface{}{workflow_dag_result.StatusColumn: shared.CanceledExecutionStatus},
+		readers.workflowReader,
+		writers.notificationWriter,
+		readers.userReader,
+		db,
+	)
+	require.Nil(t, err)
+	require.Equal(t, shared.CanceledExecutionStatus, canceled.Status)
+
+	// The executor finishing the run afterwards does not overwrite the cancellation.
+	_, err = writers.workflowDagResultWriter.UpdateWorkflowDagResultWithStatus(
+		context.Background(),
+		dagResults[0].Id,
+		workflow_dag_result.RunningStatuses,
+		map[string]interface{}{workflow_dag_result.StatusColumn: shared.SucceededExecutionStatus},
+		readers.workflowReader,
+		writers.notificationWriter,
+		readers.userReader,
+		db,
+	)
+	require.Equal(t, database.ErrNoRows, err)
+
+	reloadedDagResult, err := readers.workflowDagResultReader.GetWorkflowDagResult(
+		context.Background(),
+		dagResults[0].Id,
+		db,
+	)
+	require.Nil(t, err)
+	require.Equal(t, shared.CanceledExecutionStatus, reloadedDagResult.Status)
+}
+
 func TestGetKOffsetWorkflowDagResultsByWorkflowId(t *testing.T) {
 	defer resetDatabase(t)
 
@@ -38,6 +38,9 @@ func seedWorkflowWithUsers(t *testing.T, count int, userIds []uuid.UUID) []workf
 		retentionPolicy := workflow.RetentionPolicy{
 			KLatestRuns: 10,
 		}
+		executionPolicy := workflow.ExecutionPolicy{
+			TimeoutSeconds: 600,
+		}
 
 		testWorkflow, err := writers.workflowWriter.CreateWorkflow(
 			context.Background(),
@@ -46,6 +49,7 @@ func seedWorkflowWithUsers(t *testing.T, count int, userIds []uuid.UUID) []workf
 			description,
 			&schedule,
 			&retentionPolicy,
+			&executionPolicy,
 			db,
 		)
 		require.Nil(t, err)
@@ -97,6 +101,7 @@ func TestCreateWorkflow(t *testing.T) {
 			DisableManualTrigger: false,
 		},
 		RetentionPolicy: workflow.RetentionPolicy{KLatestRuns: 10},
+		ExecutionPolicy: workflow.ExecutionPolicy{TimeoutSeconds: 600},
 	}
 	createdAtFloor := time.Now()
 
@@ -107,6 +112,7 @@ func TestCreateWorkflow(t *testing.T) {
 		expectedWorkflow.Description,
 		&expectedWorkflow.Schedule,
 		&expectedWorkflow.RetentionPolicy,
+		&expectedWorkflow.ExecutionPolicy,
 		db,
 	)
 	require.Nil(t, err)
@@ -3,8 +3,10 @@ package utils
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/database/stmt_preparers"
 	"github.com/dropbox/godropbox/errors"
 	"github.com/google/uuid"
 )
@@ -53,6 +55,35 @@ func UpdateRecordToDest(
 	return db.Query(ctx, dest, updateStmt, args...)
 }
 
+// UpdateRecordToDestIfIn is like UpdateRecordToDest, but only modifies the record if the value of
+// `conditionColumn` is one of `conditionColumnVals`. It returns database.ErrNoRows otherwise, so
+// that concurrent writers can tell whether their update was applied.
+func UpdateRecordToDestIfIn(
+	ctx context.Context,
+	dest interface{},
+	changedColumns map[string]interface{},
+	table string,
+	predicateColumn string,
+	predicateColumnVal interface{},
+	conditionColumn string,
+	conditionColumnVals []interface{},
+	allColumns string,
+	db database.Database,
+) error {
+	updateColumns, args := prepareUpdateRecord(changedColumns, predicateColumnVal)
+	updateStmt := strings.TrimSuffix(db.PrepareUpdateWhereStmt(table, updateColumns, predicateColumn), ";")
+	updateStmt = fmt.Sprintf(
+		"%s AND %s IN (%s) RETURNING %s;",
+		updateStmt,
+		conditionColumn,
+		stmt_preparers.GenerateArgsList(len(conditionColumnVals), len(args)+1),
+		allColumns,
+	)
+	args = append(args, conditionColumnVals...)
+
+	return db.Query(ctx, dest, updateStmt, args...)
+}
+
 // UpdateRecord execute an UPDATE statement to modify the specified columns in `table`.
 // For now, the function only performs UPDATE statements with a single WHERE clause for the column
 // `predicateColumn` that has value `predicateColumnVal`.
@@ -0,0 +1,65 @@
+package workflow
+
+import (
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+var ErrCascadeCycle = errors.New("The cascade trigger would make the workflow trigger itself.")
+
+// IsCascadeSource returns whether runs of the workflow with id `workflowId` trigger a workflow
+// with this schedule.
+func (s *Schedule) IsCascadeSource(workflowId uuid.UUID) bool {
+	if s.Trigger != CascadeUpdateTrigger {
+		return false
+	}
+
+	for _, id := range s.SourceWorkflowIds {
+		if id == workflowId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetCascadeCondition returns the schedule's cascade condition, or the default condition
+// if none is set.
+func (s *Schedule) GetCascadeCondition() CascadeCondition {
+	if s.CascadeCondition == "" {
+		return AllSucceededCascadeCondition
+	}
+
+	return s.CascadeCondition
+}
+
+// HasCascadeCycle returns whether the workflow with id `workflowId` would trigger itself if it
+// were triggered by `sourceWorkflowIds`, given the schedules of the existing `workflows`.
+func HasCascadeCycle(workflowId uuid.UUID, sourceWorkflowIds []uuid.UUID, workflows []Workflow) bool {
+	sources := make(map[uuid.UUID][]uuid.UUID, len(workflows))
+	for _, w := range workflows {
+		if w.Schedule.Trigger == CascadeUpdateTrigger {
+			sources[w.Id] = w.Schedule.SourceWorkflowIds
+		}
+	}
+
+	// Walk upstream from the new sources. There is a cycle if the walk reaches the workflow.
+	visited := map[uuid.UUID]bool{}
+	queue := append([]uuid.UUID{}, sourceWorkflowIds...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		if id == workflowId {
+			return true
+		}
+
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		queue = append(queue, sources[id]...)
+	}
+
+	return false
+}
@@ -0,0 +1,36 @@
+package workflow_test
+
+import (
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasCascadeCycle(t *testing.T) {
+	ingest, report, publish := uuid.New(), uuid.New(), uuid.New()
+
+	// The report workflow runs after the ingest workflow.
+	workflows := []workflow.Workflow{
+		{Id: ingest, Schedule: workflow.Schedule{Trigger: workflow.PeriodicUpdateTrigger}},
+		{Id: report, Schedule: workflow.Schedule{
+			Trigger:           workflow.CascadeUpdateTrigger,
+			SourceWorkflowIds: []uuid.UUID{ingest},
+		}},
+		{Id: publish, Schedule: workflow.Schedule{Trigger: workflow.ManualUpdateTrigger}},
+	}
+
+	require.False(t, workflow.HasCascadeCycle(publish, []uuid.UUID{report}, workflows))
+	require.False(t, workflow.HasCascadeCycle(uuid.Nil, []uuid.UUID{report}, workflows))
+	require.True(t, workflow.HasCascadeCycle(ingest, []uuid.UUID{report}, workflows))
+	require.True(t, workflow.HasCascadeCycle(report, []uuid.UUID{report}, workflows))
+
+	// Once the publish workflow runs after the report workflow, the ingest workflow cannot run
+	// after the publish workflow.
+	workflows[2].Schedule = workflow.Schedule{
+		Trigger:           workflow.CascadeUpdateTrigger,
+		SourceWorkflowIds: []uuid.UUID{report},
+	}
+	require.True(t, workflow.HasCascadeCycle(ingest, []uuid.UUID{publish}, workflows))
+}
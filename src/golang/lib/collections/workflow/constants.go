@@ -9,13 +9,14 @@ const (
 	tableName = "workflow"
 
 	// Workflow table column names
-	IdColumn          = "id"
-	UserIdColumn      = "user_id"
-	NameColumn        = "name"
-	DescriptionColumn = "description"
-	ScheduleColumn    = "schedule"
-	CreatedAtColumn   = "created_at"
-	RetentionColumn   = "retention_policy"
+	IdColumn              = "id"
+	UserIdColumn          = "user_id"
+	NameColumn            = "name"
+	DescriptionColumn     = "description"
+	ScheduleColumn        = "schedule"
+	CreatedAtColumn       = "created_at"
+	RetentionColumn       = "retention_policy"
+	ExecutionPolicyColumn = "execution_policy"
 )
 
 // Returns a joined string of all Workflow columns.
@@ -29,6 +30,7 @@ func allColumns() string {
 			ScheduleColumn,
 			CreatedAtColumn,
 			RetentionColumn,
+			ExecutionPolicyColumn,
 		},
 		",",
 	)
@@ -45,6 +47,7 @@ func allColumnsWithPrefix() string {
 			fmt.Sprintf("%s.%s", tableName, ScheduleColumn),
 			fmt.Sprintf("%s.%s", tableName, CreatedAtColumn),
 			fmt.Sprintf("%s.%s", tableName, RetentionColumn),
+			fmt.Sprintf("%s.%s", tableName, ExecutionPolicyColumn),
 		},
 		",",
 	)
@@ -35,6 +35,7 @@ func (w *noopWriterImpl) CreateWorkflow(
 	description string,
 	schedule *Schedule,
 	retentionPolicy *RetentionPolicy,
+	executionPolicy *ExecutionPolicy,
 	db database.Database,
 ) (*Workflow, error) {
 	return nil, utils.NoopInterfaceErrorHandling(w.throwError)
@@ -0,0 +1,20 @@
+package workflow
+
+// GetOverlapPolicy returns the schedule's overlap policy, or the default policy if none is set.
+func (s *Schedule) GetOverlapPolicy() OverlapPolicy {
+	if s.OverlapPolicy == "" {
+		return AllowOverlapPolicy
+	}
+
+	return s.OverlapPolicy
+}
+
+// IsValid returns whether the overlap policy is one of the supported policies.
+func (p OverlapPolicy) IsValid() bool {
+	switch p {
+	case AllowOverlapPolicy, SkipOverlapPolicy, QueueOverlapPolicy, CancelPreviousOverlapPolicy:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,39 @@
+package workflow_test
+
+import (
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOverlapPolicy(t *testing.T) {
+	require.Equal(t, workflow.AllowOverlapPolicy, (&workflow.Schedule{}).GetOverlapPolicy())
+	require.Equal(
+		t,
+		workflow.QueueOverlapPolicy,
+		(&workflow.Schedule{OverlapPolicy: workflow.QueueOverlapPolicy}).GetOverlapPolicy(),
+	)
+
+	require.True(t, workflow.CancelPreviousOverlapPolicy.IsValid())
+	require.False(t, workflow.OverlapPolicy("replace").IsValid())
+}
+
+func TestTimingSkipIfRunning(t *testing.T) {
+	for policy, skip := range map[workflow.OverlapPolicy]bool{
+		"":                                   false,
+		workflow.SkipOverlapPolicy:           true,
+		workflow.QueueOverlapPolicy:          false,
+		workflow.CancelPreviousOverlapPolicy: false,
+	} {
+		timing, err := (&workflow.Schedule{CronSchedule: "0 * * * *", OverlapPolicy: policy}).Timing()
+		require.Nil(t, err)
+		require.Equal(t, skip, timing.SkipIfRunning())
+	}
+}
+
+func TestFailurePolicyIsValid(t *testing.T) {
+	require.True(t, workflow.FailurePolicy("").IsValid())
+	require.True(t, workflow.ContinueFailurePolicy.IsValid())
+	require.False(t, workflow.FailurePolicy("retry").IsValid())
+}
@@ -32,9 +32,10 @@ func (w *sqliteWriterImpl) CreateWorkflow(
 	description string,
 	schedule *Schedule,
 	retentionPolicy *RetentionPolicy,
+	executionPolicy *ExecutionPolicy,
 	db database.Database,
 ) (*Workflow, error) {
-	insertColumns := []string{IdColumn, UserIdColumn, NameColumn, DescriptionColumn, ScheduleColumn, CreatedAtColumn, RetentionColumn}
+	insertColumns := []string{IdColumn, UserIdColumn, NameColumn, DescriptionColumn, ScheduleColumn, CreatedAtColumn, RetentionColumn, ExecutionPolicyColumn}
 	insertWorkflowStmt := db.PrepareInsertWithReturnAllStmt(tableName, insertColumns, allColumns())
 
 	id, err := utils.GenerateUniqueUUID(ctx, tableName, db)
@@ -42,7 +43,7 @@ func (w *sqliteWriterImpl) CreateWorkflow(
 		return nil, err
 	}
 
-	args := []interface{}{id, userId, name, description, schedule, time.Now(), retentionPolicy}
+	args := []interface{}{id, userId, name, description, schedule, time.Now(), retentionPolicy, executionPolicy}
 
 	var workflow Workflow
 	err = db.Query(ctx, &workflow, insertWorkflowStmt, args...)
@@ -23,12 +23,13 @@ func (w *standardWriterImpl) CreateWorkflow(
 	description string,
 	schedule *Schedule,
 	retentionPolicy *RetentionPolicy,
+	executionPolicy *ExecutionPolicy,
 	db database.Database,
 ) (*Workflow, error) {
-	insertColumns := []string{UserIdColumn, NameColumn, DescriptionColumn, ScheduleColumn, CreatedAtColumn, RetentionColumn}
+	insertColumns := []string{UserIdColumn, NameColumn, DescriptionColumn, ScheduleColumn, CreatedAtColumn, RetentionColumn, ExecutionPolicyColumn}
 	insertWorkflowStmt := db.PrepareInsertWithReturnAllStmt(tableName, insertColumns, allColumns())
 
-	args := []interface{}{userId, name, description, schedule, time.Now(), retentionPolicy}
+	args := []interface{}{userId, name, description, schedule, time.Now(), retentionPolicy, executionPolicy}
 
 	var workflow Workflow
 	err := db.Query(ctx, &workflow, insertWorkflowStmt, args...)
@@ -0,0 +1,191 @@
+package workflow
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/dropbox/godropbox/errors"
+	"github.com/gorhill/cronexpr"
+)
+
+const (
+	// The prefix of a `CronString` that is an interval, e.g. `every 15m`.
+	intervalPrefix = "every "
+	// The shortest interval a schedule can have.
+	MinScheduleInterval = time.Minute
+
+	// How far back `Previous` looks for a run.
+	maxPreviousLookback = 10 * 365 * 24 * time.Hour
+	// How many consecutive blackout windows `Next` skips before it gives up.
+	maxBlackoutSkips = 1000
+)
+
+// Timing is the parsed form of a schedule, which computes when its scheduled runs happen.
+type Timing struct {
+	// Exactly one of these is set.
+	cron     *cronexpr.Expression
+	interval time.Duration
+
+	location  *time.Location
+	jitter    time.Duration
+	blackouts []blackout
+
+	skipIfRunning bool
+}
+
+type blackout struct {
+	start    *cronexpr.Expression
+	duration time.Duration
+}
+
+// Timing parses the cron schedule, the timezone, the jitter and the blackout windows of the
+// schedule. It returns an error if any of them is invalid.
+func (s *Schedule) Timing() (*Timing, error) {
+	if s.CronSchedule == "" {
+		return nil, errors.New("The schedule does not have a cron schedule.")
+	}
+
+	timing := &Timing{}
+
+	if interval := strings.TrimPrefix(string(s.CronSchedule), intervalPrefix); interval != string(s.CronSchedule) {
+		duration, err := time.ParseDuration(strings.TrimSpace(interval))
+		if err != nil {
+			return nil, errors.Newf("Invalid schedule interval %s.", interval)
+		}
+		if duration < MinScheduleInterval {
+			return nil, errors.Newf("The schedule interval must be at least %v.", MinScheduleInterval)
+		}
+		timing.interval = duration
+	} else {
+		expr, err := cronexpr.Parse(string(s.CronSchedule))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid cron schedule %s.", s.CronSchedule)
+		}
+		timing.cron = expr
+	}
+
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, errors.Newf("Unknown timezone %s.", s.Timezone)
+	}
+	timing.location = location
+
+	if s.JitterSeconds < 0 {
+		return nil, errors.New("The schedule jitter cannot be negative.")
+	}
+	timing.jitter = time.Duration(s.JitterSeconds) * time.Second
+	timing.skipIfRunning = s.GetOverlapPolicy() == SkipOverlapPolicy
+
+	for _, window := range s.BlackoutWindows {
+		start, err := cronexpr.Parse(string(window.Start))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid blackout window start %s.", window.Start)
+		}
+		if window.DurationSeconds <= 0 {
+			return nil, errors.New("The duration of a blackout window must be positive.")
+		}
+
+		timing.blackouts = append(timing.blackouts, blackout{
+			start:    start,
+			duration: time.Duration(window.DurationSeconds) * time.Second,
+		})
+	}
+
+	return timing, nil
+}
+
+// Next returns the time of the first scheduled run after `after`, or the zero time if there is none.
+// Runs that fall in a blackout window are skipped. The jitter is not included.
+func (t *Timing) Next(after time.Time) time.Time {
+	next := t.nextFireTime(after)
+	for i := 0; !next.IsZero(); i++ {
+		end, ok := t.blackoutEnd(next)
+		if !ok {
+			return next
+		}
+		if i >= maxBlackoutSkips {
+			return time.Time{}
+		}
+
+		// A run at the exact end of a blackout window is not skipped.
+		next = t.nextFireTime(end.Add(-time.Nanosecond))
+	}
+
+	return time.Time{}
+}
+
+// Previous returns the time of the last scheduled run before `before`, or the zero time if there is
+// none in the last ten years. Runs that fall in a blackout window are skipped.
+func (t *Timing) Previous(before time.Time) time.Time {
+	for lookback := time.Hour; lookback <= maxPreviousLookback; lookback *= 2 {
+		var previous time.Time
+		for next := t.Next(before.Add(-lookback)); !next.IsZero() && next.Before(before); next = t.Next(next) {
+			previous = next
+		}
+
+		if !previous.IsZero() {
+			return previous
+		}
+	}
+
+	return time.Time{}
+}
+
+// Interval returns the interval of the schedule, or 0 if the schedule is a cron expression.
+func (t *Timing) Interval() time.Duration {
+	return t.interval
+}
+
+// SkipIfRunning returns whether a scheduled run is skipped while the previous scheduled run is
+// still running, i.e. whether the schedule has the skip overlap policy.
+func (t *Timing) SkipIfRunning() bool {
+	return t.skipIfRunning
+}
+
+// Jitter returns a random delay for a scheduled run, up to the jitter of the schedule.
+func (t *Timing) Jitter() time.Duration {
+	if t.jitter <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(t.jitter) + 1))
+}
+
+// nextFireTime returns the first time after `after` matched by the cron expression or the
+// interval, ignoring blackout windows. Intervals are counted from midnight of January 1, 1970
+// in the timezone of the schedule.
+func (t *Timing) nextFireTime(after time.Time) time.Time {
+	after = after.In(t.location)
+
+	if t.cron != nil {
+		return t.cron.Next(after)
+	}
+
+	anchor := time.Date(1970, time.January, 1, 0, 0, 0, 0, t.location)
+	if after.Before(anchor) {
+		return anchor
+	}
+
+	return anchor.Add((after.Sub(anchor)/t.interval + 1) * t.interval)
+}
+
+// blackoutEnd returns the end of a blackout window that contains `tm`, and whether there is one.
+func (t *Timing) blackoutEnd(tm time.Time) (time.Time, bool) {
+	tm = tm.In(t.location)
+
+	var end time.Time
+	for _, window := range t.blackouts {
+		// The window that contains `tm`, if any, started after `tm - duration`.
+		start := window.start.Next(tm.Add(-window.duration))
+		if start.IsZero() || start.After(tm) {
+			continue
+		}
+
+		if windowEnd := start.Add(window.duration); windowEnd.After(end) {
+			end = windowEnd
+		}
+	}
+
+	return end, !end.IsZero()
+}
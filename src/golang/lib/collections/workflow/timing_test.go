@@ -0,0 +1,92 @@
+package workflow_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/stretchr/testify/require"
+)
+
+func mustTiming(t *testing.T, schedule workflow.Schedule) *workflow.Timing {
+	timing, err := schedule.Timing()
+	require.Nil(t, err)
+	return timing
+}
+
+func TestTimingTimezone(t *testing.T) {
+	timing := mustTiming(t, workflow.Schedule{CronSchedule: "0 9 * * *", Timezone: "America/New_York"})
+
+	// 9am in New York is 1pm UTC during daylight saving time.
+	after := time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)
+	require.True(t, time.Date(2022, time.July, 1, 13, 0, 0, 0, time.UTC).Equal(timing.Next(after)))
+
+	// ... and 2pm UTC otherwise.
+	after = time.Date(2022, time.December, 1, 0, 0, 0, 0, time.UTC)
+	require.True(t, time.Date(2022, time.December, 1, 14, 0, 0, 0, time.UTC).Equal(timing.Next(after)))
+}
+
+func TestTimingInterval(t *testing.T) {
+	timing := mustTiming(t, workflow.Schedule{CronSchedule: "every 15m"})
+
+	now := time.Date(2022, time.July, 1, 10, 7, 30, 0, time.UTC)
+	require.True(t, time.Date(2022, time.July, 1, 10, 15, 0, 0, time.UTC).Equal(timing.Next(now)))
+	require.True(t, time.Date(2022, time.July, 1, 10, 0, 0, 0, time.UTC).Equal(timing.Previous(now)))
+
+	// A run time is not its own next or previous run.
+	runTime := time.Date(2022, time.July, 1, 10, 15, 0, 0, time.UTC)
+	require.True(t, time.Date(2022, time.July, 1, 10, 30, 0, 0, time.UTC).Equal(timing.Next(runTime)))
+	require.True(t, time.Date(2022, time.July, 1, 10, 0, 0, 0, time.UTC).Equal(timing.Previous(runTime)))
+}
+
+func TestTimingBlackoutWindows(t *testing.T) {
+	// Daily runs, except from the last day of the month through the second day of the next one.
+	timing := mustTiming(t, workflow.Schedule{
+		CronSchedule: "0 6 * * *",
+		BlackoutWindows: []workflow.BlackoutWindow{
+			{Start: "0 0 L * *", DurationSeconds: 3 * 24 * 60 * 60},
+		},
+	})
+
+	after := time.Date(2022, time.June, 29, 12, 0, 0, 0, time.UTC)
+	require.True(t, time.Date(2022, time.July, 3, 6, 0, 0, 0, time.UTC).Equal(timing.Next(after)))
+
+	before := time.Date(2022, time.July, 2, 12, 0, 0, 0, time.UTC)
+	require.True(t, time.Date(2022, time.June, 29, 6, 0, 0, 0, time.UTC).Equal(timing.Previous(before)))
+}
+
+func TestTimingPreviousOfSparseSchedule(t *testing.T) {
+	// The previous run is found even if it is far in the past.
+	timing := mustTiming(t, workflow.Schedule{CronSchedule: "0 0 1 1 *"})
+
+	before := time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)
+	require.True(t, time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC).Equal(timing.Previous(before)))
+}
+
+func TestTimingJitter(t *testing.T) {
+	timing := mustTiming(t, workflow.Schedule{CronSchedule: "0 * * * *", JitterSeconds: 60})
+	for i := 0; i < 100; i++ {
+		jitter := timing.Jitter()
+		require.True(t, jitter >= 0 && jitter <= time.Minute)
+	}
+
+	require.Equal(t, time.Duration(0), mustTiming(t, workflow.Schedule{CronSchedule: "0 * * * *"}).Jitter())
+}
+
+func TestInvalidTiming(t *testing.T) {
+	invalid := []workflow.Schedule{
+		{},
+		{CronSchedule: "not a cron"},
+		{CronSchedule: "every soon"},
+		{CronSchedule: "every 10s"},
+		{CronSchedule: "0 * * * *", Timezone: "Mars/Olympus_Mons"},
+		{CronSchedule: "0 * * * *", JitterSeconds: -1},
+		{CronSchedule: "0 * * * *", BlackoutWindows: []workflow.BlackoutWindow{{Start: "0 0 * * *"}}},
+		{CronSchedule: "0 * * * *", BlackoutWindows: []workflow.BlackoutWindow{{Start: "every 1h", DurationSeconds: 60}}},
+	}
+
+	for _, schedule := range invalid {
+		_, err := schedule.Timing()
+		require.NotNil(t, err, "%+v", schedule)
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"database/sql/driver"
 
 	"github.com/aqueducthq/aqueduct/lib/collections/utils"
+	"github.com/google/uuid"
 )
 
+// CronString is either a cron expression, e.g. `0 * * * *`, or an interval, e.g. `every 15m`.
 type CronString string
 
 type UpdateTrigger string
@@ -14,6 +16,38 @@ const (
 	ManualUpdateTrigger   UpdateTrigger = "manual"
 	PeriodicUpdateTrigger UpdateTrigger = "periodic"
 	AirflowUpdateTrigger  UpdateTrigger = "airflow"
+	// The workflow runs when the runs of other workflows finish.
+	CascadeUpdateTrigger UpdateTrigger = "cascade"
+	// The workflow runs when an external system sends a signed request to its webhook.
+	WebhookUpdateTrigger UpdateTrigger = "webhook"
+	// The workflow runs when its sensor observes something new.
+	SensorUpdateTrigger UpdateTrigger = "sensor"
+)
+
+// CascadeCondition determines when a workflow with a cascade trigger runs.
+type CascadeCondition string
+
+const (
+	// The workflow runs once the latest runs of all of its source workflows succeeded since its
+	// own latest run.
+	AllSucceededCascadeCondition CascadeCondition = "all_succeeded"
+	// The workflow runs whenever a run of any of its source workflows finishes.
+	AnyFinishedCascadeCondition CascadeCondition = "any_finished"
+)
+
+// OverlapPolicy determines what happens when a scheduled run of a workflow starts while
+// earlier runs of the workflow are still pending.
+type OverlapPolicy string
+
+const (
+	// The run starts alongside the pending runs.
+	AllowOverlapPolicy OverlapPolicy = "allow"
+	// The run does not start.
+	SkipOverlapPolicy OverlapPolicy = "skip"
+	// The run waits until the pending runs finish.
+	QueueOverlapPolicy OverlapPolicy = "queue"
+	// The pending runs are cancelled and the run starts.
+	CancelPreviousOverlapPolicy OverlapPolicy = "cancel_previous"
 )
 
 type Schedule struct {
@@ -21,6 +55,61 @@ type Schedule struct {
 	CronSchedule         CronString    `json:"cron_schedule"`
 	DisableManualTrigger bool          `json:"disable_manual_trigger"`
 	Paused               bool          `json:"paused"`
+	// The workflows whose runs trigger this workflow, if it has a cascade trigger.
+	SourceWorkflowIds []uuid.UUID `json:"source_workflow_ids,omitempty"`
+	// When this workflow runs, if it has a cascade trigger. Defaults to
+	// `AllSucceededCascadeCondition`.
+	CascadeCondition CascadeCondition `json:"cascade_condition,omitempty"`
+	// What this workflow watches, if it has a sensor trigger.
+	Sensor *Sensor `json:"sensor,omitempty"`
+	// What happens when a scheduled run starts while earlier runs are pending. Defaults to
+	// `AllowOverlapPolicy`.
+	OverlapPolicy OverlapPolicy `json:"overlap_policy,omitempty"`
+	// The IANA timezone that the cron schedule and the blackout windows are evaluated in,
+	// e.g. `America/New_York`. Defaults to UTC.
+	Timezone string `json:"timezone,omitempty"`
+	// The maximum random delay added to each scheduled run, in seconds.
+	JitterSeconds int `json:"jitter_seconds,omitempty"`
+	// The windows during which scheduled runs are skipped.
+	BlackoutWindows []BlackoutWindow `json:"blackout_windows,omitempty"`
+}
+
+// BlackoutWindow is a recurring window during which scheduled runs are skipped. A window starts
+// at every time matched by `Start` and lasts for `DurationSeconds`, e.g. a `Start` of `0 0 L * *`
+// and a `DurationSeconds` of 259200 skips the runs from the last day of each month through the
+// second day of the next month.
+type BlackoutWindow struct {
+	Start           CronString `json:"start"`
+	DurationSeconds int        `json:"duration_seconds"`
+}
+
+type SensorType string
+
+const (
+	// The sensor watches a prefix of an S3 bucket or a local directory for new files.
+	StorageSensorType SensorType = "storage"
+)
+
+// Sensor polls an external system and starts a run of the workflow for every new thing it
+// observes there.
+type Sensor struct {
+	Type SensorType `json:"type"`
+	// The name of the param operator that receives what the sensor observed in each run.
+	Parameter string `json:"parameter"`
+	// How often the sensor polls. 0 means the default poll interval applies.
+	PollIntervalSeconds int `json:"poll_interval_seconds,omitempty"`
+
+	Storage *StorageSensor `json:"storage,omitempty"`
+}
+
+// StorageSensor watches either the bucket of an S3 integration or a local directory. The path of
+// every new file, relative to the bucket or the directory, is passed to the run.
+type StorageSensor struct {
+	// The S3 integration whose bucket is watched. It is unset when a local directory is watched.
+	IntegrationId *uuid.UUID `json:"integration_id,omitempty"`
+	Directory     string     `json:"directory,omitempty"`
+	// Only the files whose paths start with the prefix are watched.
+	Prefix string `json:"prefix"`
 }
 
 func (s *Schedule) Value() (driver.Value, error) {
@@ -42,3 +131,40 @@ func (s *RetentionPolicy) Value() (driver.Value, error) {
 func (s *RetentionPolicy) Scan(value interface{}) error {
 	return utils.ScanJsonB(value, s)
 }
+
+type FailurePolicy string
+
+const (
+	// The run stops as soon as any operator fails.
+	FailFastFailurePolicy FailurePolicy = "fail_fast"
+	// The run keeps executing every operator that does not depend on a failed operator.
+	ContinueFailurePolicy FailurePolicy = "continue"
+)
+
+// ExecutionPolicy determines how the runs of a workflow are executed.
+type ExecutionPolicy struct {
+	// The maximum time a run may take, not counting the time it awaits approval. 0 means the
+	// default timeout applies.
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+	// What happens when an operator fails. Defaults to `FailFastFailurePolicy`.
+	FailurePolicy FailurePolicy `json:"failure_policy,omitempty"`
+}
+
+// IsValid returns whether the failure policy is one of the supported policies. An empty policy
+// is valid, and means the default policy applies.
+func (p FailurePolicy) IsValid() bool {
+	switch p {
+	case "", FailFastFailurePolicy, ContinueFailurePolicy:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *ExecutionPolicy) Value() (driver.Value, error) {
+	return utils.ValueJsonB(*p)
+}
+
+func (p *ExecutionPolicy) Scan(value interface{}) error {
+	return utils.ScanJsonB(value, p)
+}
@@ -17,6 +17,7 @@ type Workflow struct {
 	Schedule        Schedule        `db:"schedule" json:"schedule"`
 	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
 	RetentionPolicy RetentionPolicy `db:"retention_policy" json:"retention_policy"`
+	ExecutionPolicy ExecutionPolicy `db:"execution_policy" json:"execution_policy"`
 }
 
 type latestWorkflowResponse struct {
@@ -95,6 +96,7 @@ type Writer interface {
 		description string,
 		schedule *Schedule,
 		retentionPolicy *RetentionPolicy,
+		executionPolicy *ExecutionPolicy,
 		db database.Database,
 	) (*Workflow, error)
 	UpdateWorkflow(
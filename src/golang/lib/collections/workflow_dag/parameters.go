@@ -0,0 +1,33 @@
+package workflow_dag
+
+import (
+	"encoding/json"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/dropbox/godropbox/errors"
+)
+
+// OverrideParameters replaces the values of the dag's param operators with the values in
+// `parameters`, which are keyed by the names of the param operators. Every value must be valid
+// JSON, and every name must belong to a param operator of the dag.
+func (dag *WorkflowDag) OverrideParameters(parameters shared.Parameters) error {
+	for name, val := range parameters {
+		if !json.Valid([]byte(val)) {
+			return errors.Newf("The value of parameter %s is not valid JSON.", name)
+		}
+
+		found := false
+		for _, op := range dag.Operators {
+			if op.Name == name && op.Spec.IsParam() {
+				op.Spec.Param().Val = val
+				found = true
+			}
+		}
+
+		if !found {
+			return errors.Newf("The workflow has no parameter named %s.", name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package workflow_dag_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func newParamOperator(t *testing.T, name string, val string) operator.Operator {
+	var spec operator.Spec
+	err := json.Unmarshal([]byte(`{"type": "param", "param": {"val": `+val+`}}`), &spec)
+	require.Nil(t, err)
+
+	return operator.Operator{Id: uuid.New(), Name: name, Spec: spec}
+}
+
+func TestOverrideParameters(t *testing.T) {
+	op := newParamOperator(t, "date", `"\"2022-01-01\""`)
+	dag := &workflow_dag.WorkflowDag{Operators: map[uuid.UUID]operator.Operator{op.Id: op}}
+
+	err := dag.OverrideParameters(shared.Parameters{"date": `"2022-06-01"`})
+	require.Nil(t, err)
+	require.Equal(t, `"2022-06-01"`, dag.Operators[op.Id].Spec.Param().Val)
+
+	err = dag.OverrideParameters(shared.Parameters{"day": `"2022-06-01"`})
+	require.NotNil(t, err)
+
+	err = dag.OverrideParameters(shared.Parameters{"date": `2022-06-01`})
+	require.NotNil(t, err)
+}
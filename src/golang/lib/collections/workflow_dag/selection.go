@@ -0,0 +1,132 @@
+package workflow_dag
+
+import (
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+var ErrConflictingSelection = errors.New("At most one of `from_operators`, `only_operators` and `upstream_of` can be set.")
+
+// OperatorSelection selects the operators of a workflow dag that a partial run executes.
+// At most one of its fields is set. Operators that are not selected are not run, and the
+// artifacts they produce for the selected operators are reused from an earlier run.
+type OperatorSelection struct {
+	// Runs these operators and every operator downstream of them.
+	FromOperators []uuid.UUID `json:"from_operators,omitempty"`
+	// Runs only these operators.
+	OnlyOperators []uuid.UUID `json:"only_operators,omitempty"`
+	// Runs these operators and every operator upstream of them.
+	UpstreamOf []uuid.UUID `json:"upstream_of,omitempty"`
+}
+
+// IsEmpty returns whether the selection selects the entire dag.
+func (s *OperatorSelection) IsEmpty() bool {
+	return s == nil || (len(s.FromOperators) == 0 && len(s.OnlyOperators) == 0 && len(s.UpstreamOf) == 0)
+}
+
+// Validate checks that at most one field of the selection is set and that every
+// selected operator is part of the dag.
+func (s *OperatorSelection) Validate(dag *WorkflowDag) error {
+	if s.IsEmpty() {
+		return nil
+	}
+
+	numSet := 0
+	for _, ids := range [][]uuid.UUID{s.FromOperators, s.OnlyOperators, s.UpstreamOf} {
+		if len(ids) > 0 {
+			numSet++
+		}
+
+		for _, id := range ids {
+			if _, ok := dag.Operators[id]; !ok {
+				return errors.Newf("Operator %s is not part of the latest version of the workflow.", id)
+			}
+		}
+	}
+
+	if numSet > 1 {
+		return ErrConflictingSelection
+	}
+
+	return nil
+}
+
+// SelectedOperators returns the ids of the operators of the dag that the selection runs.
+// An empty selection selects every operator.
+func (s *OperatorSelection) SelectedOperators(dag *WorkflowDag) map[uuid.UUID]bool {
+	selected := make(map[uuid.UUID]bool, len(dag.Operators))
+	if s.IsEmpty() {
+		for id := range dag.Operators {
+			selected[id] = true
+		}
+		return selected
+	}
+
+	for _, id := range s.OnlyOperators {
+		selected[id] = true
+	}
+
+	if len(s.FromOperators) > 0 {
+		artifactToDownstreamOperatorIds := make(map[uuid.UUID][]uuid.UUID, len(dag.Artifacts))
+		for id, op := range dag.Operators {
+			for _, artifactId := range op.Inputs {
+				artifactToDownstreamOperatorIds[artifactId] = append(artifactToDownstreamOperatorIds[artifactId], id)
+			}
+		}
+
+		addClosure(dag, s.FromOperators, selected, func(id uuid.UUID) []uuid.UUID {
+			downstreamOps := []uuid.UUID{}
+			for _, artifactId := range dag.Operators[id].Outputs {
+				downstreamOps = append(downstreamOps, artifactToDownstreamOperatorIds[artifactId]...)
+			}
+			return downstreamOps
+		})
+	}
+
+	if len(s.UpstreamOf) > 0 {
+		artifactToUpstreamOperatorId := make(map[uuid.UUID]uuid.UUID, len(dag.Artifacts))
+		for id, op := range dag.Operators {
+			for _, artifactId := range op.Outputs {
+				artifactToUpstreamOperatorId[artifactId] = id
+			}
+		}
+
+		addClosure(dag, s.UpstreamOf, selected, func(id uuid.UUID) []uuid.UUID {
+			upstreamOps := []uuid.UUID{}
+			for _, artifactId := range dag.Operators[id].Inputs {
+				if upstreamOpId, ok := artifactToUpstreamOperatorId[artifactId]; ok {
+					upstreamOps = append(upstreamOps, upstreamOpId)
+				}
+			}
+			return upstreamOps
+		})
+	}
+
+	return selected
+}
+
+// addClosure adds the operators and every operator reachable from them through `neighbors`
+// to `selected`.
+func addClosure(
+	dag *WorkflowDag,
+	operatorIds []uuid.UUID,
+	selected map[uuid.UUID]bool,
+	neighbors func(uuid.UUID) []uuid.UUID,
+) {
+	queue := append([]uuid.UUID{}, operatorIds...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		if selected[id] {
+			continue
+		}
+
+		if _, ok := dag.Operators[id]; !ok {
+			continue
+		}
+
+		selected[id] = true
+		queue = append(queue, neighbors(id)...)
+	}
+}
@@ -0,0 +1,66 @@
+package workflow_dag_test
+
+import (
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// newSelectionTestDag returns a dag with operators a -> b -> c and an independent operator d.
+func newSelectionTestDag() (*workflow_dag.WorkflowDag, map[string]uuid.UUID) {
+	ids := map[string]uuid.UUID{}
+	artifacts := map[string]uuid.UUID{}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		ids[name] = uuid.New()
+		artifacts[name] = uuid.New()
+	}
+
+	dag := &workflow_dag.WorkflowDag{
+		Operators: map[uuid.UUID]operator.Operator{
+			ids["a"]: {Id: ids["a"], Outputs: []uuid.UUID{artifacts["a"]}},
+			ids["b"]: {Id: ids["b"], Inputs: []uuid.UUID{artifacts["a"]}, Outputs: []uuid.UUID{artifacts["b"]}},
+			ids["c"]: {Id: ids["c"], Inputs: []uuid.UUID{artifacts["b"]}, Outputs: []uuid.UUID{artifacts["c"]}},
+			ids["d"]: {Id: ids["d"], Outputs: []uuid.UUID{artifacts["d"]}},
+		},
+	}
+
+	return dag, ids
+}
+
+func TestSelectedOperators(t *testing.T) {
+	dag, ids := newSelectionTestDag()
+
+	var selection *workflow_dag.OperatorSelection
+	require.Len(t, selection.SelectedOperators(dag), 4)
+
+	selection = &workflow_dag.OperatorSelection{FromOperators: []uuid.UUID{ids["b"]}}
+	require.Equal(t, map[uuid.UUID]bool{ids["b"]: true, ids["c"]: true}, selection.SelectedOperators(dag))
+
+	selection = &workflow_dag.OperatorSelection{OnlyOperators: []uuid.UUID{ids["b"]}}
+	require.Equal(t, map[uuid.UUID]bool{ids["b"]: true}, selection.SelectedOperators(dag))
+
+	selection = &workflow_dag.OperatorSelection{UpstreamOf: []uuid.UUID{ids["b"]}}
+	require.Equal(t, map[uuid.UUID]bool{ids["a"]: true, ids["b"]: true}, selection.SelectedOperators(dag))
+}
+
+func TestValidateSelection(t *testing.T) {
+	dag, ids := newSelectionTestDag()
+
+	var selection *workflow_dag.OperatorSelection
+	require.Nil(t, selection.Validate(dag))
+
+	selection = &workflow_dag.OperatorSelection{FromOperators: []uuid.UUID{ids["a"]}}
+	require.Nil(t, selection.Validate(dag))
+
+	selection = &workflow_dag.OperatorSelection{
+		FromOperators: []uuid.UUID{ids["a"]},
+		UpstreamOf:    []uuid.UUID{ids["c"]},
+	}
+	require.Equal(t, workflow_dag.ErrConflictingSelection, selection.Validate(dag))
+
+	selection = &workflow_dag.OperatorSelection{OnlyOperators: []uuid.UUID{uuid.New()}}
+	require.NotNil(t, selection.Validate(dag))
+}
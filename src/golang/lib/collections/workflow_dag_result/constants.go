@@ -13,6 +13,9 @@ const (
 	WorkflowDagIdColumn = "workflow_dag_id"
 	StatusColumn        = "status"
 	CreatedAtColumn     = "created_at"
+	ResumedFromColumn   = "resumed_from"
+	ParametersColumn    = "parameters"
+	ParentIdColumn      = "parent_id"
 )
 
 // Returns a joined string of all WorkflowDagResult columns.
@@ -23,6 +26,9 @@ func allColumns() string {
 			WorkflowDagIdColumn,
 			StatusColumn,
 			CreatedAtColumn,
+			ResumedFromColumn,
+			ParametersColumn,
+			ParentIdColumn,
 		},
 		",",
 	)
@@ -36,6 +42,9 @@ func allColumnsWithPrefix() string {
 			fmt.Sprintf("%s.%s", tableName, WorkflowDagIdColumn),
 			fmt.Sprintf("%s.%s", tableName, StatusColumn),
 			fmt.Sprintf("%s.%s", tableName, CreatedAtColumn),
+			fmt.Sprintf("%s.%s", tableName, ResumedFromColumn),
+			fmt.Sprintf("%s.%s", tableName, ParametersColumn),
+			fmt.Sprintf("%s.%s", tableName, ParentIdColumn),
 		},
 		",",
 	)
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/aqueducthq/aqueduct/lib/collections/notification"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
 	"github.com/aqueducthq/aqueduct/lib/collections/user"
 	"github.com/aqueducthq/aqueduct/lib/collections/utils"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
@@ -30,6 +31,8 @@ func NewNoopWriter(throwError bool) Writer {
 func (w *noopWriterImpl) CreateWorkflowDagResult(
 	ctx context.Context,
 	workflowDagId uuid.UUID,
+	resumedFrom *uuid.UUID,
+	parameters shared.Parameters,
 	db database.Database,
 ) (*WorkflowDagResult, error) {
 	return nil, utils.NoopInterfaceErrorHandling(w.throwError)
@@ -68,6 +71,19 @@ func (w *noopWriterImpl) UpdateWorkflowDagResult(
 	return nil, utils.NoopInterfaceErrorHandling(w.throwError)
 }
 
+func (w *noopWriterImpl) UpdateWorkflowDagResultWithStatus(
+	ctx context.Context,
+	id uuid.UUID,
+	statuses []shared.ExecutionStatus,
+	changes map[string]interface{},
+	workflowReader workflow.Reader,
+	notificationWriter notification.Writer,
+	userReader user.Reader,
+	db database.Database,
+) (*WorkflowDagResult, error) {
+	return nil, utils.NoopInterfaceErrorHandling(w.throwError)
+}
+
 func (w *noopWriterImpl) DeleteWorkflowDagResult(ctx context.Context, id uuid.UUID, db database.Database) error {
 	return utils.NoopInterfaceErrorHandling(w.throwError)
 }
@@ -48,9 +48,11 @@ func (r *sqliteReaderImpl) GetKOffsetWorkflowDagResultsByWorkflowId(
 func (w *sqliteWriterImpl) CreateWorkflowDagResult(
 	ctx context.Context,
 	workflowDagId uuid.UUID,
+	resumedFrom *uuid.UUID,
+	parameters shared.Parameters,
 	db database.Database,
 ) (*WorkflowDagResult, error) {
-	insertColumns := []string{IdColumn, WorkflowDagIdColumn, StatusColumn, CreatedAtColumn}
+	insertColumns := []string{IdColumn, WorkflowDagIdColumn, StatusColumn, CreatedAtColumn, ResumedFromColumn, ParametersColumn}
 	insertWorkflowDagResultStmt := db.PrepareInsertWithReturnAllStmt(tableName, insertColumns, allColumns())
 
 	id, err := utils.GenerateUniqueUUID(ctx, tableName, db)
@@ -58,7 +60,16 @@ func (w *sqliteWriterImpl) CreateWorkflowDagResult(
 		return nil, err
 	}
 
-	args := []interface{}{id, workflowDagId, shared.PendingExecutionStatus, time.Now()}
+	var resumedFromArg interface{}
+	if resumedFrom != nil {
+		resumedFromArg = *resumedFrom
+	}
+
+	if parameters == nil {
+		parameters = shared.Parameters{}
+	}
+
+	args := []interface{}{id, workflowDagId, shared.PendingExecutionStatus, time.Now(), resumedFromArg, &parameters}
 
 	var workflowDagResult WorkflowDagResult
 	err = db.Query(ctx, &workflowDagResult, insertWorkflowDagResultStmt, args...)
@@ -24,12 +24,23 @@ type standardWriterImpl struct{}
 func (w *standardWriterImpl) CreateWorkflowDagResult(
 	ctx context.Context,
 	workflowDagId uuid.UUID,
+	resumedFrom *uuid.UUID,
+	parameters shared.Parameters,
 	db database.Database,
 ) (*WorkflowDagResult, error) {
-	insertColumns := []string{WorkflowDagIdColumn, StatusColumn, CreatedAtColumn}
+	insertColumns := []string{WorkflowDagIdColumn, StatusColumn, CreatedAtColumn, ResumedFromColumn, ParametersColumn}
 	insertWorkflowDagResultStmt := db.PrepareInsertWithReturnAllStmt(tableName, insertColumns, allColumns())
 
-	args := []interface{}{workflowDagId, shared.PendingExecutionStatus, time.Now()}
+	var resumedFromArg interface{}
+	if resumedFrom != nil {
+		resumedFromArg = *resumedFrom
+	}
+
+	if parameters == nil {
+		parameters = shared.Parameters{}
+	}
+
+	args := []interface{}{workflowDagId, shared.PendingExecutionStatus, time.Now(), resumedFromArg, &parameters}
 
 	var workflowDagResult WorkflowDagResult
 	err := db.Query(ctx, &workflowDagResult, insertWorkflowDagResultStmt, args...)
@@ -111,12 +122,19 @@ func (r *standardReaderImpl) GetKOffsetWorkflowDagResultsByWorkflowId(
 	return workflowDagResults, err
 }
 
+// isWorkflowDagResultFinished returns whether the run finished with a status that users are notified about.
+func isWorkflowDagResultFinished(status shared.ExecutionStatus) bool {
+	return status == shared.SucceededExecutionStatus ||
+		status == shared.FailedExecutionStatus ||
+		status == shared.PartialSuccessExecutionStatus
+}
+
 func workflowDagResultNotificationContent(
 	workflowObject *workflow.Workflow,
 	workflowDagResult *WorkflowDagResult,
 ) string {
 	status := workflowDagResult.Status
-	if status != shared.SucceededExecutionStatus && status != shared.FailedExecutionStatus {
+	if !isWorkflowDagResultFinished(status) {
 		return ""
 	}
 
@@ -125,6 +143,10 @@ func workflowDagResultNotificationContent(
 		return fmt.Sprintf("Workflow %s has succeeded!", name)
 	}
 
+	if status == shared.PartialSuccessExecutionStatus {
+		return fmt.Sprintf("Workflow %s has partially succeeded. Some of its operators failed.", name)
+	}
+
 	return fmt.Sprintf("Workflow %s has failed.", name)
 }
 
@@ -137,7 +159,7 @@ func createWorkflowDagResultNotification(
 	db database.Database,
 ) error {
 	status := workflowDagResult.Status
-	if status != shared.SucceededExecutionStatus && status != shared.FailedExecutionStatus {
+	if !isWorkflowDagResultFinished(status) {
 		return nil
 	}
 
@@ -152,6 +174,10 @@ func createWorkflowDagResultNotification(
 		notificationLevel = notification.ErrorLevel
 	}
 
+	if status == shared.PartialSuccessExecutionStatus {
+		notificationLevel = notification.WarningLevel
+	}
+
 	notificationAssociation := notification.NotificationAssociation{
 		Object: notification.WorkflowDagResultObject,
 		Id:     workflowDagResult.Id,
@@ -202,6 +228,47 @@ func (w *standardWriterImpl) UpdateWorkflowDagResult(
 	return &workflowDagResult, nil
 }
 
+func (w *standardWriterImpl) UpdateWorkflowDagResultWithStatus(
+	ctx context.Context,
+	id uuid.UUID,
+	statuses []shared.ExecutionStatus,
+	changes map[string]interface{},
+	workflowReader workflow.Reader,
+	notificationWriter notification.Writer,
+	userReader user.Reader,
+	db database.Database,
+) (*WorkflowDagResult, error) {
+	statusArgs := make([]interface{}, 0, len(statuses))
+	for _, status := range statuses {
+		statusArgs = append(statusArgs, status)
+	}
+
+	var workflowDagResult WorkflowDagResult
+	err := utils.UpdateRecordToDestIfIn(
+		ctx,
+		&workflowDagResult,
+		changes,
+		tableName,
+		IdColumn,
+		id,
+		StatusColumn,
+		statusArgs,
+		allColumns(),
+		db,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = createWorkflowDagResultNotification(ctx, &workflowDagResult, notificationWriter, workflowReader, userReader, db)
+	if err != nil {
+		// Only log the error and hide it to caller, since the dag result itself is successfully updated
+		log.Errorf("Failed to create dag result notification: %s", err)
+	}
+
+	return &workflowDagResult, nil
+}
+
 func (w *standardWriterImpl) DeleteWorkflowDagResult(
 	ctx context.Context,
 	id uuid.UUID,
@@ -219,11 +286,25 @@ func (w *standardWriterImpl) DeleteWorkflowDagResults(
 		return nil
 	}
 
+	args := stmt_preparers.CastIdsListToInterfaceList(ids)
+
+	// Runs that resumed the deleted runs, or were started by them, are kept, but no longer
+	// point to them.
+	for _, column := range []string{ResumedFromColumn, ParentIdColumn} {
+		unlinkStmt := fmt.Sprintf(
+			"UPDATE workflow_dag_result SET %s = NULL WHERE %s IN (%s);",
+			column,
+			column,
+			stmt_preparers.GenerateArgsList(len(ids), 1),
+		)
+		if err := db.Execute(ctx, unlinkStmt, args...); err != nil {
+			return err
+		}
+	}
+
 	deleteStmt := fmt.Sprintf(
 		"DELETE FROM workflow_dag_result WHERE id IN (%s);",
 		stmt_preparers.GenerateArgsList(len(ids), 1),
 	)
-
-	args := stmt_preparers.CastIdsListToInterfaceList(ids)
 	return db.Execute(ctx, deleteStmt, args...)
 }
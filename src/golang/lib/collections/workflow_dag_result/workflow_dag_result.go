@@ -7,16 +7,29 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/collections/notification"
 	"github.com/aqueducthq/aqueduct/lib/collections/shared"
 	"github.com/aqueducthq/aqueduct/lib/collections/user"
+	"github.com/aqueducthq/aqueduct/lib/collections/utils"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/google/uuid"
 )
 
+// RunningStatuses are the statuses of a run that has not finished yet.
+var RunningStatuses = []shared.ExecutionStatus{
+	shared.PendingExecutionStatus,
+	shared.AwaitingApprovalExecutionStatus,
+}
+
 type WorkflowDagResult struct {
 	Id            uuid.UUID              `db:"id" json:"id"`
 	WorkflowDagId uuid.UUID              `db:"workflow_dag_id" json:"workflow_dag_id"`
 	Status        shared.ExecutionStatus `db:"status" json:"status"`
 	CreatedAt     time.Time              `db:"created_at" json:"created_at"`
+	// The failed run that this run resumes, if any.
+	ResumedFrom utils.NullUUID `db:"resumed_from" json:"resumed_from"`
+	// The values of the param operators that this run overrides.
+	Parameters shared.Parameters `db:"parameters" json:"parameters"`
+	// The run whose sub-workflow operator started this run, if any.
+	ParentId utils.NullUUID `db:"parent_id" json:"parent_id"`
 }
 
 type Reader interface {
@@ -47,6 +60,8 @@ type Writer interface {
 	CreateWorkflowDagResult(
 		ctx context.Context,
 		workflowDagId uuid.UUID,
+		resumedFrom *uuid.UUID,
+		parameters shared.Parameters,
 		db database.Database,
 	) (*WorkflowDagResult, error)
 	UpdateWorkflowDagResult(
@@ -58,6 +73,18 @@ type Writer interface {
 		userReader user.Reader,
 		db database.Database,
 	) (*WorkflowDagResult, error)
+	// UpdateWorkflowDagResultWithStatus only applies `changes` if the run still has one of
+	// `statuses`. It returns database.ErrNoRows otherwise.
+	UpdateWorkflowDagResultWithStatus(
+		ctx context.Context,
+		id uuid.UUID,
+		statuses []shared.ExecutionStatus,
+		changes map[string]interface{},
+		workflowReader workflow.Reader,
+		notificationWriter notification.Writer,
+		userReader user.Reader,
+		db database.Database,
+	) (*WorkflowDagResult, error)
 	DeleteWorkflowDagResult(ctx context.Context, id uuid.UUID, db database.Database) error
 	DeleteWorkflowDagResults(ctx context.Context, ids []uuid.UUID, db database.Database) error
 }
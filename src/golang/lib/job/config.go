@@ -16,6 +16,14 @@ type ProcessConfig struct {
 	BinaryDir             string `yaml:"binaryDir" json:"binary_dir"`
 	PythonExecutorPackage string `yaml:"pythonExecutorPackage" json:"python_executor_package"`
 	OperatorStorageDir    string `yaml:"operatorStorageDir" json:"operator_storage_dir"`
+	LogDir                string `yaml:"logDir" json:"log_dir"`
+	// The maximum number of operator jobs that run at the same time. Workflow jobs do not count.
+	// Jobs launched beyond this limit are queued until a running job finishes. The limit is shared
+	// by the job managers that share their slots via `EnableSharedSlots`. 0 means there is no limit.
+	MaxConcurrentJobs int `yaml:"maxConcurrentJobs" json:"max_concurrent_jobs"`
+	// The maximum number of jobs of the same workflow, across all of its runs, that run at the
+	// same time. 0 means there is no limit.
+	MaxConcurrentJobsPerWorkflow int `yaml:"maxConcurrentJobsPerWorkflow" json:"max_concurrent_jobs_per_workflow"`
 }
 
 func (*ProcessConfig) Type() ManagerType {
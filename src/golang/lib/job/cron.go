@@ -0,0 +1,15 @@
+package job
+
+import "time"
+
+// CronSchedule determines when a cron job launches its job.
+type CronSchedule interface {
+	// Next returns the first launch time after `after`, or the zero time if the job is not
+	// launched again.
+	Next(after time.Time) time.Time
+	// Jitter returns a random delay that is added to a launch time.
+	Jitter() time.Duration
+	// SkipIfRunning returns whether a launch is skipped while the job of the previous launch
+	// is still queued or running.
+	SkipIfRunning() bool
+}
@@ -17,10 +17,18 @@ var (
 type JobManager interface {
 	Config() Config
 	Launch(ctx context.Context, name string, spec Spec) error
+	// Poll returns the status of the job with the given name. A job that is waiting
+	// for a slot reports `shared.QueuedExecutionStatus`.
 	Poll(ctx context.Context, name string) (shared.ExecutionStatus, error)
-	DeployCronJob(ctx context.Context, name string, period string, spec Spec) error
+	// Cancel stops the job with the given name. A cancelled job reports
+	// `shared.CanceledExecutionStatus` the next time it is polled.
+	Cancel(ctx context.Context, name string) error
+	// DeployCronJob launches the job with the given spec according to `schedule`. A nil
+	// `schedule` deploys a paused cron job.
+	DeployCronJob(ctx context.Context, name string, schedule CronSchedule, spec Spec) error
 	CronJobExists(ctx context.Context, name string) bool
-	EditCronJob(ctx context.Context, name string, cronString string) error
+	// EditCronJob changes the schedule of the cron job. A nil `schedule` pauses the cron job.
+	EditCronJob(ctx context.Context, name string, schedule CronSchedule) error
 	DeleteCronJob(ctx context.Context, name string) error
 }
 
@@ -0,0 +1,278 @@
+package job
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/function"
+)
+
+const (
+	// The message bash prints when a child process is stopped by SIGXCPU.
+	cpuTimeExceededMessage = "CPU time limit exceeded"
+
+	// The first line of a Python traceback. Its exception is on the first unindented line after it.
+	pythonTracebackHeader = "Traceback (most recent call last):"
+	// The exception Python raises when it is unable to allocate memory. Its subclasses, e.g.
+	// numpy's `_ArrayMemoryError`, share the suffix.
+	pythonMemoryError = "MemoryError"
+	// The errno of an OSError raised when the OS is unable to allocate memory, e.g. to fork.
+	outOfMemoryErrno = "[Errno 12]"
+)
+
+// Function jobs run user code, so they only get the environment variables needed
+// to run Python, install the function's requirements and access the storage.
+var functionEnvironmentVariables = map[string]bool{
+	"PATH":                true,
+	"HOME":                true,
+	"USER":                true,
+	"LANG":                true,
+	"LC_ALL":              true,
+	"LC_CTYPE":            true,
+	"TMPDIR":              true,
+	"PYTHONPATH":          true,
+	"PYTHONHOME":          true,
+	"VIRTUAL_ENV":         true,
+	"CONDA_PREFIX":        true,
+	"CONDA_DEFAULT_ENV":   true,
+	"PIP_INDEX_URL":       true,
+	"PIP_EXTRA_INDEX_URL": true,
+	"SSL_CERT_FILE":       true,
+	"REQUESTS_CA_BUNDLE":  true,
+	"HTTP_PROXY":          true,
+	"HTTPS_PROXY":         true,
+	"NO_PROXY":            true,
+	"http_proxy":          true,
+	"https_proxy":         true,
+	"no_proxy":            true,
+}
+
+// The prefixes of the other environment variables function jobs get. The S3 storage of the
+// operator's artifacts relies on the default AWS credential chain, which reads `AWS_*`.
+var functionEnvironmentPrefixes = []string{"AWS_"}
+
+// jobEnvironment returns the environment the job with the given spec is launched with.
+func jobEnvironment(spec Spec) []string {
+	env := []string{}
+	for _, variable := range os.Environ() {
+		key := strings.SplitN(variable, "=", 2)[0]
+		// A job launched by an executor is not the executor's job.
+		if key == JobNameEnvironmentVariable {
+			continue
+		}
+
+		if spec.Type() != FunctionJobType || isFunctionEnvironmentVariable(key) {
+			env = append(env, variable)
+		}
+	}
+
+	return env
+}
+
+func isFunctionEnvironmentVariable(key string) bool {
+	if functionEnvironmentVariables[key] {
+		return true
+	}
+
+	for _, prefix := range functionEnvironmentPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withResourceLimits wraps `cmd` in a shell that sets the memory and CPU time rlimits before
+// running it. The rlimits are inherited by every process the job starts. The wall clock limit
+// is enforced by the job manager instead.
+func withResourceLimits(cmd *exec.Cmd, limits *function.ResourceLimits) *exec.Cmd {
+	if limits == nil || (limits.MemoryMB <= 0 && limits.CpuSeconds <= 0) {
+		return cmd
+	}
+
+	ulimits := []string{}
+	if limits.MemoryMB > 0 {
+		ulimits = append(ulimits, fmt.Sprintf("ulimit -v %d", limits.MemoryMB*1024))
+	}
+
+	if limits.CpuSeconds > 0 {
+		// The soft limit sends SIGXCPU, which tells a CPU time kill apart from other kills.
+		// The hard limit makes sure processes that handle SIGXCPU are stopped as well.
+		ulimits = append(
+			ulimits,
+			fmt.Sprintf("ulimit -S -t %d", limits.CpuSeconds),
+			fmt.Sprintf("ulimit -H -t %d", limits.CpuSeconds+1),
+		)
+	}
+
+	script := fmt.Sprintf("%s && exec \"$@\"", strings.Join(ulimits, " && "))
+	args := append([]string{"-c", script, "bash"}, cmd.Args...)
+
+	return exec.Command("bash", args...)
+}
+
+// detectExceededLimit returns the resource limit that stopped the job, or an empty string if
+// the job was not stopped by any of its limits.
+func detectExceededLimit(
+	limits *function.ResourceLimits,
+	cmd *exec.Cmd,
+	logs []byte,
+	operatorError string,
+	wallClockExceeded bool,
+) function.ResourceLimit {
+	if wallClockExceeded {
+		return function.WallClockResourceLimit
+	}
+
+	if limits.CpuSeconds > 0 {
+		// The job's own process is stopped directly, while its child processes
+		// are reported by bash in the job's logs.
+		waitStatus, ok := cmd.ProcessState.Sys().(syscall.WaitStatus)
+		if ok && waitStatus.Signaled() && waitStatus.Signal() == syscall.SIGXCPU {
+			return function.CpuTimeResourceLimit
+		}
+
+		if bytes.Contains(logs, []byte(cpuTimeExceededMessage)) {
+			return function.CpuTimeResourceLimit
+		}
+	}
+
+	if limits.MemoryMB > 0 && exitedWithFailure(cmd) {
+		// The operator's error holds the traceback of the user's function. An error that was
+		// not caught by the operator is only in the logs.
+		exception := finalTracebackException(operatorError)
+		if exception == "" {
+			exception = finalTracebackException(string(logs))
+		}
+
+		if isMemoryException(exception) {
+			return function.MemoryResourceLimit
+		}
+	}
+
+	return ""
+}
+
+// exitedWithFailure returns whether the job's process exited with a non-zero status or was
+// stopped by a signal.
+func exitedWithFailure(cmd *exec.Cmd) bool {
+	if cmd.ProcessState == nil {
+		return false
+	}
+
+	waitStatus, ok := cmd.ProcessState.Sys().(syscall.WaitStatus)
+	if !ok {
+		return !cmd.ProcessState.Success()
+	}
+
+	return waitStatus.Signaled() || (waitStatus.Exited() && waitStatus.ExitStatus() != 0)
+}
+
+// finalTracebackException returns the exception line of the last Python traceback in `text`,
+// e.g. "MemoryError: Unable to allocate array", or an empty string if there is none.
+func finalTracebackException(text string) string {
+	idx := strings.LastIndex(text, pythonTracebackHeader)
+	if idx < 0 {
+		return ""
+	}
+
+	lines := strings.Split(text[idx+len(pythonTracebackHeader):], "\n")
+	for _, line := range lines {
+		// The frames of the traceback are indented.
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+
+		return strings.TrimSpace(line)
+	}
+
+	return ""
+}
+
+// isMemoryException returns whether the exception line of a traceback is for an exception that
+// Python raises when it is unable to allocate memory.
+func isMemoryException(exception string) bool {
+	name := strings.TrimSpace(strings.SplitN(exception, ":", 2)[0])
+	if name == "" {
+		return false
+	}
+
+	// The name may be qualified by its module.
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		name = name[idx+1:]
+	}
+
+	if strings.HasSuffix(name, pythonMemoryError) {
+		return true
+	}
+
+	return strings.HasSuffix(name, "OSError") && strings.Contains(exception, outOfMemoryErrno)
+}
+
+func exceededLimitErrorMessage(limits *function.ResourceLimits, limit function.ResourceLimit) string {
+	switch limit {
+	case function.MemoryResourceLimit:
+		return fmt.Sprintf("Operator exceeded its memory limit of %d MB.", limits.MemoryMB)
+	case function.CpuTimeResourceLimit:
+		return fmt.Sprintf("Operator exceeded its CPU time limit of %d seconds.", limits.CpuSeconds)
+	default:
+		return fmt.Sprintf("Operator exceeded its wall clock limit of %d seconds.", limits.WallClockSeconds)
+	}
+}
+
+// checkResourceLimits checks whether the function job was stopped by one of its resource limits.
+// If so, the limit is written to the operator's metadata, so the operator fails with an error
+// that says which limit was exceeded, and the limit is returned.
+func checkResourceLimits(
+	ctx context.Context,
+	spec *FunctionSpec,
+	command *Command,
+	wallClockExceeded bool,
+) (function.ResourceLimit, error) {
+	if spec.ResourceLimits == nil {
+		return "", nil
+	}
+
+	logs, err := os.ReadFile(command.logPath)
+	if err != nil {
+		return "", err
+	}
+
+	// The operator may have written its metadata before it was stopped.
+	var metadata operator_result.Metadata
+	store := storage.NewStorage(&spec.StorageConfig)
+	if serializedMetadata, err := store.Get(ctx, spec.MetadataPath); err == nil {
+		if err := json.Unmarshal(serializedMetadata, &metadata); err != nil {
+			return "", err
+		}
+	}
+
+	limit := detectExceededLimit(spec.ResourceLimits, command.cmd, logs, metadata.Error, wallClockExceeded)
+	if limit == "" {
+		return "", nil
+	}
+
+	errMsg := exceededLimitErrorMessage(spec.ResourceLimits, limit)
+	if len(metadata.Error) > 0 {
+		errMsg = fmt.Sprintf("%s\n\n%s", errMsg, metadata.Error)
+	}
+
+	metadata.Error = errMsg
+	metadata.ExceededLimit = limit
+
+	serializedMetadata, err := json.Marshal(metadata)
+	if err != nil {
+		return "", err
+	}
+
+	return limit, store.Put(ctx, spec.MetadataPath, serializedMetadata)
+}
@@ -0,0 +1,124 @@
+package job
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// How often the log of a running job is copied to storage while it is small.
+	logFlushInterval = 2 * time.Second
+	// The whole log is copied each time, so the interval grows by `logFlushInterval` for every
+	// `logFlushBackoffSize` bytes of log, up to `maxLogFlushInterval`.
+	logFlushBackoffSize = 1 << 20
+	maxLogFlushInterval = time.Minute
+)
+
+const logStorageContextKey contextKey = "logStorage"
+
+type logStorageDestination struct {
+	storageConfig *shared.StorageConfig
+	key           string
+}
+
+// WithLogStorage returns a context that makes `Launch` stream the job's stdout and stderr
+// to `key` in the given storage, while the job runs and once it exits.
+func WithLogStorage(ctx context.Context, storageConfig *shared.StorageConfig, key string) context.Context {
+	return context.WithValue(ctx, logStorageContextKey, &logStorageDestination{
+		storageConfig: storageConfig,
+		key:           key,
+	})
+}
+
+func logStorageFromContext(ctx context.Context) *logStorageDestination {
+	destination, _ := ctx.Value(logStorageContextKey).(*logStorageDestination)
+	return destination
+}
+
+// logStreamer periodically copies a job's log file to storage.
+type logStreamer struct {
+	store   storage.Storage
+	key     string
+	logPath string
+
+	// The size of the log the last time it was copied, so the log is only copied once it grows.
+	flushedSize int64
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newLogStreamer(destination *logStorageDestination, logPath string) *logStreamer {
+	return &logStreamer{
+		store:       storage.NewStorage(destination.storageConfig),
+		key:         destination.key,
+		logPath:     logPath,
+		flushedSize: -1,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+func (s *logStreamer) run() {
+	defer close(s.done)
+
+	timer := time.NewTimer(logFlushInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			s.flush()
+			timer.Reset(flushInterval(s.flushedSize))
+		case <-s.stop:
+			s.flush()
+			return
+		}
+	}
+}
+
+// close copies the complete log to storage and stops the streamer.
+func (s *logStreamer) close() {
+	close(s.stop)
+	<-s.done
+}
+
+// flushInterval returns how long to wait before copying a log of the given size again.
+func flushInterval(size int64) time.Duration {
+	interval := logFlushInterval * time.Duration(1+size/logFlushBackoffSize)
+	if interval > maxLogFlushInterval {
+		return maxLogFlushInterval
+	}
+
+	return interval
+}
+
+func (s *logStreamer) flush() {
+	info, err := os.Stat(s.logPath)
+	if err != nil {
+		log.Errorf("Unable to read job log %s: %v", s.logPath, err)
+		return
+	}
+
+	if info.Size() <= s.flushedSize {
+		return
+	}
+
+	logs, err := os.ReadFile(s.logPath)
+	if err != nil {
+		log.Errorf("Unable to read job log %s: %v", s.logPath, err)
+		return
+	}
+
+	if err := s.store.Put(context.Background(), s.key, logs); err != nil {
+		log.Errorf("Unable to write job log %s to storage: %v", s.logPath, err)
+		return
+	}
+
+	s.flushedSize = int64(len(logs))
+}
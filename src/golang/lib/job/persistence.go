@@ -0,0 +1,235 @@
+package job
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/job_record"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/google/uuid"
+	"github.com/shirou/gopsutil/process"
+	log "github.com/sirupsen/logrus"
+)
+
+// EnablePersistence makes the job manager record every job it launches in the metadata
+// database, so that the jobs can be recovered by `Reconcile` after a restart.
+func (j *ProcessJobManager) EnablePersistence(db database.Database) error {
+	jobRecordReader, err := job_record.NewReader(db.Config())
+	if err != nil {
+		return err
+	}
+
+	jobRecordWriter, err := job_record.NewWriter(db.Config())
+	if err != nil {
+		return err
+	}
+
+	j.db = db
+	j.jobRecordReader = jobRecordReader
+	j.jobRecordWriter = jobRecordWriter
+	return nil
+}
+
+// recordJob writes the job record of a launched job. Failures are only logged, since the
+// job is already running at this point.
+func (j *ProcessJobManager) recordJob(ctx context.Context, name string, spec Spec, command *Command) {
+	var workflowId *uuid.UUID
+	if workflowSpec, ok := spec.(*WorkflowSpec); ok {
+		id, err := uuid.Parse(workflowSpec.WorkflowId)
+		if err == nil {
+			workflowId = &id
+		}
+	}
+
+	_, err := j.jobRecordWriter.CreateJobRecord(
+		ctx,
+		name,
+		command.pid,
+		string(spec.Type()),
+		workflowId,
+		command.logPath,
+		command.startTime,
+		j.db,
+	)
+	if err != nil {
+		log.Errorf("Unable to record job %s: %v", name, err)
+	}
+}
+
+// RecordWorkflowDagResult is called by the executor of a workflow job to record the run it
+// executes in the job record of the job, so that only that run is marked as failed if the
+// executor exits while the server is not watching it. It is a no-op if the job manager that
+// launched the executor does not record its jobs.
+func RecordWorkflowDagResult(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	workflowDagResultId uuid.UUID,
+	jobRecordWriter job_record.Writer,
+	db database.Database,
+) error {
+	name := os.Getenv(JobNameEnvironmentVariable)
+	if name == "" {
+		return nil
+	}
+
+	pid := os.Getpid()
+	startTime, err := processStartTime(pid)
+	if err != nil {
+		return err
+	}
+
+	return jobRecordWriter.RecordWorkflowDagResult(
+		ctx,
+		name,
+		pid,
+		string(WorkflowJobType),
+		workflowId,
+		workflowDagResultId,
+		startTime,
+		db,
+	)
+}
+
+// Reconcile recovers the jobs recorded by a previous run of the job manager. Jobs whose process
+// is still alive are reattached, so they can be cancelled by name, and are returned.
+// The records of jobs whose process is gone are deleted. `onExit` is called with the record of
+// each reattached job once its process exits.
+func (j *ProcessJobManager) Reconcile(
+	ctx context.Context,
+	onExit func(record job_record.JobRecord),
+) ([]job_record.JobRecord, error) {
+	records, err := j.jobRecordReader.GetJobRecords(ctx, j.db)
+	if err != nil {
+		return nil, err
+	}
+
+	reattached := make([]job_record.JobRecord, 0, len(records))
+	for _, record := range records {
+		j.mutex.Lock()
+		_, ok := j.cmds[record.Name]
+		j.mutex.Unlock()
+		if ok {
+			// This job was launched by this job manager.
+			continue
+		}
+
+		if !isProcessAlive(record.Pid, record.StartTime) {
+			log.Infof("Job %s is no longer running, removing its record.", record.Name)
+			if err := j.jobRecordWriter.DeleteJobRecord(ctx, record.Name, j.db); err != nil {
+				return nil, err
+			}
+
+			if err := os.Remove(record.LogPath); err != nil && !os.IsNotExist(err) {
+				log.Errorf("Unable to remove the log file of job %s: %v", record.Name, err)
+			}
+
+			continue
+		}
+
+		log.Infof("Reattaching to job %s with pid %d.", record.Name, record.Pid)
+		command := &Command{
+			takesSlot: takesSlot(JobType(record.SpecType)),
+			pid:       record.Pid,
+			startTime: record.StartTime,
+			logPath:   record.LogPath,
+			status:    shared.PendingExecutionStatus,
+		}
+
+		// The reattached job keeps the slot it holds in the shared slot table, if any, until
+		// its process exits.
+		j.mutex.Lock()
+		j.cmds[record.Name] = command
+		j.mutex.Unlock()
+
+		reattached = append(reattached, record)
+
+		go j.watchReattachedJob(record, command, onExit)
+	}
+
+	return reattached, nil
+}
+
+// watchReattachedJob blocks until the process of a reattached job exits. Since the job is not a
+// child of this process, its exit status is unknown, so the job is garbage collected right away
+// and `onExit` is called with its record.
+func (j *ProcessJobManager) watchReattachedJob(
+	record job_record.JobRecord,
+	command *Command,
+	onExit func(record job_record.JobRecord),
+) {
+	for isProcessAlive(record.Pid, record.StartTime) {
+		time.Sleep(reattachedJobPollInterval)
+	}
+
+	log.Infof("Reattached job %s has exited.", record.Name)
+
+	j.mutex.Lock()
+	command.done = true
+	command.exitCode = unknownExitCode
+	command.status = shared.UnknownExecutionStatus
+	if command.canceled {
+		command.status = shared.CanceledExecutionStatus
+	}
+	delete(j.cmds, record.Name)
+	if command.takesSlot {
+		j.slots.release(record.Name, command)
+	}
+	started := j.dispatchQueuedJobs()
+	j.mutex.Unlock()
+
+	j.recordJobs(started)
+	j.cleanupJob(context.Background(), record.Name, command)
+
+	if onExit != nil {
+		onExit(record)
+	}
+}
+
+// isProcessAlive returns whether the process with the given pid is still running and was
+// started at `startTime`, i.e. the pid has not been reused by an unrelated process.
+func isProcessAlive(pid int, startTime time.Time) bool {
+	exists, err := process.PidExists(int32(pid))
+	if err != nil || !exists {
+		return false
+	}
+
+	proc, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return false
+	}
+
+	createTimeMillis, err := proc.CreateTime()
+	if err != nil {
+		return false
+	}
+
+	createTime := time.Unix(0, createTimeMillis*int64(time.Millisecond))
+	if createTime.Sub(startTime) > pidReuseTolerance || startTime.Sub(createTime) > pidReuseTolerance {
+		return false
+	}
+
+	status, err := proc.Status()
+	if err != nil {
+		return false
+	}
+
+	// A zombie process has exited but has not been reaped by its parent yet.
+	return status != processZombieStatus
+}
+
+// processStartTime returns the creation time of the process with the given pid.
+func processStartTime(pid int) (time.Time, error) {
+	proc, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	createTimeMillis, err := proc.CreateTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(0, createTimeMillis*int64(time.Millisecond)), nil
+}
@@ -29,7 +29,8 @@ func PollJob(
 			}
 
 			if status == shared.SucceededExecutionStatus ||
-				status == shared.FailedExecutionStatus {
+				status == shared.FailedExecutionStatus ||
+				status == shared.CanceledExecutionStatus {
 				return status, nil
 			}
 		case <-timeout.C:
@@ -1,20 +1,22 @@
 package job
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
+	"sync"
+	"syscall"
 	"time"
 
+	"github.com/aqueducthq/aqueduct/lib/collections/job_record"
 	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/function"
 	"github.com/dropbox/godropbox/errors"
-	"github.com/go-co-op/gocron"
 	"github.com/google/uuid"
-	"github.com/shirou/gopsutil/process"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -25,37 +27,95 @@ const (
 	workflowExecutorBinary       = "executor"
 	functionExecutorBashScript   = "start-function-executor.sh"
 
-	processRunningStatus = "R"
+	processZombieStatus = "Z"
+
+	// The exit code reported for jobs whose exit code is unknown, e.g. jobs recovered by `Reconcile`.
+	unknownExitCode = -1
+
+	// How often the liveness of a job recovered by `Reconcile` is checked.
+	reattachedJobPollInterval = 5 * time.Second
+	// The maximum difference allowed between the recorded start time of a job and the creation
+	// time of the process with the recorded pid. A larger difference means the pid was reused.
+	pidReuseTolerance = 10 * time.Second
+
+	// The environment variable that holds the name of a workflow job launched by a job manager
+	// that records its jobs, see `RecordWorkflowDagResult`.
+	JobNameEnvironmentVariable = "AQUEDUCT_JOB_NAME"
 
 	BinaryDir          = "bin/"
 	OperatorStorageDir = "storage/operators/"
+	JobLogDir          = "logs/jobs/"
 )
 
 var (
 	defaultBinaryDir          = path.Join(os.Getenv("HOME"), ".aqueduct", BinaryDir)
 	defaultOperatorStorageDir = path.Join(os.Getenv("HOME"), ".aqueduct", OperatorStorageDir)
+	defaultJobLogDir          = path.Join(os.Getenv("HOME"), ".aqueduct", JobLogDir)
 )
 
 type Command struct {
-	cmd    *exec.Cmd
-	stdout *bytes.Buffer
-	stderr *bytes.Buffer
+	// This is nil for jobs recovered by `Reconcile`, since they are not children of this process.
+	cmd *exec.Cmd
+	// These determine when a queued job is started.
+	spec       Spec
+	priority   Priority
+	workflowId string
+	// Whether the job counts against the concurrency limits, see `takesSlot`.
+	takesSlot bool
+
+	pid       int
+	startTime time.Time
+	// The stdout and stderr of the job are written to a file instead of a pipe, so the job
+	// does not depend on this process to drain its output and survives a server restart.
+	logPath string
+	logFile *os.File
+	// If set, the log is also streamed to storage, see `WithLogStorage`.
+	logStorage  *logStorageDestination
+	logStreamer *logStreamer
+	// Whether the job was stopped by `Cancel`.
+	canceled bool
+	// This stops the job once it exceeds its wall clock limit, if it has one.
+	wallClockTimer    *time.Timer
+	wallClockExceeded bool
+
+	// These are published by the goroutine waiting on the job once the job exits.
+	done     bool
+	status   shared.ExecutionStatus
+	exitCode int
 }
 
 type cronMetadata struct {
-	// If the cronJob is nil, it means the corresponding workflow has been paused.
-	cronJob *gocron.Job
+	// If the schedule is nil, it means the corresponding workflow has been paused.
+	schedule CronSchedule
+	// This fires at the next launch time of the cron job. It is nil if the cron job is paused
+	// or will not launch again.
+	timer *time.Timer
+	// This is incremented whenever the schedule changes, so that a timer that fired for an
+	// outdated schedule does not launch the job.
+	generation int
 	// We need to store the job spec because when the workflow is resumed from the pause state, we
 	// need the spec to redeploy the cron job.
 	jobSpec Spec
+	// The name of the job the cron job launched last, if any.
+	lastJobName string
 }
 
 type ProcessJobManager struct {
-	conf          *ProcessConfig
-	cmds          map[string]*Command
-	cronScheduler *gocron.Scheduler
+	conf *ProcessConfig
+	// Guards `cmds`, `cronMapping` and the fields of the commands in `cmds`, which are accessed
+	// by the callers of the job manager, the goroutines waiting on jobs and the cron timers.
+	mutex sync.Mutex
+	cmds  map[string]*Command
 	// A mapping from cron job name to cron job object pointer.
 	cronMapping map[string]*cronMetadata
+	// Jobs waiting for a slot to become available.
+	queue jobQueue
+	slots slotTable
+
+	// These are only set if persistence is enabled via `EnablePersistence`.
+	db              database.Database
+	jobRecordReader job_record.Reader
+	jobRecordWriter job_record.Writer
 }
 
 func NewProcessJobManager(conf *ProcessConfig) (*ProcessJobManager, error) {
@@ -71,14 +131,15 @@ func NewProcessJobManager(conf *ProcessConfig) (*ProcessJobManager, error) {
 		conf.OperatorStorageDir = defaultOperatorStorageDir
 	}
 
-	cronScheduler := gocron.NewScheduler(time.UTC)
-	cronScheduler.StartAsync()
+	if conf.LogDir == "" {
+		conf.LogDir = defaultJobLogDir
+	}
 
 	return &ProcessJobManager{
-		conf:          conf,
-		cmds:          map[string]*Command{},
-		cronScheduler: cronScheduler,
-		cronMapping:   map[string]*cronMetadata{},
+		conf:        conf,
+		cmds:        map[string]*Command{},
+		cronMapping: map[string]*cronMetadata{},
+		slots:       newLocalSlots(conf.MaxConcurrentJobs, conf.MaxConcurrentJobsPerWorkflow),
 	}, nil
 }
 
@@ -111,10 +172,13 @@ func (j *ProcessJobManager) mapJobTypeToCmd(spec Spec) (*exec.Cmd, error) {
 			return nil, err
 		}
 
-		return exec.Command(
-			"bash",
-			filepath.Join(j.conf.BinaryDir, functionExecutorBashScript),
-			specStr,
+		return withResourceLimits(
+			exec.Command(
+				"bash",
+				filepath.Join(j.conf.BinaryDir, functionExecutorBashScript),
+				specStr,
+			),
+			functionSpec.ResourceLimits,
 		), nil
 	} else if spec.Type() == ParamJobType {
 		specStr, err := EncodeSpec(spec, JsonSerializationType)
@@ -145,9 +209,22 @@ func (j *ProcessJobManager) mapJobTypeToCmd(spec Spec) (*exec.Cmd, error) {
 	}
 }
 
-func (j *ProcessJobManager) generateCronFunction(name string, jobSpec Spec) func() {
-	return func() {
-		jobName := fmt.Sprintf("%s-%d", name, time.Now().Unix())
+// Command returns the command line that runs a job with the given spec, without running it.
+func (j *ProcessJobManager) Command(spec Spec) ([]string, error) {
+	cmd, err := j.mapJobTypeToCmd(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return cmd.Args, nil
+}
+
+func (j *ProcessJobManager) generateCronFunction(jobSpec Spec) func(jobName string) {
+	// The executor enforces the overlap policy of the workflow on the runs launched here, since
+	// it also sees the runs that were not launched by the cron job.
+	jobSpec = NewScheduledSpec(jobSpec)
+
+	return func(jobName string) {
 		log.Infof("Running cron job %s", jobName)
 		err := j.Launch(context.Background(), jobName, jobSpec)
 		if err != nil {
@@ -162,139 +239,454 @@ func (j *ProcessJobManager) Config() Config {
 	return j.conf
 }
 
+// Launch starts the job if a slot is available, and queues it otherwise. The job's priority
+// and workflow are read from `ctx`, see `WithPriority` and `WithWorkflowId`.
 func (j *ProcessJobManager) Launch(
 	ctx context.Context,
 	name string,
 	spec Spec,
 ) error {
+	priority := priorityFromContext(ctx)
+	if workflowSpec, ok := spec.(*WorkflowSpec); ok {
+		// The operator jobs of the run are queued with the priority of the run.
+		workflowSpec.Priority = priority
+	}
+
+	cmd, err := j.mapJobTypeToCmd(spec)
+	if err != nil {
+		return err
+	}
+	cmd.Env = jobEnvironment(spec)
+	if j.db != nil && spec.Type() == WorkflowJobType {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", JobNameEnvironmentVariable, name))
+	}
+
+	if err := os.MkdirAll(j.conf.LogDir, os.ModePerm); err != nil {
+		return errors.Wrap(err, "Unable to create job log directory.")
+	}
+
+	j.mutex.Lock()
 	if _, ok := j.cmds[name]; ok {
+		j.mutex.Unlock()
 		return ErrJobAlreadyExists
 	}
 
-	cmd, err := j.mapJobTypeToCmd(spec)
-	cmd.Env = os.Environ()
+	command := &Command{
+		cmd:        cmd,
+		spec:       spec,
+		priority:   priority,
+		workflowId: workflowIdFromContext(ctx),
+		takesSlot:  takesSlot(spec.Type()),
+		logStorage: logStorageFromContext(ctx),
+	}
+
+	if command.takesSlot {
+		acquired, err := j.slots.acquire(name, command)
+		if err != nil {
+			j.slots.release(name, command)
+			j.mutex.Unlock()
+			return errors.Wrap(err, "Unable to acquire a slot for the job.")
+		}
+
+		if !acquired {
+			log.Infof("Queueing job %s until a slot is available.", name)
+			command.status = shared.QueuedExecutionStatus
+			j.cmds[name] = command
+			j.queue.push(name, command)
+			j.mutex.Unlock()
+			return nil
+		}
+	}
+
+	err = j.startCommand(name, command)
 	if err != nil {
+		if command.takesSlot {
+			j.slots.release(name, command)
+		}
+		j.mutex.Unlock()
 		return err
 	}
+	j.mutex.Unlock()
 
-	j.cmds[name] = &Command{
-		cmd:    cmd,
-		stdout: &bytes.Buffer{},
-		stderr: &bytes.Buffer{},
+	if j.db != nil {
+		j.recordJob(ctx, name, spec, command)
 	}
-	cmd.Stdout = j.cmds[name].stdout
-	cmd.Stderr = j.cmds[name].stderr
 
-	return cmd.Start()
+	return nil
 }
 
+// startCommand starts the job's process and adds the job to `j.cmds`. A job that takes a slot
+// must already hold one. The caller must hold `j.mutex`.
+func (j *ProcessJobManager) startCommand(name string, command *Command) error {
+	logPath := filepath.Join(j.conf.LogDir, fmt.Sprintf("%s.log", name))
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return errors.Wrap(err, "Unable to create job log file.")
+	}
+
+	cmd := command.cmd
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	// Run the job in its own process group so that `Cancel` also stops any process
+	// the job spawns, e.g. the Python process started by the function executor script.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	if err := cmd.Start(); err != nil {
+		logFile.Close()
+		os.Remove(logPath)
+		return err
+	}
+
+	command.pid = cmd.Process.Pid
+	command.startTime = time.Now()
+	command.logPath = logPath
+	command.logFile = logFile
+	command.status = shared.PendingExecutionStatus
+	j.cmds[name] = command
+	if command.takesSlot {
+		j.slots.started(name, command)
+	}
+
+	if command.logStorage != nil {
+		command.logStreamer = newLogStreamer(command.logStorage, logPath)
+		go command.logStreamer.run()
+	}
+
+	if functionSpec, ok := command.spec.(*FunctionSpec); ok &&
+		functionSpec.ResourceLimits != nil && functionSpec.ResourceLimits.WallClockSeconds > 0 {
+		command.wallClockTimer = time.AfterFunc(
+			time.Duration(functionSpec.ResourceLimits.WallClockSeconds)*time.Second,
+			func() { j.stopOnWallClockLimit(name, command) },
+		)
+	}
+
+	go j.waitForJob(name, command)
+
+	return nil
+}
+
+// stopOnWallClockLimit stops the job's processes because the job exceeded its wall clock limit.
+func (j *ProcessJobManager) stopOnWallClockLimit(name string, command *Command) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if command.done || command.canceled {
+		return
+	}
+
+	command.wallClockExceeded = true
+	err := syscall.Kill(-command.pid, syscall.SIGKILL)
+	if err != nil && err != syscall.ESRCH {
+		log.Errorf("Unable to stop job %s after it exceeded its wall clock limit: %v", name, err)
+	}
+}
+
+// dispatchQueuedJobs starts the queued jobs that acquire a slot, in order of priority.
+// It returns the names of the started jobs. The caller must hold `j.mutex`.
+func (j *ProcessJobManager) dispatchQueuedJobs() []string {
+	started := []string{}
+	for i := 0; i < len(j.queue); {
+		queued := j.queue[i]
+		acquired, err := j.slots.acquire(queued.name, queued.command)
+		if err != nil {
+			log.Errorf("Unable to acquire a slot for queued job %s: %v", queued.name, err)
+		}
+		if !acquired {
+			i++
+			continue
+		}
+
+		j.queue = append(j.queue[:i], j.queue[i+1:]...)
+		if err := j.startCommand(queued.name, queued.command); err != nil {
+			log.Errorf("Unable to start queued job %s: %v", queued.name, err)
+			j.slots.release(queued.name, queued.command)
+			queued.command.done = true
+			queued.command.status = shared.FailedExecutionStatus
+			continue
+		}
+
+		started = append(started, queued.name)
+	}
+
+	return started
+}
+
+// waitForJob blocks until the job's process exits and then publishes its status and exit code.
+// The job's slot is then handed to the queued jobs.
+func (j *ProcessJobManager) waitForJob(name string, command *Command) {
+	err := command.cmd.Wait()
+	command.logFile.Close()
+	if command.logStreamer != nil {
+		command.logStreamer.close()
+	}
+	if command.wallClockTimer != nil {
+		command.wallClockTimer.Stop()
+	}
+
+	j.mutex.Lock()
+	canceled := command.canceled
+	wallClockExceeded := command.wallClockExceeded
+	j.mutex.Unlock()
+
+	// This is done before the job is marked as done, so the operator's metadata
+	// already records the exceeded limit when the job's status is polled.
+	var exceededLimit function.ResourceLimit
+	if functionSpec, ok := command.spec.(*FunctionSpec); ok && !canceled {
+		var limitErr error
+		exceededLimit, limitErr = checkResourceLimits(context.Background(), functionSpec, command, wallClockExceeded)
+		if limitErr != nil {
+			log.Errorf("Unable to check the resource limits of job %s: %v", name, limitErr)
+		}
+	}
+
+	j.mutex.Lock()
+	command.done = true
+	command.exitCode = command.cmd.ProcessState.ExitCode()
+
+	if command.canceled {
+		command.status = shared.CanceledExecutionStatus
+	} else if exceededLimit != "" {
+		log.Errorf("Job %s was stopped because it exceeded its %s limit.", name, exceededLimit)
+		command.status = shared.FailedExecutionStatus
+	} else if err != nil {
+		command.status = shared.FailedExecutionStatus
+
+		logs, readErr := os.ReadFile(command.logPath)
+		if readErr != nil {
+			log.Errorf("Unable to read the logs of job %s: %v", name, readErr)
+		}
+
+		log.Errorf(
+			"Unexpected error occured while executing the job %s (exit code %d): \nLogs: %s",
+			name,
+			command.exitCode,
+			string(logs),
+		)
+	} else {
+		command.status = shared.SucceededExecutionStatus
+	}
+
+	if command.takesSlot {
+		j.slots.release(name, command)
+	}
+	started := j.dispatchQueuedJobs()
+	j.mutex.Unlock()
+
+	j.recordJobs(started)
+}
+
+// recordJobs writes the job records of jobs started from the queue.
+func (j *ProcessJobManager) recordJobs(names []string) {
+	if j.db == nil {
+		return
+	}
+
+	for _, name := range names {
+		j.mutex.Lock()
+		command, ok := j.cmds[name]
+		j.mutex.Unlock()
+		if ok {
+			j.recordJob(context.Background(), name, command.spec, command)
+		}
+	}
+}
+
+// Poll returns the status of the job without blocking. Once the job is finished, its entry is
+// garbage collected, so later calls return `ErrJobNotExist`.
 func (j *ProcessJobManager) Poll(ctx context.Context, name string) (shared.ExecutionStatus, error) {
+	j.mutex.Lock()
 	command, ok := j.cmds[name]
 	if !ok {
+		j.mutex.Unlock()
 		return shared.UnknownExecutionStatus, ErrJobNotExist
 	}
 
-	proc, err := process.NewProcess(int32(command.cmd.Process.Pid))
-	if err != nil {
-		return shared.UnknownExecutionStatus, err
+	if !command.done {
+		// The job is either queued or running.
+		status := command.status
+		j.mutex.Unlock()
+		return status, nil
 	}
 
-	status, err := proc.Status()
-	if err != nil {
-		return shared.UnknownExecutionStatus, err
+	// We are done with this job and already consumed all of its output, so we garbage
+	// collect the entry in j.cmds.
+	delete(j.cmds, name)
+	status := command.status
+	j.mutex.Unlock()
+
+	j.cleanupJob(ctx, name, command)
+	return status, nil
+}
+
+// cleanupJob removes the log file and the job record of a finished job.
+func (j *ProcessJobManager) cleanupJob(ctx context.Context, name string, command *Command) {
+	if err := os.Remove(command.logPath); err != nil && !os.IsNotExist(err) {
+		log.Errorf("Unable to remove the log file of job %s: %v", name, err)
 	}
 
-	if status == processRunningStatus {
-		return shared.PendingExecutionStatus, nil
+	if j.db != nil {
+		if err := j.jobRecordWriter.DeleteJobRecord(ctx, name, j.db); err != nil {
+			log.Errorf("Unable to delete the job record of job %s: %v", name, err)
+		}
 	}
+}
 
-	err = command.cmd.Wait()
-	// After wait, we are done with this job and already consumed all of its output, so we garbage
-	// collect the entry in j.cmds.
-	defer delete(j.cmds, name)
-	if err != nil {
-		log.Errorf("Unexpected error occured while executing the job: \nStdout: %s\nStderr: %s",
-			command.stdout.String(),
-			command.stderr.String(),
-		)
+func (j *ProcessJobManager) Cancel(ctx context.Context, name string) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	command, ok := j.cmds[name]
+	if !ok {
+		return ErrJobNotExist
+	}
+
+	if command.canceled || command.done {
+		return nil
+	}
+
+	if j.queue.remove(name) {
+		// The job has not started yet, so there is no process to stop.
+		j.slots.release(name, command)
+		command.canceled = true
+		command.done = true
+		command.status = shared.CanceledExecutionStatus
+		return nil
+	}
 
-		return shared.FailedExecutionStatus, nil
+	// A negative pid sends the signal to every process in the job's process group.
+	err := syscall.Kill(-command.pid, syscall.SIGKILL)
+	if err != nil && err != syscall.ESRCH {
+		return errors.Wrapf(err, "Unable to cancel job %s.", name)
 	}
 
-	return shared.SucceededExecutionStatus, nil
+	command.canceled = true
+	return nil
 }
 
 func (j *ProcessJobManager) DeployCronJob(
 	ctx context.Context,
 	name string,
-	period string,
+	schedule CronSchedule,
 	spec Spec,
 ) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
 	if _, ok := j.cronMapping[name]; ok {
 		return errors.Newf("Cron job with name %s already exists", name)
 	}
 
-	j.cronMapping[name] = &cronMetadata{
-		cronJob: nil,
-		jobSpec: spec,
+	metadata := &cronMetadata{
+		schedule: schedule,
+		jobSpec:  spec,
 	}
+	j.cronMapping[name] = metadata
 
-	if period != "" {
-		cronJob, err := j.cronScheduler.Cron(period).Do(j.generateCronFunction(name, spec))
-		if err != nil {
-			return err
-		}
-
-		j.cronMapping[name].cronJob = cronJob
+	if schedule != nil {
+		j.scheduleCronJob(name, metadata, time.Now())
 	}
 
 	return nil
 }
 
 func (j *ProcessJobManager) CronJobExists(ctx context.Context, name string) bool {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
 	_, ok := j.cronMapping[name]
 	return ok
 }
 
-func (j *ProcessJobManager) EditCronJob(ctx context.Context, name string, cronString string) error {
+func (j *ProcessJobManager) EditCronJob(ctx context.Context, name string, schedule CronSchedule) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
 	cronMetadata, ok := j.cronMapping[name]
 	if !ok {
 		return errors.New("Cron job not found")
-	} else {
-		if cronMetadata.cronJob == nil {
-			// This means the current cron job is paused.
-			if cronString == "" {
-				return errors.Newf("Attempting to pause an already paused cron job %s", name)
-			}
-
-			cronJob, err := j.cronScheduler.Cron(cronString).Do(j.generateCronFunction(name, cronMetadata.jobSpec))
-			if err != nil {
-				return err
-			}
-
-			cronMetadata.cronJob = cronJob
-		} else {
-			if cronString == "" {
-				// This means we want to pause the cron job.
-				j.cronScheduler.RemoveByReference(cronMetadata.cronJob)
-				cronMetadata.cronJob = nil
-			} else {
-				_, err := j.cronScheduler.Job(cronMetadata.cronJob).Cron(cronString).Update()
-				if err != nil {
-					return err
-				}
-			}
-		}
-		return nil
 	}
+
+	if cronMetadata.schedule == nil && schedule == nil {
+		return errors.Newf("Attempting to pause an already paused cron job %s", name)
+	}
+
+	// A nil schedule pauses the cron job.
+	stopCronJob(cronMetadata)
+	cronMetadata.schedule = schedule
+	if schedule != nil {
+		j.scheduleCronJob(name, cronMetadata, time.Now())
+	}
+
+	return nil
 }
 
 func (j *ProcessJobManager) DeleteCronJob(ctx context.Context, name string) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
 	cronMetadata, ok := j.cronMapping[name]
 	if ok {
-		j.cronScheduler.RemoveByReference(cronMetadata.cronJob)
+		stopCronJob(cronMetadata)
 		delete(j.cronMapping, name)
 	}
 
 	return nil
 }
+
+// scheduleCronJob sets the timer of the cron job to its first launch time after `after`, plus
+// the jitter of its schedule. The caller must hold `j.mutex`.
+func (j *ProcessJobManager) scheduleCronJob(name string, cronMetadata *cronMetadata, after time.Time) {
+	cronMetadata.timer = nil
+
+	next := cronMetadata.schedule.Next(after)
+	if next.IsZero() {
+		log.Infof("Cron job %s will not launch again.", name)
+		return
+	}
+
+	generation := cronMetadata.generation
+	launch := j.generateCronFunction(cronMetadata.jobSpec)
+	cronMetadata.timer = time.AfterFunc(time.Until(next)+cronMetadata.schedule.Jitter(), func() {
+		j.mutex.Lock()
+		current, ok := j.cronMapping[name]
+		if !ok || current != cronMetadata || current.generation != generation {
+			// The cron job was edited or deleted after this timer was set.
+			j.mutex.Unlock()
+			return
+		}
+
+		// The next launch time is computed from the launch time rather than from now, so that
+		// a timer that fires early does not launch the job twice.
+		if now := time.Now(); now.After(next) {
+			next = now
+		}
+		j.scheduleCronJob(name, cronMetadata, next)
+
+		if cronMetadata.schedule.SkipIfRunning() && j.isActive(cronMetadata.lastJobName) {
+			log.Infof("Skipping cron job %s, since its job %s is still running.", name, cronMetadata.lastJobName)
+			j.mutex.Unlock()
+			return
+		}
+
+		jobName := fmt.Sprintf("%s-%d", name, time.Now().Unix())
+		cronMetadata.lastJobName = jobName
+		j.mutex.Unlock()
+
+		launch(jobName)
+	})
+}
+
+// isActive returns whether the job is queued or running. The caller must hold `j.mutex`.
+func (j *ProcessJobManager) isActive(name string) bool {
+	command, ok := j.cmds[name]
+	return ok && !command.done
+}
+
+// stopCronJob stops the timer of the cron job, if it has one. The caller must hold `j.mutex`.
+func stopCronJob(cronMetadata *cronMetadata) {
+	if cronMetadata.timer != nil {
+		cronMetadata.timer.Stop()
+		cronMetadata.timer = nil
+	}
+	cronMetadata.generation++
+}
@@ -2,9 +2,21 @@ package job
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
-	"github.com/go-co-op/gocron"
+	"github.com/aqueducthq/aqueduct/internal/migration"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/function"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -13,6 +25,23 @@ var (
 	dummyWorkflowSpec  = &WorkflowSpec{}
 )
 
+func mustCronSchedule(t *testing.T, cronString string) CronSchedule {
+	timing, err := (&workflow.Schedule{CronSchedule: workflow.CronString(cronString)}).Timing()
+	require.Nil(t, err)
+	return timing
+}
+
+// activeCronJobs returns the number of cron jobs that are waiting for their next launch.
+func activeCronJobs(jobManager *ProcessJobManager) int {
+	active := 0
+	for _, cronMetadata := range jobManager.cronMapping {
+		if cronMetadata.timer != nil {
+			active++
+		}
+	}
+	return active
+}
+
 func TestDeployCronJob(t *testing.T) {
 	jobManager, err := NewProcessJobManager(dummyProcessConfig)
 	require.Nil(t, err)
@@ -20,23 +49,23 @@ func TestDeployCronJob(t *testing.T) {
 	ctx := context.Background()
 
 	workflowName := "workflow"
-	cronString := "0 * * * *"
+	schedule := mustCronSchedule(t, "0 * * * *")
 
 	// Deploy an unpaused workflow.
-	err = jobManager.DeployCronJob(ctx, workflowName, cronString, dummyWorkflowSpec)
+	err = jobManager.DeployCronJob(ctx, workflowName, schedule, dummyWorkflowSpec)
 	require.Nil(t, err)
 	require.Equal(t, 1, len(jobManager.cronMapping))
-	require.NotEqual(t, (*gocron.Job)(nil), jobManager.cronMapping[workflowName].cronJob)
-	require.Equal(t, 1, len(jobManager.cronScheduler.Jobs()))
+	require.NotNil(t, jobManager.cronMapping[workflowName].timer)
+	require.Equal(t, 1, activeCronJobs(jobManager))
 
 	// Deploy a paused workflow.
 	pausedWorkflowName := "paused_workflow"
-	emptyCronString := ""
-	err = jobManager.DeployCronJob(ctx, pausedWorkflowName, emptyCronString, dummyWorkflowSpec)
+	var pausedSchedule CronSchedule
+	err = jobManager.DeployCronJob(ctx, pausedWorkflowName, pausedSchedule, dummyWorkflowSpec)
 	require.Nil(t, err)
 	require.Equal(t, 2, len(jobManager.cronMapping))
-	require.Equal(t, (*gocron.Job)(nil), jobManager.cronMapping[pausedWorkflowName].cronJob)
-	require.Equal(t, 1, len(jobManager.cronScheduler.Jobs()))
+	require.Nil(t, jobManager.cronMapping[pausedWorkflowName].timer)
+	require.Equal(t, 1, activeCronJobs(jobManager))
 }
 
 func TestEditCronJob(t *testing.T) {
@@ -47,33 +76,33 @@ func TestEditCronJob(t *testing.T) {
 
 	workflowName := "workflow"
 	pausedWorkflowName := "paused_workflow"
-	cronString := "0 * * * *"
-	newCronString := "1 * * * *"
-	emptyCronString := ""
+	schedule := mustCronSchedule(t, "0 * * * *")
+	newSchedule := mustCronSchedule(t, "1 * * * *")
+	var pausedSchedule CronSchedule
 
-	jobManager.DeployCronJob(ctx, workflowName, cronString, dummyWorkflowSpec)
-	jobManager.DeployCronJob(ctx, pausedWorkflowName, emptyCronString, dummyWorkflowSpec)
+	jobManager.DeployCronJob(ctx, workflowName, schedule, dummyWorkflowSpec)
+	jobManager.DeployCronJob(ctx, pausedWorkflowName, pausedSchedule, dummyWorkflowSpec)
 
 	// Edit an unpaused workflow to another schedule.
-	err = jobManager.EditCronJob(ctx, workflowName, newCronString)
+	err = jobManager.EditCronJob(ctx, workflowName, newSchedule)
 	require.Nil(t, err)
 	require.Equal(t, 2, len(jobManager.cronMapping))
-	require.NotEqual(t, (*gocron.Job)(nil), jobManager.cronMapping[workflowName].cronJob)
-	require.Equal(t, 1, len(jobManager.cronScheduler.Jobs()))
+	require.NotNil(t, jobManager.cronMapping[workflowName].timer)
+	require.Equal(t, 1, activeCronJobs(jobManager))
 
 	// Edit an unpaused workflow to paused.
-	err = jobManager.EditCronJob(ctx, workflowName, emptyCronString)
+	err = jobManager.EditCronJob(ctx, workflowName, pausedSchedule)
 	require.Nil(t, err)
 	require.Equal(t, 2, len(jobManager.cronMapping))
-	require.Equal(t, (*gocron.Job)(nil), jobManager.cronMapping[workflowName].cronJob)
-	require.Equal(t, 0, len(jobManager.cronScheduler.Jobs()))
+	require.Nil(t, jobManager.cronMapping[workflowName].timer)
+	require.Equal(t, 0, activeCronJobs(jobManager))
 
 	// Edit a paused workflow to unpaused.
-	err = jobManager.EditCronJob(ctx, pausedWorkflowName, cronString)
+	err = jobManager.EditCronJob(ctx, pausedWorkflowName, schedule)
 	require.Nil(t, err)
 	require.Equal(t, 2, len(jobManager.cronMapping))
-	require.NotEqual(t, (*gocron.Job)(nil), jobManager.cronMapping[pausedWorkflowName].cronJob)
-	require.Equal(t, 1, len(jobManager.cronScheduler.Jobs()))
+	require.NotNil(t, jobManager.cronMapping[pausedWorkflowName].timer)
+	require.Equal(t, 1, activeCronJobs(jobManager))
 }
 
 func TestDeleteCronJob(t *testing.T) {
@@ -83,11 +112,798 @@ func TestDeleteCronJob(t *testing.T) {
 	ctx := context.Background()
 
 	workflowName := "workflow"
-	cronString := "0 * * * *"
+	schedule := mustCronSchedule(t, "0 * * * *")
 
-	jobManager.DeployCronJob(ctx, workflowName, cronString, dummyWorkflowSpec)
+	jobManager.DeployCronJob(ctx, workflowName, schedule, dummyWorkflowSpec)
 	err = jobManager.DeleteCronJob(ctx, workflowName)
 	require.Nil(t, err)
 	require.Equal(t, 0, len(jobManager.cronMapping))
-	require.Equal(t, 0, len(jobManager.cronScheduler.Jobs()))
+	require.Equal(t, 0, activeCronJobs(jobManager))
+}
+
+// onceSchedule launches a cron job once, at `at`.
+type onceSchedule struct {
+	at time.Time
+}
+
+func (s onceSchedule) Next(after time.Time) time.Time {
+	if after.Before(s.at) {
+		return s.at
+	}
+	return time.Time{}
+}
+
+func (onceSchedule) Jitter() time.Duration {
+	return 0
+}
+
+func (onceSchedule) SkipIfRunning() bool {
+	return false
+}
+
+func TestCronJobLaunches(t *testing.T) {
+	jobManager := newTestFunctionJobManager(t, "exit 0\n")
+
+	ctx := context.Background()
+	cronName := "function-cron"
+
+	err := jobManager.DeployCronJob(ctx, cronName, onceSchedule{at: time.Now().Add(50 * time.Millisecond)}, &FunctionSpec{})
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		jobManager.mutex.Lock()
+		defer jobManager.mutex.Unlock()
+		return len(jobManager.cmds) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The schedule does not launch the job again.
+	jobManager.mutex.Lock()
+	defer jobManager.mutex.Unlock()
+	require.Nil(t, jobManager.cronMapping[cronName].timer)
+}
+
+// launchTimesSchedule launches a cron job at each of `times`, and skips launches while the
+// previous job is running if `skipIfRunning` is set.
+type launchTimesSchedule struct {
+	times         []time.Time
+	skipIfRunning bool
+}
+
+func (s launchTimesSchedule) Next(after time.Time) time.Time {
+	for _, at := range s.times {
+		if after.Before(at) {
+			return at
+		}
+	}
+	return time.Time{}
+}
+
+func (launchTimesSchedule) Jitter() time.Duration {
+	return 0
+}
+
+func (s launchTimesSchedule) SkipIfRunning() bool {
+	return s.skipIfRunning
+}
+
+func TestCronJobSkipsWhileRunning(t *testing.T) {
+	jobManager := newTestFunctionJobManager(t, "sleep 60 & wait\n")
+
+	ctx := context.Background()
+	cronName := "function-cron"
+
+	// The launches are more than a second apart, so that they get different job names.
+	now := time.Now()
+	schedule := launchTimesSchedule{
+		times:         []time.Time{now.Add(50 * time.Millisecond), now.Add(1200 * time.Millisecond)},
+		skipIfRunning: true,
+	}
+	err := jobManager.DeployCronJob(ctx, cronName, schedule, &FunctionSpec{})
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		jobManager.mutex.Lock()
+		defer jobManager.mutex.Unlock()
+		return jobManager.cronMapping[cronName].timer == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The second launch was skipped, since the job of the first launch is still running.
+	jobManager.mutex.Lock()
+	require.Equal(t, 1, len(jobManager.cmds))
+	jobName := jobManager.cronMapping[cronName].lastJobName
+	jobManager.mutex.Unlock()
+
+	require.Nil(t, jobManager.Cancel(ctx, jobName))
+	_, err = PollJob(ctx, jobName, jobManager, 10*time.Millisecond, 5*time.Second)
+	require.Nil(t, err)
+}
+
+func TestNewScheduledSpec(t *testing.T) {
+	spec := &WorkflowSpec{WorkflowId: "workflow"}
+	scheduled := NewScheduledSpec(spec).(*WorkflowSpec)
+	require.True(t, scheduled.Scheduled)
+	require.Equal(t, "workflow", scheduled.WorkflowId)
+	require.False(t, spec.Scheduled)
+
+	functionSpec := &FunctionSpec{}
+	require.Equal(t, functionSpec, NewScheduledSpec(functionSpec))
+}
+
+func TestPausedCronJobDoesNotLaunch(t *testing.T) {
+	jobManager := newTestFunctionJobManager(t, "exit 0\n")
+
+	ctx := context.Background()
+	cronName := "function-cron"
+
+	err := jobManager.DeployCronJob(ctx, cronName, onceSchedule{at: time.Now().Add(50 * time.Millisecond)}, &FunctionSpec{})
+	require.Nil(t, err)
+
+	err = jobManager.EditCronJob(ctx, cronName, nil)
+	require.Nil(t, err)
+
+	time.Sleep(200 * time.Millisecond)
+	jobManager.mutex.Lock()
+	defer jobManager.mutex.Unlock()
+	require.Equal(t, 0, len(jobManager.cmds))
+}
+
+// newTestFunctionJobManager returns a job manager whose function executor script
+// is replaced by `script`, so that function jobs can be launched without Python.
+func newTestFunctionJobManager(t *testing.T, script string) *ProcessJobManager {
+	return newTestFunctionJobManagerWithLimits(t, script, 0, 0)
+}
+
+// newTestFunctionJobManagerWithLimits is like `newTestFunctionJobManager`, but with the given
+// concurrency limits.
+func newTestFunctionJobManagerWithLimits(
+	t *testing.T,
+	script string,
+	maxJobs int,
+	maxJobsPerWorkflow int,
+) *ProcessJobManager {
+	binaryDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(binaryDir, functionExecutorBashScript), []byte(script), 0o755)
+	require.Nil(t, err)
+
+	jobManager, err := NewProcessJobManager(&ProcessConfig{
+		BinaryDir:                    binaryDir,
+		OperatorStorageDir:           t.TempDir(),
+		LogDir:                       t.TempDir(),
+		MaxConcurrentJobs:            maxJobs,
+		MaxConcurrentJobsPerWorkflow: maxJobsPerWorkflow,
+	})
+	require.Nil(t, err)
+
+	return jobManager
+}
+
+func TestCancelJob(t *testing.T) {
+	jobManager := newTestFunctionJobManager(t, "sleep 60 & wait\n")
+
+	ctx := context.Background()
+	jobName := "function-operator-cancel"
+
+	err := jobManager.Launch(ctx, jobName, &FunctionSpec{})
+	require.Nil(t, err)
+
+	err = jobManager.Cancel(ctx, jobName)
+	require.Nil(t, err)
+
+	status, err := PollJob(ctx, jobName, jobManager, 10*time.Millisecond, 5*time.Second)
+	require.Nil(t, err)
+	require.Equal(t, shared.CanceledExecutionStatus, status)
+
+	err = jobManager.Cancel(ctx, jobName)
+	require.Equal(t, ErrJobNotExist, err)
+}
+
+func TestIsProcessAlive(t *testing.T) {
+	jobManager := newTestFunctionJobManager(t, "sleep 60\n")
+
+	ctx := context.Background()
+	jobName := "function-operator-alive"
+
+	err := jobManager.Launch(ctx, jobName, &FunctionSpec{})
+	require.Nil(t, err)
+
+	command := jobManager.cmds[jobName]
+	require.True(t, isProcessAlive(command.pid, command.startTime))
+	// A start time that does not match the process creation time means the pid was reused.
+	require.False(t, isProcessAlive(command.pid, command.startTime.Add(-time.Hour)))
+
+	err = jobManager.Cancel(ctx, jobName)
+	require.Nil(t, err)
+
+	_, err = PollJob(ctx, jobName, jobManager, 10*time.Millisecond, 5*time.Second)
+	require.Nil(t, err)
+	require.False(t, isProcessAlive(command.pid, command.startTime))
+}
+
+func TestPollSleepingJob(t *testing.T) {
+	jobManager := newTestFunctionJobManager(t, "sleep 0.5\n")
+
+	ctx := context.Background()
+	jobName := "function-operator-sleeping"
+
+	err := jobManager.Launch(ctx, jobName, &FunctionSpec{})
+	require.Nil(t, err)
+
+	// The job is sleeping, so it must be reported as pending without blocking until it exits.
+	start := time.Now()
+	status, err := jobManager.Poll(ctx, jobName)
+	require.Nil(t, err)
+	require.Equal(t, shared.PendingExecutionStatus, status)
+	require.Less(t, int64(time.Since(start)), int64(100*time.Millisecond))
+
+	status, err = PollJob(ctx, jobName, jobManager, 10*time.Millisecond, 5*time.Second)
+	require.Nil(t, err)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+
+	_, err = jobManager.Poll(ctx, jobName)
+	require.Equal(t, ErrJobNotExist, err)
+}
+
+func TestFailedJobExitCode(t *testing.T) {
+	jobManager := newTestFunctionJobManager(t, "exit 3\n")
+
+	ctx := context.Background()
+	jobName := "function-operator-failed"
+
+	err := jobManager.Launch(ctx, jobName, &FunctionSpec{})
+	require.Nil(t, err)
+
+	command := jobManager.cmds[jobName]
+
+	status, err := PollJob(ctx, jobName, jobManager, 10*time.Millisecond, 5*time.Second)
+	require.Nil(t, err)
+	require.Equal(t, shared.FailedExecutionStatus, status)
+	require.Equal(t, 3, command.exitCode)
+}
+
+// This test is meant to be run with the race detector. It launches and polls jobs while
+// cron jobs are deployed, edited and deleted, as the preview and cron paths do.
+func TestConcurrentJobsAndCronJobs(t *testing.T) {
+	jobManager := newTestFunctionJobManager(t, "sleep 0.1\n")
+
+	ctx := context.Background()
+	numJobs := 10
+
+	var wg sync.WaitGroup
+	for i := 0; i < numJobs; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			jobName := fmt.Sprintf("function-operator-%d", i)
+			err := jobManager.Launch(ctx, jobName, &FunctionSpec{})
+			require.Nil(t, err)
+
+			status, err := PollJob(ctx, jobName, jobManager, 10*time.Millisecond, 5*time.Second)
+			require.Nil(t, err)
+			require.Equal(t, shared.SucceededExecutionStatus, status)
+		}(i)
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			cronName := fmt.Sprintf("workflow-%d", i)
+			err := jobManager.DeployCronJob(ctx, cronName, mustCronSchedule(t, "0 * * * *"), dummyWorkflowSpec)
+			require.Nil(t, err)
+			require.True(t, jobManager.CronJobExists(ctx, cronName))
+
+			err = jobManager.EditCronJob(ctx, cronName, mustCronSchedule(t, "1 * * * *"))
+			require.Nil(t, err)
+
+			err = jobManager.DeleteCronJob(ctx, cronName)
+			require.Nil(t, err)
+		}(i)
+	}
+
+	wg.Wait()
+	require.Equal(t, 0, len(jobManager.cmds))
+	require.Equal(t, 0, len(jobManager.cronMapping))
+}
+
+func TestQueuedJob(t *testing.T) {
+	jobManager := newTestFunctionJobManagerWithLimits(t, "sleep 0.3\n", 1, 0)
+
+	ctx := context.Background()
+	runningJobName := "function-operator-running"
+	queuedJobName := "function-operator-queued"
+
+	err := jobManager.Launch(ctx, runningJobName, &FunctionSpec{})
+	require.Nil(t, err)
+
+	err = jobManager.Launch(ctx, queuedJobName, &FunctionSpec{})
+	require.Nil(t, err)
+
+	status, err := jobManager.Poll(ctx, queuedJobName)
+	require.Nil(t, err)
+	require.Equal(t, shared.QueuedExecutionStatus, status)
+
+	// The queued job is started once the running job exits.
+	status, err = PollJob(ctx, queuedJobName, jobManager, 10*time.Millisecond, 5*time.Second)
+	require.Nil(t, err)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+
+	status, err = PollJob(ctx, runningJobName, jobManager, 10*time.Millisecond, 5*time.Second)
+	require.Nil(t, err)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+}
+
+func TestQueuedJobPriority(t *testing.T) {
+	jobManager := newTestFunctionJobManagerWithLimits(t, "sleep 0.3\n", 1, 0)
+
+	ctx := context.Background()
+
+	err := jobManager.Launch(ctx, "running", &FunctionSpec{})
+	require.Nil(t, err)
+
+	err = jobManager.Launch(WithPriority(ctx, BackfillPriority), "backfill", &FunctionSpec{})
+	require.Nil(t, err)
+	err = jobManager.Launch(ctx, "scheduled-1", &FunctionSpec{})
+	require.Nil(t, err)
+	err = jobManager.Launch(WithPriority(ctx, PreviewPriority), "preview", &FunctionSpec{})
+	require.Nil(t, err)
+	err = jobManager.Launch(ctx, "scheduled-2", &FunctionSpec{})
+	require.Nil(t, err)
+
+	jobManager.mutex.Lock()
+	queuedJobNames := make([]string, 0, len(jobManager.queue))
+	for _, queued := range jobManager.queue {
+		queuedJobNames = append(queuedJobNames, queued.name)
+	}
+	jobManager.mutex.Unlock()
+
+	require.Equal(t, []string{"preview", "scheduled-1", "scheduled-2", "backfill"}, queuedJobNames)
+
+	for _, jobName := range []string{"running", "preview", "scheduled-1", "scheduled-2", "backfill"} {
+		err = jobManager.Cancel(ctx, jobName)
+		require.Nil(t, err)
+	}
+}
+
+func TestPerWorkflowLimit(t *testing.T) {
+	jobManager := newTestFunctionJobManagerWithLimits(t, "sleep 0.3\n", 0, 1)
+
+	ctx := context.Background()
+	workflowCtx := WithWorkflowId(ctx, "workflow")
+	otherWorkflowCtx := WithWorkflowId(ctx, "other-workflow")
+
+	err := jobManager.Launch(workflowCtx, "workflow-running", &FunctionSpec{})
+	require.Nil(t, err)
+	err = jobManager.Launch(workflowCtx, "workflow-queued", &FunctionSpec{})
+	require.Nil(t, err)
+	err = jobManager.Launch(otherWorkflowCtx, "other-workflow-running", &FunctionSpec{})
+	require.Nil(t, err)
+
+	status, err := jobManager.Poll(ctx, "workflow-queued")
+	require.Nil(t, err)
+	require.Equal(t, shared.QueuedExecutionStatus, status)
+
+	status, err = jobManager.Poll(ctx, "other-workflow-running")
+	require.Nil(t, err)
+	require.Equal(t, shared.PendingExecutionStatus, status)
+
+	status, err = PollJob(ctx, "workflow-queued", jobManager, 10*time.Millisecond, 5*time.Second)
+	require.Nil(t, err)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+}
+
+func TestCancelQueuedJob(t *testing.T) {
+	jobManager := newTestFunctionJobManagerWithLimits(t, "sleep 0.3\n", 1, 0)
+
+	ctx := context.Background()
+	runningJobName := "function-operator-running"
+	queuedJobName := "function-operator-queued"
+
+	err := jobManager.Launch(ctx, runningJobName, &FunctionSpec{})
+	require.Nil(t, err)
+	err = jobManager.Launch(ctx, queuedJobName, &FunctionSpec{})
+	require.Nil(t, err)
+
+	err = jobManager.Cancel(ctx, queuedJobName)
+	require.Nil(t, err)
+
+	status, err := jobManager.Poll(ctx, queuedJobName)
+	require.Nil(t, err)
+	require.Equal(t, shared.CanceledExecutionStatus, status)
+
+	status, err = PollJob(ctx, runningJobName, jobManager, 10*time.Millisecond, 5*time.Second)
+	require.Nil(t, err)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+	require.Equal(t, 0, len(jobManager.queue))
+}
+
+// newTestSlotDatabase returns a metadata database for job managers that share their slots.
+func newTestSlotDatabase(t *testing.T) database.Database {
+	db, err := database.NewSqliteDatabase(&database.SqliteConfig{File: filepath.Join(t.TempDir(), "aqueduct.db")})
+	require.Nil(t, err)
+	t.Cleanup(db.Close)
+
+	err = migration.GoTo(context.Background(), 20, db)
+	require.Nil(t, err)
+
+	return db
+}
+
+// newTestSharedSlotJobManager returns a job manager that shares its slots via `db`, as the
+// server and each workflow executor do.
+func newTestSharedSlotJobManager(
+	t *testing.T,
+	db database.Database,
+	maxJobs int,
+	maxJobsPerWorkflow int,
+) *ProcessJobManager {
+	jobManager := newTestFunctionJobManagerWithLimits(t, "sleep 0.5\n", maxJobs, maxJobsPerWorkflow)
+	err := jobManager.EnableSharedSlots(db)
+	require.Nil(t, err)
+
+	return jobManager
+}
+
+// waitUntilStarted waits until the job is no longer queued.
+func waitUntilStarted(t *testing.T, jobManager *ProcessJobManager, name string) {
+	require.Eventually(t, func() bool {
+		jobManager.mutex.Lock()
+		defer jobManager.mutex.Unlock()
+		return jobManager.cmds[name].status != shared.QueuedExecutionStatus
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestSharedSlots(t *testing.T) {
+	db := newTestSlotDatabase(t)
+	jobManager := newTestSharedSlotJobManager(t, db, 1, 0)
+	otherJobManager := newTestSharedSlotJobManager(t, db, 1, 0)
+
+	ctx := context.Background()
+	err := jobManager.Launch(ctx, "running", &FunctionSpec{})
+	require.Nil(t, err)
+	err = otherJobManager.Launch(ctx, "queued", &FunctionSpec{})
+	require.Nil(t, err)
+
+	// The limit also holds for the jobs of the other job manager.
+	status, err := otherJobManager.Poll(ctx, "queued")
+	require.Nil(t, err)
+	require.Equal(t, shared.QueuedExecutionStatus, status)
+
+	status, err = PollJob(ctx, "running", jobManager, 10*time.Millisecond, 5*time.Second)
+	require.Nil(t, err)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+
+	status, err = PollJob(ctx, "queued", otherJobManager, 10*time.Millisecond, 5*time.Second)
+	require.Nil(t, err)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+}
+
+func TestSharedSlotsPriority(t *testing.T) {
+	db := newTestSlotDatabase(t)
+	jobManager := newTestSharedSlotJobManager(t, db, 1, 0)
+	otherJobManager := newTestSharedSlotJobManager(t, db, 1, 0)
+
+	ctx := context.Background()
+	err := jobManager.Launch(ctx, "running", &FunctionSpec{})
+	require.Nil(t, err)
+	err = otherJobManager.Launch(WithPriority(ctx, BackfillPriority), "backfill", &FunctionSpec{})
+	require.Nil(t, err)
+	err = jobManager.Launch(WithPriority(ctx, PreviewPriority), "preview", &FunctionSpec{})
+	require.Nil(t, err)
+
+	// The preview is started first, although the backfill was queued earlier.
+	waitUntilStarted(t, jobManager, "preview")
+	status, err := otherJobManager.Poll(ctx, "backfill")
+	require.Nil(t, err)
+	require.Equal(t, shared.QueuedExecutionStatus, status)
+
+	status, err = PollJob(ctx, "backfill", otherJobManager, 10*time.Millisecond, 5*time.Second)
+	require.Nil(t, err)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+}
+
+func TestSharedPerWorkflowLimit(t *testing.T) {
+	db := newTestSlotDatabase(t)
+	jobManager := newTestSharedSlotJobManager(t, db, 0, 1)
+	otherJobManager := newTestSharedSlotJobManager(t, db, 0, 1)
+
+	// The job managers run overlapping runs of the same workflow.
+	ctx := context.Background()
+	workflowCtx := WithWorkflowId(ctx, uuid.New().String())
+	otherWorkflowCtx := WithWorkflowId(ctx, uuid.New().String())
+
+	err := jobManager.Launch(workflowCtx, "workflow-running", &FunctionSpec{})
+	require.Nil(t, err)
+	err = otherJobManager.Launch(workflowCtx, "workflow-queued", &FunctionSpec{})
+	require.Nil(t, err)
+	err = otherJobManager.Launch(otherWorkflowCtx, "other-workflow-running", &FunctionSpec{})
+	require.Nil(t, err)
+
+	status, err := otherJobManager.Poll(ctx, "workflow-queued")
+	require.Nil(t, err)
+	require.Equal(t, shared.QueuedExecutionStatus, status)
+
+	status, err = otherJobManager.Poll(ctx, "other-workflow-running")
+	require.Nil(t, err)
+	require.Equal(t, shared.PendingExecutionStatus, status)
+
+	status, err = PollJob(ctx, "workflow-queued", otherJobManager, 10*time.Millisecond, 5*time.Second)
+	require.Nil(t, err)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+}
+
+// newTestLimitedFunctionSpec returns a function spec with the given resource limits, whose
+// operator metadata is written to a temporary directory.
+func newTestLimitedFunctionSpec(t *testing.T, limits *function.ResourceLimits) *FunctionSpec {
+	return &FunctionSpec{
+		basePythonSpec: basePythonSpec{
+			StorageConfig: shared.StorageConfig{
+				Type:       shared.FileStorageType,
+				FileConfig: &shared.FileConfig{Directory: t.TempDir()},
+			},
+			MetadataPath: "operator-metadata",
+		},
+		ResourceLimits: limits,
+	}
+}
+
+func readTestOperatorMetadata(t *testing.T, spec *FunctionSpec) operator_result.Metadata {
+	serializedMetadata, err := os.ReadFile(filepath.Join(spec.StorageConfig.FileConfig.Directory, spec.MetadataPath))
+	require.Nil(t, err)
+
+	var metadata operator_result.Metadata
+	err = json.Unmarshal(serializedMetadata, &metadata)
+	require.Nil(t, err)
+
+	return metadata
+}
+
+// testMemoryTraceback is the traceback of a Python process that ran out of memory.
+const testMemoryTraceback = `Traceback (most recent call last):
+  File "main.py", line 3, in <module>
+    data = bytearray(10 ** 12)
+MemoryError
+`
+
+func TestResourceLimits(t *testing.T) {
+	tests := []struct {
+		name          string
+		script        string
+		limits        *function.ResourceLimits
+		exceededLimit function.ResourceLimit
+	}{
+		{
+			name:          "wall-clock",
+			script:        "sleep 60\n",
+			limits:        &function.ResourceLimits{WallClockSeconds: 1},
+			exceededLimit: function.WallClockResourceLimit,
+		},
+		{
+			name:          "cpu-time",
+			script:        "while :; do :; done\n",
+			limits:        &function.ResourceLimits{CpuSeconds: 1},
+			exceededLimit: function.CpuTimeResourceLimit,
+		},
+		{
+			// The executor script does not exit with the status of the process that was stopped.
+			name:          "cpu-time-child",
+			script:        "(while :; do :; done)\n",
+			limits:        &function.ResourceLimits{CpuSeconds: 1},
+			exceededLimit: function.CpuTimeResourceLimit,
+		},
+		{
+			name:          "memory",
+			script:        "cat >&2 <<EOF\n" + testMemoryTraceback + "EOF\nexit 1\n",
+			limits:        &function.ResourceLimits{MemoryMB: 512},
+			exceededLimit: function.MemoryResourceLimit,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			jobManager := newTestFunctionJobManager(t, test.script)
+
+			ctx := context.Background()
+			jobName := fmt.Sprintf("function-operator-%s", test.name)
+			spec := newTestLimitedFunctionSpec(t, test.limits)
+
+			err := jobManager.Launch(ctx, jobName, spec)
+			require.Nil(t, err)
+
+			status, err := PollJob(ctx, jobName, jobManager, 10*time.Millisecond, 10*time.Second)
+			require.Nil(t, err)
+			require.Equal(t, shared.FailedExecutionStatus, status)
+
+			metadata := readTestOperatorMetadata(t, spec)
+			require.Equal(t, test.exceededLimit, metadata.ExceededLimit)
+			require.NotEmpty(t, metadata.Error)
+		})
+	}
+}
+
+func TestResourceLimitsNotExceeded(t *testing.T) {
+	jobManager := newTestFunctionJobManager(t, "ulimit -v > /dev/null\n")
+
+	ctx := context.Background()
+	jobName := "function-operator-within-limits"
+	spec := newTestLimitedFunctionSpec(t, &function.ResourceLimits{
+		MemoryMB:         512,
+		CpuSeconds:       10,
+		WallClockSeconds: 10,
+	})
+
+	err := jobManager.Launch(ctx, jobName, spec)
+	require.Nil(t, err)
+
+	status, err := PollJob(ctx, jobName, jobManager, 10*time.Millisecond, 5*time.Second)
+	require.Nil(t, err)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+
+	_, err = os.Stat(filepath.Join(spec.StorageConfig.FileConfig.Directory, spec.MetadataPath))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestMemoryErrorOnlyInLogs(t *testing.T) {
+	// The job fails with another error, after it handled a MemoryError.
+	script := "echo 'Retrying after MemoryError'\ncat >&2 <<EOF\n" +
+		"Traceback (most recent call last):\n" +
+		"  File \"main.py\", line 5, in <module>\n" +
+		"    raise ValueError(\"bad input\")\n" +
+		"ValueError: bad input\n" +
+		"EOF\nexit 1\n"
+	jobManager := newTestFunctionJobManager(t, script)
+
+	ctx := context.Background()
+	jobName := "function-operator-memory-error-in-logs"
+	spec := newTestLimitedFunctionSpec(t, &function.ResourceLimits{MemoryMB: 512})
+
+	err := jobManager.Launch(ctx, jobName, spec)
+	require.Nil(t, err)
+
+	status, err := PollJob(ctx, jobName, jobManager, 10*time.Millisecond, 5*time.Second)
+	require.Nil(t, err)
+	require.Equal(t, shared.FailedExecutionStatus, status)
+
+	_, err = os.Stat(filepath.Join(spec.StorageConfig.FileConfig.Directory, spec.MetadataPath))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestFinalTracebackException(t *testing.T) {
+	chained := "Traceback (most recent call last):\n" +
+		"  File \"main.py\", line 3, in <module>\n" +
+		"ValueError: bad input\n\n" +
+		"During handling of the above exception, another exception occurred:\n\n" +
+		testMemoryTraceback
+	require.Equal(t, "MemoryError", finalTracebackException(chained))
+	require.Equal(t, "", finalTracebackException("MemoryError\n"))
+
+	memoryExceptions := []string{
+		"MemoryError",
+		"numpy.core._exceptions._ArrayMemoryError: Unable to allocate 7.45 GiB",
+		"OSError: [Errno 12] Cannot allocate memory",
+	}
+	for _, exception := range memoryExceptions {
+		require.True(t, isMemoryException(exception), exception)
+	}
+
+	otherExceptions := []string{
+		"",
+		"ValueError: MemoryError",
+		"OSError: [Errno 2] No such file or directory",
+	}
+	for _, exception := range otherExceptions {
+		require.False(t, isMemoryException(exception), exception)
+	}
+}
+
+func TestFunctionJobEnvironment(t *testing.T) {
+	os.Setenv("AQUEDUCT_TEST_SECRET", "secret")
+	defer os.Unsetenv("AQUEDUCT_TEST_SECRET")
+	os.Setenv("AWS_PROFILE", "aqueduct-test")
+	defer os.Unsetenv("AWS_PROFILE")
+	os.Setenv(JobNameEnvironmentVariable, "workflow-parent")
+	defer os.Unsetenv(JobNameEnvironmentVariable)
+
+	// The script writes its environment next to itself.
+	jobManager := newTestFunctionJobManager(t, "env > \"$(dirname \"$0\")/env\"\n")
+
+	ctx := context.Background()
+	jobName := "function-operator-environment"
+
+	err := jobManager.Launch(ctx, jobName, &FunctionSpec{})
+	require.Nil(t, err)
+
+	status, err := PollJob(ctx, jobName, jobManager, 10*time.Millisecond, 5*time.Second)
+	require.Nil(t, err)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+
+	env, err := os.ReadFile(filepath.Join(jobManager.conf.BinaryDir, "env"))
+	require.Nil(t, err)
+	require.Contains(t, string(env), "PATH=")
+	require.NotContains(t, string(env), "AQUEDUCT_TEST_SECRET")
+	// The credentials of the S3 storage are passed on.
+	require.Contains(t, string(env), "AWS_PROFILE=aqueduct-test")
+	// The job is not the job of the executor that launched it.
+	require.NotContains(t, string(env), JobNameEnvironmentVariable)
+}
+
+func TestLogStorage(t *testing.T) {
+	jobManager := newTestFunctionJobManager(t, "echo first\nsleep 3\necho second\n")
+
+	storageDir := t.TempDir()
+	storageConfig := &shared.StorageConfig{
+		Type:       shared.FileStorageType,
+		FileConfig: &shared.FileConfig{Directory: storageDir},
+	}
+	logKey := "operator-logs"
+
+	ctx := context.Background()
+	jobName := "function-operator-logs"
+
+	err := jobManager.Launch(WithLogStorage(ctx, storageConfig, logKey), jobName, &FunctionSpec{})
+	require.Nil(t, err)
+
+	// The log is streamed to storage while the job runs.
+	var logs []byte
+	require.Eventually(t, func() bool {
+		logs, err = os.ReadFile(filepath.Join(storageDir, logKey))
+		return err == nil
+	}, 2*logFlushInterval, 10*time.Millisecond)
+	require.Equal(t, "first\n", string(logs))
+
+	status, err := jobManager.Poll(ctx, jobName)
+	require.Nil(t, err)
+	require.Equal(t, shared.PendingExecutionStatus, status)
+
+	// The complete log is in storage once the job exits.
+	status, err = PollJob(ctx, jobName, jobManager, 10*time.Millisecond, 5*time.Second)
+	require.Nil(t, err)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+
+	logs, err = os.ReadFile(filepath.Join(storageDir, logKey))
+	require.Nil(t, err)
+	require.Equal(t, "first\nsecond\n", string(logs))
+}
+
+func TestLogFlushInterval(t *testing.T) {
+	require.Equal(t, logFlushInterval, flushInterval(-1))
+	require.Equal(t, logFlushInterval, flushInterval(logFlushBackoffSize-1))
+	require.Equal(t, 2*logFlushInterval, flushInterval(logFlushBackoffSize))
+	require.Equal(t, maxLogFlushInterval, flushInterval(1<<40))
+}
+
+func TestLogStreamerOnlyCopiesGrownLog(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "job.log")
+	require.Nil(t, os.WriteFile(logPath, []byte("first\n"), 0o644))
+
+	storageDir := t.TempDir()
+	streamer := newLogStreamer(&logStorageDestination{
+		storageConfig: &shared.StorageConfig{
+			Type:       shared.FileStorageType,
+			FileConfig: &shared.FileConfig{Directory: storageDir},
+		},
+		key: "operator-logs",
+	}, logPath)
+	storedPath := filepath.Join(storageDir, "operator-logs")
+
+	streamer.flush()
+	logs, err := os.ReadFile(storedPath)
+	require.Nil(t, err)
+	require.Equal(t, "first\n", string(logs))
+
+	// A log that has not grown is not copied again.
+	require.Nil(t, os.Remove(storedPath))
+	streamer.flush()
+	_, err = os.Stat(storedPath)
+	require.True(t, os.IsNotExist(err))
+
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.Nil(t, err)
+	_, err = f.WriteString("second\n")
+	require.Nil(t, err)
+	require.Nil(t, f.Close())
+
+	streamer.flush()
+	logs, err = os.ReadFile(storedPath)
+	require.Nil(t, err)
+	require.Equal(t, "first\nsecond\n", string(logs))
 }
@@ -0,0 +1,80 @@
+package job
+
+import (
+	"context"
+)
+
+// Priority determines the order in which queued jobs are started. Jobs with a higher priority
+// are started first, and jobs with the same priority are started in the order they were launched.
+type Priority int
+
+const (
+	BackfillPriority Priority = iota
+	ScheduledPriority
+	PreviewPriority
+)
+
+type contextKey string
+
+const (
+	priorityContextKey   contextKey = "priority"
+	workflowIdContextKey contextKey = "workflowId"
+)
+
+// WithPriority returns a context that makes `Launch` queue jobs with the given priority.
+// Jobs launched without a priority get `ScheduledPriority`.
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityContextKey, priority)
+}
+
+// WithWorkflowId returns a context that makes `Launch` count jobs against the per-workflow
+// limit of the given workflow, e.g. the operator jobs of a workflow run.
+func WithWorkflowId(ctx context.Context, workflowId string) context.Context {
+	return context.WithValue(ctx, workflowIdContextKey, workflowId)
+}
+
+func priorityFromContext(ctx context.Context) Priority {
+	if priority, ok := ctx.Value(priorityContextKey).(Priority); ok {
+		return priority
+	}
+
+	return ScheduledPriority
+}
+
+func workflowIdFromContext(ctx context.Context) string {
+	if workflowId, ok := ctx.Value(workflowIdContextKey).(string); ok {
+		return workflowId
+	}
+
+	return ""
+}
+
+type queuedJob struct {
+	name    string
+	command *Command
+}
+
+// jobQueue holds the jobs waiting for a slot, ordered by priority and then by launch order.
+type jobQueue []queuedJob
+
+func (q *jobQueue) push(name string, command *Command) {
+	i := len(*q)
+	for i > 0 && (*q)[i-1].command.priority < command.priority {
+		i--
+	}
+
+	*q = append(*q, queuedJob{})
+	copy((*q)[i+1:], (*q)[i:])
+	(*q)[i] = queuedJob{name: name, command: command}
+}
+
+func (q *jobQueue) remove(name string) bool {
+	for i, job := range *q {
+		if job.name == name {
+			*q = append((*q)[:i], (*q)[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
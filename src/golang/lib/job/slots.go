@@ -0,0 +1,230 @@
+package job
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/job_slot"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// How often a job manager that shares its slots retries its queued jobs, since their slots may
+// be freed by the jobs of other processes.
+const sharedSlotPollInterval = time.Second
+
+// slotTable hands out the slots that bound the number of jobs that run at the same time, in
+// total and per workflow. Only jobs that take a slot, see `takesSlot`, use it.
+type slotTable interface {
+	// acquire takes a slot for the job and returns whether one was available to it.
+	acquire(name string, command *Command) (bool, error)
+	// started records the process of a job that holds a slot.
+	started(name string, command *Command)
+	// release frees the slot of the job, or gives up its place in line if it is still queued.
+	release(name string, command *Command)
+}
+
+// takesSlot returns whether jobs of the given type count against the concurrency limits. Workflow
+// jobs do not, since the operator jobs of their runs do, and a workflow job that held a slot
+// while its operator jobs wait for one could prevent them from ever getting one.
+func takesSlot(jobType JobType) bool {
+	return jobType != WorkflowJobType && jobType != WorkflowRetentionType
+}
+
+// localSlots keeps track of the slots of a single job manager. It is used by job managers that
+// do not share their slots via `EnableSharedSlots`. A limit of 0 means there is no limit.
+type localSlots struct {
+	maxJobs            int
+	maxJobsPerWorkflow int
+	// A mapping from the name of each job that holds a slot to its workflow.
+	holders            map[string]string
+	runningPerWorkflow map[string]int
+}
+
+func newLocalSlots(maxJobs int, maxJobsPerWorkflow int) *localSlots {
+	return &localSlots{
+		maxJobs:            maxJobs,
+		maxJobsPerWorkflow: maxJobsPerWorkflow,
+		holders:            map[string]string{},
+		runningPerWorkflow: map[string]int{},
+	}
+}
+
+func (s *localSlots) acquire(name string, command *Command) (bool, error) {
+	if s.maxJobs > 0 && len(s.holders) >= s.maxJobs {
+		return false, nil
+	}
+
+	workflowId := command.workflowId
+	if workflowId != "" && s.maxJobsPerWorkflow > 0 && s.runningPerWorkflow[workflowId] >= s.maxJobsPerWorkflow {
+		return false, nil
+	}
+
+	s.holders[name] = workflowId
+	if workflowId != "" {
+		s.runningPerWorkflow[workflowId]++
+	}
+
+	return true, nil
+}
+
+func (*localSlots) started(name string, command *Command) {}
+
+func (s *localSlots) release(name string, command *Command) {
+	workflowId, ok := s.holders[name]
+	if !ok {
+		return
+	}
+
+	delete(s.holders, name)
+	if workflowId != "" {
+		s.runningPerWorkflow[workflowId]--
+		if s.runningPerWorkflow[workflowId] <= 0 {
+			delete(s.runningPerWorkflow, workflowId)
+		}
+	}
+}
+
+// sharedSlots hands out the slots of the job_slot table, which are shared by the job managers
+// of the server and of every workflow executor, so the limits bound the jobs of all of them
+// and queued jobs are started in order of priority across runs.
+type sharedSlots struct {
+	maxJobs            int
+	maxJobsPerWorkflow int
+	// The process of the job manager, which owns the rows of its queued jobs.
+	pid       int
+	startTime time.Time
+	// The jobs of the job manager that have a row in the table.
+	requested map[string]bool
+
+	db            database.Database
+	jobSlotReader job_slot.Reader
+	jobSlotWriter job_slot.Writer
+}
+
+func (s *sharedSlots) acquire(name string, command *Command) (bool, error) {
+	ctx := context.Background()
+	if !s.requested[name] {
+		var workflowId *uuid.UUID
+		if id, err := uuid.Parse(command.workflowId); err == nil {
+			workflowId = &id
+		}
+
+		_, err := s.jobSlotWriter.CreateJobSlot(
+			ctx,
+			name,
+			workflowId,
+			int(command.priority),
+			s.pid,
+			s.startTime,
+			s.db,
+		)
+		if err != nil {
+			return false, err
+		}
+		s.requested[name] = true
+	}
+
+	return s.jobSlotWriter.AcquireJobSlot(ctx, name, s.maxJobs, s.maxJobsPerWorkflow, s.db)
+}
+
+// started hands the job's row over to the job's process, so that the slot stays held while the
+// job runs, even if this process exits first.
+func (s *sharedSlots) started(name string, command *Command) {
+	err := s.jobSlotWriter.UpdateJobSlotOwner(context.Background(), name, command.pid, command.startTime, s.db)
+	if err != nil {
+		log.Errorf("Unable to record the process of job %s in its slot: %v", name, err)
+	}
+}
+
+func (s *sharedSlots) release(name string, command *Command) {
+	// The job may also hold a slot acquired by a previous run of this job manager, e.g. if it
+	// was reattached by `Reconcile`.
+	delete(s.requested, name)
+	if err := s.jobSlotWriter.DeleteJobSlot(context.Background(), name, s.db); err != nil {
+		log.Errorf("Unable to release the slot of job %s: %v", name, err)
+	}
+}
+
+// releaseStaleSlots releases the slots whose owner is gone, e.g. a job that was killed along
+// with the executor that launched it, or a job manager that exited while its jobs were queued.
+func (s *sharedSlots) releaseStaleSlots() {
+	ctx := context.Background()
+	jobSlots, err := s.jobSlotReader.GetJobSlots(ctx, s.db)
+	if err != nil {
+		log.Errorf("Unable to read the job slots: %v", err)
+		return
+	}
+
+	for _, jobSlot := range jobSlots {
+		if isProcessAlive(jobSlot.Pid, jobSlot.StartTime) {
+			continue
+		}
+
+		log.Infof("The owner of the slot of job %s is no longer running, releasing the slot.", jobSlot.Name)
+		if err := s.jobSlotWriter.DeleteJobSlot(ctx, jobSlot.Name, s.db); err != nil {
+			log.Errorf("Unable to release the slot of job %s: %v", jobSlot.Name, err)
+		}
+	}
+}
+
+// EnableSharedSlots makes the job manager take the slots of its jobs from the metadata database,
+// so that its concurrency limits are shared with every other job manager that uses the same
+// database. It must be called before the job manager launches any job.
+func (j *ProcessJobManager) EnableSharedSlots(db database.Database) error {
+	jobSlotReader, err := job_slot.NewReader(db.Config())
+	if err != nil {
+		return err
+	}
+
+	jobSlotWriter, err := job_slot.NewWriter(db.Config())
+	if err != nil {
+		return err
+	}
+
+	pid := os.Getpid()
+	startTime, err := processStartTime(pid)
+	if err != nil {
+		return err
+	}
+
+	slots := &sharedSlots{
+		maxJobs:            j.conf.MaxConcurrentJobs,
+		maxJobsPerWorkflow: j.conf.MaxConcurrentJobsPerWorkflow,
+		pid:                pid,
+		startTime:          startTime,
+		requested:          map[string]bool{},
+		db:                 db,
+		jobSlotReader:      jobSlotReader,
+		jobSlotWriter:      jobSlotWriter,
+	}
+
+	j.mutex.Lock()
+	j.slots = slots
+	j.mutex.Unlock()
+
+	go j.pollSharedSlots(slots)
+	return nil
+}
+
+// pollSharedSlots periodically starts the queued jobs whose slots were freed by other processes.
+func (j *ProcessJobManager) pollSharedSlots(slots *sharedSlots) {
+	for range time.Tick(sharedSlotPollInterval) {
+		j.mutex.Lock()
+		queued := len(j.queue)
+		j.mutex.Unlock()
+		if queued == 0 {
+			continue
+		}
+
+		slots.releaseStaleSlots()
+
+		j.mutex.Lock()
+		started := j.dispatchQueuedJobs()
+		j.mutex.Unlock()
+
+		j.recordJobs(started)
+	}
+}
@@ -5,16 +5,20 @@ import (
 	"encoding/base64"
 	"encoding/gob"
 	"encoding/json"
+	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/collections/artifact"
 	"github.com/aqueducthq/aqueduct/lib/collections/integration"
 	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
 	"github.com/aqueducthq/aqueduct/lib/database"
 	"github.com/aqueducthq/aqueduct/lib/vault"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/auth"
 	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/github"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/function"
 	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
 )
 
 var (
@@ -43,6 +47,7 @@ const (
 	ExtractJobType        JobType = "extract"
 	LoadJobType           JobType = "load"
 	DiscoverJobType       JobType = "discover"
+	SensorJobType         JobType = "sensor"
 	WorkflowRetentionType JobType = "workflow_retention"
 )
 
@@ -75,6 +80,35 @@ type WorkflowSpec struct {
 	WorkflowId     string               `json:"workflow_id" yaml:"workflowId"`
 	GithubManager  github.ManagerConfig `json:"github_manager" yaml:"github_manager"`
 	ExecutorConfig *ExecutorConfiguration
+	// The operators to run, if this is a partial run of the workflow.
+	Selection *workflow_dag.OperatorSelection `json:"selection,omitempty" yaml:"selection,omitempty"`
+	// The failed workflow dag result to resume, if this run resumes one.
+	ResumedFrom *uuid.UUID `json:"resumed_from,omitempty" yaml:"resumed_from,omitempty"`
+	// The values that override the registered values of the param operators in this run.
+	Parameters shared.Parameters `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	// The workflow dag result that was created for this run when it was requested, if any.
+	WorkflowDagResultId *uuid.UUID `json:"workflow_dag_result_id,omitempty" yaml:"workflow_dag_result_id,omitempty"`
+	// Whether the run was launched by the cron schedule of the workflow. The overlap policy of
+	// the workflow only applies to scheduled runs.
+	Scheduled bool `json:"scheduled,omitempty" yaml:"scheduled,omitempty"`
+	// The sub-workflow operator that launched this run, if any.
+	Parent *ParentOperator `json:"parent,omitempty" yaml:"parent,omitempty"`
+	// The priority the job was launched with. The operator jobs of the run are queued with it.
+	Priority Priority `json:"priority,omitempty" yaml:"priority,omitempty"`
+}
+
+// ParentOperator is a sub-workflow operator of another workflow run. The run it launches is
+// recorded as a child of its run. When the child run finishes, it copies the artifacts the
+// operator outputs to the operator's output paths, and writes the operator's metadata.
+type ParentOperator struct {
+	WorkflowDagResultId uuid.UUID            `json:"workflow_dag_result_id" yaml:"workflow_dag_result_id"`
+	StorageConfig       shared.StorageConfig `json:"storage_config" yaml:"storage_config"`
+	MetadataPath        string               `json:"metadata_path" yaml:"metadata_path"`
+	// The names of the artifacts of the child run that are output by the operator, in the
+	// order of the operator's outputs.
+	OutputArtifactNames []string `json:"output_artifact_names" yaml:"output_artifact_names"`
+	OutputContentPaths  []string `json:"output_content_paths" yaml:"output_content_paths"`
+	OutputMetadataPaths []string `json:"output_metadata_paths" yaml:"output_metadata_paths"`
 }
 
 // basePythonSpec defines fields shared by all Python job specs.
@@ -100,6 +134,8 @@ type FunctionSpec struct {
 	OutputMetadataPaths []string        `json:"output_metadata_paths"  yaml:"output_metadata_paths"`
 	InputArtifactTypes  []artifact.Type `json:"input_artifact_types"  yaml:"input_artifact_types"`
 	OutputArtifactTypes []artifact.Type `json:"output_artifact_types"  yaml:"output_artifact_types"`
+	// The limits are enforced by the job manager, so they are not passed to the executor.
+	ResourceLimits *function.ResourceLimits `json:"-"  yaml:"-"`
 }
 
 type ParamSpec struct {
@@ -127,6 +163,17 @@ type LoadSpec struct {
 	InputMetadataPath string               `json:"input_metadata_path"  yaml:"input_metadata_path"`
 }
 
+type SensorSpec struct {
+	basePythonSpec
+	ConnectorName       integration.Service `json:"connector_name"  yaml:"connector_name"`
+	ConnectorConfig     auth.Config         `json:"connector_config"  yaml:"connector_config"`
+	Query               string              `json:"query"  yaml:"query"`
+	PollIntervalSeconds float64             `json:"poll_interval_seconds"  yaml:"poll_interval_seconds"`
+	DeadlineSeconds     float64             `json:"deadline_seconds"  yaml:"deadline_seconds"`
+	OutputContentPath   string              `json:"output_content_path"  yaml:"output_content_path"`
+	OutputMetadataPath  string              `json:"output_metadata_path"  yaml:"output_metadata_path"`
+}
+
 type AuthenticateSpec struct {
 	basePythonSpec
 	ConnectorName   integration.Service `json:"connector_name"  yaml:"connector_name"`
@@ -172,6 +219,10 @@ func (*DiscoverSpec) Type() JobType {
 	return DiscoverJobType
 }
 
+func (*SensorSpec) Type() JobType {
+	return SensorJobType
+}
+
 // NewWorkflowRetentionSpec constructs a Spec for a WorkflowRetentionJob.
 func NewWorkflowRetentionJobSpec(
 	database *database.DatabaseConfig,
@@ -200,6 +251,10 @@ func NewWorkflowSpec(
 	vault vault.Config,
 	jobManager Config,
 	githubManager github.ManagerConfig,
+	selection *workflow_dag.OperatorSelection,
+	resumedFrom *uuid.UUID,
+	parameters shared.Parameters,
+	workflowDagResultId *uuid.UUID,
 ) Spec {
 	return &WorkflowSpec{
 		baseSpec: baseSpec{
@@ -213,9 +268,27 @@ func NewWorkflowSpec(
 			Vault:      vault,
 			JobManager: jobManager,
 		},
+		Selection:   selection,
+		ResumedFrom: resumedFrom,
+		Parameters:  parameters,
+
+		WorkflowDagResultId: workflowDagResultId,
 	}
 }
 
+// NewScheduledSpec returns a copy of the spec that marks the run as scheduled, if the spec is a
+// WorkflowSpec. The executor enforces the overlap policy of the workflow on scheduled runs.
+func NewScheduledSpec(spec Spec) Spec {
+	workflowSpec, ok := spec.(*WorkflowSpec)
+	if !ok {
+		return spec
+	}
+
+	scheduledSpec := *workflowSpec
+	scheduledSpec.Scheduled = true
+	return &scheduledSpec
+}
+
 // NewFunctionSpec constructs a Spec for a FunctionJob.
 func NewFunctionSpec(
 	name string,
@@ -232,6 +305,7 @@ func NewFunctionSpec(
 	outputMetadataPaths []string,
 	inputArtifactTypes []artifact.Type,
 	outputArtifactTypes []artifact.Type,
+	resourceLimits *function.ResourceLimits,
 ) Spec {
 	return &FunctionSpec{
 		basePythonSpec: basePythonSpec{
@@ -253,6 +327,7 @@ func NewFunctionSpec(
 		OutputMetadataPaths: outputMetadataPaths,
 		InputArtifactTypes:  inputArtifactTypes,
 		OutputArtifactTypes: outputArtifactTypes,
+		ResourceLimits:      resourceLimits,
 	}
 }
 
@@ -356,6 +431,38 @@ func NewLoadSpec(
 	}
 }
 
+// NewSensorSpec constructs a Spec for a SensorJob.
+func NewSensorSpec(
+	name string,
+	storageConfig *shared.StorageConfig,
+	metadataPath string,
+	connectorName integration.Service,
+	connectorConfig auth.Config,
+	query string,
+	pollInterval time.Duration,
+	deadline time.Duration,
+	outputContentPath string,
+	outputMetadataPath string,
+) Spec {
+	return &SensorSpec{
+		basePythonSpec: basePythonSpec{
+			baseSpec: baseSpec{
+				Type: SensorJobType,
+				Name: name,
+			},
+			StorageConfig: *storageConfig,
+			MetadataPath:  metadataPath,
+		},
+		ConnectorName:       connectorName,
+		ConnectorConfig:     connectorConfig,
+		Query:               query,
+		PollIntervalSeconds: pollInterval.Seconds(),
+		DeadlineSeconds:     deadline.Seconds(),
+		OutputContentPath:   outputContentPath,
+		OutputMetadataPath:  outputMetadataPath,
+	}
+}
+
 // NewDiscoverSpec constructs a Spec for a DiscoverJob.
 func NewDiscoverSpec(
 	name string,
@@ -432,6 +539,8 @@ func DecodeSpec(specData string, serializationType SerializationType) (Spec, err
 			spec = &LoadSpec{}
 		case DiscoverJobType:
 			spec = &DiscoverSpec{}
+		case SensorJobType:
+			spec = &SensorSpec{}
 		default:
 			return nil, errors.Newf("Unknown job type: %v", base.Type)
 		}
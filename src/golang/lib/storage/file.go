@@ -2,8 +2,12 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/aqueducthq/aqueduct/lib/collections/shared"
 )
@@ -36,6 +40,71 @@ func (f *fileStorage) Delete(ctx context.Context, key string) error {
 	return os.Remove(f.getFullPath(key))
 }
 
+func (f *fileStorage) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := os.Stat(f.getFullPath(key))
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return false, err
+}
+
+func (f *fileStorage) List(ctx context.Context, prefix string) ([]Object, error) {
+	// Only the directory that contains every key with the prefix is walked, i.e. the prefix up
+	// to its last slash.
+	root := f.fileConfig.Directory
+	if idx := strings.LastIndex(prefix, "/"); idx >= 0 {
+		root = filepath.Join(root, filepath.FromSlash(prefix[:idx]))
+	}
+
+	var objects []Object
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipDir
+			}
+			return err
+		}
+
+		relPath, err := filepath.Rel(f.fileConfig.Directory, path)
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(relPath)
+		if entry.IsDir() {
+			// Skip the directories that cannot contain a key with the prefix.
+			if path != root && !strings.HasPrefix(key+"/", prefix) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, Object{
+			Key:        key,
+			Version:    fmt.Sprintf("%d-%d", info.ModTime().UnixNano(), info.Size()),
+			ModifiedAt: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
 func (f *fileStorage) getFullPath(key string) string {
 	return fmt.Sprintf("%s/%s", f.fileConfig.Directory, key)
 }
@@ -3,16 +3,24 @@ package storage
 import (
 	"bytes"
 	"context"
+	"net/http"
 
 	"github.com/aqueducthq/aqueduct/lib/collections/shared"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 )
 
+// The region used to look up the region of a bucket.
+const defaultS3Region = "us-east-1"
+
 type s3Storage struct {
 	s3Config *shared.S3Config
+	// The credentials to access the bucket with. The server's credentials are used if it is nil.
+	credentials *credentials.Credentials
 }
 
 func newS3Storage(s3Config *shared.S3Config) *s3Storage {
@@ -21,8 +29,39 @@ func newS3Storage(s3Config *shared.S3Config) *s3Storage {
 	}
 }
 
+// NewS3IntegrationStorage returns a Storage for a bucket that is accessed with the credentials
+// of an S3 integration rather than the server's.
+func NewS3IntegrationStorage(
+	ctx context.Context,
+	bucket string,
+	accessKeyId string,
+	secretAccessKey string,
+) (Storage, error) {
+	creds := credentials.NewStaticCredentials(accessKeyId, secretAccessKey, "")
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String(defaultS3Region),
+		Credentials: creds,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	region, err := s3manager.GetBucketRegion(ctx, sess, bucket, defaultS3Region)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Storage{
+		s3Config: &shared.S3Config{
+			Region: region,
+			Bucket: bucket,
+		},
+		credentials: creds,
+	}, nil
+}
+
 func (s *s3Storage) Get(ctx context.Context, key string) ([]byte, error) {
-	sess, err := CreateS3Session(s.s3Config.Region)
+	sess, err := s.createSession()
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +82,7 @@ func (s *s3Storage) Get(ctx context.Context, key string) ([]byte, error) {
 }
 
 func (s *s3Storage) Put(ctx context.Context, key string, value []byte) error {
-	sess, err := CreateS3Session(s.s3Config.Region)
+	sess, err := s.createSession()
 	if err != nil {
 		return err
 	}
@@ -65,7 +104,7 @@ func (s *s3Storage) Put(ctx context.Context, key string, value []byte) error {
 }
 
 func (s *s3Storage) Delete(ctx context.Context, key string) error {
-	sess, err := CreateS3Session(s.s3Config.Region)
+	sess, err := s.createSession()
 	if err != nil {
 		return err
 	}
@@ -81,6 +120,72 @@ func (s *s3Storage) Delete(ctx context.Context, key string) error {
 	return err
 }
 
+func (s *s3Storage) Exists(ctx context.Context, key string) (bool, error) {
+	sess, err := s.createSession()
+	if err != nil {
+		return false, err
+	}
+
+	s3Client := s3.New(sess)
+	_, err = s3Client.HeadObjectWithContext(
+		ctx,
+		&s3.HeadObjectInput{
+			Bucket: aws.String(s.s3Config.Bucket),
+			Key:    aws.String(key),
+		},
+	)
+	if err != nil {
+		if awsErr, ok := err.(awserr.RequestFailure); ok && awsErr.StatusCode() == http.StatusNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *s3Storage) List(ctx context.Context, prefix string) ([]Object, error) {
+	sess, err := s.createSession()
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []Object
+	s3Client := s3.New(sess)
+	err = s3Client.ListObjectsV2PagesWithContext(
+		ctx,
+		&s3.ListObjectsV2Input{
+			Bucket: aws.String(s.s3Config.Bucket),
+			Prefix: aws.String(prefix),
+		},
+		func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+			for _, object := range page.Contents {
+				objects = append(objects, Object{
+					Key:        aws.StringValue(object.Key),
+					Version:    aws.StringValue(object.ETag),
+					ModifiedAt: aws.TimeValue(object.LastModified),
+				})
+			}
+			return true
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+func (s *s3Storage) createSession() (*session.Session, error) {
+	if s.credentials == nil {
+		return CreateS3Session(s.s3Config.Region)
+	}
+
+	return session.NewSession(&aws.Config{
+		Region:      aws.String(s.s3Config.Region),
+		Credentials: s.credentials,
+	})
+}
+
 func CreateS3Session(awsRegion string) (*session.Session, error) {
 	sess, err := session.NewSession(&aws.Config{
 		Region: aws.String(awsRegion),
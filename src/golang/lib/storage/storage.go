@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/aqueducthq/aqueduct/lib/collections/shared"
 )
@@ -11,6 +12,18 @@ type Storage interface {
 	Get(ctx context.Context, key string) ([]byte, error)
 	Put(ctx context.Context, key string, value []byte) error
 	Delete(ctx context.Context, key string) error
+	// Exists returns whether there is a value for the key, without reading it.
+	Exists(ctx context.Context, key string) (bool, error)
+	// List returns the objects whose keys start with the prefix.
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// Object is a listed key along with a version that changes whenever its value is overwritten:
+// the ETag of an S3 object, or the modification time and size of a file.
+type Object struct {
+	Key        string
+	Version    string
+	ModifiedAt time.Time
 }
 
 func NewStorage(config *shared.StorageConfig) Storage {
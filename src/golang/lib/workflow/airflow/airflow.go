@@ -0,0 +1,442 @@
+package airflow
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact"
+	"github.com/aqueducthq/aqueduct/lib/collections/integration"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow/scheduler"
+	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+// Airflow ids may only contain alphanumeric characters, dashes, dots and underscores.
+var invalidIdCharacters = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+var dagTemplate = template.Must(template.New("dag").Parse(
+	`# This file was generated by Aqueduct from the workflow {{ .WorkflowName }} ({{ .WorkflowId }}).
+#
+# Each task runs one operator of the workflow with the Aqueduct executor, so the Airflow workers
+# need the executor installed and access to the storage the workflow writes its artifacts to.
+{{- if .Integrations }}
+#
+# This file does not contain the credentials of the integrations the workflow uses. Each task
+# that connects to an integration reads them when it runs from an Airflow Variable holding the
+# integration's credentials as a JSON object, so create these Variables before running the DAG:
+{{- range .Integrations }}
+#   {{ .Variable }}: the {{ .Service }} integration {{ .Id }}
+{{- end }}
+# The tasks pass the credentials to the executor in their environment, which needs Airflow 2.3
+# or later.
+{{- end }}
+import datetime
+
+import pendulum
+from airflow import DAG
+from airflow.operators.bash import BashOperator
+
+with DAG(
+    dag_id={{ .DagId }},
+    description={{ .Description }},
+    schedule_interval={{ .ScheduleInterval }},
+    start_date=pendulum.datetime(2022, 1, 1, tz={{ .Timezone }}),
+    catchup=False,
+    # Every run writes the artifacts of the workflow to the same storage paths.
+    max_active_runs=1,
+    is_paused_upon_creation={{ .Paused }},
+) as dag:
+{{- range .Tasks }}
+    {{ .Variable }} = BashOperator(
+        task_id={{ .TaskId }},
+        bash_command={{ .Command }},
+{{- if .Env }}
+        env={{ .Env }},
+        append_env=True,
+{{- end }}
+    )
+{{- end }}
+{{ if .Edges }}
+{{ range .Edges }}    {{ .From }} >> {{ .To }}
+{{ end }}{{ end -}}
+`))
+
+type dagFile struct {
+	WorkflowName     string
+	WorkflowId       string
+	DagId            string
+	Description      string
+	ScheduleInterval string
+	Timezone         string
+	Paused           string
+	Integrations     []integrationVariable
+	Tasks            []task
+	Edges            []edge
+}
+
+type integrationVariable struct {
+	Variable string
+	Service  string
+	Id       string
+}
+
+type task struct {
+	Variable string
+	TaskId   string
+	Command  string
+	Env      string
+}
+
+type edge struct {
+	From string
+	To   string
+}
+
+// The environment variable from which the connector executor reads the credentials of the
+// integration, instead of from its spec.
+const connectorConfigEnvVar = "AQUEDUCT_CONNECTOR_CONFIG"
+
+// integrationSecretVariable returns the name of the Airflow Variable that holds the credentials
+// of the integration. The name contains "secret", so that Airflow masks its value.
+func integrationSecretVariable(integrationId uuid.UUID) string {
+	return fmt.Sprintf("aqueduct_integration_secret_%s", strings.ReplaceAll(integrationId.String(), "-", "_"))
+}
+
+// integrationSecrets stands in for the vault while compiling, so that the credentials of the
+// integrations are never read into the DAG file. It gives every integration an empty config,
+// which the task replaces when it runs, and records which integrations were requested.
+type integrationSecrets struct {
+	vault.Vault
+	integrationIds []uuid.UUID
+}
+
+func (s *integrationSecrets) Get(ctx context.Context, name string) (map[string]string, error) {
+	integrationId, err := uuid.Parse(name)
+	if err != nil {
+		return nil, errors.Newf("Unexpected secret %s.", name)
+	}
+
+	s.integrationIds = append(s.integrationIds, integrationId)
+	return map[string]string{}, nil
+}
+
+// specRecorder records the spec of each job instead of launching it.
+type specRecorder struct {
+	job.JobManager
+	specs []job.Spec
+}
+
+func (r *specRecorder) Launch(ctx context.Context, name string, spec job.Spec) error {
+	r.specs = append(r.specs, spec)
+	return nil
+}
+
+// Compile generates an Airflow DAG file that runs the given workflow dag. Each operator becomes
+// a task that runs the same command `jobManager` would run for it, the edges of the workflow dag
+// become task dependencies, and the cron schedule of a periodic workflow becomes the schedule
+// of the DAG. The artifacts are written to the storage configured for the workflow dag. The
+// credentials of the integrations are left out of the file: the tasks read them from Airflow
+// Variables when they run.
+func Compile(
+	ctx context.Context,
+	dag *workflow_dag.WorkflowDag,
+	jobManager *job.ProcessJobManager,
+) ([]byte, error) {
+	if dag.Metadata == nil {
+		return nil, errors.New("Workflow dag is missing its workflow metadata.")
+	}
+
+	operatorIds := make([]uuid.UUID, 0, len(dag.Operators))
+	for id := range dag.Operators {
+		operatorIds = append(operatorIds, id)
+	}
+	sort.Slice(operatorIds, func(i, j int) bool {
+		a, b := dag.Operators[operatorIds[i]], dag.Operators[operatorIds[j]]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Id.String() < b.Id.String()
+	})
+
+	storagePaths := utils.GenerateWorkflowStoragePaths(dag)
+
+	variables := make(map[uuid.UUID]string, len(operatorIds))
+	producers := map[uuid.UUID]uuid.UUID{}
+	taskIds := map[string]bool{}
+	integrations := []integrationVariable{}
+	integrationVariables := map[uuid.UUID]bool{}
+	tasks := make([]task, 0, len(operatorIds))
+	for i, id := range operatorIds {
+		op := dag.Operators[id]
+
+		// Airflow cannot skip a task or fan it out based on the content of an artifact, cannot
+		// record a run of another workflow as part of the run, and cannot wait for an approval.
+		if op.Spec.RunCondition() != nil {
+			return nil, errors.Newf("Operator %s has a run condition, which cannot run on Airflow.", op.Name)
+		}
+		if op.Spec.Map() != nil {
+			return nil, errors.Newf("Operator %s is a map operator, which cannot run on Airflow.", op.Name)
+		}
+		if op.Spec.IsSubWorkflow() {
+			return nil, errors.Newf("Operator %s runs another workflow, which cannot run on Airflow.", op.Name)
+		}
+		if op.Spec.IsApproval() {
+			return nil, errors.Newf("Operator %s awaits approval, which cannot run on Airflow.", op.Name)
+		}
+
+		secrets := &integrationSecrets{}
+		command, err := operatorCommand(ctx, dag, op, storagePaths, jobManager, secrets)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Unable to compile operator %s.", op.Name)
+		}
+
+		env := ""
+		if len(secrets.integrationIds) > 1 {
+			return nil, errors.Newf("Operator %s uses more than one integration.", op.Name)
+		}
+		for _, integrationId := range secrets.integrationIds {
+			variable := integrationSecretVariable(integrationId)
+			env = fmt.Sprintf(
+				"{%s: %s}",
+				strconv.Quote(connectorConfigEnvVar),
+				strconv.Quote(fmt.Sprintf("{{ var.value.get('%s') }}", variable)),
+			)
+
+			if !integrationVariables[integrationId] {
+				integrationVariables[integrationId] = true
+				integrations = append(integrations, integrationVariable{
+					Variable: variable,
+					Service:  string(integrationService(op)),
+					Id:       integrationId.String(),
+				})
+			}
+		}
+
+		taskId := sanitizeId(op.Name)
+		if taskIds[taskId] {
+			taskId = fmt.Sprintf("%s_%s", taskId, op.Id.String()[:8])
+		}
+		taskIds[taskId] = true
+
+		variables[id] = fmt.Sprintf("t%d", i)
+		for _, outputId := range op.Outputs {
+			producers[outputId] = id
+		}
+
+		tasks = append(tasks, task{
+			Variable: variables[id],
+			TaskId:   strconv.Quote(taskId),
+			Command:  strconv.Quote(command),
+			Env:      env,
+		})
+	}
+
+	edges := []edge{}
+	for _, id := range operatorIds {
+		upstream := map[uuid.UUID]bool{}
+		for _, inputId := range dag.Operators[id].Inputs {
+			producerId, ok := producers[inputId]
+			if !ok {
+				return nil, errors.Newf("Cannot find the operator that produces artifact %v.", inputId)
+			}
+			upstream[producerId] = true
+		}
+
+		for _, upstreamId := range operatorIds {
+			if upstream[upstreamId] {
+				edges = append(edges, edge{From: variables[upstreamId], To: variables[id]})
+			}
+		}
+	}
+
+	scheduleInterval, err := airflowScheduleInterval(&dag.Metadata.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := dag.Metadata.Schedule
+	timezone := schedule.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	paused := "False"
+	if schedule.Paused {
+		paused = "True"
+	}
+
+	var buf bytes.Buffer
+	err = dagTemplate.Execute(&buf, &dagFile{
+		WorkflowName:     strconv.Quote(dag.Metadata.Name),
+		WorkflowId:       dag.WorkflowId.String(),
+		DagId:            strconv.Quote(sanitizeId(fmt.Sprintf("aqueduct_%s", dag.Metadata.Name))),
+		Description:      strconv.Quote(dag.Metadata.Description),
+		ScheduleInterval: scheduleInterval,
+		Timezone:         strconv.Quote(timezone),
+		Paused:           paused,
+		Integrations:     integrations,
+		Tasks:            tasks,
+		Edges:            edges,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to generate the Airflow DAG file.")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// airflowScheduleInterval returns the Python expression of the Airflow schedule interval of a
+// workflow with the given schedule. Airflow has no blackout windows, so a schedule with blackout
+// windows cannot be compiled. The jitter of the schedule is dropped.
+func airflowScheduleInterval(schedule *workflow.Schedule) (string, error) {
+	if schedule.Trigger != workflow.PeriodicUpdateTrigger || schedule.CronSchedule == "" {
+		return "None", nil
+	}
+
+	if len(schedule.BlackoutWindows) > 0 {
+		return "", errors.New("Workflows with blackout windows cannot run on Airflow.")
+	}
+
+	timing, err := schedule.Timing()
+	if err != nil {
+		return "", err
+	}
+
+	if interval := timing.Interval(); interval > 0 {
+		return fmt.Sprintf("datetime.timedelta(seconds=%d)", int64(interval.Seconds())), nil
+	}
+
+	return strconv.Quote(string(schedule.CronSchedule)), nil
+}
+
+// operatorCommand returns the shell command that runs the given operator.
+func operatorCommand(
+	ctx context.Context,
+	dag *workflow_dag.WorkflowDag,
+	op operator.Operator,
+	storagePaths *utils.WorkflowStoragePaths,
+	jobManager *job.ProcessJobManager,
+	vaultObject vault.Vault,
+) (string, error) {
+	inputArtifactSpecs := make([]artifact.Spec, 0, len(op.Inputs))
+	inputContentPaths := make([]string, 0, len(op.Inputs))
+	inputMetadataPaths := make([]string, 0, len(op.Inputs))
+	for _, inputArtifactId := range op.Inputs {
+		inputArtifact, ok := dag.Artifacts[inputArtifactId]
+		if !ok {
+			return "", errors.Newf("Cannot find artifact with ID %v", inputArtifactId)
+		}
+
+		// The output of a sensor only orders the operators, so it is not passed to the job.
+		if isSensorOutput(dag, inputArtifactId) {
+			continue
+		}
+
+		inputArtifactSpecs = append(inputArtifactSpecs, inputArtifact.Spec)
+		inputContentPaths = append(inputContentPaths, storagePaths.ArtifactPaths[inputArtifactId])
+		inputMetadataPaths = append(inputMetadataPaths, storagePaths.ArtifactMetadataPaths[inputArtifactId])
+	}
+
+	outputArtifactSpecs := make([]artifact.Spec, 0, len(op.Outputs))
+	outputContentPaths := make([]string, 0, len(op.Outputs))
+	outputMetadataPaths := make([]string, 0, len(op.Outputs))
+	for _, outputArtifactId := range op.Outputs {
+		outputArtifact, ok := dag.Artifacts[outputArtifactId]
+		if !ok {
+			return "", errors.Newf("Cannot find artifact with ID %v", outputArtifactId)
+		}
+
+		outputArtifactSpecs = append(outputArtifactSpecs, outputArtifact.Spec)
+		outputContentPaths = append(outputContentPaths, storagePaths.ArtifactPaths[outputArtifactId])
+		outputMetadataPaths = append(outputMetadataPaths, storagePaths.ArtifactMetadataPaths[outputArtifactId])
+	}
+
+	recorder := &specRecorder{JobManager: jobManager}
+	_, err := scheduler.ScheduleOperator(
+		ctx,
+		op.Spec,
+		inputArtifactSpecs,
+		outputArtifactSpecs,
+		storagePaths.OperatorMetadataPaths[op.Id],
+		inputContentPaths,
+		inputMetadataPaths,
+		outputContentPaths,
+		outputMetadataPaths,
+		&dag.StorageConfig,
+		recorder,
+		vaultObject,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	if len(recorder.specs) != 1 {
+		return "", errors.Newf("Expected one job for the operator, but got %d.", len(recorder.specs))
+	}
+
+	args, err := jobManager.Command(recorder.specs[0])
+	if err != nil {
+		return "", err
+	}
+
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		quoted = append(quoted, shellQuote(arg))
+	}
+
+	return strings.Join(quoted, " "), nil
+}
+
+// integrationService returns the service of the integration that the operator connects to.
+func integrationService(op operator.Operator) integration.Service {
+	switch {
+	case op.Spec.IsExtract():
+		return op.Spec.Extract().Service
+	case op.Spec.IsLoad():
+		return op.Spec.Load().Service
+	case op.Spec.IsSensor():
+		return op.Spec.Sensor().Service
+	default:
+		return ""
+	}
+}
+
+func isSensorOutput(dag *workflow_dag.WorkflowDag, artifactId uuid.UUID) bool {
+	for _, op := range dag.Operators {
+		if !op.Spec.IsSensor() {
+			continue
+		}
+
+		for _, outputId := range op.Outputs {
+			if outputId == artifactId {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func sanitizeId(id string) string {
+	sanitized := strings.Trim(invalidIdCharacters.ReplaceAllString(id, "_"), "_")
+	if sanitized == "" {
+		return "operator"
+	}
+	return sanitized
+}
+
+func shellQuote(arg string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(arg, "'", `'\''`))
+}
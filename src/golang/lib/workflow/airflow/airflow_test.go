@@ -0,0 +1,161 @@
+package airflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact"
+	"github.com/aqueducthq/aqueduct/lib/collections/integration"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/aqueducthq/aqueduct/lib/workflow/artifact/table"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/function"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func testDag(schedule workflow.Schedule) *workflow_dag.WorkflowDag {
+	raw := artifact.Artifact{Id: uuid.New(), Spec: *artifact.NewSpecFromTable(table.Table{})}
+	cleaned := artifact.Artifact{Id: uuid.New(), Spec: *artifact.NewSpecFromTable(table.Table{})}
+
+	generate := operator.Operator{
+		Id:      uuid.New(),
+		Name:    "generate rows",
+		Spec:    *operator.NewSpecFromFunction(function.Function{Type: function.FileFunctionType}),
+		Outputs: []uuid.UUID{raw.Id},
+	}
+	clean := operator.Operator{
+		Id:      uuid.New(),
+		Name:    "clean",
+		Spec:    *operator.NewSpecFromFunction(function.Function{Type: function.FileFunctionType}),
+		Inputs:  []uuid.UUID{raw.Id},
+		Outputs: []uuid.UUID{cleaned.Id},
+	}
+
+	return &workflow_dag.WorkflowDag{
+		Id:            uuid.New(),
+		WorkflowId:    uuid.New(),
+		StorageConfig: shared.StorageConfig{Type: shared.FileStorageType},
+		Metadata: &workflow.Workflow{
+			Name:     "daily report",
+			Schedule: schedule,
+		},
+		Operators: map[uuid.UUID]operator.Operator{generate.Id: generate, clean.Id: clean},
+		Artifacts: map[uuid.UUID]artifact.Artifact{raw.Id: raw, cleaned.Id: cleaned},
+	}
+}
+
+func testJobManager(t *testing.T) *job.ProcessJobManager {
+	jobManager, err := job.NewProcessJobManager(&job.ProcessConfig{BinaryDir: "/opt/aqueduct/bin"})
+	require.Nil(t, err)
+	return jobManager
+}
+
+func TestCompile(t *testing.T) {
+	dag := testDag(workflow.Schedule{
+		Trigger:      workflow.PeriodicUpdateTrigger,
+		CronSchedule: "0 * * * *",
+	})
+
+	file, err := Compile(context.Background(), dag, testJobManager(t))
+	require.Nil(t, err)
+
+	source := string(file)
+	require.Contains(t, source, `dag_id="aqueduct_daily_report"`)
+	require.Contains(t, source, `schedule_interval="0 * * * *"`)
+	require.Contains(t, source, "is_paused_upon_creation=False")
+
+	// The tasks are ordered by operator name.
+	require.Contains(t, source, "    t0 = BashOperator(\n        task_id=\"clean\"")
+	require.Contains(t, source, "    t1 = BashOperator(\n        task_id=\"generate_rows\"")
+	require.Contains(t, source, `bash_command="'bash' '/opt/aqueduct/bin/start-function-executor.sh' '`)
+	require.Contains(t, source, "    t1 >> t0\n")
+	require.Equal(t, 1, strings.Count(source, ">>"))
+}
+
+func TestCompileUnscheduled(t *testing.T) {
+	dag := testDag(workflow.Schedule{
+		Trigger:      workflow.ManualUpdateTrigger,
+		CronSchedule: "0 * * * *",
+		Paused:       true,
+	})
+
+	file, err := Compile(context.Background(), dag, testJobManager(t))
+	require.Nil(t, err)
+
+	source := string(file)
+	require.Contains(t, source, "schedule_interval=None")
+	require.Contains(t, source, "is_paused_upon_creation=True")
+}
+
+func TestSanitizeId(t *testing.T) {
+	require.Equal(t, "my_workflow.v2", sanitizeId("my workflow.v2"))
+	require.Equal(t, "a_b", sanitizeId("(a) + [b]"))
+	require.Equal(t, "operator", sanitizeId("!!!"))
+}
+
+func TestCompileIntervalInTimezone(t *testing.T) {
+	dag := testDag(workflow.Schedule{
+		Trigger:      workflow.PeriodicUpdateTrigger,
+		CronSchedule: "every 15m",
+		Timezone:     "Europe/Berlin",
+	})
+
+	file, err := Compile(context.Background(), dag, testJobManager(t))
+	require.Nil(t, err)
+
+	source := string(file)
+	require.Contains(t, source, "schedule_interval=datetime.timedelta(seconds=900)")
+	require.Contains(t, source, `start_date=pendulum.datetime(2022, 1, 1, tz="Europe/Berlin")`)
+}
+
+func TestCompileBlackoutWindows(t *testing.T) {
+	dag := testDag(workflow.Schedule{
+		Trigger:         workflow.PeriodicUpdateTrigger,
+		CronSchedule:    "0 * * * *",
+		BlackoutWindows: []workflow.BlackoutWindow{{Start: "0 0 L * *", DurationSeconds: 86400}},
+	})
+
+	_, err := Compile(context.Background(), dag, testJobManager(t))
+	require.NotNil(t, err)
+}
+
+func TestCompileLeavesOutCredentials(t *testing.T) {
+	dag := testDag(workflow.Schedule{Trigger: workflow.ManualUpdateTrigger})
+
+	integrationId := uuid.New()
+	var generate operator.Operator
+	for _, op := range dag.Operators {
+		if op.Name == "generate rows" {
+			generate = op
+		}
+	}
+	generate.Spec = *operator.NewSpecFromExtract(connector.Extract{
+		Service:       integration.Postgres,
+		IntegrationId: integrationId,
+		Parameters: &connector.PostgresExtractParams{
+			RelationalDBExtractParams: connector.RelationalDBExtractParams{Query: "SELECT * FROM wine;"},
+		},
+	})
+	dag.Operators[generate.Id] = generate
+
+	file, err := Compile(context.Background(), dag, testJobManager(t))
+	require.Nil(t, err)
+
+	// The extract task reads the credentials of the integration from an Airflow Variable when
+	// it runs, and the file tells the user to create that Variable.
+	variable := integrationSecretVariable(integrationId)
+	source := string(file)
+	require.Contains(t, source, fmt.Sprintf("#   %s: the Postgres integration %s\n", variable, integrationId))
+	require.Contains(t, source, fmt.Sprintf(
+		"        env={\"AQUEDUCT_CONNECTOR_CONFIG\": \"{{ var.value.get('%s') }}\"},\n        append_env=True,\n",
+		variable,
+	))
+	require.Equal(t, 1, strings.Count(source, "        env="))
+}
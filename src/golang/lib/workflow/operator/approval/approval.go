@@ -0,0 +1,62 @@
+package approval
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+// The default timeout gives approvers a day to act. Time spent awaiting approval does not count
+// towards the workflow's timeout.
+const DefaultTimeout = 24 * time.Hour
+
+// Approval defines the spec for an Approval operator. It pauses the run until one of its approvers
+// approves or rejects it, e.g. after reviewing the artifacts the operator takes as inputs. It
+// outputs a boolean artifact that operators, such as loads, take as an input so that they only run
+// once the run is approved. The operator fails if the run is rejected, or if no decision is made
+// before its timeout.
+type Approval struct {
+	// The emails of the users who may approve or reject the run. If there are none, only the
+	// owner of the workflow may.
+	Approvers []string `json:"approvers,omitempty"`
+	// Shown to the users who are notified that the run awaits approval.
+	Message string `json:"message,omitempty"`
+	// How long to wait for a decision. 0 means the default timeout applies.
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+}
+
+// Validate checks that the approval has a valid timeout.
+func (a *Approval) Validate() error {
+	if a.TimeoutSeconds < 0 {
+		return errors.New("The timeout of an approval cannot be negative.")
+	}
+
+	return nil
+}
+
+// Timeout returns how long the approval waits for a decision.
+func (a *Approval) Timeout() time.Duration {
+	if a.TimeoutSeconds == 0 {
+		return DefaultTimeout
+	}
+
+	return time.Duration(a.TimeoutSeconds) * time.Second
+}
+
+// CanDecide returns whether the user with `userId` and `email` may approve or reject a run of a
+// workflow owned by `ownerId`.
+func (a *Approval) CanDecide(userId uuid.UUID, email string, ownerId uuid.UUID) bool {
+	if len(a.Approvers) == 0 {
+		return userId == ownerId
+	}
+
+	for _, approver := range a.Approvers {
+		if strings.EqualFold(approver, email) {
+			return true
+		}
+	}
+
+	return false
+}
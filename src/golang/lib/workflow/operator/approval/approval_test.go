@@ -0,0 +1,29 @@
+package approval
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApprovalTimeout(t *testing.T) {
+	require.Equal(t, DefaultTimeout, (&Approval{}).Timeout())
+	require.Equal(t, time.Hour, (&Approval{TimeoutSeconds: 3600}).Timeout())
+	require.NotNil(t, (&Approval{TimeoutSeconds: -1}).Validate())
+}
+
+func TestApprovalCanDecide(t *testing.T) {
+	ownerId := uuid.New()
+	userId := uuid.New()
+
+	// Without approvers, only the owner of the workflow decides.
+	a := &Approval{}
+	require.True(t, a.CanDecide(ownerId, "owner@example.com", ownerId))
+	require.False(t, a.CanDecide(userId, "finance@example.com", ownerId))
+
+	a.Approvers = []string{"Finance@example.com"}
+	require.True(t, a.CanDecide(userId, "finance@example.com", ownerId))
+	require.False(t, a.CanDecide(ownerId, "owner@example.com", ownerId))
+}
@@ -0,0 +1,70 @@
+package connector
+
+import (
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/integration"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultSensorPollInterval = time.Minute
+	// The deadline is shorter than the default workflow timeout, which also bounds the sensor.
+	DefaultSensorDeadline = 10 * time.Minute
+)
+
+// Sensor defines the spec for a Sensor operator. It runs a boolean SQL query against a
+// relational integration every poll interval, until the query returns true or the deadline
+// passes. The sensor succeeds in the former case and fails in the latter, so the operators
+// downstream of it only run once the data they depend on is ready.
+type Sensor struct {
+	Service       integration.Service `json:"service"`
+	IntegrationId uuid.UUID           `json:"integration_id"`
+	// The query must return a single row whose first column is a boolean,
+	// e.g. `SELECT COUNT(*) > 0 FROM events WHERE ds = CURRENT_DATE`.
+	Query string `json:"query"`
+	// 0 means the default poll interval applies.
+	PollIntervalSeconds int `json:"poll_interval_seconds,omitempty"`
+	// How long the sensor waits in total. 0 means the default deadline applies.
+	DeadlineSeconds int `json:"deadline_seconds,omitempty"`
+}
+
+// Validate checks that the sensor queries a relational integration at a valid interval.
+func (s *Sensor) Validate() error {
+	if _, ok := integration.GetRelationalDatabaseIntegrations()[s.Service]; !ok {
+		return errors.Newf("A sensor cannot query a %s integration.", s.Service)
+	}
+
+	if s.Query == "" {
+		return errors.New("A sensor must have a query.")
+	}
+
+	if s.PollIntervalSeconds < 0 || s.DeadlineSeconds < 0 {
+		return errors.New("The poll interval and the deadline of a sensor cannot be negative.")
+	}
+
+	if s.PollInterval() > s.Deadline() {
+		return errors.New("The poll interval of a sensor cannot be longer than its deadline.")
+	}
+
+	return nil
+}
+
+// PollInterval returns how long the sensor waits between queries.
+func (s *Sensor) PollInterval() time.Duration {
+	if s.PollIntervalSeconds == 0 {
+		return DefaultSensorPollInterval
+	}
+
+	return time.Duration(s.PollIntervalSeconds) * time.Second
+}
+
+// Deadline returns how long the sensor waits for its query to return true.
+func (s *Sensor) Deadline() time.Duration {
+	if s.DeadlineSeconds == 0 {
+		return DefaultSensorDeadline
+	}
+
+	return time.Duration(s.DeadlineSeconds) * time.Second
+}
@@ -0,0 +1,34 @@
+package connector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/integration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSensorDefaults(t *testing.T) {
+	sensor := Sensor{Service: integration.Postgres, Query: "SELECT true;"}
+	require.Nil(t, sensor.Validate())
+	require.Equal(t, DefaultSensorPollInterval, sensor.PollInterval())
+	require.Equal(t, DefaultSensorDeadline, sensor.Deadline())
+
+	sensor.PollIntervalSeconds = 30
+	sensor.DeadlineSeconds = 3600
+	require.Nil(t, sensor.Validate())
+	require.Equal(t, 30*time.Second, sensor.PollInterval())
+	require.Equal(t, time.Hour, sensor.Deadline())
+}
+
+func TestValidateSensor(t *testing.T) {
+	invalid := []Sensor{
+		{Service: integration.S3, Query: "SELECT true;"},
+		{Service: integration.Postgres},
+		{Service: integration.Postgres, Query: "SELECT true;", PollIntervalSeconds: -1},
+		{Service: integration.Postgres, Query: "SELECT true;", PollIntervalSeconds: 60, DeadlineSeconds: 30},
+	}
+	for _, sensor := range invalid {
+		require.NotNil(t, sensor.Validate())
+	}
+}
@@ -1,6 +1,7 @@
 package function
 
 import (
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact"
 	github "github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/github/types"
 )
 
@@ -12,6 +13,7 @@ type Function struct {
 	GithubMetadata *github.GithubMetadata `json:"github_metadata,omitempty"`
 	EntryPoint     *EntryPoint            `json:"entry_point,omitempty"`
 	CustomArgs     string                 `json:"custom_args"`
+	ResourceLimits *ResourceLimits        `json:"resource_limits,omitempty"`
 }
 
 type Type string
@@ -34,3 +36,33 @@ type EntryPoint struct {
 	ClassName string `json:"class_name"`
 	Method    string `json:"method"`
 }
+
+// ResourceLimits bounds the resources the job of a function operator may use.
+// A limit of 0 means there is no limit.
+type ResourceLimits struct {
+	// The maximum virtual memory of each process of the job.
+	MemoryMB int `json:"memory_mb,omitempty"`
+	// The maximum CPU time of each process of the job.
+	CpuSeconds int `json:"cpu_seconds,omitempty"`
+	// The maximum time the job may run for.
+	WallClockSeconds int `json:"wall_clock_seconds,omitempty"`
+}
+
+type ResourceLimit string
+
+const (
+	MemoryResourceLimit    ResourceLimit = "memory"
+	CpuTimeResourceLimit   ResourceLimit = "cpu_time"
+	WallClockResourceLimit ResourceLimit = "wall_clock"
+)
+
+// IsSupportedArtifactType returns whether functions, including metrics and checks, can take
+// artifacts of `artifactType` as inputs. General functions can also output them.
+func IsSupportedArtifactType(artifactType artifact.Type) bool {
+	switch artifactType {
+	case artifact.TableType, artifact.FloatType, artifact.BoolType, artifact.JsonType:
+		return true
+	default:
+		return false
+	}
+}
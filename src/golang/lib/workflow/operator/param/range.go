@@ -0,0 +1,94 @@
+package param
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/dropbox/godropbox/errors"
+)
+
+const rangeDateLayout = "2006-01-02"
+
+var ErrInvalidRange = errors.New("The start and end of a range must both be dates (YYYY-MM-DD) or both be integers, and the start cannot be after the end.")
+
+// Range is an inclusive range of parameter values. Dates advance by `Step` days and integers
+// by `Step`. A `Step` of 0 is treated as 1.
+type Range struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Step  int    `json:"step,omitempty"`
+}
+
+// Values returns the JSON serialized values in the range, in order. Dates are serialized
+// as JSON strings and integers as JSON numbers. The number of values is computed before any
+// of them are generated, and a range with more than `limit` values is rejected.
+func (r *Range) Values(limit int) ([]string, error) {
+	step := r.Step
+	if step == 0 {
+		step = 1
+	}
+	if step < 0 {
+		return nil, errors.New("The step of a range must be positive.")
+	}
+
+	if start, err := time.Parse(rangeDateLayout, r.Start); err == nil {
+		end, err := time.Parse(rangeDateLayout, r.End)
+		if err != nil || end.Before(start) {
+			return nil, ErrInvalidRange
+		}
+
+		// Dates are parsed in UTC, so every day has the same number of seconds.
+		days := uint64(end.Unix()-start.Unix()) / uint64(24*time.Hour/time.Second)
+		count, err := rangeCount(days, step, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		values := make([]string, 0, count)
+		for k := 0; k < count; k++ {
+			val, err := json.Marshal(start.AddDate(0, 0, k*step).Format(rangeDateLayout))
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, string(val))
+		}
+
+		return values, nil
+	}
+
+	start, err := strconv.ParseInt(r.Start, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidRange
+	}
+
+	end, err := strconv.ParseInt(r.End, 10, 64)
+	if err != nil || end < start {
+		return nil, ErrInvalidRange
+	}
+
+	// The difference is computed on unsigned integers, since it may not fit in an int64.
+	count, err := rangeCount(uint64(end)-uint64(start), step, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 0, count)
+	for k := 0; k < count; k++ {
+		// The value is at most `end`, so it does not overflow.
+		values = append(values, strconv.FormatInt(start+int64(k)*int64(step), 10))
+	}
+
+	return values, nil
+}
+
+// `rangeCount` returns the number of values in a range whose end is `span` after its start,
+// or an error if there are more than `limit` of them.
+func rangeCount(span uint64, step int, limit int) (int, error) {
+	// The count is compared before 1 is added to it, which could overflow.
+	if span/uint64(step) >= uint64(limit) {
+		return 0, errors.Newf("The range has more than %d values.", limit)
+	}
+
+	return int(span/uint64(step)) + 1, nil
+}
@@ -0,0 +1,38 @@
+package param
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRangeValues(t *testing.T) {
+	values, err := (&Range{Start: "2022-02-27", End: "2022-03-02"}).Values(10)
+	require.Nil(t, err)
+	require.Equal(t, []string{`"2022-02-27"`, `"2022-02-28"`, `"2022-03-01"`, `"2022-03-02"`}, values)
+
+	values, err = (&Range{Start: "1", End: "7", Step: 3}).Values(10)
+	require.Nil(t, err)
+	require.Equal(t, []string{"1", "4", "7"}, values)
+
+	_, err = (&Range{Start: "2022-03-02", End: "2022-02-27"}).Values(10)
+	require.Equal(t, ErrInvalidRange, err)
+
+	_, err = (&Range{Start: "2022-02-27", End: "10"}).Values(10)
+	require.Equal(t, ErrInvalidRange, err)
+
+	// Ranges with too many values are rejected before their values are generated.
+	_, err = (&Range{Start: "1", End: "11"}).Values(10)
+	require.NotNil(t, err)
+
+	_, err = (&Range{Start: "0001-01-01", End: "9999-12-31"}).Values(10)
+	require.NotNil(t, err)
+
+	_, err = (&Range{Start: "-9223372036854775808", End: "9223372036854775807"}).Values(10)
+	require.NotNil(t, err)
+
+	// The last values of the integer range do not overflow.
+	values, err = (&Range{Start: "9223372036854775805", End: "9223372036854775807", Step: 2}).Values(10)
+	require.Nil(t, err)
+	require.Equal(t, []string{"9223372036854775805", "9223372036854775807"}, values)
+}
@@ -0,0 +1,70 @@
+package subworkflow
+
+import (
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+// SubWorkflow defines the spec for a SubWorkflow operator. It runs the latest version of another
+// workflow as a child run of the run it is part of. The workflow is referenced either by ID or by
+// name, in which case it is looked up among the workflows of the same user. The operator's inputs
+// set the values of the workflow's param operators, and the operator outputs some of the
+// artifacts of the child run.
+type SubWorkflow struct {
+	WorkflowId   *uuid.UUID `json:"workflow_id,omitempty"`
+	WorkflowName string     `json:"workflow_name,omitempty"`
+	// The names of the param operators of the workflow that take the values of the operator's
+	// inputs, in the order of the inputs.
+	Inputs []string `json:"inputs,omitempty"`
+	// The JSON serialized values of other param operators of the workflow.
+	Params shared.Parameters `json:"params,omitempty"`
+	// The names of the artifacts of the workflow that are output by the operator, in the order
+	// of the outputs.
+	Outputs []string `json:"outputs,omitempty"`
+}
+
+// Validate checks that the sub-workflow references a single workflow, and that it maps every
+// input and output of the operator to a param or an artifact of the workflow.
+func (w *SubWorkflow) Validate(numInputs int, numOutputs int) error {
+	if (w.WorkflowId == nil) == (w.WorkflowName == "") {
+		return errors.New("A sub-workflow must reference a workflow by either its ID or its name.")
+	}
+
+	if len(w.Inputs) != numInputs {
+		return errors.Newf("A sub-workflow with %d inputs must name %d params.", numInputs, numInputs)
+	}
+
+	if len(w.Outputs) != numOutputs {
+		return errors.Newf("A sub-workflow with %d outputs must name %d artifacts.", numOutputs, numOutputs)
+	}
+
+	seen := make(map[string]bool, len(w.Inputs))
+	for _, name := range w.Inputs {
+		if _, ok := w.Params[name]; ok || seen[name] {
+			return errors.Newf("The value of param %s is set more than once.", name)
+		}
+		seen[name] = true
+	}
+
+	return nil
+}
+
+// Parameters returns the values of the workflow's param operators, given the JSON serialized
+// values of the operator's inputs.
+func (w *SubWorkflow) Parameters(inputValues []string) (shared.Parameters, error) {
+	if len(inputValues) != len(w.Inputs) {
+		return nil, errors.Newf("Expected %d input values, but got %d.", len(w.Inputs), len(inputValues))
+	}
+
+	parameters := make(shared.Parameters, len(w.Params)+len(w.Inputs))
+	for name, val := range w.Params {
+		parameters[name] = val
+	}
+
+	for i, name := range w.Inputs {
+		parameters[name] = inputValues[i]
+	}
+
+	return parameters, nil
+}
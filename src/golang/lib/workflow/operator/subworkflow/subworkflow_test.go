@@ -0,0 +1,40 @@
+package subworkflow
+
+import (
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubWorkflowValidate(t *testing.T) {
+	workflowId := uuid.New()
+	w := &SubWorkflow{WorkflowId: &workflowId, Inputs: []string{"region"}, Outputs: []string{"report"}}
+	require.Nil(t, w.Validate(1, 1))
+	require.NotNil(t, w.Validate(2, 1))
+	require.NotNil(t, w.Validate(1, 0))
+
+	// The workflow is referenced by either its ID or its name.
+	w.WorkflowName = "daily_report"
+	require.NotNil(t, w.Validate(1, 1))
+	w.WorkflowId = nil
+	require.Nil(t, w.Validate(1, 1))
+	w.WorkflowName = ""
+	require.NotNil(t, w.Validate(1, 1))
+
+	// An input cannot set a param that already has a value.
+	w = &SubWorkflow{WorkflowName: "daily_report", Inputs: []string{"region"}, Params: shared.Parameters{"region": `"us"`}}
+	require.NotNil(t, w.Validate(1, 0))
+}
+
+func TestSubWorkflowParameters(t *testing.T) {
+	w := &SubWorkflow{WorkflowName: "daily_report", Inputs: []string{"region"}, Params: shared.Parameters{"days": "7"}}
+
+	parameters, err := w.Parameters([]string{`"eu"`})
+	require.Nil(t, err)
+	require.Equal(t, shared.Parameters{"region": `"eu"`, "days": "7"}, parameters)
+
+	_, err = w.Parameters(nil)
+	require.NotNil(t, err)
+}
@@ -0,0 +1,313 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/notification"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/user"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// `approvalJobManager` runs approval operators on top of the wrapped job manager. Each approval is
+// tracked as a single job that only this job manager knows of. Launching the job marks the operator
+// and the run as awaiting approval and notifies the users who watch the workflow or may approve the
+// run. Polling the job checks whether a decision was recorded on the operator's result through the
+// approve and reject routes. All other jobs are handled by the wrapped job manager.
+type approvalJobManager struct {
+	job.JobManager
+	// The workflow dag result of the current run. It is nil for previews, which cannot be approved.
+	workflowDagResultId *uuid.UUID
+	workflowMetadata    *workflow.Workflow
+	approvals           map[string]*pendingApproval
+	// When the run last started awaiting approval, and how long it awaited approval before that.
+	// Time spent awaiting approval does not count towards the workflow's timeout.
+	awaitingSince time.Time
+	awaited       time.Duration
+
+	operatorResultReader    operator_result.Reader
+	operatorResultWriter    operator_result.Writer
+	workflowReader          workflow.Reader
+	workflowDagResultWriter workflow_dag_result.Writer
+	notificationWriter      notification.Writer
+	userReader              user.Reader
+	db                      database.Database
+}
+
+// `pendingApproval` is an approval operator that awaits a decision.
+type pendingApproval struct {
+	op                 *operator.Operator
+	operatorResultId   uuid.UUID
+	deadline           time.Time
+	storageConfig      *shared.StorageConfig
+	metadataPath       string
+	outputContentPath  string
+	outputMetadataPath string
+}
+
+func newApprovalJobManager(
+	jobManager job.JobManager,
+	workflowMetadata *workflow.Workflow,
+	operatorResultReader operator_result.Reader,
+	operatorResultWriter operator_result.Writer,
+	workflowReader workflow.Reader,
+	workflowDagResultWriter workflow_dag_result.Writer,
+	notificationWriter notification.Writer,
+	userReader user.Reader,
+	db database.Database,
+) *approvalJobManager {
+	return &approvalJobManager{
+		JobManager:              jobManager,
+		workflowMetadata:        workflowMetadata,
+		approvals:               map[string]*pendingApproval{},
+		operatorResultReader:    operatorResultReader,
+		operatorResultWriter:    operatorResultWriter,
+		workflowReader:          workflowReader,
+		workflowDagResultWriter: workflowDagResultWriter,
+		notificationWriter:      notificationWriter,
+		userReader:              userReader,
+		db:                      db,
+	}
+}
+
+func (m *approvalJobManager) Poll(ctx context.Context, name string) (shared.ExecutionStatus, error) {
+	a, ok := m.approvals[name]
+	if !ok {
+		return m.JobManager.Poll(ctx, name)
+	}
+
+	operatorResult, err := m.operatorResultReader.GetOperatorResult(ctx, a.operatorResultId, m.db)
+	if err != nil {
+		// A transient database error should not fail the approval.
+		log.Errorf("Unable to check whether operator %s was approved: %v", a.op.Name, err)
+		return shared.PendingExecutionStatus, nil
+	}
+
+	metadata := operatorResult.Metadata.Metadata
+	status := operatorResult.Status
+	switch status {
+	case shared.SucceededExecutionStatus:
+		if err := a.writeApproved(ctx); err != nil {
+			return "", err
+		}
+	case shared.FailedExecutionStatus:
+	default:
+		if time.Now().Before(a.deadline) {
+			return shared.PendingExecutionStatus, nil
+		}
+
+		status = shared.FailedExecutionStatus
+		metadata = operator_result.Metadata{
+			Error: fmt.Sprintf("No decision was made within %s.", a.op.Spec.Approval().Timeout()),
+		}
+
+		// The timeout is only recorded if no decision was made in the meantime. Otherwise, the
+		// decision is picked up on the next poll.
+		_, err := m.operatorResultWriter.UpdateOperatorResultWithStatus(
+			ctx,
+			a.operatorResultId,
+			shared.AwaitingApprovalExecutionStatus,
+			map[string]interface{}{
+				operator_result.StatusColumn:   status,
+				operator_result.MetadataColumn: &metadata,
+			},
+			m.db,
+		)
+		if err == database.ErrNoRows {
+			return shared.PendingExecutionStatus, nil
+		}
+		if err != nil {
+			log.Errorf("Unable to record that operator %s timed out: %v", a.op.Name, err)
+			return shared.PendingExecutionStatus, nil
+		}
+	}
+
+	rawMetadata, err := json.Marshal(metadata)
+	if err != nil {
+		return "", err
+	}
+
+	if err := storage.NewStorage(a.storageConfig).Put(ctx, a.metadataPath, rawMetadata); err != nil {
+		return "", err
+	}
+
+	m.remove(ctx, name)
+	return status, nil
+}
+
+func (m *approvalJobManager) Cancel(ctx context.Context, name string) error {
+	if _, ok := m.approvals[name]; ok {
+		m.remove(ctx, name)
+		return nil
+	}
+
+	return m.JobManager.Cancel(ctx, name)
+}
+
+// `launch` pauses the run until the approval operator is approved or rejected.
+func (m *approvalJobManager) launch(
+	ctx context.Context,
+	op *operator.Operator,
+	outputContentPaths []string,
+	outputMetadataPaths []string,
+	metadataPath string,
+	storageConfig *shared.StorageConfig,
+	operatorResultId uuid.UUID,
+) (string, error) {
+	if m == nil || m.workflowDagResultId == nil {
+		return "", errors.Newf("Operator %s awaits approval, which is only supported in workflow runs.", op.Name)
+	}
+
+	spec := op.Spec.Approval()
+	if spec == nil || len(outputContentPaths) != 1 {
+		return "", errors.Newf("Operator %s must be an approval with a single output.", op.Name)
+	}
+
+	_, err := m.operatorResultWriter.UpdateOperatorResult(
+		ctx,
+		operatorResultId,
+		map[string]interface{}{operator_result.StatusColumn: shared.AwaitingApprovalExecutionStatus},
+		m.db,
+	)
+	if err != nil {
+		return "", errors.Wrapf(err, "Unable to mark operator %s as awaiting approval.", op.Name)
+	}
+
+	if len(m.approvals) == 0 {
+		m.awaitingSince = time.Now()
+		err := m.updateRunStatus(ctx, shared.PendingExecutionStatus, shared.AwaitingApprovalExecutionStatus)
+		if err != nil {
+			return "", errors.Wrap(err, "Unable to mark the run as awaiting approval.")
+		}
+	}
+
+	jobName := fmt.Sprintf("approval-%s", uuid.New().String())
+	m.approvals[jobName] = &pendingApproval{
+		op:                 op,
+		operatorResultId:   operatorResultId,
+		deadline:           time.Now().Add(spec.Timeout()),
+		storageConfig:      storageConfig,
+		metadataPath:       metadataPath,
+		outputContentPath:  outputContentPaths[0],
+		outputMetadataPath: outputMetadataPaths[0],
+	}
+
+	log.Infof("Operator %s is awaiting approval.", op.Name)
+	m.notify(ctx, op)
+	return jobName, nil
+}
+
+// `remove` stops tracking the approval. Once no approval is pending, the run continues.
+func (m *approvalJobManager) remove(ctx context.Context, name string) {
+	delete(m.approvals, name)
+	if len(m.approvals) > 0 {
+		return
+	}
+
+	m.awaited += time.Since(m.awaitingSince)
+
+	err := m.updateRunStatus(ctx, shared.AwaitingApprovalExecutionStatus, shared.PendingExecutionStatus)
+	if err != nil {
+		log.Errorf("Unable to mark workflow dag result %s as pending: %v", *m.workflowDagResultId, err)
+	}
+}
+
+// `timeAwaitingApproval` returns how long the run has awaited approval so far.
+func (m *approvalJobManager) timeAwaitingApproval() time.Duration {
+	if len(m.approvals) == 0 {
+		return m.awaited
+	}
+
+	return m.awaited + time.Since(m.awaitingSince)
+}
+
+// `updateRunStatus` moves the run from status `from` to status `to`. The run is left as is if its
+// status changed in the meantime, e.g. because it was cancelled.
+func (m *approvalJobManager) updateRunStatus(ctx context.Context, from shared.ExecutionStatus, to shared.ExecutionStatus) error {
+	_, err := m.workflowDagResultWriter.UpdateWorkflowDagResultWithStatus(
+		ctx,
+		*m.workflowDagResultId,
+		[]shared.ExecutionStatus{from},
+		map[string]interface{}{workflow_dag_result.StatusColumn: to},
+		m.workflowReader,
+		m.notificationWriter,
+		m.userReader,
+		m.db,
+	)
+	if err == database.ErrNoRows {
+		return nil
+	}
+
+	return err
+}
+
+// `notify` lets the watchers of the workflow and the users who may approve the run know that the
+// run awaits approval. Errors are only logged, since the run can still be approved.
+func (m *approvalJobManager) notify(ctx context.Context, op *operator.Operator) {
+	spec := op.Spec.Approval()
+	receivers := map[uuid.UUID]bool{}
+
+	watchers, err := m.userReader.GetWatchersByWorkflowId(ctx, m.workflowMetadata.Id, m.db)
+	if err != nil {
+		log.Errorf("Unable to get the watchers of workflow %s: %v", m.workflowMetadata.Name, err)
+	}
+	for _, watcher := range watchers {
+		receivers[watcher.Id] = true
+	}
+
+	if len(spec.Approvers) == 0 {
+		receivers[m.workflowMetadata.UserId] = true
+	}
+	for _, email := range spec.Approvers {
+		approver, err := m.userReader.GetUserFromEmail(ctx, email, m.db)
+		if err != nil {
+			log.Errorf("Unable to find approver %s of operator %s: %v", email, op.Name, err)
+			continue
+		}
+		receivers[approver.Id] = true
+	}
+
+	content := fmt.Sprintf("Workflow %s is awaiting approval of operator %s.", m.workflowMetadata.Name, op.Name)
+	if spec.Message != "" {
+		content = fmt.Sprintf("%s %s", content, spec.Message)
+	}
+
+	for receiverId := range receivers {
+		_, err := m.notificationWriter.CreateNotification(
+			ctx,
+			receiverId,
+			content,
+			notification.InfoLevel,
+			notification.NotificationAssociation{
+				Object: notification.WorkflowDagResultObject,
+				Id:     *m.workflowDagResultId,
+			},
+			m.db,
+		)
+		if err != nil {
+			log.Errorf("Unable to notify user %s that operator %s awaits approval: %v", receiverId, op.Name, err)
+		}
+	}
+}
+
+// `writeApproved` writes the output of an approved operator, which is always true.
+func (a *pendingApproval) writeApproved(ctx context.Context) error {
+	store := storage.NewStorage(a.storageConfig)
+	if err := store.Put(ctx, a.outputContentPath, []byte("True")); err != nil {
+		return err
+	}
+
+	return store.Put(ctx, a.outputMetadataPath, []byte("[]"))
+}
@@ -0,0 +1,326 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/notification"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/user"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/approval"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// operatorResults keeps the results of operators in memory.
+type operatorResults struct {
+	operator_result.Reader
+	operator_result.Writer
+	results map[uuid.UUID]*operator_result.OperatorResult
+	// beforeUpdate, if set, runs once before the next conditional update to simulate a concurrent
+	// writer.
+	beforeUpdate func()
+}
+
+func (r *operatorResults) GetOperatorResult(
+	ctx context.Context,
+	id uuid.UUID,
+	db database.Database,
+) (*operator_result.OperatorResult, error) {
+	return r.results[id], nil
+}
+
+func (r *operatorResults) UpdateOperatorResult(
+	ctx context.Context,
+	id uuid.UUID,
+	changes map[string]interface{},
+	db database.Database,
+) (*operator_result.OperatorResult, error) {
+	r.results[id].Status = changes[operator_result.StatusColumn].(shared.ExecutionStatus)
+	return r.results[id], nil
+}
+
+func (r *operatorResults) UpdateOperatorResultWithStatus(
+	ctx context.Context,
+	id uuid.UUID,
+	status shared.ExecutionStatus,
+	changes map[string]interface{},
+	db database.Database,
+) (*operator_result.OperatorResult, error) {
+	if r.beforeUpdate != nil {
+		r.beforeUpdate()
+		r.beforeUpdate = nil
+	}
+
+	if r.results[id].Status != status {
+		return nil, database.ErrNoRows
+	}
+
+	return r.UpdateOperatorResult(ctx, id, changes, db)
+}
+
+// runStatuses keeps the statuses of runs in memory.
+type runStatuses struct {
+	workflow_dag_result.Reader
+	workflow_dag_result.Writer
+	statuses map[uuid.UUID]shared.ExecutionStatus
+}
+
+func (r *runStatuses) GetWorkflowDagResult(
+	ctx context.Context,
+	id uuid.UUID,
+	db database.Database,
+) (*workflow_dag_result.WorkflowDagResult, error) {
+	return &workflow_dag_result.WorkflowDagResult{Id: id, Status: r.statuses[id]}, nil
+}
+
+func (r *runStatuses) UpdateWorkflowDagResult(
+	ctx context.Context,
+	id uuid.UUID,
+	changes map[string]interface{},
+	workflowReader workflow.Reader,
+	notificationWriter notification.Writer,
+	userReader user.Reader,
+	db database.Database,
+) (*workflow_dag_result.WorkflowDagResult, error) {
+	r.statuses[id] = changes[workflow_dag_result.StatusColumn].(shared.ExecutionStatus)
+	return &workflow_dag_result.WorkflowDagResult{Id: id, Status: r.statuses[id]}, nil
+}
+
+func (r *runStatuses) UpdateWorkflowDagResultWithStatus(
+	ctx context.Context,
+	id uuid.UUID,
+	statuses []shared.ExecutionStatus,
+	changes map[string]interface{},
+	workflowReader workflow.Reader,
+	notificationWriter notification.Writer,
+	userReader user.Reader,
+	db database.Database,
+) (*workflow_dag_result.WorkflowDagResult, error) {
+	if !hasStatus(r.statuses[id], statuses) {
+		return nil, database.ErrNoRows
+	}
+
+	return r.UpdateWorkflowDagResult(ctx, id, changes, workflowReader, notificationWriter, userReader, db)
+}
+
+// hasStatus returns whether `status` is one of `statuses`.
+func hasStatus(status shared.ExecutionStatus, statuses []shared.ExecutionStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sentNotifications records the receivers of the notifications that are sent.
+type sentNotifications struct {
+	notification.Writer
+	receivers []uuid.UUID
+}
+
+func (w *sentNotifications) CreateNotification(
+	ctx context.Context,
+	receiverId uuid.UUID,
+	content string,
+	level notification.Level,
+	association notification.NotificationAssociation,
+	db database.Database,
+) (*notification.Notification, error) {
+	w.receivers = append(w.receivers, receiverId)
+	return &notification.Notification{}, nil
+}
+
+// approvalUsers has a single watcher and a single approver.
+type approvalUsers struct {
+	user.Reader
+	watcher  user.User
+	approver user.User
+}
+
+func (r *approvalUsers) GetWatchersByWorkflowId(ctx context.Context, workflowId uuid.UUID, db database.Database) ([]user.User, error) {
+	return []user.User{r.watcher}, nil
+}
+
+func (r *approvalUsers) GetUserFromEmail(ctx context.Context, email string, db database.Database) (*user.User, error) {
+	if email != r.approver.Email {
+		return nil, database.ErrNoRows
+	}
+
+	return &r.approver, nil
+}
+
+type testApproval struct {
+	approvals     *approvalJobManager
+	results       *operatorResults
+	runs          *runStatuses
+	notified      *sentNotifications
+	users         *approvalUsers
+	store         storage.Storage
+	runId         uuid.UUID
+	resultId      uuid.UUID
+	op            *operator.Operator
+	storageConfig *shared.StorageConfig
+}
+
+func newTestApproval(t *testing.T) *testApproval {
+	a := &testApproval{
+		results:  &operatorResults{results: map[uuid.UUID]*operator_result.OperatorResult{}},
+		runs:     &runStatuses{statuses: map[uuid.UUID]shared.ExecutionStatus{}},
+		notified: &sentNotifications{},
+		users: &approvalUsers{
+			watcher:  user.User{Id: uuid.New(), Email: "owner@example.com"},
+			approver: user.User{Id: uuid.New(), Email: "finance@example.com"},
+		},
+		runId:    uuid.New(),
+		resultId: uuid.New(),
+		storageConfig: &shared.StorageConfig{
+			Type:       shared.FileStorageType,
+			FileConfig: &shared.FileConfig{Directory: t.TempDir()},
+		},
+	}
+	a.store = storage.NewStorage(a.storageConfig)
+	a.results.results[a.resultId] = &operator_result.OperatorResult{Id: a.resultId, Status: shared.PendingExecutionStatus}
+	a.runs.statuses[a.runId] = shared.PendingExecutionStatus
+
+	a.approvals = newApprovalJobManager(
+		nil, /* jobManager */
+		&workflow.Workflow{Id: uuid.New(), UserId: a.users.watcher.Id, Name: "finance"},
+		a.results,
+		a.results,
+		nil, /* workflowReader */
+		a.runs,
+		a.notified,
+		a.users,
+		database.NewNoopDatabase(),
+	)
+	a.approvals.workflowDagResultId = &a.runId
+	a.op = &operator.Operator{
+		Name: "review ledger",
+		Spec: *operator.NewSpecFromApproval(approval.Approval{Approvers: []string{a.users.approver.Email}}),
+	}
+
+	return a
+}
+
+func (a *testApproval) launch(t *testing.T) string {
+	jobName, err := a.approvals.launch(
+		context.Background(),
+		a.op,
+		[]string{"approved"},
+		[]string{"approved-metadata"},
+		"operator-metadata",
+		a.storageConfig,
+		a.resultId,
+	)
+	require.Nil(t, err)
+	return jobName
+}
+
+func TestApprovalContinuesApprovedRun(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApproval(t)
+	jobName := a.launch(t)
+
+	// The operator and the run await approval, and the watcher and the approver are notified.
+	require.Equal(t, shared.AwaitingApprovalExecutionStatus, a.results.results[a.resultId].Status)
+	require.Equal(t, shared.AwaitingApprovalExecutionStatus, a.runs.statuses[a.runId])
+	require.ElementsMatch(t, []uuid.UUID{a.users.watcher.Id, a.users.approver.Id}, a.notified.receivers)
+
+	status, err := a.approvals.Poll(ctx, jobName)
+	require.Nil(t, err)
+	require.Equal(t, shared.PendingExecutionStatus, status)
+
+	// The approve route records the decision on the operator's result.
+	a.results.results[a.resultId].Status = shared.SucceededExecutionStatus
+	a.results.results[a.resultId].Metadata.Metadata = operator_result.Metadata{
+		Logs: map[string]string{"stdout": "Approved by finance@example.com."},
+	}
+
+	status, err = a.approvals.Poll(ctx, jobName)
+	require.Nil(t, err)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+	require.Equal(t, shared.PendingExecutionStatus, a.runs.statuses[a.runId])
+
+	approved, err := a.store.Get(ctx, "approved")
+	require.Nil(t, err)
+	require.Equal(t, "True", string(approved))
+	require.Contains(t, readTestOperatorMetadata(t, a.store).Logs["stdout"], "finance@example.com")
+}
+
+func TestApprovalTimesOut(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApproval(t)
+	jobName := a.launch(t)
+	a.approvals.approvals[jobName].deadline = time.Now()
+
+	status, err := a.approvals.Poll(ctx, jobName)
+	require.Nil(t, err)
+	require.Equal(t, shared.FailedExecutionStatus, status)
+	require.Equal(t, shared.FailedExecutionStatus, a.results.results[a.resultId].Status)
+	require.Contains(t, readTestOperatorMetadata(t, a.store).Error, "No decision was made")
+
+	// The operators downstream of the approval do not run.
+	_, err = a.store.Get(ctx, "approved")
+	require.NotNil(t, err)
+}
+
+func TestApprovalTimeoutLosesToConcurrentDecision(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApproval(t)
+	jobName := a.launch(t)
+	a.approvals.approvals[jobName].deadline = time.Now()
+
+	// The operator result is read while awaiting approval, but the run is approved before the
+	// timeout is recorded.
+	a.results.beforeUpdate = func() {
+		a.results.results[a.resultId].Status = shared.SucceededExecutionStatus
+	}
+
+	status, err := a.approvals.Poll(ctx, jobName)
+	require.Nil(t, err)
+	require.Equal(t, shared.PendingExecutionStatus, status)
+	require.Equal(t, shared.SucceededExecutionStatus, a.results.results[a.resultId].Status)
+
+	status, err = a.approvals.Poll(ctx, jobName)
+	require.Nil(t, err)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+}
+
+func TestApprovalPausesWorkflowTimeout(t *testing.T) {
+	ctx := context.Background()
+	a := newTestApproval(t)
+	require.Equal(t, time.Duration(0), a.approvals.timeAwaitingApproval())
+
+	jobName := a.launch(t)
+	a.approvals.awaitingSince = time.Now().Add(-time.Hour)
+	require.True(t, a.approvals.timeAwaitingApproval() >= time.Hour)
+
+	a.results.results[a.resultId].Status = shared.SucceededExecutionStatus
+	_, err := a.approvals.Poll(ctx, jobName)
+	require.Nil(t, err)
+
+	// The time awaited is kept once the run continues, and stops growing.
+	awaited := a.approvals.timeAwaitingApproval()
+	require.True(t, awaited >= time.Hour)
+	require.Equal(t, awaited, a.approvals.timeAwaitingApproval())
+}
+
+func TestApprovalKeepsCancelledRun(t *testing.T) {
+	a := newTestApproval(t)
+	jobName := a.launch(t)
+
+	// The run is cancelled while it awaits approval.
+	a.runs.statuses[a.runId] = shared.CanceledExecutionStatus
+	require.Nil(t, a.approvals.Cancel(context.Background(), jobName))
+	require.Equal(t, shared.CanceledExecutionStatus, a.runs.statuses[a.runId])
+}
@@ -0,0 +1,336 @@
+package orchestrator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact"
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_cache"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// `operatorCache` lets a workflow run reuse the outputs of an earlier run of an operator with
+// caching enabled, instead of running the operator again. An operator's outputs are reused
+// if its spec, its function code and the contents of its inputs are the same as in that run.
+type operatorCache struct {
+	workflowId    uuid.UUID
+	storageConfig *shared.StorageConfig
+	reader        operator_cache.Reader
+	writer        operator_cache.Writer
+	db            database.Database
+
+	// The cache keys of the operators that missed the cache, so their outputs can be
+	// cached once they succeed.
+	keys map[uuid.UUID]string
+}
+
+func newOperatorCache(
+	workflowId uuid.UUID,
+	storageConfig *shared.StorageConfig,
+	reader operator_cache.Reader,
+	writer operator_cache.Writer,
+	db database.Database,
+) *operatorCache {
+	return &operatorCache{
+		workflowId:    workflowId,
+		storageConfig: storageConfig,
+		reader:        reader,
+		writer:        writer,
+		db:            db,
+		keys:          map[uuid.UUID]string{},
+	}
+}
+
+// `writeHashPart` writes a length-prefixed part to the hash, so different parts can't be
+// mistaken for one another.
+func writeHashPart(h hash.Hash, part []byte) {
+	fmt.Fprintf(h, "%d:", len(part))
+	h.Write(part)
+}
+
+// `cacheKey` hashes everything that determines the outputs of the operator: its spec, its
+// function code, the specs of its outputs and the contents of its inputs. The storage path of
+// the function is left out, since the function's code is hashed instead.
+func (c *operatorCache) cacheKey(
+	ctx context.Context,
+	op *operator.Operator,
+	artifacts map[uuid.UUID]artifact.Artifact,
+	artifactContentPaths map[uuid.UUID]string,
+) (string, error) {
+	store := storage.NewStorage(c.storageConfig)
+	h := sha256.New()
+	writeHashPart(h, []byte(c.workflowId.String()))
+
+	rawSpec, err := json.Marshal(op.Spec)
+	if err != nil {
+		return "", err
+	}
+
+	if op.Spec.HasFunction() {
+		code, err := store.Get(ctx, op.Spec.Function().StoragePath)
+		if err != nil {
+			return "", err
+		}
+		writeHashPart(h, code)
+
+		var spec operator.Spec
+		if err := json.Unmarshal(rawSpec, &spec); err != nil {
+			return "", err
+		}
+		spec.Function().StoragePath = ""
+
+		rawSpec, err = json.Marshal(spec)
+		if err != nil {
+			return "", err
+		}
+	}
+	writeHashPart(h, rawSpec)
+
+	for _, outputArtifactId := range op.Outputs {
+		rawArtifactSpec, err := json.Marshal(artifacts[outputArtifactId].Spec)
+		if err != nil {
+			return "", err
+		}
+		writeHashPart(h, rawArtifactSpec)
+	}
+
+	for _, inputArtifactId := range op.Inputs {
+		content, err := store.Get(ctx, artifactContentPaths[inputArtifactId])
+		if err != nil {
+			return "", err
+		}
+		writeHashPart(h, content)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// `lookup` returns the cache entry for the operator, or nil if there is no usable entry.
+// Entries whose outputs no longer exist in storage are removed.
+func (c *operatorCache) lookup(
+	ctx context.Context,
+	op *operator.Operator,
+	artifacts map[uuid.UUID]artifact.Artifact,
+	artifactContentPaths map[uuid.UUID]string,
+) (*operator_cache.CacheEntry, error) {
+	key, err := c.cacheKey(ctx, op, artifacts, artifactContentPaths)
+	if err != nil {
+		return nil, err
+	}
+	c.keys[op.Id] = key
+
+	entry, err := c.reader.GetCacheEntry(ctx, key, c.db)
+	if err == database.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	store := storage.NewStorage(c.storageConfig)
+	usable := len(entry.Outputs) == len(op.Outputs)
+	for _, output := range entry.Outputs {
+		if !usable {
+			break
+		}
+
+		exists, err := store.Exists(ctx, output.ContentPath)
+		if err != nil {
+			return nil, err
+		}
+		usable = exists
+	}
+
+	if !usable {
+		log.Infof("Outputs of the cached run of operator %s are no longer available.", op.Name)
+		return nil, c.writer.DeleteCacheEntry(ctx, key, c.db)
+	}
+
+	return entry, nil
+}
+
+// `reuse` completes the operator with the outputs of the cache entry. The cached content and
+// metadata are copied to this run's artifact paths, so this run's results don't depend on
+// the run that was cached, whose outputs may be cleaned up with it.
+func (c *operatorCache) reuse(
+	ctx context.Context,
+	op *operator.Operator,
+	entry *operator_cache.CacheEntry,
+	artifactContentPaths map[uuid.UUID]string,
+	artifactMetadataPaths map[uuid.UUID]string,
+	operatorToOperatorResult map[uuid.UUID]uuid.UUID,
+	artifactToArtifactResult map[uuid.UUID]uuid.UUID,
+	operatorResultWriter operator_result.Writer,
+	artifactResultWriter artifact_result.Writer,
+) error {
+	store := storage.NewStorage(c.storageConfig)
+
+	for i, outputArtifactId := range op.Outputs {
+		content, err := store.Get(ctx, entry.Outputs[i].ContentPath)
+		if err != nil {
+			return err
+		}
+
+		if err := store.Put(ctx, artifactContentPaths[outputArtifactId], content); err != nil {
+			return err
+		}
+
+		rawMetadata, err := json.Marshal(entry.Outputs[i].Metadata)
+		if err != nil {
+			return err
+		}
+
+		if err := store.Put(ctx, artifactMetadataPaths[outputArtifactId], rawMetadata); err != nil {
+			return err
+		}
+	}
+
+	delete(c.keys, op.Id)
+	cachedFrom := entry.OperatorResultId
+	utils.UpdateOperatorAndArtifactResults(
+		ctx,
+		op,
+		c.storageConfig,
+		shared.SucceededExecutionStatus,
+		&operator_result.Metadata{Cached: true, CachedFrom: &cachedFrom},
+		artifactMetadataPaths,
+		operatorToOperatorResult,
+		artifactToArtifactResult,
+		operatorResultWriter,
+		artifactResultWriter,
+		c.db,
+	)
+
+	return nil
+}
+
+// `store` caches the outputs of an operator that missed the cache and succeeded.
+// Errors are logged, since they only mean a later run has to run the operator again.
+func (c *operatorCache) store(
+	ctx context.Context,
+	op *operator.Operator,
+	artifactContentPaths map[uuid.UUID]string,
+	artifactMetadataPaths map[uuid.UUID]string,
+	operatorToOperatorResult map[uuid.UUID]uuid.UUID,
+) {
+	key, ok := c.keys[op.Id]
+	if !ok {
+		return
+	}
+	delete(c.keys, op.Id)
+
+	outputs := make(operator_cache.CachedOutputs, 0, len(op.Outputs))
+	for _, outputArtifactId := range op.Outputs {
+		var metadata artifact_result.Metadata
+		err := utils.ReadFromStorage(ctx, c.storageConfig, artifactMetadataPaths[outputArtifactId], &metadata)
+		if err != nil {
+			log.Errorf("Unable to read the output metadata of operator %s to cache it: %v", op.Name, err)
+			return
+		}
+
+		outputs = append(outputs, operator_cache.CachedOutput{
+			ContentPath: artifactContentPaths[outputArtifactId],
+			Metadata:    metadata,
+		})
+	}
+
+	// An existing entry for the key points to outputs that are no longer available.
+	if err := c.writer.DeleteCacheEntry(ctx, key, c.db); err != nil {
+		log.Errorf("Unable to replace the cache entry of operator %s: %v", op.Name, err)
+		return
+	}
+
+	_, err := c.writer.CreateCacheEntry(ctx, key, c.workflowId, operatorToOperatorResult[op.Id], &outputs, c.db)
+	if err != nil {
+		log.Errorf("Unable to cache the outputs of operator %s: %v", op.Name, err)
+	}
+}
+
+// `forget` drops the cache key of an operator that did not succeed.
+func (c *operatorCache) forget(operatorId uuid.UUID) {
+	delete(c.keys, operatorId)
+}
+
+// `reuseCachedOperators` completes the ready operators whose outputs are cached, instead of
+// scheduling them. The operators they unblock are looked up as well. Operators whose cache
+// lookup fails are scheduled as usual.
+func (c *operatorCache) reuseCachedOperators(
+	ctx context.Context,
+	operators map[uuid.UUID]operator.Operator,
+	artifacts map[uuid.UUID]artifact.Artifact,
+	ready map[uuid.UUID]bool,
+	operatorDependencies map[uuid.UUID]map[uuid.UUID]bool,
+	artifactToDownstreamOperatorIds map[uuid.UUID][]uuid.UUID,
+	artifactContentPaths map[uuid.UUID]string,
+	artifactMetadataPaths map[uuid.UUID]string,
+	operatorToOperatorResult map[uuid.UUID]uuid.UUID,
+	artifactToArtifactResult map[uuid.UUID]uuid.UUID,
+	operatorResultWriter operator_result.Writer,
+	artifactResultWriter artifact_result.Writer,
+) error {
+	checked := map[uuid.UUID]bool{}
+	for {
+		hits := map[uuid.UUID]*operator_cache.CacheEntry{}
+		for id := range ready {
+			op := operators[id]
+			// Operators that already missed the cache, e.g. ones being retried, are not looked up again.
+			if _, missed := c.keys[id]; missed || checked[id] || !op.Spec.CacheEnabled() {
+				continue
+			}
+			checked[id] = true
+
+			entry, err := c.lookup(ctx, &op, artifacts, artifactContentPaths)
+			if err != nil {
+				log.Errorf("Unable to look up operator %s in the cache: %v", op.Name, err)
+				continue
+			}
+
+			if entry != nil {
+				hits[id] = entry
+			}
+		}
+
+		if len(hits) == 0 {
+			return nil
+		}
+
+		for id, entry := range hits {
+			op := operators[id]
+			err := c.reuse(
+				ctx,
+				&op,
+				entry,
+				artifactContentPaths,
+				artifactMetadataPaths,
+				operatorToOperatorResult,
+				artifactToArtifactResult,
+				operatorResultWriter,
+				artifactResultWriter,
+			)
+			if err != nil {
+				log.Errorf("Unable to reuse the cached outputs of operator %s: %v", op.Name, err)
+				continue
+			}
+
+			log.Infof("Reused the cached outputs of operator %s.", op.Name)
+			delete(ready, id)
+
+			err = unblockDownstreamOperators(&op, operators, ready, operatorDependencies, artifactToDownstreamOperatorIds)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
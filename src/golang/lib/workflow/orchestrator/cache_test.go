@@ -0,0 +1,235 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact"
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_cache"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// inMemoryOperatorCache is an operator_cache.Reader and Writer backed by a map.
+type inMemoryOperatorCache struct {
+	entries map[string]*operator_cache.CacheEntry
+}
+
+func (c *inMemoryOperatorCache) GetCacheEntry(
+	ctx context.Context,
+	cacheKey string,
+	db database.Database,
+) (*operator_cache.CacheEntry, error) {
+	entry, ok := c.entries[cacheKey]
+	if !ok {
+		return nil, database.ErrNoRows
+	}
+
+	return entry, nil
+}
+
+func (c *inMemoryOperatorCache) CreateCacheEntry(
+	ctx context.Context,
+	cacheKey string,
+	workflowId uuid.UUID,
+	operatorResultId uuid.UUID,
+	outputs *operator_cache.CachedOutputs,
+	db database.Database,
+) (*operator_cache.CacheEntry, error) {
+	entry := &operator_cache.CacheEntry{
+		CacheKey:         cacheKey,
+		WorkflowId:       workflowId,
+		OperatorResultId: operatorResultId,
+		Outputs:          *outputs,
+	}
+	c.entries[cacheKey] = entry
+
+	return entry, nil
+}
+
+func (c *inMemoryOperatorCache) DeleteCacheEntry(ctx context.Context, cacheKey string, db database.Database) error {
+	delete(c.entries, cacheKey)
+	return nil
+}
+
+func (c *inMemoryOperatorCache) DeleteCacheEntriesByWorkflowId(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	db database.Database,
+) error {
+	c.entries = map[string]*operator_cache.CacheEntry{}
+	return nil
+}
+
+func newTestOperatorCache(t *testing.T) (*operatorCache, storage.Storage) {
+	storageConfig := &shared.StorageConfig{
+		Type:       shared.FileStorageType,
+		FileConfig: &shared.FileConfig{Directory: t.TempDir()},
+	}
+	entries := &inMemoryOperatorCache{entries: map[string]*operator_cache.CacheEntry{}}
+
+	cache := newOperatorCache(uuid.New(), storageConfig, entries, entries, database.NewNoopDatabase())
+	return cache, storage.NewStorage(storageConfig)
+}
+
+func newTestCachedOperator(t *testing.T, storagePath string, inputs []uuid.UUID, outputs []uuid.UUID) operator.Operator {
+	var spec operator.Spec
+	err := json.Unmarshal(
+		[]byte(`{"function": {"type": "file", "storage_path": "`+storagePath+`"}, "enable_cache": true}`),
+		&spec,
+	)
+	require.Nil(t, err)
+
+	return operator.Operator{Id: uuid.New(), Name: storagePath, Spec: spec, Inputs: inputs, Outputs: outputs}
+}
+
+func TestCacheKey(t *testing.T) {
+	ctx := context.Background()
+	cache, store := newTestOperatorCache(t)
+
+	inputId := uuid.New()
+	artifactContentPaths := map[uuid.UUID]string{inputId: "input"}
+	require.Nil(t, store.Put(ctx, "input", []byte("data")))
+	require.Nil(t, store.Put(ctx, "function", []byte("code")))
+	require.Nil(t, store.Put(ctx, "function-copy", []byte("code")))
+
+	op := newTestCachedOperator(t, "function", []uuid.UUID{inputId}, nil)
+	key, err := cache.cacheKey(ctx, &op, nil, artifactContentPaths)
+	require.Nil(t, err)
+
+	// The same code in a different storage path has the same key.
+	opCopy := newTestCachedOperator(t, "function-copy", []uuid.UUID{inputId}, nil)
+	keyOfCopy, err := cache.cacheKey(ctx, &opCopy, nil, artifactContentPaths)
+	require.Nil(t, err)
+	require.Equal(t, key, keyOfCopy)
+
+	// Changing the inputs or the code changes the key.
+	require.Nil(t, store.Put(ctx, "input", []byte("new data")))
+	keyWithNewInput, err := cache.cacheKey(ctx, &op, nil, artifactContentPaths)
+	require.Nil(t, err)
+	require.NotEqual(t, key, keyWithNewInput)
+
+	require.Nil(t, store.Put(ctx, "function", []byte("new code")))
+	keyWithNewCode, err := cache.cacheKey(ctx, &op, nil, artifactContentPaths)
+	require.Nil(t, err)
+	require.NotEqual(t, keyWithNewInput, keyWithNewCode)
+}
+
+func TestReuseCachedOperators(t *testing.T) {
+	ctx := context.Background()
+	cache, store := newTestOperatorCache(t)
+	require.Nil(t, store.Put(ctx, "function-a", []byte("code a")))
+	require.Nil(t, store.Put(ctx, "function-b", []byte("code b")))
+
+	// Operator a produces artifact x, which operator b reads.
+	x, y := uuid.New(), uuid.New()
+	a := newTestCachedOperator(t, "function-a", nil, []uuid.UUID{x})
+	b := newTestCachedOperator(t, "function-b", []uuid.UUID{x}, []uuid.UUID{y})
+	operators := map[uuid.UUID]operator.Operator{a.Id: a, b.Id: b}
+	artifacts := map[uuid.UUID]artifact.Artifact{x: {Id: x}, y: {Id: y}}
+
+	ready := map[uuid.UUID]bool{}
+	operatorDependencies := map[uuid.UUID]map[uuid.UUID]bool{}
+	artifactToDownstreamOperatorIds := map[uuid.UUID][]uuid.UUID{}
+	initializeOrchestration(operators, ready, operatorDependencies, artifactToDownstreamOperatorIds)
+
+	artifactContentPaths := map[uuid.UUID]string{x: "run-2-x", y: "run-2-y"}
+	artifactMetadataPaths := map[uuid.UUID]string{x: "run-2-x-metadata", y: "run-2-y-metadata"}
+
+	// A previous run cached the output of operator a.
+	require.Nil(t, store.Put(ctx, "run-1-x", []byte("x")))
+	key, err := cache.cacheKey(ctx, &a, artifacts, artifactContentPaths)
+	require.Nil(t, err)
+	_, err = cache.writer.CreateCacheEntry(
+		ctx,
+		key,
+		cache.workflowId,
+		uuid.New(),
+		&operator_cache.CachedOutputs{{ContentPath: "run-1-x", Metadata: artifact_result.Metadata{{"x": "int64"}}}},
+		cache.db,
+	)
+	require.Nil(t, err)
+
+	err = cache.reuseCachedOperators(
+		ctx,
+		operators,
+		artifacts,
+		ready,
+		operatorDependencies,
+		artifactToDownstreamOperatorIds,
+		artifactContentPaths,
+		artifactMetadataPaths,
+		map[uuid.UUID]uuid.UUID{},
+		map[uuid.UUID]uuid.UUID{},
+		operator_result.NewNoopWriter(false),
+		artifact_result.NewNoopWriter(false),
+	)
+	require.Nil(t, err)
+
+	// Operator a is completed with a copy of its cached output, and operator b missed the cache.
+	require.Equal(t, map[uuid.UUID]bool{b.Id: true}, ready)
+	require.Equal(t, "run-2-x", artifactContentPaths[x])
+
+	content, err := store.Get(ctx, "run-2-x")
+	require.Nil(t, err)
+	require.Equal(t, "x", string(content))
+
+	// The copy doesn't depend on the cached run, whose outputs may be cleaned up.
+	require.Nil(t, store.Delete(ctx, "run-1-x"))
+	content, err = store.Get(ctx, "run-2-x")
+	require.Nil(t, err)
+	require.Equal(t, "x", string(content))
+
+	rawMetadata, err := store.Get(ctx, "run-2-x-metadata")
+	require.Nil(t, err)
+	require.JSONEq(t, `[{"x": "int64"}]`, string(rawMetadata))
+
+	_, missed := cache.keys[b.Id]
+	require.True(t, missed)
+
+	// Once operator b succeeds, its output is cached for the next run.
+	require.Nil(t, store.Put(ctx, "run-2-y-metadata", []byte(`[{"y": "float64"}]`)))
+	cache.store(ctx, &b, artifactContentPaths, artifactMetadataPaths, map[uuid.UUID]uuid.UUID{})
+
+	require.Nil(t, store.Put(ctx, "run-2-y", []byte("y")))
+	entry, err := cache.lookup(ctx, &b, artifacts, artifactContentPaths)
+	require.Nil(t, err)
+	require.NotNil(t, entry)
+	require.Equal(t, "run-2-y", entry.Outputs[0].ContentPath)
+}
+
+func TestCacheEntryWithMissingOutputs(t *testing.T) {
+	ctx := context.Background()
+	cache, store := newTestOperatorCache(t)
+	require.Nil(t, store.Put(ctx, "function", []byte("code")))
+
+	x := uuid.New()
+	op := newTestCachedOperator(t, "function", nil, []uuid.UUID{x})
+	key, err := cache.cacheKey(ctx, &op, nil, nil)
+	require.Nil(t, err)
+
+	_, err = cache.writer.CreateCacheEntry(
+		ctx,
+		key,
+		cache.workflowId,
+		uuid.New(),
+		&operator_cache.CachedOutputs{{ContentPath: "deleted"}},
+		cache.db,
+	)
+	require.Nil(t, err)
+
+	// The entry is removed, since its output no longer exists.
+	entry, err := cache.lookup(ctx, &op, nil, nil)
+	require.Nil(t, err)
+	require.Nil(t, entry)
+
+	_, err = cache.reader.GetCacheEntry(ctx, key, cache.db)
+	require.Equal(t, database.ErrNoRows, err)
+}
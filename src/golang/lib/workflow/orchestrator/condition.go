@@ -0,0 +1,165 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact"
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// `isRunConditionInput` returns whether the input artifact is only read to evaluate the operator's
+// run condition, in which case it is not passed on to the operator's job.
+func isRunConditionInput(op *operator.Operator, artifactId uuid.UUID) bool {
+	runCondition := op.Spec.RunCondition()
+	return runCondition != nil && runCondition.ArtifactId == artifactId
+}
+
+// `operatorConditions` keeps track of the operators that were skipped because their run condition,
+// or the run condition of an operator upstream of them, was not met.
+type operatorConditions struct {
+	skipped map[uuid.UUID]bool
+}
+
+func newOperatorConditions() *operatorConditions {
+	return &operatorConditions{
+		skipped: map[uuid.UUID]bool{},
+	}
+}
+
+// `skipUnmetConditions` evaluates the run conditions of the ready operators. The operators whose
+// condition is not met are removed from `ready`, and they and every operator downstream of them
+// are skipped. For non-preview execution, their results and the results of their artifacts are
+// marked as skipped.
+func (c *operatorConditions) skipUnmetConditions(
+	ctx context.Context,
+	operators map[uuid.UUID]operator.Operator,
+	artifacts map[uuid.UUID]artifact.Artifact,
+	ready map[uuid.UUID]bool,
+	artifactToDownstreamOperatorIds map[uuid.UUID][]uuid.UUID,
+	storageConfig *shared.StorageConfig,
+	artifactContentPaths map[uuid.UUID]string,
+	artifactMetadataPaths map[uuid.UUID]string,
+	operatorToOperatorResult map[uuid.UUID]uuid.UUID,
+	artifactToArtifactResult map[uuid.UUID]uuid.UUID,
+	operatorResultWriter operator_result.Writer,
+	artifactResultWriter artifact_result.Writer,
+	db database.Database,
+	isPreview bool,
+) error {
+	for id := range ready {
+		op, ok := operators[id]
+		if !ok {
+			return operatorExecutionError(operators, id)
+		}
+
+		runCondition := op.Spec.RunCondition()
+		if runCondition == nil {
+			continue
+		}
+
+		content, err := storage.NewStorage(storageConfig).Get(ctx, artifactContentPaths[runCondition.ArtifactId])
+		if err != nil {
+			return errors.Wrapf(err, "Unable to read the run condition of operator %s.", op.Name)
+		}
+
+		value, err := strconv.ParseBool(string(content))
+		if err != nil {
+			return errors.Wrapf(err, "The run condition of operator %s is not a boolean.", op.Name)
+		}
+
+		if runCondition.IsMet(value) {
+			continue
+		}
+
+		log.Infof("Skipping operator %s, since its run condition was not met.", op.Name)
+		delete(ready, id)
+		c.skipped[id] = true
+
+		skippedOps := []uuid.UUID{id}
+		skippedOps = append(skippedOps, c.downstreamOperators(&op, operators, artifactToDownstreamOperatorIds)...)
+		if isPreview {
+			continue
+		}
+
+		for _, skippedOpId := range skippedOps {
+			skippedOp := operators[skippedOpId]
+			reason := fmt.Sprintf("Operator was skipped because upstream operator %s was skipped.", op.Name)
+			if skippedOpId == id {
+				reason = fmt.Sprintf(
+					"Operator was skipped because artifact %s is %t.",
+					artifacts[runCondition.ArtifactId].Name,
+					value,
+				)
+			}
+
+			utils.UpdateOperatorAndArtifactResults(
+				ctx,
+				&skippedOp,
+				storageConfig,
+				shared.SkippedExecutionStatus,
+				&operator_result.Metadata{Error: reason},
+				artifactMetadataPaths,
+				operatorToOperatorResult,
+				artifactToArtifactResult,
+				operatorResultWriter,
+				artifactResultWriter,
+				db,
+			)
+		}
+	}
+
+	return nil
+}
+
+// `downstreamOperators` marks every operator downstream of the skipped operator as skipped, and
+// returns the ones that were not skipped before. They can never run, because one of their inputs
+// will never be produced.
+func (c *operatorConditions) downstreamOperators(
+	skippedOp *operator.Operator,
+	operators map[uuid.UUID]operator.Operator,
+	artifactToDownstreamOperatorIds map[uuid.UUID][]uuid.UUID,
+) []uuid.UUID {
+	downstream := []uuid.UUID{}
+	artifactIds := append([]uuid.UUID{}, skippedOp.Outputs...)
+
+	for len(artifactIds) > 0 {
+		artifactId := artifactIds[0]
+		artifactIds = artifactIds[1:]
+
+		for _, downstreamOpId := range artifactToDownstreamOperatorIds[artifactId] {
+			if c.skipped[downstreamOpId] {
+				continue
+			}
+
+			c.skipped[downstreamOpId] = true
+			downstream = append(downstream, downstreamOpId)
+			artifactIds = append(artifactIds, operators[downstreamOpId].Outputs...)
+		}
+	}
+
+	return downstream
+}
+
+// `numExpectedOperators` returns how many of the run's operators are expected to run, i.e. the
+// operators that were not skipped by a run condition. Operators that were also skipped because
+// of a failed operator still count towards the failure.
+func (c *operatorConditions) numExpectedOperators(numOperators int, failures *operatorFailures) int {
+	for id := range c.skipped {
+		if !failures.skipped[id] {
+			numOperators--
+		}
+	}
+
+	return numOperators
+}
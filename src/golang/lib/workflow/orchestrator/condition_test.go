@@ -0,0 +1,122 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/workflow/artifact/boolean"
+	"github.com/aqueducthq/aqueduct/lib/workflow/artifact/table"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConditionalOperator(t *testing.T, name string, conditionArtifactId uuid.UUID, runIf bool) operator.Operator {
+	var spec operator.Spec
+	err := json.Unmarshal([]byte(fmt.Sprintf(
+		`{"function": {"type": "file"}, "run_condition": {"artifact_id": "%s", "run_if": %t}}`,
+		conditionArtifactId,
+		runIf,
+	)), &spec)
+	require.Nil(t, err)
+
+	return operator.Operator{Id: uuid.New(), Name: name, Spec: spec, Inputs: []uuid.UUID{conditionArtifactId}}
+}
+
+func TestConditionalBranches(t *testing.T) {
+	ctx := context.Background()
+	storageConfig := &shared.StorageConfig{
+		Type:       shared.FileStorageType,
+		FileConfig: &shared.FileConfig{Directory: t.TempDir()},
+	}
+
+	passed := artifact.Artifact{Id: uuid.New(), Name: "fresh", Spec: *artifact.NewSpecFromBool(boolean.Bool{})}
+	loaded := artifact.Artifact{Id: uuid.New(), Spec: *artifact.NewSpecFromTable(table.Table{})}
+	artifacts := map[uuid.UUID]artifact.Artifact{passed.Id: passed, loaded.Id: loaded}
+
+	check := operator.Operator{Id: uuid.New(), Name: "freshness", Outputs: []uuid.UUID{passed.Id}}
+	production := newTestConditionalOperator(t, "production", passed.Id, true)
+	production.Outputs = []uuid.UUID{loaded.Id}
+	quarantine := newTestConditionalOperator(t, "quarantine", passed.Id, false)
+	report := operator.Operator{Id: uuid.New(), Name: "report", Inputs: []uuid.UUID{loaded.Id}}
+	operators := map[uuid.UUID]operator.Operator{
+		check.Id:      check,
+		production.Id: production,
+		quarantine.Id: quarantine,
+		report.Id:     report,
+	}
+
+	ready := map[uuid.UUID]bool{}
+	operatorDependencies := map[uuid.UUID]map[uuid.UUID]bool{}
+	artifactToDownstreamOperatorIds := map[uuid.UUID][]uuid.UUID{}
+	initializeOrchestration(operators, ready, operatorDependencies, artifactToDownstreamOperatorIds)
+
+	// The check did not pass.
+	contentPaths := map[uuid.UUID]string{passed.Id: "passed", loaded.Id: "loaded"}
+	require.Nil(t, storage.NewStorage(storageConfig).Put(ctx, contentPaths[passed.Id], []byte("false")))
+
+	delete(ready, check.Id)
+	require.Nil(t, unblockDownstreamOperators(&check, operators, ready, operatorDependencies, artifactToDownstreamOperatorIds))
+	require.Equal(t, map[uuid.UUID]bool{production.Id: true, quarantine.Id: true}, ready)
+
+	conditions := newOperatorConditions()
+	err := conditions.skipUnmetConditions(
+		ctx,
+		operators,
+		artifacts,
+		ready,
+		artifactToDownstreamOperatorIds,
+		storageConfig,
+		contentPaths,
+		map[uuid.UUID]string{},
+		map[uuid.UUID]uuid.UUID{},
+		map[uuid.UUID]uuid.UUID{},
+		nil, /* operatorResultWriter */
+		nil, /* artifactResultWriter */
+		database.NewNoopDatabase(),
+		true, /* isPreview */
+	)
+	require.Nil(t, err)
+
+	// Only the quarantine branch runs, and the operators downstream of production are skipped.
+	require.Equal(t, map[uuid.UUID]bool{quarantine.Id: true}, ready)
+	require.Equal(t, map[uuid.UUID]bool{production.Id: true, report.Id: true}, conditions.skipped)
+
+	// The skipped operators do not count towards the status of a run that continues on failure.
+	require.Equal(t, 2, conditions.numExpectedOperators(len(operators), newOperatorFailures()))
+
+	// The condition's artifact is not passed to the quarantine operator.
+	jobManager := &launchedSpecs{}
+	err = scheduleOperators(
+		ctx,
+		operators,
+		artifacts,
+		map[uuid.UUID]bool{},
+		ready,
+		map[uuid.UUID]bool{},
+		map[uuid.UUID]string{},
+		storageConfig,
+		contentPaths,
+		map[uuid.UUID]string{passed.Id: "passed-metadata", loaded.Id: "loaded-metadata"},
+		map[uuid.UUID]string{quarantine.Id: "quarantine-metadata"},
+		map[uuid.UUID]uuid.UUID{},
+		nil, /* fanOuts */
+		nil, /* subWorkflows */
+		nil, /* approvals */
+		jobManager,
+		nil, /* vaultObject */
+	)
+	require.Nil(t, err)
+	require.Len(t, jobManager.specs, 1)
+
+	functionSpec, ok := jobManager.specs[0].(*job.FunctionSpec)
+	require.True(t, ok)
+	require.Empty(t, functionSpec.InputContentPaths)
+}
@@ -0,0 +1,121 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/google/uuid"
+)
+
+// `continuesOnFailure` returns whether the run keeps executing the operators that do not depend on
+// a failed operator.
+func continuesOnFailure(workflowMetadata *workflow.Workflow) bool {
+	return workflowMetadata != nil &&
+		workflowMetadata.ExecutionPolicy.FailurePolicy == workflow.ContinueFailurePolicy
+}
+
+// `operatorFailures` keeps track of the operators that failed during a run that continues on failure,
+// and of the operators that were skipped because they depend on a failed operator.
+type operatorFailures struct {
+	failed  map[uuid.UUID]bool
+	skipped map[uuid.UUID]bool
+}
+
+func newOperatorFailures() *operatorFailures {
+	return &operatorFailures{
+		failed:  map[uuid.UUID]bool{},
+		skipped: map[uuid.UUID]bool{},
+	}
+}
+
+// `unreachableOperators` returns the operators downstream of the failed operator that have not been
+// skipped yet. They can never run, because one of their inputs will never be produced.
+func (f *operatorFailures) unreachableOperators(
+	failedOp *operator.Operator,
+	operators map[uuid.UUID]operator.Operator,
+	artifactToDownstreamOperatorIds map[uuid.UUID][]uuid.UUID,
+) []uuid.UUID {
+	unreachable := []uuid.UUID{}
+	artifactIds := append([]uuid.UUID{}, failedOp.Outputs...)
+
+	for len(artifactIds) > 0 {
+		artifactId := artifactIds[0]
+		artifactIds = artifactIds[1:]
+
+		for _, downstreamOpId := range artifactToDownstreamOperatorIds[artifactId] {
+			if f.skipped[downstreamOpId] {
+				continue
+			}
+
+			f.skipped[downstreamOpId] = true
+			unreachable = append(unreachable, downstreamOpId)
+			artifactIds = append(artifactIds, operators[downstreamOpId].Outputs...)
+		}
+	}
+
+	return unreachable
+}
+
+// `recordFailure` marks the operator as failed and skips every operator downstream of it.
+// For non-preview execution, the results of the skipped operators and their artifacts are
+// marked as skipped.
+func (f *operatorFailures) recordFailure(
+	ctx context.Context,
+	failedOp *operator.Operator,
+	operators map[uuid.UUID]operator.Operator,
+	artifactToDownstreamOperatorIds map[uuid.UUID][]uuid.UUID,
+	storageConfig *shared.StorageConfig,
+	artifactMetadataPaths map[uuid.UUID]string,
+	operatorToOperatorResult map[uuid.UUID]uuid.UUID,
+	artifactToArtifactResult map[uuid.UUID]uuid.UUID,
+	operatorResultWriter operator_result.Writer,
+	artifactResultWriter artifact_result.Writer,
+	db database.Database,
+	isPreview bool,
+) {
+	f.failed[failedOp.Id] = true
+
+	for _, id := range f.unreachableOperators(failedOp, operators, artifactToDownstreamOperatorIds) {
+		if isPreview {
+			continue
+		}
+
+		skippedOp := operators[id]
+		utils.UpdateOperatorAndArtifactResults(
+			ctx,
+			&skippedOp,
+			storageConfig,
+			shared.SkippedExecutionStatus,
+			&operator_result.Metadata{
+				Error: fmt.Sprintf("Operator was skipped because upstream operator %s failed.", failedOp.Name),
+			},
+			artifactMetadataPaths,
+			operatorToOperatorResult,
+			artifactToArtifactResult,
+			operatorResultWriter,
+			artifactResultWriter,
+			db,
+		)
+	}
+}
+
+// `runStatus` returns the status of a run that finished executing every reachable operator.
+// The run partially succeeds if some, but not all, of its operators ran successfully.
+func (f *operatorFailures) runStatus(numOperators int) shared.ExecutionStatus {
+	if len(f.failed) == 0 {
+		return shared.SucceededExecutionStatus
+	}
+
+	if len(f.failed)+len(f.skipped) == numOperators {
+		return shared.FailedExecutionStatus
+	}
+
+	return shared.PartialSuccessExecutionStatus
+}
@@ -0,0 +1,86 @@
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDag returns operators a -> b -> d and c -> d, plus an independent operator e,
+// connected by one artifact per operator.
+func newTestDag() (map[string]operator.Operator, map[uuid.UUID]operator.Operator, map[uuid.UUID][]uuid.UUID) {
+	artifacts := map[string]uuid.UUID{}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		artifacts[name] = uuid.New()
+	}
+
+	byName := map[string]operator.Operator{
+		"a": {Id: uuid.New(), Name: "a", Outputs: []uuid.UUID{artifacts["a"]}},
+		"b": {Id: uuid.New(), Name: "b", Inputs: []uuid.UUID{artifacts["a"]}, Outputs: []uuid.UUID{artifacts["b"]}},
+		"c": {Id: uuid.New(), Name: "c", Outputs: []uuid.UUID{artifacts["c"]}},
+		"d": {
+			Id:      uuid.New(),
+			Name:    "d",
+			Inputs:  []uuid.UUID{artifacts["b"], artifacts["c"]},
+			Outputs: []uuid.UUID{artifacts["d"]},
+		},
+		"e": {Id: uuid.New(), Name: "e", Outputs: []uuid.UUID{artifacts["e"]}},
+	}
+
+	operators := map[uuid.UUID]operator.Operator{}
+	for _, op := range byName {
+		operators[op.Id] = op
+	}
+
+	artifactToDownstreamOperatorIds := map[uuid.UUID][]uuid.UUID{}
+	initializeOrchestration(
+		operators,
+		map[uuid.UUID]bool{},
+		map[uuid.UUID]map[uuid.UUID]bool{},
+		artifactToDownstreamOperatorIds,
+	)
+
+	return byName, operators, artifactToDownstreamOperatorIds
+}
+
+func TestUnreachableOperators(t *testing.T) {
+	byName, operators, artifactToDownstreamOperatorIds := newTestDag()
+	failures := newOperatorFailures()
+
+	a := byName["a"]
+	unreachable := failures.unreachableOperators(&a, operators, artifactToDownstreamOperatorIds)
+	require.ElementsMatch(t, []uuid.UUID{byName["b"].Id, byName["d"].Id}, unreachable)
+
+	// Operators that were already skipped are not returned again.
+	c := byName["c"]
+	require.Empty(t, failures.unreachableOperators(&c, operators, artifactToDownstreamOperatorIds))
+}
+
+func TestRunStatus(t *testing.T) {
+	byName, operators, artifactToDownstreamOperatorIds := newTestDag()
+
+	require.Equal(t, shared.SucceededExecutionStatus, newOperatorFailures().runStatus(len(operators)))
+
+	failures := newOperatorFailures()
+	a := byName["a"]
+	failures.failed[a.Id] = true
+	failures.unreachableOperators(&a, operators, artifactToDownstreamOperatorIds)
+	require.Equal(t, shared.PartialSuccessExecutionStatus, failures.runStatus(len(operators)))
+
+	for _, name := range []string{"c", "e"} {
+		failures.failed[byName[name].Id] = true
+	}
+	require.Equal(t, shared.FailedExecutionStatus, failures.runStatus(len(operators)))
+}
+
+func TestContinuesOnFailure(t *testing.T) {
+	require.False(t, continuesOnFailure(nil))
+	require.False(t, continuesOnFailure(&workflow.Workflow{}))
+	require.True(t, continuesOnFailure(&workflow.Workflow{
+		ExecutionPolicy: workflow.ExecutionPolicy{FailurePolicy: workflow.ContinueFailurePolicy},
+	}))
+}
@@ -0,0 +1,378 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/workflow/scheduler"
+	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// The content of a table artifact, as written by the executor.
+type tableContent struct {
+	Schema json.RawMessage   `json:"schema"`
+	Data   []json.RawMessage `json:"data"`
+}
+
+// `fanOutJobManager` runs map operators on top of the wrapped job manager. Each map operator is
+// tracked as a single job that only this job manager knows of: polling the job polls and launches
+// the jobs of its elements, and cancelling the job cancels them. All other jobs are handled by the
+// wrapped job manager.
+type fanOutJobManager struct {
+	job.JobManager
+	fanOuts map[string]*fanOut
+}
+
+func newFanOutJobManager(jobManager job.JobManager) *fanOutJobManager {
+	return &fanOutJobManager{
+		JobManager: jobManager,
+		fanOuts:    map[string]*fanOut{},
+	}
+}
+
+func (m *fanOutJobManager) Poll(ctx context.Context, name string) (shared.ExecutionStatus, error) {
+	if f, ok := m.fanOuts[name]; ok {
+		return f.poll(ctx, m.JobManager)
+	}
+
+	return m.JobManager.Poll(ctx, name)
+}
+
+func (m *fanOutJobManager) Cancel(ctx context.Context, name string) error {
+	if f, ok := m.fanOuts[name]; ok {
+		f.cancel(ctx, m.JobManager)
+		return nil
+	}
+
+	return m.JobManager.Cancel(ctx, name)
+}
+
+// `fanOutElement` is a single element of a map operator, run as its own job.
+type fanOutElement struct {
+	jobName            string
+	inputContentPath   string
+	metadataPath       string
+	outputContentPath  string
+	outputMetadataPath string
+	result             operator_result.ElementResult
+	logs               map[string]string
+}
+
+// `fanOut` keeps track of the elements of a single run of a map operator.
+type fanOut struct {
+	op                 *operator.Operator
+	elements           []fanOutElement
+	inputArtifactTypes []artifact.Type
+	inputContentPaths  []string
+	inputMetadataPaths []string
+	mapInputIndex      int
+	metadataPath       string
+	outputContentPath  string
+	outputMetadataPath string
+	storageConfig      *shared.StorageConfig
+	status             shared.ExecutionStatus
+}
+
+// `launch` starts running the map operator `op`. The list the operator maps over is read from
+// `inputContentPaths[mapInputIndex]`, and each of its elements is written to storage for the job
+// of that element. It returns the name of the job that tracks the operator. If the list cannot
+// be read, the job fails with the reason in the operator's metadata.
+func (m *fanOutJobManager) launch(
+	ctx context.Context,
+	op *operator.Operator,
+	inputArtifactSpecs []artifact.Spec,
+	inputContentPaths []string,
+	inputMetadataPaths []string,
+	outputArtifactSpecs []artifact.Spec,
+	outputContentPaths []string,
+	outputMetadataPaths []string,
+	metadataPath string,
+	mapInputIndex int,
+	storageConfig *shared.StorageConfig,
+) (string, error) {
+	if !op.Spec.IsFunction() {
+		return "", errors.Newf("Only function operators can be mapped, but operator %s is a %s.", op.Name, op.Spec.Type())
+	}
+
+	if len(outputArtifactSpecs) != 1 || !outputArtifactSpecs[0].IsTable() {
+		return "", errors.Newf("Map operator %s must output a single table.", op.Name)
+	}
+
+	inputArtifactTypes := make([]artifact.Type, 0, len(inputArtifactSpecs))
+	for _, inputArtifactSpec := range inputArtifactSpecs {
+		inputArtifactTypes = append(inputArtifactTypes, inputArtifactSpec.Type())
+	}
+
+	f := &fanOut{
+		op:                 op,
+		inputArtifactTypes: inputArtifactTypes,
+		inputContentPaths:  inputContentPaths,
+		inputMetadataPaths: inputMetadataPaths,
+		mapInputIndex:      mapInputIndex,
+		metadataPath:       metadataPath,
+		outputContentPath:  outputContentPaths[0],
+		outputMetadataPath: outputMetadataPaths[0],
+		storageConfig:      storageConfig,
+		status:             shared.PendingExecutionStatus,
+	}
+
+	name := fmt.Sprintf("map-operator-%s", uuid.New().String())
+	m.fanOuts[name] = f
+
+	store := storage.NewStorage(storageConfig)
+	content, err := store.Get(ctx, inputContentPaths[mapInputIndex])
+	if err != nil {
+		return "", errors.Wrapf(err, "Unable to read the list of map operator %s.", op.Name)
+	}
+
+	var elements []json.RawMessage
+	if err := json.Unmarshal(content, &elements); err != nil {
+		f.finish(ctx, shared.FailedExecutionStatus, fmt.Sprintf("Map operator %s can only map over a JSON list.", op.Name))
+		return name, nil
+	}
+
+	f.elements = make([]fanOutElement, 0, len(elements))
+	for i, element := range elements {
+		e := fanOutElement{
+			inputContentPath:   fmt.Sprintf("%s-element-%d", inputContentPaths[mapInputIndex], i),
+			metadataPath:       fmt.Sprintf("%s-element-%d", metadataPath, i),
+			outputContentPath:  fmt.Sprintf("%s-element-%d", f.outputContentPath, i),
+			outputMetadataPath: fmt.Sprintf("%s-element-%d", f.outputMetadataPath, i),
+			result:             operator_result.ElementResult{Status: shared.PendingExecutionStatus},
+		}
+
+		if err := store.Put(ctx, e.inputContentPath, element); err != nil {
+			return "", errors.Wrapf(err, "Unable to write element %d of map operator %s.", i, op.Name)
+		}
+
+		f.elements = append(f.elements, e)
+	}
+
+	log.Infof("Mapping operator %s over %d elements.", op.Name, len(f.elements))
+	if _, err := f.poll(ctx, m.JobManager); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// `poll` collects the elements whose jobs finished, and launches the elements that are waiting,
+// as long as the operator's concurrency limit allows and no element has failed. Once every element
+// that was launched finished, the operator finishes as well.
+func (f *fanOut) poll(ctx context.Context, jobManager job.JobManager) (shared.ExecutionStatus, error) {
+	if f.status != shared.PendingExecutionStatus {
+		return f.status, nil
+	}
+
+	numActive := 0
+	var failed *fanOutElement
+	for i := range f.elements {
+		e := &f.elements[i]
+		if e.jobName != "" && e.result.Status == shared.PendingExecutionStatus {
+			jobStatus, err := jobManager.Poll(ctx, e.jobName)
+			if err != nil {
+				return shared.FailedExecutionStatus, err
+			}
+
+			if isJobWaiting(jobStatus) {
+				numActive++
+				continue
+			}
+
+			metadata, status, _ := scheduler.CheckOperatorExecutionStatus(ctx, jobStatus, f.storageConfig, e.metadataPath)
+			e.result = operator_result.ElementResult{Status: status, Error: metadata.Error}
+			e.logs = metadata.Logs
+		}
+
+		if e.result.Status == shared.FailedExecutionStatus && failed == nil {
+			failed = e
+		}
+	}
+
+	if failed == nil {
+		maxConcurrency := f.op.Spec.Map().MaxConcurrency
+		for i := range f.elements {
+			if maxConcurrency > 0 && numActive >= maxConcurrency {
+				break
+			}
+
+			e := &f.elements[i]
+			if e.jobName != "" {
+				continue
+			}
+
+			jobName, err := f.launchElement(ctx, e, jobManager)
+			if err != nil {
+				return shared.FailedExecutionStatus, err
+			}
+
+			e.jobName = jobName
+			numActive++
+		}
+	}
+
+	if numActive > 0 {
+		return shared.PendingExecutionStatus, nil
+	}
+
+	if failed != nil {
+		f.finish(ctx, shared.FailedExecutionStatus, fmt.Sprintf("An element of map operator %s failed: %s", f.op.Name, failed.result.Error))
+	} else if err := f.combineOutputs(ctx); err != nil {
+		log.Errorf("Unable to combine the outputs of map operator %s: %v", f.op.Name, err)
+		f.finish(ctx, shared.FailedExecutionStatus, fmt.Sprintf("Unable to combine the outputs of map operator %s.", f.op.Name))
+	} else {
+		f.finish(ctx, shared.SucceededExecutionStatus, "")
+	}
+
+	return f.status, nil
+}
+
+// `launchElement` launches the job of an element. The element takes the place of the list among
+// the operator's inputs. The logs of the element's job are not streamed, but they are collected
+// into the operator's metadata once the operator finishes.
+func (f *fanOut) launchElement(ctx context.Context, e *fanOutElement, jobManager job.JobManager) (string, error) {
+	inputContentPaths := append([]string{}, f.inputContentPaths...)
+	inputContentPaths[f.mapInputIndex] = e.inputContentPath
+
+	inputArtifactTypes := append([]artifact.Type{}, f.inputArtifactTypes...)
+	inputArtifactTypes[f.mapInputIndex] = artifact.JsonType
+
+	return scheduler.ScheduleFunction(
+		ctx,
+		*f.op.Spec.Function(),
+		e.metadataPath,
+		inputContentPaths,
+		f.inputMetadataPaths,
+		[]string{e.outputContentPath},
+		[]string{e.outputMetadataPath},
+		inputArtifactTypes,
+		[]artifact.Type{artifact.TableType},
+		f.storageConfig,
+		jobManager,
+	)
+}
+
+// `cancel` stops the jobs of the elements that are running.
+func (f *fanOut) cancel(ctx context.Context, jobManager job.JobManager) {
+	if f.status != shared.PendingExecutionStatus {
+		return
+	}
+
+	for i := range f.elements {
+		e := &f.elements[i]
+		if e.jobName == "" || e.result.Status != shared.PendingExecutionStatus {
+			continue
+		}
+
+		err := jobManager.Cancel(ctx, e.jobName)
+		if err != nil && err != job.ErrJobNotExist {
+			log.Errorf("Unable to cancel job %s: %v", e.jobName, err)
+		}
+		e.result = operator_result.ElementResult{Status: shared.CanceledExecutionStatus}
+	}
+
+	f.finish(ctx, shared.CanceledExecutionStatus, "Operator was cancelled.")
+}
+
+// `combineOutputs` writes the rows of the tables output by the elements, in the order of the list,
+// to the operator's output. The schema of the combined table is the schema of the first element's.
+func (f *fanOut) combineOutputs(ctx context.Context) error {
+	store := storage.NewStorage(f.storageConfig)
+
+	combined := tableContent{
+		Schema: json.RawMessage(`{"fields": []}`),
+		Data:   []json.RawMessage{},
+	}
+	outputMetadata := []byte("[]")
+	for i, e := range f.elements {
+		content, err := store.Get(ctx, e.outputContentPath)
+		if err != nil {
+			return err
+		}
+
+		var table tableContent
+		if err := json.Unmarshal(content, &table); err != nil {
+			return errors.Wrapf(err, "Element %d did not output a table.", i)
+		}
+
+		if i == 0 {
+			combined.Schema = table.Schema
+			outputMetadata, err = store.Get(ctx, e.outputMetadataPath)
+			if err != nil {
+				return err
+			}
+		}
+		combined.Data = append(combined.Data, table.Data...)
+	}
+
+	rawCombined, err := json.Marshal(combined)
+	if err != nil {
+		return err
+	}
+
+	if err := store.Put(ctx, f.outputContentPath, rawCombined); err != nil {
+		return err
+	}
+
+	return store.Put(ctx, f.outputMetadataPath, outputMetadata)
+}
+
+// `finish` writes the operator's metadata, which holds the results of the elements and their logs,
+// and removes the files the elements were run with. The elements that were never launched are
+// marked as skipped.
+func (f *fanOut) finish(ctx context.Context, status shared.ExecutionStatus, errMsg string) {
+	f.status = status
+
+	metadata := operator_result.Metadata{
+		Error:    errMsg,
+		Logs:     map[string]string{},
+		Elements: make([]operator_result.ElementResult, 0, len(f.elements)),
+	}
+	intermediatePaths := make([]string, 0, 4*len(f.elements))
+	for i := range f.elements {
+		e := &f.elements[i]
+		if e.jobName == "" {
+			e.result = operator_result.ElementResult{Status: shared.SkippedExecutionStatus}
+		}
+		metadata.Elements = append(metadata.Elements, e.result)
+
+		logKeys := make([]string, 0, len(e.logs))
+		for key := range e.logs {
+			logKeys = append(logKeys, key)
+		}
+		sort.Strings(logKeys)
+		for _, key := range logKeys {
+			metadata.Logs[key] += fmt.Sprintf("[element %d]\n%s\n", i, strings.TrimRight(e.logs[key], "\n"))
+		}
+
+		intermediatePaths = append(
+			intermediatePaths,
+			e.inputContentPath,
+			e.metadataPath,
+			e.outputContentPath,
+			e.outputMetadataPath,
+		)
+	}
+
+	rawMetadata, err := json.Marshal(metadata)
+	if err == nil {
+		err = storage.NewStorage(f.storageConfig).Put(ctx, f.metadataPath, rawMetadata)
+	}
+	if err != nil {
+		log.Errorf("Unable to write the metadata of map operator %s: %v", f.op.Name, err)
+	}
+
+	utils.CleanupStorageFiles(ctx, f.storageConfig, intermediatePaths)
+}
@@ -0,0 +1,193 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/workflow/artifact/jsonable"
+	"github.com/aqueducthq/aqueduct/lib/workflow/artifact/table"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// elementJobs runs the jobs of map operator elements until the test finishes them.
+type elementJobs struct {
+	job.JobManager
+	store    storage.Storage
+	names    []string
+	specs    map[string]*job.FunctionSpec
+	statuses map[string]shared.ExecutionStatus
+}
+
+func newElementJobs(store storage.Storage) *elementJobs {
+	return &elementJobs{
+		store:    store,
+		specs:    map[string]*job.FunctionSpec{},
+		statuses: map[string]shared.ExecutionStatus{},
+	}
+}
+
+func (m *elementJobs) Launch(ctx context.Context, name string, spec job.Spec) error {
+	m.names = append(m.names, name)
+	m.specs[name] = spec.(*job.FunctionSpec)
+	m.statuses[name] = shared.PendingExecutionStatus
+	return nil
+}
+
+func (m *elementJobs) Poll(ctx context.Context, name string) (shared.ExecutionStatus, error) {
+	return m.statuses[name], nil
+}
+
+// finish completes the i-th launched job. The job outputs a single row with the element it was
+// given, or fails with `errMsg` if it is set.
+func (m *elementJobs) finish(t *testing.T, i int, errMsg string) {
+	ctx := context.Background()
+	name := m.names[i]
+	spec := m.specs[name]
+
+	element, err := m.store.Get(ctx, spec.InputContentPaths[0])
+	require.Nil(t, err)
+
+	rawMetadata, err := json.Marshal(operator_result.Metadata{
+		Error: errMsg,
+		Logs:  map[string]string{"stdout": fmt.Sprintf("processing %s", element)},
+	})
+	require.Nil(t, err)
+	require.Nil(t, m.store.Put(ctx, spec.MetadataPath, rawMetadata))
+
+	output := fmt.Sprintf(`{"schema": {"fields": [{"name": "region", "type": "string"}]}, "data": [{"region": %s}]}`, element)
+	require.Nil(t, m.store.Put(ctx, spec.OutputContentPaths[0], []byte(output)))
+	require.Nil(t, m.store.Put(ctx, spec.OutputMetadataPaths[0], []byte(`[{"region": "object"}]`)))
+
+	m.statuses[name] = shared.SucceededExecutionStatus
+}
+
+func newTestMapOperator(t *testing.T, list artifact.Artifact, maxConcurrency int) *operator.Operator {
+	var spec operator.Spec
+	err := json.Unmarshal([]byte(fmt.Sprintf(
+		`{"function": {"type": "file"}, "map": {"artifact_id": "%s", "max_concurrency": %d}}`,
+		list.Id,
+		maxConcurrency,
+	)), &spec)
+	require.Nil(t, err)
+
+	return &operator.Operator{Name: "per region", Spec: spec, Inputs: []uuid.UUID{list.Id}}
+}
+
+func launchTestFanOut(t *testing.T, list string, maxConcurrency int) (*fanOutJobManager, *elementJobs, storage.Storage, string) {
+	ctx := context.Background()
+	storageConfig := &shared.StorageConfig{
+		Type:       shared.FileStorageType,
+		FileConfig: &shared.FileConfig{Directory: t.TempDir()},
+	}
+	store := storage.NewStorage(storageConfig)
+	require.Nil(t, store.Put(ctx, "regions", []byte(list)))
+
+	regions := artifact.Artifact{Id: uuid.New(), Spec: *artifact.NewSpecFromJson(jsonable.Json{})}
+	jobs := newElementJobs(store)
+	fanOuts := newFanOutJobManager(jobs)
+	name, err := fanOuts.launch(
+		ctx,
+		newTestMapOperator(t, regions, maxConcurrency),
+		[]artifact.Spec{regions.Spec},
+		[]string{"regions"},
+		[]string{"regions-metadata"},
+		[]artifact.Spec{*artifact.NewSpecFromTable(table.Table{})},
+		[]string{"output"},
+		[]string{"output-metadata"},
+		"operator-metadata",
+		0, /* mapInputIndex */
+		storageConfig,
+	)
+	require.Nil(t, err)
+
+	return fanOuts, jobs, store, name
+}
+
+func readTestOperatorMetadata(t *testing.T, store storage.Storage) operator_result.Metadata {
+	rawMetadata, err := store.Get(context.Background(), "operator-metadata")
+	require.Nil(t, err)
+
+	var metadata operator_result.Metadata
+	require.Nil(t, json.Unmarshal(rawMetadata, &metadata))
+	return metadata
+}
+
+func TestFanOutCombinesElements(t *testing.T) {
+	ctx := context.Background()
+	fanOuts, jobs, store, name := launchTestFanOut(t, `["us", "eu", "ap"]`, 2)
+
+	// Only two elements run at the same time.
+	require.Len(t, jobs.names, 2)
+	require.Equal(t, []artifact.Type{artifact.JsonType}, jobs.specs[jobs.names[0]].InputArtifactTypes)
+
+	jobs.finish(t, 0, "")
+	status, err := fanOuts.Poll(ctx, name)
+	require.Nil(t, err)
+	require.Equal(t, shared.PendingExecutionStatus, status)
+	require.Len(t, jobs.names, 3)
+
+	jobs.finish(t, 2, "")
+	jobs.finish(t, 1, "")
+	status, err = fanOuts.Poll(ctx, name)
+	require.Nil(t, err)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+
+	// The rows are combined in the order of the list.
+	output, err := store.Get(ctx, "output")
+	require.Nil(t, err)
+	var combined tableContent
+	require.Nil(t, json.Unmarshal(output, &combined))
+	require.Len(t, combined.Data, 3)
+	require.JSONEq(t, `{"region": "us"}`, string(combined.Data[0]))
+	require.JSONEq(t, `{"region": "ap"}`, string(combined.Data[2]))
+
+	metadata := readTestOperatorMetadata(t, store)
+	require.Empty(t, metadata.Error)
+	require.Len(t, metadata.Elements, 3)
+	for _, element := range metadata.Elements {
+		require.Equal(t, shared.SucceededExecutionStatus, element.Status)
+	}
+	require.Contains(t, metadata.Logs["stdout"], "[element 1]\nprocessing \"eu\"")
+
+	// The files of the elements are removed.
+	_, err = store.Get(ctx, jobs.specs[jobs.names[0]].OutputContentPaths[0])
+	require.NotNil(t, err)
+}
+
+func TestFanOutStopsOnFailedElement(t *testing.T) {
+	ctx := context.Background()
+	fanOuts, jobs, store, name := launchTestFanOut(t, `["us", "eu", "ap"]`, 1)
+
+	jobs.finish(t, 0, "no data for us")
+	status, err := fanOuts.Poll(ctx, name)
+	require.Nil(t, err)
+	require.Equal(t, shared.FailedExecutionStatus, status)
+	require.Len(t, jobs.names, 1)
+
+	metadata := readTestOperatorMetadata(t, store)
+	require.Contains(t, metadata.Error, "no data for us")
+	require.Equal(t, []operator_result.ElementResult{
+		{Status: shared.FailedExecutionStatus, Error: "no data for us"},
+		{Status: shared.SkippedExecutionStatus},
+		{Status: shared.SkippedExecutionStatus},
+	}, metadata.Elements)
+}
+
+func TestFanOutRequiresList(t *testing.T) {
+	fanOuts, jobs, store, name := launchTestFanOut(t, `{"region": "us"}`, 0)
+
+	status, err := fanOuts.Poll(context.Background(), name)
+	require.Nil(t, err)
+	require.Equal(t, shared.FailedExecutionStatus, status)
+	require.Empty(t, jobs.names)
+	require.NotEmpty(t, readTestOperatorMetadata(t, store).Error)
+}
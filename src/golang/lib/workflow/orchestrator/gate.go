@@ -0,0 +1,24 @@
+package orchestrator
+
+import (
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/google/uuid"
+)
+
+// gateOutputs returns the artifacts output by the gates among `operators`, i.e. the sensors and
+// the approvals. Operators take these artifacts as inputs only so that they become ready once the
+// gate succeeds, so the artifacts are not passed on to the operators' jobs.
+func gateOutputs(operators map[uuid.UUID]operator.Operator) map[uuid.UUID]bool {
+	outputs := map[uuid.UUID]bool{}
+	for _, op := range operators {
+		if !op.Spec.IsGate() {
+			continue
+		}
+
+		for _, artifactId := range op.Outputs {
+			outputs[artifactId] = true
+		}
+	}
+
+	return outputs
+}
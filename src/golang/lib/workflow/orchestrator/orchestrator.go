@@ -8,7 +8,9 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/collections/artifact_result"
 	"github.com/aqueducthq/aqueduct/lib/collections/notification"
 	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_cache"
 	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/run_request"
 	"github.com/aqueducthq/aqueduct/lib/collections/shared"
 	"github.com/aqueducthq/aqueduct/lib/collections/user"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
@@ -26,6 +28,10 @@ import (
 
 const (
 	defaultTimeout = 15 * time.Minute
+	// How often a run checks whether it was cancelled through the cancel workflow run route.
+	cancelCheckInterval = 5 * time.Second
+	// Bounds the writes that record how a run ended, which happen even if its context is done.
+	cleanupTimeout = time.Minute
 )
 
 var ErrIncorrectOperatorsScheduled = errors.New("Incorrect number of operators scheduled.")
@@ -75,7 +81,9 @@ func initializeOrchestration(
 // `updateCompletedOp` checks the status of actively running operators and updates
 // their status according to the execution result. It returns a bool indicating
 // whether the workflow execution should stop due to an error in the user code, and
-// any internal system error occured during the execution.
+// any internal system error occured during the execution. If `failures` is set, the
+// run continues on failure: failed operators are recorded there instead, and the
+// operators downstream of them are skipped.
 func updateCompletedOp(
 	ctx context.Context,
 	operators map[uuid.UUID]operator.Operator,
@@ -84,7 +92,13 @@ func updateCompletedOp(
 	operatorDependencies map[uuid.UUID]map[uuid.UUID]bool,
 	artifactToDownstreamOperatorIds map[uuid.UUID][]uuid.UUID,
 	operatorIdToJobId map[uuid.UUID]string,
+	operatorIdToJobStatus map[uuid.UUID]shared.ExecutionStatus,
+	retries *operatorRetries,
+	timeouts *operatorTimeouts,
+	failures *operatorFailures,
+	cache *operatorCache,
 	storageConfig *shared.StorageConfig,
+	artifactContentPaths map[uuid.UUID]string,
 	artifactMetadataPaths map[uuid.UUID]string,
 	operatorMetadataPaths map[uuid.UUID]string,
 	operatorToOperatorResult map[uuid.UUID]uuid.UUID,
@@ -103,7 +117,23 @@ func updateCompletedOp(
 			return false, err
 		}
 
-		if jobStatus != shared.PendingExecutionStatus {
+		if !isPreview {
+			updateWaitingOperatorResult(
+				ctx,
+				id,
+				jobStatus,
+				operatorIdToJobStatus,
+				operatorToOperatorResult,
+				operatorResultWriter,
+				db,
+			)
+		}
+
+		if jobStatus == shared.PendingExecutionStatus {
+			timeouts.enforce(ctx, id, operators[id].Spec.Timeout(), operatorIdToJobId[id], jobManager)
+		}
+
+		if !isJobWaiting(jobStatus) {
 			op, ok := operators[id]
 			if !ok {
 				return false, operatorExecutionError(operators, id)
@@ -116,7 +146,36 @@ func updateCompletedOp(
 				operatorMetadataPaths[op.Id],
 			)
 
+			if timedOutMetadata, ok := timeouts.checkTimedOut(id, op.Spec.Timeout(), jobStatus); ok {
+				operatorResultMetadata = timedOutMetadata
+				operatorStatus = shared.FailedExecutionStatus
+				failureType = scheduler.UserFailure
+			}
+			timeouts.reset(id)
+
+			if operatorStatus == shared.FailedExecutionStatus && retries.shouldRetry(&op, failureType) {
+				log.Infof("Operator %s failed, retrying it according to its retry policy.", op.Name)
+				retries.scheduleRetry(&op, operatorResultMetadata)
+				prepareRetry(
+					ctx,
+					&op,
+					retries,
+					storageConfig,
+					operatorMetadataPaths[op.Id],
+					operatorToOperatorResult,
+					operatorResultWriter,
+					db,
+					isPreview,
+				)
+				operatorIdToJobStatus[id] = shared.PendingExecutionStatus
+
+				// The operator is no longer active, but its downstream operators keep waiting for it.
+				completedIds = append(completedIds, id)
+				continue
+			}
+
 			if !isPreview {
+				retries.addAttempts(op.Id, operatorResultMetadata)
 				utils.UpdateOperatorAndArtifactResults(
 					ctx,
 					&op,
@@ -132,6 +191,37 @@ func updateCompletedOp(
 				)
 			}
 
+			if cache != nil {
+				if operatorStatus == shared.SucceededExecutionStatus {
+					cache.store(ctx, &op, artifactContentPaths, artifactMetadataPaths, operatorToOperatorResult)
+				} else {
+					cache.forget(op.Id)
+				}
+			}
+
+			if operatorStatus == shared.FailedExecutionStatus && failures != nil {
+				if failureType == scheduler.SystemFailure {
+					log.Errorf("%v", operatorExecutionError(operators, id))
+				}
+
+				failures.recordFailure(
+					ctx,
+					&op,
+					operators,
+					artifactToDownstreamOperatorIds,
+					storageConfig,
+					artifactMetadataPaths,
+					operatorToOperatorResult,
+					artifactToArtifactResult,
+					operatorResultWriter,
+					artifactResultWriter,
+					db,
+					isPreview,
+				)
+				completedIds = append(completedIds, id)
+				continue
+			}
+
 			if operatorStatus == shared.FailedExecutionStatus && failureType == scheduler.SystemFailure {
 				return false, operatorExecutionError(operators, id)
 			}
@@ -143,19 +233,9 @@ func updateCompletedOp(
 
 			completedIds = append(completedIds, id)
 
-			for _, artifactId := range op.Outputs {
-				if downstreampOps, ok := artifactToDownstreamOperatorIds[artifactId]; ok {
-					for _, downstreamOpId := range downstreampOps {
-						if _, ok := operatorDependencies[downstreamOpId][artifactId]; !ok {
-							return false, operatorExecutionError(operators, downstreamOpId)
-						}
-
-						delete(operatorDependencies[downstreamOpId], artifactId)
-						if len(operatorDependencies[downstreamOpId]) == 0 {
-							ready[downstreamOpId] = true
-						}
-					}
-				}
+			err := unblockDownstreamOperators(&op, operators, ready, operatorDependencies, artifactToDownstreamOperatorIds)
+			if err != nil {
+				return false, err
 			}
 		}
 	}
@@ -167,10 +247,38 @@ func updateCompletedOp(
 	return false, nil
 }
 
+// `unblockDownstreamOperators` marks the outputs of the completed operator as available, and the
+// downstream operators whose inputs are now all available as ready.
+func unblockDownstreamOperators(
+	op *operator.Operator,
+	operators map[uuid.UUID]operator.Operator,
+	ready map[uuid.UUID]bool,
+	operatorDependencies map[uuid.UUID]map[uuid.UUID]bool,
+	artifactToDownstreamOperatorIds map[uuid.UUID][]uuid.UUID,
+) error {
+	for _, artifactId := range op.Outputs {
+		if downstreampOps, ok := artifactToDownstreamOperatorIds[artifactId]; ok {
+			for _, downstreamOpId := range downstreampOps {
+				if _, ok := operatorDependencies[downstreamOpId][artifactId]; !ok {
+					return operatorExecutionError(operators, downstreamOpId)
+				}
+
+				delete(operatorDependencies[downstreamOpId], artifactId)
+				if len(operatorDependencies[downstreamOpId]) == 0 {
+					ready[downstreamOpId] = true
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
 func scheduleOperators(
 	ctx context.Context,
 	operators map[uuid.UUID]operator.Operator,
 	artifacts map[uuid.UUID]artifact.Artifact,
+	gateArtifacts map[uuid.UUID]bool,
 	ready map[uuid.UUID]bool,
 	active map[uuid.UUID]bool,
 	operatorIdToJobId map[uuid.UUID]string,
@@ -178,6 +286,10 @@ func scheduleOperators(
 	artifactContentPaths map[uuid.UUID]string,
 	artifactMetadataPaths map[uuid.UUID]string,
 	operatorMetadataPaths map[uuid.UUID]string,
+	operatorToOperatorResult map[uuid.UUID]uuid.UUID,
+	fanOuts *fanOutJobManager,
+	subWorkflows *subWorkflowJobManager,
+	approvals *approvalJobManager,
 	jobManager job.JobManager,
 	vaultObject vault.Vault,
 ) error {
@@ -195,12 +307,21 @@ func scheduleOperators(
 		inputArtifactSpecs := make([]artifact.Spec, 0, len(op.Inputs))
 		inputContentPaths := make([]string, 0, len(op.Inputs))
 		inputMetadataPaths := make([]string, 0, len(op.Inputs))
+		mapInputIndex := -1
 		for _, inputArtifactId := range op.Inputs {
+			if gateArtifacts[inputArtifactId] || isRunConditionInput(&op, inputArtifactId) {
+				continue
+			}
+
 			inputArtifact, ok := artifacts[inputArtifactId]
 			if !ok {
 				return errors.Newf("Cannot find artifact with ID %v", inputArtifactId)
 			}
 
+			if opMap := op.Spec.Map(); opMap != nil && opMap.ArtifactId == inputArtifactId {
+				mapInputIndex = len(inputContentPaths)
+			}
+
 			inputArtifactSpecs = append(inputArtifactSpecs, inputArtifact.Spec)
 			inputContentPaths = append(inputContentPaths, artifactContentPaths[inputArtifact.Id])
 			inputMetadataPaths = append(inputMetadataPaths, artifactMetadataPaths[inputArtifact.Id])
@@ -220,20 +341,68 @@ func scheduleOperators(
 			outputMetadataPaths = append(outputMetadataPaths, artifactMetadataPaths[outputArtifact.Id])
 		}
 
-		jobId, err := scheduler.ScheduleOperator(
-			ctx,
-			op.Spec,
-			inputArtifactSpecs,
-			outputArtifactSpecs,
-			operatorMetadataPath,
-			inputContentPaths,
-			inputMetadataPaths,
-			outputContentPaths,
-			outputMetadataPaths,
-			storageConfig,
-			jobManager,
-			vaultObject,
-		)
+		// The logs of non-preview operators are kept with their operator results.
+		jobCtx := ctx
+		if operatorResultId, ok := operatorToOperatorResult[id]; ok {
+			jobCtx = job.WithLogStorage(ctx, storageConfig, utils.OperatorResultLogsPath(operatorResultId))
+		}
+
+		var jobId string
+		var err error
+		if op.Spec.IsApproval() {
+			jobId, err = approvals.launch(
+				ctx,
+				&op,
+				outputContentPaths,
+				outputMetadataPaths,
+				operatorMetadataPath,
+				storageConfig,
+				operatorToOperatorResult[id],
+			)
+		} else if op.Spec.IsSubWorkflow() {
+			jobId, err = subWorkflows.launch(
+				jobCtx,
+				&op,
+				inputContentPaths,
+				outputContentPaths,
+				outputMetadataPaths,
+				operatorMetadataPath,
+				storageConfig,
+			)
+		} else if op.Spec.Map() != nil {
+			if mapInputIndex < 0 {
+				return errors.Newf("Map operator %s does not take the list it maps over as an input.", op.Name)
+			}
+
+			jobId, err = fanOuts.launch(
+				ctx,
+				&op,
+				inputArtifactSpecs,
+				inputContentPaths,
+				inputMetadataPaths,
+				outputArtifactSpecs,
+				outputContentPaths,
+				outputMetadataPaths,
+				operatorMetadataPath,
+				mapInputIndex,
+				storageConfig,
+			)
+		} else {
+			jobId, err = scheduler.ScheduleOperator(
+				jobCtx,
+				op.Spec,
+				inputArtifactSpecs,
+				outputArtifactSpecs,
+				operatorMetadataPath,
+				inputContentPaths,
+				inputMetadataPaths,
+				outputContentPaths,
+				outputMetadataPaths,
+				storageConfig,
+				jobManager,
+				vaultObject,
+			)
+		}
 		if err != nil {
 			return err
 		}
@@ -255,6 +424,7 @@ func waitForActiveOperators(
 	ctx context.Context,
 	active map[uuid.UUID]bool,
 	operatorIdToJobId map[uuid.UUID]string,
+	pollInterval time.Duration,
 	jobManager job.JobManager,
 ) {
 	for len(active) != 0 {
@@ -272,7 +442,7 @@ func waitForActiveOperators(
 				}
 			}
 
-			if jobStatus != shared.PendingExecutionStatus {
+			if !isJobWaiting(jobStatus) {
 				completedIds = append(completedIds, id)
 			}
 		}
@@ -280,9 +450,127 @@ func waitForActiveOperators(
 		for _, id := range completedIds {
 			delete(active, id)
 		}
+
+		if len(active) != 0 {
+			time.Sleep(pollInterval)
+		}
+	}
+}
+
+// `isJobWaiting` returns whether the job is still waiting for a slot or running.
+func isJobWaiting(jobStatus shared.ExecutionStatus) bool {
+	return jobStatus == shared.PendingExecutionStatus || jobStatus == shared.QueuedExecutionStatus
+}
+
+// `updateWaitingOperatorResult` updates the status of the operator result when the operator's job
+// moves between queued and running, so the operator shows up as waiting while its job is queued.
+func updateWaitingOperatorResult(
+	ctx context.Context,
+	operatorId uuid.UUID,
+	jobStatus shared.ExecutionStatus,
+	operatorIdToJobStatus map[uuid.UUID]shared.ExecutionStatus,
+	operatorToOperatorResult map[uuid.UUID]uuid.UUID,
+	operatorResultWriter operator_result.Writer,
+	db database.Database,
+) {
+	if !isJobWaiting(jobStatus) {
+		return
+	}
+
+	// Operator results are created with a pending status.
+	previousStatus, ok := operatorIdToJobStatus[operatorId]
+	if !ok {
+		previousStatus = shared.PendingExecutionStatus
+	}
+	operatorIdToJobStatus[operatorId] = jobStatus
+
+	if previousStatus == jobStatus {
+		return
+	}
+
+	_, err := operatorResultWriter.UpdateOperatorResult(
+		ctx,
+		operatorToOperatorResult[operatorId],
+		map[string]interface{}{
+			operator_result.StatusColumn: jobStatus,
+		},
+		db,
+	)
+	if err != nil {
+		log.Errorf("Unable to update the status of operator result %s: %v", operatorToOperatorResult[operatorId], err)
+	}
+}
+
+// `cancelActiveOperators` stops the jobs of all actively running operators. Operators whose
+// jobs have already finished are left for `updateCompletedOp` or `waitForActiveOperators`
+// to collect. For non-preview execution, the results of the cancelled operators are marked
+// as cancelled as well.
+func cancelActiveOperators(
+	ctx context.Context,
+	operators map[uuid.UUID]operator.Operator,
+	active map[uuid.UUID]bool,
+	operatorIdToJobId map[uuid.UUID]string,
+	artifactMetadataPaths map[uuid.UUID]string,
+	operatorToOperatorResult map[uuid.UUID]uuid.UUID,
+	artifactToArtifactResult map[uuid.UUID]uuid.UUID,
+	storageConfig *shared.StorageConfig,
+	operatorResultWriter operator_result.Writer,
+	artifactResultWriter artifact_result.Writer,
+	db database.Database,
+	jobManager job.JobManager,
+	reason string,
+	isPreview bool,
+) {
+	for id := range active {
+		err := jobManager.Cancel(ctx, operatorIdToJobId[id])
+		if err != nil && err != job.ErrJobNotExist {
+			log.Errorf("Unable to cancel job %s: %v", operatorIdToJobId[id], err)
+			continue
+		}
+
+		if isPreview {
+			continue
+		}
+
+		op, ok := operators[id]
+		if !ok {
+			continue
+		}
+
+		utils.UpdateOperatorAndArtifactResults(
+			ctx,
+			&op,
+			storageConfig,
+			shared.CanceledExecutionStatus,
+			&operator_result.Metadata{Error: reason},
+			artifactMetadataPaths,
+			operatorToOperatorResult,
+			artifactToArtifactResult,
+			operatorResultWriter,
+			artifactResultWriter,
+			db,
+		)
 	}
 }
 
+// `isWorkflowDagResultCanceled` returns whether the workflow dag result has been marked as cancelled,
+// e.g. through the cancel workflow run route. Errors are logged and treated as not cancelled, so that
+// a transient database error does not abort the run.
+func isWorkflowDagResultCanceled(
+	ctx context.Context,
+	workflowDagResultId uuid.UUID,
+	workflowDagResultReader workflow_dag_result.Reader,
+	db database.Database,
+) bool {
+	workflowDagResult, err := workflowDagResultReader.GetWorkflowDagResult(ctx, workflowDagResultId, db)
+	if err != nil {
+		log.Errorf("Unable to check whether workflow dag result %s was cancelled: %v", workflowDagResultId, err)
+		return false
+	}
+
+	return workflowDagResult.Status == shared.CanceledExecutionStatus
+}
+
 func Preview(
 	ctx context.Context,
 	dag *workflow_dag.WorkflowDag,
@@ -291,17 +579,29 @@ func Preview(
 	jobManager job.JobManager,
 	vaultObject vault.Vault,
 ) (shared.ExecutionStatus, error) {
+	// Previews are interactive, so their jobs skip ahead of queued scheduled runs.
 	return orchestrate(
-		ctx,
+		job.WithPriority(ctx, job.PreviewPriority),
 		dag,
+		nil, /* selection */
+		nil, /* resumedFrom */
+		nil, /* parameters */
+		nil, /* existingWorkflowDagResultId */
 		workflowStoragePaths,
 		pollIntervalMillisec,
 		workflow.NewNoopReader(true),
+		nil, /* workflowDagReader */
+		workflow_dag_result.NewNoopReader(true),
 		workflow_dag_result.NewNoopWriter(true),
+		operator_result.NewNoopReader(true),
 		operator_result.NewNoopWriter(true),
+		operator_cache.NewNoopReader(true),
+		operator_cache.NewNoopWriter(true),
+		artifact_result.NewNoopReader(true),
 		artifact_result.NewNoopWriter(true),
 		notification.NewNoopWriter(true),
 		user.NewNoopReader(true),
+		run_request.NewNoopWriter(true),
 		database.NewNoopDatabase(),
 		jobManager,
 		vaultObject,
@@ -312,14 +612,25 @@ func Preview(
 func Execute(
 	ctx context.Context,
 	dag *workflow_dag.WorkflowDag,
+	selection *workflow_dag.OperatorSelection,
+	resumedFrom *uuid.UUID,
+	parameters shared.Parameters,
+	existingWorkflowDagResultId *uuid.UUID,
 	workflowStoragePaths *utils.WorkflowStoragePaths,
 	pollIntervalMillisec time.Duration,
 	workflowReader workflow.Reader,
+	workflowDagReader workflow_dag.Reader,
+	workflowDagResultReader workflow_dag_result.Reader,
 	workflowDagResultWriter workflow_dag_result.Writer,
+	operatorResultReader operator_result.Reader,
 	operatorResultWriter operator_result.Writer,
+	operatorCacheReader operator_cache.Reader,
+	operatorCacheWriter operator_cache.Writer,
+	artifactResultReader artifact_result.Reader,
 	artifactResultWriter artifact_result.Writer,
 	notificationWriter notification.Writer,
 	userReader user.Reader,
+	runRequestWriter run_request.Writer,
 	db database.Database,
 	jobManager job.JobManager,
 	vaultObject vault.Vault,
@@ -327,14 +638,25 @@ func Execute(
 	return orchestrate(
 		ctx,
 		dag,
+		selection,
+		resumedFrom,
+		parameters,
+		existingWorkflowDagResultId,
 		workflowStoragePaths,
 		pollIntervalMillisec,
 		workflowReader,
+		workflowDagReader,
+		workflowDagResultReader,
 		workflowDagResultWriter,
+		operatorResultReader,
 		operatorResultWriter,
+		operatorCacheReader,
+		operatorCacheWriter,
+		artifactResultReader,
 		artifactResultWriter,
 		notificationWriter,
 		userReader,
+		runRequestWriter,
 		db,
 		jobManager,
 		vaultObject,
@@ -345,33 +667,109 @@ func Execute(
 func orchestrate(
 	ctx context.Context,
 	dag *workflow_dag.WorkflowDag,
+	selection *workflow_dag.OperatorSelection,
+	resumedFrom *uuid.UUID,
+	parameters shared.Parameters,
+	existingWorkflowDagResultId *uuid.UUID,
 	workflowStoragePaths *utils.WorkflowStoragePaths,
 	pollIntervalMillisec time.Duration,
 	workflowReader workflow.Reader,
+	workflowDagReader workflow_dag.Reader,
+	workflowDagResultReader workflow_dag_result.Reader,
 	workflowDagResultWriter workflow_dag_result.Writer,
+	operatorResultReader operator_result.Reader,
 	operatorResultWriter operator_result.Writer,
+	operatorCacheReader operator_cache.Reader,
+	operatorCacheWriter operator_cache.Writer,
+	artifactResultReader artifact_result.Reader,
 	artifactResultWriter artifact_result.Writer,
 	notificationWriter notification.Writer,
 	userReader user.Reader,
+	runRequestWriter run_request.Writer,
 	db database.Database,
 	jobManager job.JobManager,
 	vaultObject vault.Vault,
 	isPreview bool,
 ) (shared.ExecutionStatus, error) {
-	numOperators := len(dag.Operators)
+	// Only the selected operators are orchestrated. For a partial run, the artifacts they read
+	// from the other operators are reused from the latest successful run. A resumed run only
+	// orchestrates the operators that did not succeed in the resumed run.
+	operators := selectOperators(dag, selection)
+	// Map operators are run as jobs of this job manager, which launches the jobs of their elements.
+	fanOuts := newFanOutJobManager(jobManager)
+	jobManager = fanOuts
+	// Sub-workflow operators are run as child runs of this run.
+	subWorkflows := newSubWorkflowJobManager(
+		jobManager,
+		dag.Metadata,
+		workflowReader,
+		workflowDagReader,
+		workflowDagResultReader,
+		workflowDagResultWriter,
+		runRequestWriter,
+		notificationWriter,
+		userReader,
+		db,
+	)
+	jobManager = subWorkflows
+	// Approval operators pause the run until they are approved or rejected.
+	approvals := newApprovalJobManager(
+		jobManager,
+		dag.Metadata,
+		operatorResultReader,
+		operatorResultWriter,
+		workflowReader,
+		workflowDagResultWriter,
+		notificationWriter,
+		userReader,
+		db,
+	)
+	jobManager = approvals
+	var succeededInResumedRun map[uuid.UUID]operator_result.OperatorResult
+	if resumedFrom != nil && !isPreview {
+		var err error
+		operators, succeededInResumedRun, err = resumedOperators(
+			ctx,
+			dag,
+			*resumedFrom,
+			workflowDagResultReader,
+			operatorResultReader,
+			db,
+		)
+		if err != nil {
+			return shared.FailedExecutionStatus, err
+		}
+	}
+	numOperators := len(operators)
 	artifactToDownstreamOperatorIds := make(map[uuid.UUID][]uuid.UUID, len(dag.Artifacts))
 	operatorIdToJobId := make(map[uuid.UUID]string, numOperators)
+	operatorIdToJobStatus := make(map[uuid.UUID]shared.ExecutionStatus, numOperators)
+	retries := newOperatorRetries()
+	timeouts := newOperatorTimeouts()
+	var failures *operatorFailures
+	if continuesOnFailure(dag.Metadata) {
+		failures = newOperatorFailures()
+	}
+	conditions := newOperatorConditions()
+	// Previews clean up the content of their artifacts, so they never use the cache.
+	var cache *operatorCache
+	if !isPreview {
+		cache = newOperatorCache(dag.WorkflowId, &dag.StorageConfig, operatorCacheReader, operatorCacheWriter, db)
+	}
+	// The gates are looked up among all operators, since a partial or resumed run may not run them.
+	gateArtifacts := gateOutputs(dag.Operators)
 	// Maps from operator ID to its upstream artifact dependencies.
 	operatorDependencies := make(map[uuid.UUID]map[uuid.UUID]bool, numOperators)
 	ready := make(map[uuid.UUID]bool, numOperators)
 	active := make(map[uuid.UUID]bool, numOperators)
 
 	defer func() {
-		waitForActiveOperators(ctx, active, operatorIdToJobId, jobManager)
+		// The jobs are waited for even if the context of the run is done.
+		waitForActiveOperators(context.Background(), active, operatorIdToJobId, pollIntervalMillisec, jobManager)
 	}()
 
 	initializeOrchestration(
-		dag.Operators,
+		operators,
 		ready,
 		operatorDependencies,
 		artifactToDownstreamOperatorIds,
@@ -388,23 +786,53 @@ func orchestrate(
 		// First, we create a database record of workflow dag result and set its status to `pending`.
 		// TODO: wrap these writes into a transaction.
 		// eng-599-adding-transaction-support-to-our-database-reader-and-writer
-		workflowDagResult, err := workflowDagResultWriter.CreateWorkflowDagResult(ctx, dag.Id, db)
+		var workflowDagResult *workflow_dag_result.WorkflowDagResult
+		var err error
+		if existingWorkflowDagResultId != nil {
+			// The record was created when the run was requested, but the dag may have been
+			// updated since.
+			workflowDagResult, err = workflowDagResultWriter.UpdateWorkflowDagResult(
+				ctx,
+				*existingWorkflowDagResultId,
+				map[string]interface{}{workflow_dag_result.WorkflowDagIdColumn: dag.Id},
+				workflowReader,
+				notificationWriter,
+				userReader,
+				db,
+			)
+		} else {
+			workflowDagResult, err = workflowDagResultWriter.CreateWorkflowDagResult(
+				ctx,
+				dag.Id,
+				resumedFrom,
+				parameters,
+				db,
+			)
+		}
 		if err != nil {
 			return shared.FailedExecutionStatus, errors.Wrap(err, "Unable to create workflow dag result record.")
 		}
 
 		workflowDagResultId = workflowDagResult.Id
+		subWorkflows.workflowDagResultId = &workflowDagResultId
+		approvals.workflowDagResultId = &workflowDagResultId
 
 		defer func() {
 			// We `defer` this call to ensure that the WorkflowDagResult metadata is always updated.
+			cleanupCtx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+			defer cancel()
 			utils.UpdateWorkflowDagResultMetadata(
-				ctx,
+				cleanupCtx,
+				dag.WorkflowId,
 				workflowDagResultId,
 				status,
+				workflowDagResultReader,
 				workflowDagResultWriter,
 				workflowReader,
 				notificationWriter,
 				userReader,
+				workflowDagReader,
+				runRequestWriter,
 				db,
 			)
 		}()
@@ -439,9 +867,66 @@ func orchestrate(
 
 			artifactToArtifactResult[artifactId] = artifactResult.Id
 		}
+
+		if resumedFrom != nil {
+			err = seedResumedRun(
+				ctx,
+				dag,
+				operators,
+				succeededInResumedRun,
+				*resumedFrom,
+				ready,
+				operatorDependencies,
+				workflowStoragePaths.ArtifactPaths,
+				workflowStoragePaths.ArtifactMetadataPaths,
+				operatorToOperatorResult,
+				artifactToArtifactResult,
+				operatorResultWriter,
+				artifactResultReader,
+				artifactResultWriter,
+				db,
+			)
+			if err != nil {
+				return shared.FailedExecutionStatus, errors.Wrap(err, "Unable to copy the results of the resumed run.")
+			}
+		} else if len(operators) < len(dag.Operators) {
+			// The operators that are not selected, but produce inputs of the selected ones.
+			reused := reusedOperators(dag, operators)
+			var earlierWorkflowDagResultIds []uuid.UUID
+			if len(reused) > 0 {
+				earlierWorkflowDagResultIds, err = successfulWorkflowDagResultIds(ctx, dag, workflowDagResultReader, db)
+				if err != nil {
+					return shared.FailedExecutionStatus, errors.Wrap(err, "Unable to find the runs to reuse artifacts from.")
+				}
+			}
+
+			err = seedPartialRun(
+				ctx,
+				dag,
+				operators,
+				reused,
+				earlierWorkflowDagResultIds,
+				ready,
+				operatorDependencies,
+				workflowStoragePaths.ArtifactPaths,
+				workflowStoragePaths.ArtifactMetadataPaths,
+				operatorToOperatorResult,
+				artifactToArtifactResult,
+				operatorResultReader,
+				operatorResultWriter,
+				artifactResultReader,
+				artifactResultWriter,
+				db,
+			)
+			if err != nil {
+				return shared.FailedExecutionStatus, errors.Wrap(err, "Unable to reuse the artifacts of the earlier run.")
+			}
+		}
 	}
 
 	start := time.Now()
+	timeout := workflowTimeout(dag.Metadata)
+	var lastCancelCheck time.Time
 
 	// We keep orchestrating while there's any active or ready-to-schedule operators.
 	// While such case, we do the following:
@@ -453,20 +938,77 @@ func orchestrate(
 	// - schedule all operators in ready list
 	//   - mark each of them as active
 	//   - clear the ready list after all operators are scheduled
-	for len(ready) > 0 || len(active) > 0 {
-		if time.Since(start) > defaultTimeout {
-			return shared.FailedExecutionStatus, errors.New("Reached timeout waiting for workflow to complete.")
+	for len(ready) > 0 || len(active) > 0 || retries.numWaiting() > 0 {
+		// Time spent awaiting approval does not count towards the workflow's timeout, since
+		// it depends on when the approvers act.
+		if time.Since(start)-approvals.timeAwaitingApproval() > timeout {
+			retries.moveWaitingToActive(active)
+			cancelActiveOperators(
+				ctx,
+				operators,
+				active,
+				operatorIdToJobId,
+				workflowStoragePaths.ArtifactMetadataPaths,
+				operatorToOperatorResult,
+				artifactToArtifactResult,
+				&dag.StorageConfig,
+				operatorResultWriter,
+				artifactResultWriter,
+				db,
+				jobManager,
+				workflowTimeoutErrorMessage(timeout),
+				isPreview,
+			)
+			return shared.FailedExecutionStatus, errors.Newf("Reached timeout of %s waiting for workflow to complete.", timeout)
+		}
+
+		canceled := ctx.Err() != nil
+		if !canceled && !isPreview && time.Since(lastCancelCheck) >= cancelCheckInterval {
+			lastCancelCheck = time.Now()
+			canceled = isWorkflowDagResultCanceled(ctx, workflowDagResultId, workflowDagResultReader, db)
+		}
+
+		if canceled {
+			// The context of the run may be done, so the cancellation is recorded with a new one.
+			cleanupCtx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+			defer cancel()
+
+			retries.moveWaitingToActive(active)
+			cancelActiveOperators(
+				cleanupCtx,
+				operators,
+				active,
+				operatorIdToJobId,
+				workflowStoragePaths.ArtifactMetadataPaths,
+				operatorToOperatorResult,
+				artifactToArtifactResult,
+				&dag.StorageConfig,
+				operatorResultWriter,
+				artifactResultWriter,
+				db,
+				jobManager,
+				"Operator was cancelled.",
+				isPreview,
+			)
+			status = shared.CanceledExecutionStatus
+			return status, nil
 		}
 
 		stopWorkflowExecution, err := updateCompletedOp(
 			ctx,
-			dag.Operators,
+			operators,
 			ready,
 			active,
 			operatorDependencies,
 			artifactToDownstreamOperatorIds,
 			operatorIdToJobId,
+			operatorIdToJobStatus,
+			retries,
+			timeouts,
+			failures,
+			cache,
 			&dag.StorageConfig,
+			workflowStoragePaths.ArtifactPaths,
 			workflowStoragePaths.ArtifactMetadataPaths,
 			workflowStoragePaths.OperatorMetadataPaths,
 			operatorToOperatorResult,
@@ -488,11 +1030,56 @@ func orchestrate(
 			return shared.FailedExecutionStatus, nil
 		}
 
+		// Operators whose retry backoff has passed are scheduled again.
+		retries.moveDueRetries(ready)
+
+		// Operators whose run condition is not met are skipped instead of scheduled.
+		err = conditions.skipUnmetConditions(
+			ctx,
+			operators,
+			dag.Artifacts,
+			ready,
+			artifactToDownstreamOperatorIds,
+			&dag.StorageConfig,
+			workflowStoragePaths.ArtifactPaths,
+			workflowStoragePaths.ArtifactMetadataPaths,
+			operatorToOperatorResult,
+			artifactToArtifactResult,
+			operatorResultWriter,
+			artifactResultWriter,
+			db,
+			isPreview,
+		)
+		if err != nil {
+			return shared.FailedExecutionStatus, err
+		}
+
+		if cache != nil {
+			err = cache.reuseCachedOperators(
+				ctx,
+				operators,
+				dag.Artifacts,
+				ready,
+				operatorDependencies,
+				artifactToDownstreamOperatorIds,
+				workflowStoragePaths.ArtifactPaths,
+				workflowStoragePaths.ArtifactMetadataPaths,
+				operatorToOperatorResult,
+				artifactToArtifactResult,
+				operatorResultWriter,
+				artifactResultWriter,
+			)
+			if err != nil {
+				return shared.FailedExecutionStatus, err
+			}
+		}
+
 		// Schedule all operators in ready state.
 		err = scheduleOperators(
 			ctx,
-			dag.Operators,
+			operators,
 			dag.Artifacts,
+			gateArtifacts,
 			ready,
 			active,
 			operatorIdToJobId,
@@ -500,6 +1087,10 @@ func orchestrate(
 			workflowStoragePaths.ArtifactPaths,
 			workflowStoragePaths.ArtifactMetadataPaths,
 			workflowStoragePaths.OperatorMetadataPaths,
+			operatorToOperatorResult,
+			fanOuts,
+			subWorkflows,
+			approvals,
 			jobManager,
 			vaultObject,
 		)
@@ -513,6 +1104,9 @@ func orchestrate(
 	}
 
 	status = shared.SucceededExecutionStatus
+	if failures != nil {
+		status = failures.runStatus(conditions.numExpectedOperators(numOperators, failures))
+	}
 
 	return status, nil
 }
@@ -0,0 +1,307 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+var ErrNoSuccessfulRun = errors.New("A partial run needs a successful run of the latest version of the workflow to reuse artifacts from.")
+
+const unselectedOperatorErrorMessage = "Operator was not selected for this run."
+
+// `selectOperators` returns the operators of the dag that the selection runs.
+func selectOperators(
+	dag *workflow_dag.WorkflowDag,
+	selection *workflow_dag.OperatorSelection,
+) map[uuid.UUID]operator.Operator {
+	if selection.IsEmpty() {
+		return dag.Operators
+	}
+
+	operators := map[uuid.UUID]operator.Operator{}
+	for id := range selection.SelectedOperators(dag) {
+		operators[id] = dag.Operators[id]
+	}
+
+	return operators
+}
+
+// `reusedOperators` returns the operators that are not selected, but produce an input of a
+// selected operator. All of their outputs are reused from an earlier run.
+func reusedOperators(
+	dag *workflow_dag.WorkflowDag,
+	selected map[uuid.UUID]operator.Operator,
+) map[uuid.UUID]operator.Operator {
+	artifactToUpstreamOperatorId := make(map[uuid.UUID]uuid.UUID, len(dag.Artifacts))
+	for id, op := range dag.Operators {
+		for _, artifactId := range op.Outputs {
+			artifactToUpstreamOperatorId[artifactId] = id
+		}
+	}
+
+	reused := map[uuid.UUID]operator.Operator{}
+	for _, op := range selected {
+		for _, artifactId := range op.Inputs {
+			upstreamOpId, ok := artifactToUpstreamOperatorId[artifactId]
+			if !ok {
+				continue
+			}
+
+			if _, ok := selected[upstreamOpId]; !ok {
+				reused[upstreamOpId] = dag.Operators[upstreamOpId]
+			}
+		}
+	}
+
+	return reused
+}
+
+// `successfulWorkflowDagResultIds` returns the ids of the runs of the dag that succeeded or
+// partially succeeded, latest first. These runs may only have produced some of the artifacts, so
+// artifacts are looked up run by run.
+func successfulWorkflowDagResultIds(
+	ctx context.Context,
+	dag *workflow_dag.WorkflowDag,
+	workflowDagResultReader workflow_dag_result.Reader,
+	db database.Database,
+) ([]uuid.UUID, error) {
+	workflowDagResults, err := workflowDagResultReader.GetWorkflowDagResultsByWorkflowId(ctx, dag.WorkflowId, db)
+	if err != nil {
+		return nil, err
+	}
+
+	successful := make([]workflow_dag_result.WorkflowDagResult, 0, len(workflowDagResults))
+	for _, workflowDagResult := range workflowDagResults {
+		if workflowDagResult.WorkflowDagId != dag.Id {
+			continue
+		}
+
+		if workflowDagResult.Status == shared.SucceededExecutionStatus ||
+			workflowDagResult.Status == shared.PartialSuccessExecutionStatus {
+			successful = append(successful, workflowDagResult)
+		}
+	}
+
+	if len(successful) == 0 {
+		return nil, ErrNoSuccessfulRun
+	}
+
+	sort.Slice(successful, func(i, j int) bool {
+		return successful[i].CreatedAt.After(successful[j].CreatedAt)
+	})
+
+	ids := make([]uuid.UUID, 0, len(successful))
+	for _, workflowDagResult := range successful {
+		ids = append(ids, workflowDagResult.Id)
+	}
+
+	return ids, nil
+}
+
+// CheckReusableRun returns ErrNoSuccessfulRun if the selection reuses artifacts from an earlier
+// run, but the dag has no successful run to reuse them from.
+func CheckReusableRun(
+	ctx context.Context,
+	dag *workflow_dag.WorkflowDag,
+	selection *workflow_dag.OperatorSelection,
+	workflowDagResultReader workflow_dag_result.Reader,
+	db database.Database,
+) error {
+	if len(reusedOperators(dag, selectOperators(dag, selection))) == 0 {
+		return nil
+	}
+
+	_, err := successfulWorkflowDagResultIds(ctx, dag, workflowDagResultReader, db)
+	return err
+}
+
+// `earlierOutputResults` returns the results of the operator's outputs in the latest of the earlier
+// runs that produced all of them, along with the id of that run.
+func earlierOutputResults(
+	ctx context.Context,
+	op *operator.Operator,
+	earlierWorkflowDagResultIds []uuid.UUID,
+	artifactResultReader artifact_result.Reader,
+	db database.Database,
+) (uuid.UUID, map[uuid.UUID]*artifact_result.ArtifactResult, error) {
+	for _, workflowDagResultId := range earlierWorkflowDagResultIds {
+		results := make(map[uuid.UUID]*artifact_result.ArtifactResult, len(op.Outputs))
+		for _, artifactId := range op.Outputs {
+			earlierArtifactResult, err := artifactResultReader.GetArtifactResultByWorkflowDagResultIdAndArtifactId(
+				ctx,
+				workflowDagResultId,
+				artifactId,
+				db,
+			)
+			if err == database.ErrNoRows {
+				break
+			}
+			if err != nil {
+				return uuid.Nil, nil, errors.Wrapf(err, "Unable to retrieve the earlier result of artifact %s.", artifactId)
+			}
+
+			// Runs that did not select the operator skipped its outputs.
+			if earlierArtifactResult.Status != shared.SucceededExecutionStatus || earlierArtifactResult.Metadata.IsNull {
+				break
+			}
+
+			results[artifactId] = earlierArtifactResult
+		}
+
+		if len(results) == len(op.Outputs) {
+			return workflowDagResultId, results, nil
+		}
+	}
+
+	return uuid.Nil, nil, errors.Newf("The outputs of operator %s were not produced by an earlier run.", op.Name)
+}
+
+// `seedPartialRun` prepares a run of the selected operators. The outputs of each reused operator
+// are taken from the latest earlier run that produced them: their metadata is written to this
+// run's artifact metadata paths, and this run's artifacts point to the earlier content. The
+// selected operators that only read reused artifacts become ready. The results of the reused
+// operators point to the earlier runs, and every other operator that is not selected is skipped.
+func seedPartialRun(
+	ctx context.Context,
+	dag *workflow_dag.WorkflowDag,
+	selected map[uuid.UUID]operator.Operator,
+	reused map[uuid.UUID]operator.Operator,
+	earlierWorkflowDagResultIds []uuid.UUID,
+	ready map[uuid.UUID]bool,
+	operatorDependencies map[uuid.UUID]map[uuid.UUID]bool,
+	artifactContentPaths map[uuid.UUID]string,
+	artifactMetadataPaths map[uuid.UUID]string,
+	operatorToOperatorResult map[uuid.UUID]uuid.UUID,
+	artifactToArtifactResult map[uuid.UUID]uuid.UUID,
+	operatorResultReader operator_result.Reader,
+	operatorResultWriter operator_result.Writer,
+	artifactResultReader artifact_result.Reader,
+	artifactResultWriter artifact_result.Writer,
+	db database.Database,
+) error {
+	store := storage.NewStorage(&dag.StorageConfig)
+
+	earlierContentPaths := map[uuid.UUID]string{}
+	// The earlier run that each reused operator's outputs are taken from.
+	earlierWorkflowDagResultIdByOperator := make(map[uuid.UUID]uuid.UUID, len(reused))
+	for id, op := range reused {
+		earlierWorkflowDagResultId, earlierArtifactResults, err := earlierOutputResults(
+			ctx,
+			&op,
+			earlierWorkflowDagResultIds,
+			artifactResultReader,
+			db,
+		)
+		if err != nil {
+			return err
+		}
+		earlierWorkflowDagResultIdByOperator[id] = earlierWorkflowDagResultId
+
+		for artifactId, earlierArtifactResult := range earlierArtifactResults {
+			rawMetadata, err := json.Marshal(earlierArtifactResult.Metadata.Metadata)
+			if err != nil {
+				return err
+			}
+
+			if err := store.Put(ctx, artifactMetadataPaths[artifactId], rawMetadata); err != nil {
+				return err
+			}
+
+			earlierContentPaths[artifactId] = earlierArtifactResult.ContentPath
+		}
+	}
+
+	for artifactId, contentPath := range earlierContentPaths {
+		artifactContentPaths[artifactId] = contentPath
+
+		_, err := artifactResultWriter.UpdateArtifactResult(
+			ctx,
+			artifactToArtifactResult[artifactId],
+			map[string]interface{}{
+				artifact_result.ContentPathColumn: contentPath,
+			},
+			db,
+		)
+		if err != nil {
+			log.Errorf("Unable to update the content path of artifact result %s: %v", artifactToArtifactResult[artifactId], err)
+		}
+	}
+
+	reusedArtifactIds := make([]uuid.UUID, 0, len(earlierContentPaths))
+	for artifactId := range earlierContentPaths {
+		reusedArtifactIds = append(reusedArtifactIds, artifactId)
+	}
+	unblockOperatorsOnArtifacts(selected, reusedArtifactIds, ready, operatorDependencies)
+
+	for id, op := range dag.Operators {
+		if _, ok := selected[id]; ok {
+			continue
+		}
+
+		status := shared.SkippedExecutionStatus
+		metadata := &operator_result.Metadata{Error: unselectedOperatorErrorMessage}
+		if _, ok := reused[id]; ok {
+			earlierOperatorResult, err := operatorResultReader.GetOperatorResultByWorkflowDagResultIdAndOperatorId(
+				ctx,
+				earlierWorkflowDagResultIdByOperator[id],
+				id,
+				db,
+			)
+			if err != nil {
+				return errors.Wrapf(err, "Unable to retrieve the earlier result of operator %s.", op.Name)
+			}
+
+			status = shared.SucceededExecutionStatus
+			metadata = &operator_result.Metadata{Cached: true, CachedFrom: &earlierOperatorResult.Id}
+		}
+
+		utils.UpdateOperatorAndArtifactResults(
+			ctx,
+			&op,
+			&dag.StorageConfig,
+			status,
+			metadata,
+			artifactMetadataPaths,
+			operatorToOperatorResult,
+			artifactToArtifactResult,
+			operatorResultWriter,
+			artifactResultWriter,
+			db,
+		)
+	}
+
+	return nil
+}
+
+// `unblockOperatorsOnArtifacts` marks the artifacts, which were produced before this run started,
+// as available to the operators. Operators whose inputs are now all available become ready.
+func unblockOperatorsOnArtifacts(
+	operators map[uuid.UUID]operator.Operator,
+	artifactIds []uuid.UUID,
+	ready map[uuid.UUID]bool,
+	operatorDependencies map[uuid.UUID]map[uuid.UUID]bool,
+) {
+	for id := range operators {
+		for _, artifactId := range artifactIds {
+			delete(operatorDependencies[id], artifactId)
+		}
+
+		if len(operatorDependencies[id]) == 0 {
+			ready[id] = true
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// earlierArtifactResults returns the artifact results of earlier runs, by run id and artifact id.
+type earlierArtifactResults struct {
+	artifact_result.Reader
+	results map[uuid.UUID]map[uuid.UUID]*artifact_result.ArtifactResult
+}
+
+func (r *earlierArtifactResults) GetArtifactResultByWorkflowDagResultIdAndArtifactId(
+	ctx context.Context,
+	workflowDagResultId, artifactId uuid.UUID,
+	db database.Database,
+) (*artifact_result.ArtifactResult, error) {
+	result, ok := r.results[workflowDagResultId][artifactId]
+	if !ok {
+		return nil, database.ErrNoRows
+	}
+
+	return result, nil
+}
+
+// earlierOperatorResults returns an operator result with a new id for every operator, and
+// records the run that each operator's result was retrieved from.
+type earlierOperatorResults struct {
+	operator_result.Reader
+	runs map[uuid.UUID]uuid.UUID
+}
+
+func (r *earlierOperatorResults) GetOperatorResultByWorkflowDagResultIdAndOperatorId(
+	ctx context.Context,
+	workflowDagResultId, operatorId uuid.UUID,
+	db database.Database,
+) (*operator_result.OperatorResult, error) {
+	if r.runs != nil {
+		r.runs[operatorId] = workflowDagResultId
+	}
+	return &operator_result.OperatorResult{Id: uuid.New()}, nil
+}
+
+// successfulRuns returns the runs of a workflow.
+type successfulRuns struct {
+	workflow_dag_result.Reader
+	results []workflow_dag_result.WorkflowDagResult
+}
+
+func (r *successfulRuns) GetWorkflowDagResultsByWorkflowId(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	db database.Database,
+) ([]workflow_dag_result.WorkflowDagResult, error) {
+	return r.results, nil
+}
+
+func TestSeedPartialRun(t *testing.T) {
+	ctx := context.Background()
+	byName, operators, _ := newTestDag()
+	dag := &workflow_dag.WorkflowDag{
+		Operators: operators,
+		StorageConfig: shared.StorageConfig{
+			Type:       shared.FileStorageType,
+			FileConfig: &shared.FileConfig{Directory: t.TempDir()},
+		},
+	}
+	store := storage.NewStorage(&dag.StorageConfig)
+
+	// Rerun operator b and everything downstream of it, i.e. operator d.
+	selection := &workflow_dag.OperatorSelection{FromOperators: []uuid.UUID{byName["b"].Id}}
+	selected := selectOperators(dag, selection)
+	require.Len(t, selected, 2)
+
+	// Operator b reads the output of operator a, and operator d reads the output of operator c.
+	reused := reusedOperators(dag, selected)
+	require.Len(t, reused, 2)
+	require.Contains(t, reused, byName["a"].Id)
+	require.Contains(t, reused, byName["c"].Id)
+
+	earlierRunId := uuid.New()
+	earlierResults := &earlierArtifactResults{results: map[uuid.UUID]map[uuid.UUID]*artifact_result.ArtifactResult{
+		earlierRunId: {},
+	}}
+	artifactContentPaths := map[uuid.UUID]string{}
+	artifactMetadataPaths := map[uuid.UUID]string{}
+	for name, op := range byName {
+		artifactId := op.Outputs[0]
+		artifactContentPaths[artifactId] = "run-2-" + name
+		artifactMetadataPaths[artifactId] = "run-2-" + name + "-metadata"
+		earlierResults.results[earlierRunId][artifactId] = &artifact_result.ArtifactResult{
+			ContentPath: "run-1-" + name,
+			Status:      shared.SucceededExecutionStatus,
+			Metadata:    artifact_result.NullMetadata{Metadata: artifact_result.Metadata{{name: "int64"}}},
+		}
+	}
+
+	ready := map[uuid.UUID]bool{}
+	operatorDependencies := map[uuid.UUID]map[uuid.UUID]bool{}
+	initializeOrchestration(selected, ready, operatorDependencies, map[uuid.UUID][]uuid.UUID{})
+	require.Empty(t, ready)
+
+	err := seedPartialRun(
+		ctx,
+		dag,
+		selected,
+		reused,
+		[]uuid.UUID{earlierRunId},
+		ready,
+		operatorDependencies,
+		artifactContentPaths,
+		artifactMetadataPaths,
+		map[uuid.UUID]uuid.UUID{},
+		map[uuid.UUID]uuid.UUID{},
+		&earlierOperatorResults{},
+		operator_result.NewNoopWriter(false),
+		earlierResults,
+		artifact_result.NewNoopWriter(false),
+		database.NewNoopDatabase(),
+	)
+	require.Nil(t, err)
+
+	// Operator b can run right away, while operator d still waits for the output of operator b.
+	require.Equal(t, map[uuid.UUID]bool{byName["b"].Id: true}, ready)
+	require.Equal(t, map[uuid.UUID]bool{byName["b"].Outputs[0]: true}, operatorDependencies[byName["d"].Id])
+
+	// The reused artifacts point to the earlier run, while the others are produced by this run.
+	require.Equal(t, "run-1-a", artifactContentPaths[byName["a"].Outputs[0]])
+	require.Equal(t, "run-1-c", artifactContentPaths[byName["c"].Outputs[0]])
+	require.Equal(t, "run-2-b", artifactContentPaths[byName["b"].Outputs[0]])
+
+	rawMetadata, err := store.Get(ctx, "run-2-a-metadata")
+	require.Nil(t, err)
+	require.JSONEq(t, `[{"a": "int64"}]`, string(rawMetadata))
+}
+
+func TestSeedPartialRunWithMissingArtifact(t *testing.T) {
+	byName, operators, _ := newTestDag()
+	dag := &workflow_dag.WorkflowDag{
+		Operators: operators,
+		StorageConfig: shared.StorageConfig{
+			Type:       shared.FileStorageType,
+			FileConfig: &shared.FileConfig{Directory: t.TempDir()},
+		},
+	}
+
+	selection := &workflow_dag.OperatorSelection{OnlyOperators: []uuid.UUID{byName["b"].Id}}
+	selected := selectOperators(dag, selection)
+
+	// The earlier run did not produce the output of operator a.
+	err := seedPartialRun(
+		context.Background(),
+		dag,
+		selected,
+		reusedOperators(dag, selected),
+		[]uuid.UUID{uuid.New()},
+		map[uuid.UUID]bool{},
+		map[uuid.UUID]map[uuid.UUID]bool{},
+		map[uuid.UUID]string{},
+		map[uuid.UUID]string{},
+		map[uuid.UUID]uuid.UUID{},
+		map[uuid.UUID]uuid.UUID{},
+		&earlierOperatorResults{},
+		operator_result.NewNoopWriter(false),
+		&earlierArtifactResults{},
+		artifact_result.NewNoopWriter(false),
+		database.NewNoopDatabase(),
+	)
+	require.NotNil(t, err)
+}
+
+func TestSeedPartialRunAfterPartialRun(t *testing.T) {
+	ctx := context.Background()
+	byName, operators, _ := newTestDag()
+	dag := &workflow_dag.WorkflowDag{
+		Id:        uuid.New(),
+		Operators: operators,
+		StorageConfig: shared.StorageConfig{
+			Type:       shared.FileStorageType,
+			FileConfig: &shared.FileConfig{Directory: t.TempDir()},
+		},
+	}
+
+	// Run 0 partially succeeded and run 1 ran every operator. Run 2 only ran operator b, so it
+	// reused the output of operator a and skipped the others. Run 3 is the current run, and the
+	// failed run is never reused from.
+	now := time.Now()
+	run0, run1, run2, run3 := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	runs := &successfulRuns{results: []workflow_dag_result.WorkflowDagResult{
+		{Id: run2, WorkflowDagId: dag.Id, Status: shared.SucceededExecutionStatus, CreatedAt: now.Add(-time.Hour)},
+		{Id: run3, WorkflowDagId: dag.Id, Status: shared.PendingExecutionStatus, CreatedAt: now},
+		{Id: run1, WorkflowDagId: dag.Id, Status: shared.SucceededExecutionStatus, CreatedAt: now.Add(-2 * time.Hour)},
+		{Id: run0, WorkflowDagId: dag.Id, Status: shared.PartialSuccessExecutionStatus, CreatedAt: now.Add(-3 * time.Hour)},
+		{Id: uuid.New(), WorkflowDagId: dag.Id, Status: shared.FailedExecutionStatus, CreatedAt: now.Add(-30 * time.Minute)},
+	}}
+
+	earlierResults := &earlierArtifactResults{results: map[uuid.UUID]map[uuid.UUID]*artifact_result.ArtifactResult{
+		run1: {},
+		run2: {},
+	}}
+	artifactContentPaths := map[uuid.UUID]string{}
+	artifactMetadataPaths := map[uuid.UUID]string{}
+	for name, op := range byName {
+		artifactId := op.Outputs[0]
+		artifactContentPaths[artifactId] = "run-3-" + name
+		artifactMetadataPaths[artifactId] = "run-3-" + name + "-metadata"
+		earlierResults.results[run1][artifactId] = &artifact_result.ArtifactResult{
+			ContentPath: "run-1-" + name,
+			Status:      shared.SucceededExecutionStatus,
+			Metadata:    artifact_result.NullMetadata{Metadata: artifact_result.Metadata{{name: "int64"}}},
+		}
+		earlierResults.results[run2][artifactId] = &artifact_result.ArtifactResult{
+			ContentPath: "run-2-" + name,
+			Status:      shared.SkippedExecutionStatus,
+			Metadata:    artifact_result.NullMetadata{IsNull: true},
+		}
+	}
+	earlierResults.results[run2][byName["a"].Outputs[0]] = earlierResults.results[run1][byName["a"].Outputs[0]]
+	earlierResults.results[run2][byName["b"].Outputs[0]] = &artifact_result.ArtifactResult{
+		ContentPath: "run-2-b",
+		Status:      shared.SucceededExecutionStatus,
+		Metadata:    artifact_result.NullMetadata{Metadata: artifact_result.Metadata{{"b": "float64"}}},
+	}
+
+	earlierRunIds, err := successfulWorkflowDagResultIds(ctx, dag, runs, database.NewNoopDatabase())
+	require.Nil(t, err)
+	require.Equal(t, []uuid.UUID{run2, run1, run0}, earlierRunIds)
+
+	// Run 3 only runs operator d, which reads the outputs of operators b and c.
+	selection := &workflow_dag.OperatorSelection{OnlyOperators: []uuid.UUID{byName["d"].Id}}
+	selected := selectOperators(dag, selection)
+	reused := reusedOperators(dag, selected)
+
+	ready := map[uuid.UUID]bool{}
+	operatorDependencies := map[uuid.UUID]map[uuid.UUID]bool{}
+	initializeOrchestration(selected, ready, operatorDependencies, map[uuid.UUID][]uuid.UUID{})
+
+	earlierOperators := &earlierOperatorResults{runs: map[uuid.UUID]uuid.UUID{}}
+	err = seedPartialRun(
+		ctx,
+		dag,
+		selected,
+		reused,
+		earlierRunIds,
+		ready,
+		operatorDependencies,
+		artifactContentPaths,
+		artifactMetadataPaths,
+		map[uuid.UUID]uuid.UUID{},
+		map[uuid.UUID]uuid.UUID{},
+		earlierOperators,
+		operator_result.NewNoopWriter(false),
+		earlierResults,
+		artifact_result.NewNoopWriter(false),
+		database.NewNoopDatabase(),
+	)
+	require.Nil(t, err)
+	require.Equal(t, map[uuid.UUID]bool{byName["d"].Id: true}, ready)
+
+	// The output of operator b is taken from run 2, while run 2 skipped operator c, so its
+	// output is taken from run 1.
+	require.Equal(t, "run-2-b", artifactContentPaths[byName["b"].Outputs[0]])
+	require.Equal(t, "run-1-c", artifactContentPaths[byName["c"].Outputs[0]])
+	require.Equal(t, run2, earlierOperators.runs[byName["b"].Id])
+	require.Equal(t, run1, earlierOperators.runs[byName["c"].Id])
+}
+
+func TestCheckReusableRun(t *testing.T) {
+	ctx := context.Background()
+	byName, operators, _ := newTestDag()
+	dag := &workflow_dag.WorkflowDag{Id: uuid.New(), Operators: operators}
+	runs := &successfulRuns{results: []workflow_dag_result.WorkflowDagResult{
+		{Id: uuid.New(), WorkflowDagId: dag.Id, Status: shared.FailedExecutionStatus},
+	}}
+
+	// Running operator d and everything upstream of it reuses nothing.
+	upstream := &workflow_dag.OperatorSelection{UpstreamOf: []uuid.UUID{byName["d"].Id}}
+	require.Nil(t, CheckReusableRun(ctx, dag, upstream, runs, database.NewNoopDatabase()))
+
+	// Running only operator d reuses the outputs of operators b and c.
+	only := &workflow_dag.OperatorSelection{OnlyOperators: []uuid.UUID{byName["d"].Id}}
+	require.Equal(t, ErrNoSuccessfulRun, CheckReusableRun(ctx, dag, only, runs, database.NewNoopDatabase()))
+
+	runs.results = append(runs.results, workflow_dag_result.WorkflowDagResult{
+		Id:            uuid.New(),
+		WorkflowDagId: dag.Id,
+		Status:        shared.PartialSuccessExecutionStatus,
+	})
+	require.Nil(t, CheckReusableRun(ctx, dag, only, runs, database.NewNoopDatabase()))
+}
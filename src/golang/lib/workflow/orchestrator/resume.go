@@ -0,0 +1,160 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+var ErrResumedRunOfOlderVersion = errors.New("Only runs of the latest version of the workflow can be resumed.")
+
+// `resumedOperators` splits the operators of the dag into the ones that succeeded in the resumed
+// run, by their operator results in that run, and the ones that failed or were never reached,
+// which are run again.
+func resumedOperators(
+	ctx context.Context,
+	dag *workflow_dag.WorkflowDag,
+	resumedFrom uuid.UUID,
+	workflowDagResultReader workflow_dag_result.Reader,
+	operatorResultReader operator_result.Reader,
+	db database.Database,
+) (map[uuid.UUID]operator.Operator, map[uuid.UUID]operator_result.OperatorResult, error) {
+	resumedWorkflowDagResult, err := workflowDagResultReader.GetWorkflowDagResult(ctx, resumedFrom, db)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Unable to retrieve the resumed run.")
+	}
+
+	if resumedWorkflowDagResult.WorkflowDagId != dag.Id {
+		return nil, nil, ErrResumedRunOfOlderVersion
+	}
+
+	operatorResults, err := operatorResultReader.GetOperatorResultsByWorkflowDagResultIds(
+		ctx,
+		[]uuid.UUID{resumedFrom},
+		db,
+	)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Unable to retrieve the operator results of the resumed run.")
+	}
+
+	succeeded := map[uuid.UUID]operator_result.OperatorResult{}
+	for _, operatorResult := range operatorResults {
+		if operatorResult.Status == shared.SucceededExecutionStatus {
+			succeeded[operatorResult.OperatorId] = operatorResult
+		}
+	}
+
+	operators := map[uuid.UUID]operator.Operator{}
+	for id, op := range dag.Operators {
+		if _, ok := succeeded[id]; !ok {
+			operators[id] = op
+		}
+	}
+
+	return operators, succeeded, nil
+}
+
+// `seedResumedRun` copies the results of the operators that succeeded in the resumed run. Their
+// outputs' contents and metadata are copied to this run's artifact paths, and their operator and
+// artifact results are marked as succeeded with the metadata of the resumed run. The operators
+// to run that only read copied artifacts become ready.
+func seedResumedRun(
+	ctx context.Context,
+	dag *workflow_dag.WorkflowDag,
+	operators map[uuid.UUID]operator.Operator,
+	succeeded map[uuid.UUID]operator_result.OperatorResult,
+	resumedFrom uuid.UUID,
+	ready map[uuid.UUID]bool,
+	operatorDependencies map[uuid.UUID]map[uuid.UUID]bool,
+	artifactContentPaths map[uuid.UUID]string,
+	artifactMetadataPaths map[uuid.UUID]string,
+	operatorToOperatorResult map[uuid.UUID]uuid.UUID,
+	artifactToArtifactResult map[uuid.UUID]uuid.UUID,
+	operatorResultWriter operator_result.Writer,
+	artifactResultReader artifact_result.Reader,
+	artifactResultWriter artifact_result.Writer,
+	db database.Database,
+) error {
+	store := storage.NewStorage(&dag.StorageConfig)
+
+	resumedArtifactResults, err := artifactResultReader.GetArtifactResultsByWorkflowDagResultIds(
+		ctx,
+		[]uuid.UUID{resumedFrom},
+		db,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Unable to retrieve the artifact results of the resumed run.")
+	}
+
+	artifactIdToResumedResult := make(map[uuid.UUID]artifact_result.ArtifactResult, len(resumedArtifactResults))
+	for _, artifactResult := range resumedArtifactResults {
+		artifactIdToResumedResult[artifactResult.ArtifactId] = artifactResult
+	}
+
+	copiedArtifactIds := []uuid.UUID{}
+	for id := range succeeded {
+		op := dag.Operators[id]
+		for _, artifactId := range op.Outputs {
+			resumedArtifactResult, ok := artifactIdToResumedResult[artifactId]
+			if !ok || resumedArtifactResult.Metadata.IsNull {
+				return errors.Newf("Artifact %s was not produced by the resumed run.", artifactId)
+			}
+
+			content, err := store.Get(ctx, resumedArtifactResult.ContentPath)
+			if err != nil {
+				return errors.Wrapf(err, "Unable to read the content of artifact %s in the resumed run.", artifactId)
+			}
+
+			if err := store.Put(ctx, artifactContentPaths[artifactId], content); err != nil {
+				return err
+			}
+
+			rawMetadata, err := json.Marshal(resumedArtifactResult.Metadata.Metadata)
+			if err != nil {
+				return err
+			}
+
+			if err := store.Put(ctx, artifactMetadataPaths[artifactId], rawMetadata); err != nil {
+				return err
+			}
+
+			copiedArtifactIds = append(copiedArtifactIds, artifactId)
+		}
+	}
+
+	for id, operatorResult := range succeeded {
+		op := dag.Operators[id]
+		metadata := &operator_result.Metadata{}
+		if !operatorResult.Metadata.IsNull {
+			metadata = &operatorResult.Metadata.Metadata
+		}
+
+		utils.UpdateOperatorAndArtifactResults(
+			ctx,
+			&op,
+			&dag.StorageConfig,
+			shared.SucceededExecutionStatus,
+			metadata,
+			artifactMetadataPaths,
+			operatorToOperatorResult,
+			artifactToArtifactResult,
+			operatorResultWriter,
+			artifactResultWriter,
+			db,
+		)
+	}
+
+	unblockOperatorsOnArtifacts(operators, copiedArtifactIds, ready, operatorDependencies)
+	return nil
+}
@@ -0,0 +1,169 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// resumedWorkflowDagResult returns the workflow dag result of the resumed run.
+type resumedWorkflowDagResult struct {
+	workflow_dag_result.Reader
+	result workflow_dag_result.WorkflowDagResult
+}
+
+func (r *resumedWorkflowDagResult) GetWorkflowDagResult(
+	ctx context.Context,
+	id uuid.UUID,
+	db database.Database,
+) (*workflow_dag_result.WorkflowDagResult, error) {
+	return &r.result, nil
+}
+
+// resumedOperatorResults returns the operator results of the resumed run.
+type resumedOperatorResults struct {
+	operator_result.Reader
+	results []operator_result.OperatorResult
+}
+
+func (r *resumedOperatorResults) GetOperatorResultsByWorkflowDagResultIds(
+	ctx context.Context,
+	workflowDagResultIds []uuid.UUID,
+	db database.Database,
+) ([]operator_result.OperatorResult, error) {
+	return r.results, nil
+}
+
+// resumedArtifactResults returns the artifact results of the resumed run.
+type resumedArtifactResults struct {
+	artifact_result.Reader
+	results []artifact_result.ArtifactResult
+}
+
+func (r *resumedArtifactResults) GetArtifactResultsByWorkflowDagResultIds(
+	ctx context.Context,
+	workflowDagResultIds []uuid.UUID,
+	db database.Database,
+) ([]artifact_result.ArtifactResult, error) {
+	return r.results, nil
+}
+
+func TestResumeRun(t *testing.T) {
+	ctx := context.Background()
+	byName, operators, _ := newTestDag()
+	dag := &workflow_dag.WorkflowDag{
+		Id:        uuid.New(),
+		Operators: operators,
+		StorageConfig: shared.StorageConfig{
+			Type:       shared.FileStorageType,
+			FileConfig: &shared.FileConfig{Directory: t.TempDir()},
+		},
+	}
+	store := storage.NewStorage(&dag.StorageConfig)
+
+	// Operators a, c and e succeeded in the resumed run, operator b failed and operator d was never reached.
+	run := &resumedWorkflowDagResult{result: workflow_dag_result.WorkflowDagResult{Id: uuid.New(), WorkflowDagId: dag.Id}}
+	operatorResults := &resumedOperatorResults{}
+	artifactResults := &resumedArtifactResults{}
+	artifactContentPaths := map[uuid.UUID]string{}
+	artifactMetadataPaths := map[uuid.UUID]string{}
+	for name, op := range byName {
+		artifactId := op.Outputs[0]
+		artifactContentPaths[artifactId] = "run-2-" + name
+		artifactMetadataPaths[artifactId] = "run-2-" + name + "-metadata"
+
+		status := shared.SucceededExecutionStatus
+		if name == "b" || name == "d" {
+			status = shared.FailedExecutionStatus
+		}
+		operatorResults.results = append(
+			operatorResults.results,
+			operator_result.OperatorResult{OperatorId: op.Id, Status: status},
+		)
+
+		if status == shared.SucceededExecutionStatus {
+			require.Nil(t, store.Put(ctx, "run-1-"+name, []byte(name)))
+			artifactResults.results = append(artifactResults.results, artifact_result.ArtifactResult{
+				ArtifactId:  artifactId,
+				ContentPath: "run-1-" + name,
+				Status:      shared.SucceededExecutionStatus,
+				Metadata:    artifact_result.NullMetadata{Metadata: artifact_result.Metadata{{name: "int64"}}},
+			})
+		}
+	}
+
+	toRun, succeeded, err := resumedOperators(
+		ctx,
+		dag,
+		run.result.Id,
+		run,
+		operatorResults,
+		database.NewNoopDatabase(),
+	)
+	require.Nil(t, err)
+	require.Len(t, toRun, 2)
+	require.Contains(t, toRun, byName["b"].Id)
+	require.Contains(t, toRun, byName["d"].Id)
+	require.Len(t, succeeded, 3)
+
+	ready := map[uuid.UUID]bool{}
+	operatorDependencies := map[uuid.UUID]map[uuid.UUID]bool{}
+	initializeOrchestration(toRun, ready, operatorDependencies, map[uuid.UUID][]uuid.UUID{})
+
+	err = seedResumedRun(
+		ctx,
+		dag,
+		toRun,
+		succeeded,
+		run.result.Id,
+		ready,
+		operatorDependencies,
+		artifactContentPaths,
+		artifactMetadataPaths,
+		map[uuid.UUID]uuid.UUID{},
+		map[uuid.UUID]uuid.UUID{},
+		operator_result.NewNoopWriter(false),
+		artifactResults,
+		artifact_result.NewNoopWriter(false),
+		database.NewNoopDatabase(),
+	)
+	require.Nil(t, err)
+
+	// Operator b runs again right away, and operator d waits for it.
+	require.Equal(t, map[uuid.UUID]bool{byName["b"].Id: true}, ready)
+	require.Equal(t, map[uuid.UUID]bool{byName["b"].Outputs[0]: true}, operatorDependencies[byName["d"].Id])
+
+	// The succeeded outputs are copied to this run's paths.
+	content, err := store.Get(ctx, "run-2-c")
+	require.Nil(t, err)
+	require.Equal(t, "c", string(content))
+
+	rawMetadata, err := store.Get(ctx, "run-2-a-metadata")
+	require.Nil(t, err)
+	require.JSONEq(t, `[{"a": "int64"}]`, string(rawMetadata))
+}
+
+func TestResumeRunOfOlderVersion(t *testing.T) {
+	_, operators, _ := newTestDag()
+	dag := &workflow_dag.WorkflowDag{Id: uuid.New(), Operators: operators}
+	run := &resumedWorkflowDagResult{result: workflow_dag_result.WorkflowDagResult{Id: uuid.New(), WorkflowDagId: uuid.New()}}
+
+	_, _, err := resumedOperators(
+		context.Background(),
+		dag,
+		run.result.Id,
+		run,
+		&resumedOperatorResults{},
+		database.NewNoopDatabase(),
+	)
+	require.Equal(t, ErrResumedRunOfOlderVersion, err)
+}
@@ -0,0 +1,124 @@
+package orchestrator
+
+import (
+	"context"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/workflow/scheduler"
+	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// `operatorRetries` keeps track of the failed attempts of operators with a retry policy,
+// and of the operators waiting for their backoff to pass before they are run again.
+type operatorRetries struct {
+	failedAttempts map[uuid.UUID][]operator_result.FailedAttempt
+	retryAt        map[uuid.UUID]time.Time
+}
+
+func newOperatorRetries() *operatorRetries {
+	return &operatorRetries{
+		failedAttempts: map[uuid.UUID][]operator_result.FailedAttempt{},
+		retryAt:        map[uuid.UUID]time.Time{},
+	}
+}
+
+// `shouldRetry` returns whether the operator should be run again after it failed with `failureType`.
+func (r *operatorRetries) shouldRetry(op *operator.Operator, failureType scheduler.FailureType) bool {
+	retryPolicy := op.Spec.RetryPolicy()
+	if retryPolicy == nil {
+		return false
+	}
+
+	if failureType == scheduler.UserFailure && !retryPolicy.RetryOnUserFailure {
+		return false
+	}
+
+	return len(r.failedAttempts[op.Id])+1 < retryPolicy.MaxAttempts
+}
+
+// `scheduleRetry` records the failed attempt and schedules the operator to be run again
+// once its backoff has passed.
+func (r *operatorRetries) scheduleRetry(op *operator.Operator, operatorResultMetadata *operator_result.Metadata) {
+	r.failedAttempts[op.Id] = append(r.failedAttempts[op.Id], operator_result.FailedAttempt{
+		Error:         operatorResultMetadata.Error,
+		ExceededLimit: operatorResultMetadata.ExceededLimit,
+		FinishedAt:    time.Now(),
+	})
+
+	numFailedAttempts := len(r.failedAttempts[op.Id])
+	r.retryAt[op.Id] = time.Now().Add(op.Spec.RetryPolicy().Backoff(numFailedAttempts))
+}
+
+// `addAttempts` records the number of attempts and the failed attempts of the operator in its metadata.
+func (r *operatorRetries) addAttempts(operatorId uuid.UUID, operatorResultMetadata *operator_result.Metadata) {
+	operatorResultMetadata.FailedAttempts = r.failedAttempts[operatorId]
+	operatorResultMetadata.Attempts = len(r.failedAttempts[operatorId]) + 1
+}
+
+// `moveDueRetries` marks the operators whose backoff has passed as ready to be scheduled.
+func (r *operatorRetries) moveDueRetries(ready map[uuid.UUID]bool) {
+	now := time.Now()
+	for id, retryAt := range r.retryAt {
+		if !now.Before(retryAt) {
+			ready[id] = true
+			delete(r.retryAt, id)
+		}
+	}
+}
+
+// `moveWaitingToActive` moves the operators waiting for a retry to `active`, so they are
+// cancelled along with the running operators. Their jobs already finished, so cancelling them
+// only updates their results.
+func (r *operatorRetries) moveWaitingToActive(active map[uuid.UUID]bool) {
+	for id := range r.retryAt {
+		active[id] = true
+		delete(r.retryAt, id)
+	}
+}
+
+func (r *operatorRetries) numWaiting() int {
+	return len(r.retryAt)
+}
+
+// `prepareRetry` removes the metadata written by the failed attempt, so it is not mistaken
+// for the metadata of the next attempt. For non-preview execution, the operator result is
+// set back to pending and records the failed attempts so far.
+func prepareRetry(
+	ctx context.Context,
+	op *operator.Operator,
+	retries *operatorRetries,
+	storageConfig *shared.StorageConfig,
+	operatorMetadataPath string,
+	operatorToOperatorResult map[uuid.UUID]uuid.UUID,
+	operatorResultWriter operator_result.Writer,
+	db database.Database,
+	isPreview bool,
+) {
+	utils.CleanupStorageFiles(ctx, storageConfig, []string{operatorMetadataPath})
+
+	if isPreview {
+		return
+	}
+
+	var operatorResultMetadata operator_result.Metadata
+	retries.addAttempts(op.Id, &operatorResultMetadata)
+
+	_, err := operatorResultWriter.UpdateOperatorResult(
+		ctx,
+		operatorToOperatorResult[op.Id],
+		map[string]interface{}{
+			operator_result.StatusColumn:   shared.PendingExecutionStatus,
+			operator_result.MetadataColumn: &operatorResultMetadata,
+		},
+		db,
+	)
+	if err != nil {
+		log.Errorf("Unable to record the failed attempt of operator result %s: %v", operatorToOperatorResult[op.Id], err)
+	}
+}
@@ -0,0 +1,76 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/workflow/scheduler"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOperatorWithRetryPolicy(t *testing.T, retryPolicy string) *operator.Operator {
+	var spec operator.Spec
+	err := json.Unmarshal([]byte(`{"function": {}, "retry_policy": `+retryPolicy+`}`), &spec)
+	require.Nil(t, err)
+
+	return &operator.Operator{Id: uuid.New(), Name: "test", Spec: spec}
+}
+
+func TestOperatorRetries(t *testing.T) {
+	op := newTestOperatorWithRetryPolicy(t, `{"max_attempts": 3}`)
+	retries := newOperatorRetries()
+
+	// User failures are not retried unless the retry policy says so.
+	require.False(t, retries.shouldRetry(op, scheduler.UserFailure))
+
+	for attempt := 1; attempt < 3; attempt++ {
+		require.True(t, retries.shouldRetry(op, scheduler.SystemFailure))
+		retries.scheduleRetry(op, &operator_result.Metadata{Error: "error"})
+		require.Equal(t, 1, retries.numWaiting())
+
+		// Without a backoff, the operator is ready to be scheduled right away.
+		ready := map[uuid.UUID]bool{}
+		retries.moveDueRetries(ready)
+		require.True(t, ready[op.Id])
+		require.Equal(t, 0, retries.numWaiting())
+	}
+
+	require.False(t, retries.shouldRetry(op, scheduler.SystemFailure))
+
+	var metadata operator_result.Metadata
+	retries.addAttempts(op.Id, &metadata)
+	require.Equal(t, 3, metadata.Attempts)
+	require.Len(t, metadata.FailedAttempts, 2)
+	require.Equal(t, "error", metadata.FailedAttempts[0].Error)
+}
+
+func TestOperatorRetriesBackoff(t *testing.T) {
+	op := newTestOperatorWithRetryPolicy(
+		t,
+		`{"max_attempts": 2, "initial_backoff_seconds": 60, "retry_on_user_failure": true}`,
+	)
+	retries := newOperatorRetries()
+
+	require.True(t, retries.shouldRetry(op, scheduler.UserFailure))
+	retries.scheduleRetry(op, &operator_result.Metadata{Error: "error"})
+
+	// The operator waits for its backoff, unless the run is cancelled.
+	ready := map[uuid.UUID]bool{}
+	retries.moveDueRetries(ready)
+	require.Empty(t, ready)
+
+	active := map[uuid.UUID]bool{}
+	retries.moveWaitingToActive(active)
+	require.True(t, active[op.Id])
+	require.Equal(t, 0, retries.numWaiting())
+}
+
+func TestOperatorWithoutRetryPolicy(t *testing.T) {
+	op := &operator.Operator{Id: uuid.New()}
+	retries := newOperatorRetries()
+
+	require.False(t, retries.shouldRetry(op, scheduler.SystemFailure))
+}
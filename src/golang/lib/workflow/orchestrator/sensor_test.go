@@ -0,0 +1,98 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/artifact"
+	"github.com/aqueducthq/aqueduct/lib/collections/integration"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/aqueducthq/aqueduct/lib/workflow/artifact/boolean"
+	"github.com/aqueducthq/aqueduct/lib/workflow/artifact/table"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/function"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// launchedSpecs records the specs of the launched jobs.
+type launchedSpecs struct {
+	job.JobManager
+	specs []job.Spec
+}
+
+func (m *launchedSpecs) Launch(ctx context.Context, name string, spec job.Spec) error {
+	m.specs = append(m.specs, spec)
+	return nil
+}
+
+func TestSensorGatesDownstreamOperators(t *testing.T) {
+	sensorOutput := artifact.Artifact{Id: uuid.New(), Spec: *artifact.NewSpecFromBool(boolean.Bool{})}
+	tableArtifact := artifact.Artifact{Id: uuid.New(), Spec: *artifact.NewSpecFromTable(table.Table{})}
+	output := artifact.Artifact{Id: uuid.New(), Spec: *artifact.NewSpecFromTable(table.Table{})}
+	artifacts := map[uuid.UUID]artifact.Artifact{sensorOutput.Id: sensorOutput, tableArtifact.Id: tableArtifact, output.Id: output}
+
+	sensor := operator.Operator{
+		Id: uuid.New(),
+		Spec: *operator.NewSpecFromSensor(connector.Sensor{
+			Service: integration.Postgres,
+			Query:   "SELECT true;",
+		}),
+		Outputs: []uuid.UUID{sensorOutput.Id},
+	}
+	fn := operator.Operator{
+		Id:      uuid.New(),
+		Spec:    *operator.NewSpecFromFunction(function.Function{Type: function.FileFunctionType}),
+		Inputs:  []uuid.UUID{sensorOutput.Id, tableArtifact.Id},
+		Outputs: []uuid.UUID{output.Id},
+	}
+	operators := map[uuid.UUID]operator.Operator{sensor.Id: sensor, fn.Id: fn}
+
+	ready := map[uuid.UUID]bool{}
+	operatorDependencies := map[uuid.UUID]map[uuid.UUID]bool{}
+	artifactToDownstreamOperatorIds := map[uuid.UUID][]uuid.UUID{}
+	initializeOrchestration(operators, ready, operatorDependencies, artifactToDownstreamOperatorIds)
+	require.Equal(t, map[uuid.UUID]bool{sensor.Id: true}, ready)
+
+	// The function becomes ready once the sensor succeeds and its table input is available.
+	delete(ready, sensor.Id)
+	require.Nil(t, unblockDownstreamOperators(&sensor, operators, ready, operatorDependencies, artifactToDownstreamOperatorIds))
+	require.Empty(t, ready)
+	delete(operatorDependencies[fn.Id], tableArtifact.Id)
+	require.Empty(t, operatorDependencies[fn.Id])
+	ready[fn.Id] = true
+
+	// The sensor's output is not passed to the function.
+	gateArtifacts := gateOutputs(operators)
+	require.Equal(t, map[uuid.UUID]bool{sensorOutput.Id: true}, gateArtifacts)
+
+	jobManager := &launchedSpecs{}
+	err := scheduleOperators(
+		context.Background(),
+		operators,
+		artifacts,
+		gateArtifacts,
+		ready,
+		map[uuid.UUID]bool{},
+		map[uuid.UUID]string{},
+		&shared.StorageConfig{},
+		map[uuid.UUID]string{sensorOutput.Id: "sensor-output", tableArtifact.Id: "table", output.Id: "output"},
+		map[uuid.UUID]string{sensorOutput.Id: "sensor-metadata", tableArtifact.Id: "table-metadata", output.Id: "output-metadata"},
+		map[uuid.UUID]string{fn.Id: "fn-metadata"},
+		map[uuid.UUID]uuid.UUID{},
+		nil, /* fanOuts */
+		nil, /* subWorkflows */
+		nil, /* approvals */
+		jobManager,
+		nil, /* vaultObject */
+	)
+	require.Nil(t, err)
+	require.Len(t, jobManager.specs, 1)
+
+	functionSpec, ok := jobManager.specs[0].(*job.FunctionSpec)
+	require.True(t, ok)
+	require.Equal(t, []string{"table"}, functionSpec.InputContentPaths)
+	require.Equal(t, []artifact.Type{artifact.TableType}, functionSpec.InputArtifactTypes)
+}
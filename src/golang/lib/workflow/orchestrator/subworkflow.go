@@ -0,0 +1,295 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/notification"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/run_request"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/user"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// How long a finished child run has to write the metadata of its sub-workflow operator before the
+// operator fails. The child run's status is recorded just before it writes the metadata.
+const childRunMetadataGracePeriod = time.Minute
+
+// `subWorkflowJobManager` runs sub-workflow operators on top of the wrapped job manager. Each
+// sub-workflow operator requests a child run of the current run, which the server launches. The
+// job is done once the child run has finished and written the operator's metadata. Cancelling
+// the job cancels the child run, so that its executor stops its operators and records the run as
+// cancelled. All other jobs are handled by the wrapped job manager.
+type subWorkflowJobManager struct {
+	job.JobManager
+	// The workflow dag result of the current run. It is nil for previews, which do not run
+	// sub-workflow operators.
+	workflowDagResultId *uuid.UUID
+	userId              uuid.UUID
+	// Maps from the name of a sub-workflow job to its child run.
+	childRuns map[string]*childRun
+
+	workflowReader          workflow.Reader
+	workflowDagReader       workflow_dag.Reader
+	workflowDagResultReader workflow_dag_result.Reader
+	workflowDagResultWriter workflow_dag_result.Writer
+	runRequestWriter        run_request.Writer
+	notificationWriter      notification.Writer
+	userReader              user.Reader
+	db                      database.Database
+}
+
+// `childRun` is the run of another workflow that a sub-workflow operator requested.
+type childRun struct {
+	id            uuid.UUID
+	workflowName  string
+	storageConfig *shared.StorageConfig
+	metadataPath  string
+	// When the child run was first seen to have finished.
+	finishedAt time.Time
+}
+
+func newSubWorkflowJobManager(
+	jobManager job.JobManager,
+	workflowMetadata *workflow.Workflow,
+	workflowReader workflow.Reader,
+	workflowDagReader workflow_dag.Reader,
+	workflowDagResultReader workflow_dag_result.Reader,
+	workflowDagResultWriter workflow_dag_result.Writer,
+	runRequestWriter run_request.Writer,
+	notificationWriter notification.Writer,
+	userReader user.Reader,
+	db database.Database,
+) *subWorkflowJobManager {
+	// Previews may not have the metadata of their workflow, but they do not run sub-workflows.
+	var userId uuid.UUID
+	if workflowMetadata != nil {
+		userId = workflowMetadata.UserId
+	}
+
+	return &subWorkflowJobManager{
+		JobManager:              jobManager,
+		userId:                  userId,
+		childRuns:               map[string]*childRun{},
+		workflowReader:          workflowReader,
+		workflowDagReader:       workflowDagReader,
+		workflowDagResultReader: workflowDagResultReader,
+		workflowDagResultWriter: workflowDagResultWriter,
+		runRequestWriter:        runRequestWriter,
+		notificationWriter:      notificationWriter,
+		userReader:              userReader,
+		db:                      db,
+	}
+}
+
+func (m *subWorkflowJobManager) Poll(ctx context.Context, name string) (shared.ExecutionStatus, error) {
+	child, ok := m.childRuns[name]
+	if !ok {
+		return m.JobManager.Poll(ctx, name)
+	}
+
+	run, err := m.workflowDagResultReader.GetWorkflowDagResult(ctx, child.id, m.db)
+	if err != nil {
+		// A transient database error should not fail the operator.
+		log.Errorf("Unable to check the status of run %s of workflow %s: %v", child.id, child.workflowName, err)
+		return shared.PendingExecutionStatus, nil
+	}
+
+	if run.Status == shared.PendingExecutionStatus || run.Status == shared.AwaitingApprovalExecutionStatus {
+		return shared.PendingExecutionStatus, nil
+	}
+
+	// The metadata records whether the operator succeeded.
+	store := storage.NewStorage(child.storageConfig)
+	reported, err := store.Exists(ctx, child.metadataPath)
+	if err != nil {
+		return "", err
+	}
+	if reported {
+		delete(m.childRuns, name)
+		return shared.SucceededExecutionStatus, nil
+	}
+
+	if child.finishedAt.IsZero() {
+		child.finishedAt = time.Now()
+	}
+	if time.Since(child.finishedAt) < childRunMetadataGracePeriod {
+		return shared.PendingExecutionStatus, nil
+	}
+
+	// The child run stopped without reporting to this run, e.g. because it was never launched.
+	rawMetadata, err := json.Marshal(&operator_result.Metadata{
+		Error: fmt.Sprintf("Run %s of workflow %s finished with status %s.", child.id, child.workflowName, run.Status),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := store.Put(ctx, child.metadataPath, rawMetadata); err != nil {
+		return "", err
+	}
+
+	delete(m.childRuns, name)
+	return shared.FailedExecutionStatus, nil
+}
+
+func (m *subWorkflowJobManager) Cancel(ctx context.Context, name string) error {
+	child, ok := m.childRuns[name]
+	if !ok {
+		return m.JobManager.Cancel(ctx, name)
+	}
+
+	// A child run that already finished keeps its status.
+	_, err := m.workflowDagResultWriter.UpdateWorkflowDagResultWithStatus(
+		ctx,
+		child.id,
+		workflow_dag_result.RunningStatuses,
+		map[string]interface{}{workflow_dag_result.StatusColumn: shared.CanceledExecutionStatus},
+		m.workflowReader,
+		m.notificationWriter,
+		m.userReader,
+		m.db,
+	)
+	if err == database.ErrNoRows {
+		return nil
+	}
+
+	return err
+}
+
+// `launch` requests a child run of the workflow the sub-workflow operator references, which the
+// server launches. The contents of the operator's inputs are passed to the child run as the values
+// of the workflow's params. The child run writes the operator's metadata and outputs once it
+// finishes.
+func (m *subWorkflowJobManager) launch(
+	ctx context.Context,
+	op *operator.Operator,
+	inputContentPaths []string,
+	outputContentPaths []string,
+	outputMetadataPaths []string,
+	metadataPath string,
+	storageConfig *shared.StorageConfig,
+) (string, error) {
+	if m == nil || m.workflowDagResultId == nil {
+		return "", errors.Newf("Operator %s runs another workflow, which is only supported in workflow runs.", op.Name)
+	}
+
+	subWorkflow := op.Spec.SubWorkflow()
+	if subWorkflow == nil {
+		return "", errors.Newf("Operator %s is not a sub-workflow operator.", op.Name)
+	}
+
+	workflowObject, err := m.findWorkflow(ctx, op)
+	if err != nil {
+		return "", err
+	}
+
+	inputValues := make([]string, 0, len(inputContentPaths))
+	for _, path := range inputContentPaths {
+		content, err := storage.NewStorage(storageConfig).Get(ctx, path)
+		if err != nil {
+			return "", errors.Wrapf(err, "Unable to read the inputs of operator %s.", op.Name)
+		}
+
+		inputValues = append(inputValues, strings.TrimSpace(string(content)))
+	}
+
+	parameters, err := subWorkflow.Parameters(inputValues)
+	if err != nil {
+		return "", err
+	}
+
+	// The child run is created up front, so that it can be cancelled before it is launched.
+	workflowDag, err := m.workflowDagReader.GetLatestWorkflowDag(ctx, workflowObject.Id, m.db)
+	if err != nil {
+		return "", errors.Wrapf(err, "Unable to read workflow %s.", workflowObject.Name)
+	}
+
+	run, err := m.workflowDagResultWriter.CreateWorkflowDagResult(
+		ctx,
+		workflowDag.Id,
+		nil, /* resumedFrom */
+		parameters,
+		m.db,
+	)
+	if err != nil {
+		return "", errors.Wrapf(err, "Unable to create a run of workflow %s.", workflowObject.Name)
+	}
+
+	_, err = m.workflowDagResultWriter.UpdateWorkflowDagResult(
+		ctx,
+		run.Id,
+		map[string]interface{}{workflow_dag_result.ParentIdColumn: *m.workflowDagResultId},
+		m.workflowReader,
+		m.notificationWriter,
+		m.userReader,
+		m.db,
+	)
+	if err != nil {
+		return "", errors.Wrapf(err, "Unable to link the run of workflow %s to this run.", workflowObject.Name)
+	}
+
+	parent := &job.ParentOperator{
+		WorkflowDagResultId: *m.workflowDagResultId,
+		StorageConfig:       *storageConfig,
+		MetadataPath:        metadataPath,
+		OutputArtifactNames: subWorkflow.Outputs,
+		OutputContentPaths:  outputContentPaths,
+		OutputMetadataPaths: outputMetadataPaths,
+	}
+
+	_, err = m.runRequestWriter.CreateRunRequest(ctx, run.Id, workflowObject.Id, parent, m.db)
+	if err != nil {
+		return "", errors.Wrapf(err, "Unable to request a run of workflow %s.", workflowObject.Name)
+	}
+
+	jobName := fmt.Sprintf("sub-workflow-%s", uuid.New().String())
+	log.Infof("Operator %s requested run %s of workflow %s.", op.Name, run.Id, workflowObject.Name)
+	m.childRuns[jobName] = &childRun{
+		id:            run.Id,
+		workflowName:  workflowObject.Name,
+		storageConfig: storageConfig,
+		metadataPath:  metadataPath,
+	}
+	return jobName, nil
+}
+
+// `findWorkflow` looks up the workflow the sub-workflow operator references among the workflows
+// of the user who owns the current workflow.
+func (m *subWorkflowJobManager) findWorkflow(ctx context.Context, op *operator.Operator) (*workflow.Workflow, error) {
+	subWorkflow := op.Spec.SubWorkflow()
+
+	var workflowObject *workflow.Workflow
+	var err error
+	if subWorkflow.WorkflowId != nil {
+		workflowObject, err = m.workflowReader.GetWorkflow(ctx, *subWorkflow.WorkflowId, m.db)
+	} else {
+		workflowObject, err = m.workflowReader.GetWorkflowByName(ctx, m.userId, subWorkflow.WorkflowName, m.db)
+	}
+	if err != nil {
+		if err == database.ErrNoRows {
+			return nil, errors.Newf("Unable to find the workflow operator %s runs.", op.Name)
+		}
+		return nil, errors.Wrapf(err, "Unable to find the workflow operator %s runs.", op.Name)
+	}
+
+	// Workflows of other users cannot be referenced by ID either.
+	if workflowObject.UserId != m.userId {
+		return nil, errors.Newf("Unable to find the workflow operator %s runs.", op.Name)
+	}
+
+	return workflowObject, nil
+}
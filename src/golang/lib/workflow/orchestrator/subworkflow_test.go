@@ -0,0 +1,258 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/notification"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator"
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/run_request"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/user"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// userWorkflows looks up the workflows of a single user by name.
+type userWorkflows struct {
+	workflow.Reader
+	workflows map[string]workflow.Workflow
+}
+
+func (r *userWorkflows) GetWorkflowByName(
+	ctx context.Context,
+	userId uuid.UUID,
+	name string,
+	db database.Database,
+) (*workflow.Workflow, error) {
+	workflowObject, ok := r.workflows[name]
+	if !ok || workflowObject.UserId != userId {
+		return nil, database.ErrNoRows
+	}
+
+	return &workflowObject, nil
+}
+
+// latestDags returns a new dag of any workflow.
+type latestDags struct {
+	workflow_dag.Reader
+}
+
+func (*latestDags) GetLatestWorkflowDag(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	db database.Database,
+) (*workflow_dag.WorkflowDag, error) {
+	return &workflow_dag.WorkflowDag{Id: uuid.New(), WorkflowId: workflowId}, nil
+}
+
+// childRunRecords records the workflow dag results that are created and the changes made to them.
+type childRunRecords struct {
+	workflow_dag_result.Reader
+	workflow_dag_result.Writer
+	parameters map[uuid.UUID]shared.Parameters
+	changes    map[uuid.UUID][]map[string]interface{}
+	statuses   map[uuid.UUID]shared.ExecutionStatus
+}
+
+func (r *childRunRecords) GetWorkflowDagResult(
+	ctx context.Context,
+	id uuid.UUID,
+	db database.Database,
+) (*workflow_dag_result.WorkflowDagResult, error) {
+	return &workflow_dag_result.WorkflowDagResult{Id: id, Status: r.statuses[id]}, nil
+}
+
+func (w *childRunRecords) CreateWorkflowDagResult(
+	ctx context.Context,
+	workflowDagId uuid.UUID,
+	resumedFrom *uuid.UUID,
+	parameters shared.Parameters,
+	db database.Database,
+) (*workflow_dag_result.WorkflowDagResult, error) {
+	run := &workflow_dag_result.WorkflowDagResult{Id: uuid.New(), WorkflowDagId: workflowDagId}
+	w.parameters[run.Id] = parameters
+	w.statuses[run.Id] = shared.PendingExecutionStatus
+	return run, nil
+}
+
+func (w *childRunRecords) UpdateWorkflowDagResult(
+	ctx context.Context,
+	id uuid.UUID,
+	changes map[string]interface{},
+	workflowReader workflow.Reader,
+	notificationWriter notification.Writer,
+	userReader user.Reader,
+	db database.Database,
+) (*workflow_dag_result.WorkflowDagResult, error) {
+	w.changes[id] = append(w.changes[id], changes)
+	return &workflow_dag_result.WorkflowDagResult{Id: id}, nil
+}
+
+func (w *childRunRecords) UpdateWorkflowDagResultWithStatus(
+	ctx context.Context,
+	id uuid.UUID,
+	statuses []shared.ExecutionStatus,
+	changes map[string]interface{},
+	workflowReader workflow.Reader,
+	notificationWriter notification.Writer,
+	userReader user.Reader,
+	db database.Database,
+) (*workflow_dag_result.WorkflowDagResult, error) {
+	if !hasStatus(w.statuses[id], statuses) {
+		return nil, database.ErrNoRows
+	}
+
+	return w.UpdateWorkflowDagResult(ctx, id, changes, workflowReader, notificationWriter, userReader, db)
+}
+
+// childRunRequests records the runs that are requested.
+type childRunRequests struct {
+	run_request.Writer
+	parents map[uuid.UUID]*job.ParentOperator
+}
+
+func (w *childRunRequests) CreateRunRequest(
+	ctx context.Context,
+	workflowDagResultId uuid.UUID,
+	workflowId uuid.UUID,
+	parent *job.ParentOperator,
+	db database.Database,
+) (*run_request.RunRequest, error) {
+	w.parents[workflowDagResultId] = parent
+	return &run_request.RunRequest{WorkflowDagResultId: workflowDagResultId, WorkflowId: workflowId}, nil
+}
+
+// cancelledJobs records the jobs that are cancelled.
+type cancelledJobs struct {
+	job.JobManager
+	cancelled []string
+}
+
+func (m *cancelledJobs) Cancel(ctx context.Context, name string) error {
+	m.cancelled = append(m.cancelled, name)
+	return nil
+}
+
+func TestSubWorkflowRequestsChildRun(t *testing.T) {
+	ctx := context.Background()
+	storageConfig := &shared.StorageConfig{
+		Type:       shared.FileStorageType,
+		FileConfig: &shared.FileConfig{Directory: t.TempDir()},
+	}
+	store := storage.NewStorage(storageConfig)
+	require.Nil(t, store.Put(ctx, "region", []byte("\"eu\"\n")))
+
+	userId := uuid.New()
+	report := workflow.Workflow{Id: uuid.New(), UserId: userId, Name: "daily_report"}
+	runs := &childRunRecords{
+		parameters: map[uuid.UUID]shared.Parameters{},
+		changes:    map[uuid.UUID][]map[string]interface{}{},
+		statuses:   map[uuid.UUID]shared.ExecutionStatus{},
+	}
+	requests := &childRunRequests{parents: map[uuid.UUID]*job.ParentOperator{}}
+	jobs := &cancelledJobs{}
+
+	subWorkflows := newSubWorkflowJobManager(
+		jobs,
+		&workflow.Workflow{Id: uuid.New(), UserId: userId},
+		&userWorkflows{workflows: map[string]workflow.Workflow{report.Name: report}},
+		&latestDags{},
+		runs,
+		runs,
+		requests,
+		nil, /* notificationWriter */
+		nil, /* userReader */
+		database.NewNoopDatabase(),
+	)
+
+	var spec operator.Spec
+	require.Nil(t, json.Unmarshal([]byte(
+		`{"sub_workflow": {"workflow_name": "daily_report", "inputs": ["region"], "params": {"days": "7"}, "outputs": ["report"]}}`,
+	), &spec))
+	op := &operator.Operator{Id: uuid.New(), Name: "regional report", Spec: spec}
+	launch := func() (string, error) {
+		return subWorkflows.launch(ctx, op, []string{"region"}, []string{"output"}, []string{"output-metadata"}, "metadata", storageConfig)
+	}
+
+	// Previews do not run sub-workflows.
+	_, err := launch()
+	require.NotNil(t, err)
+
+	parentRunId := uuid.New()
+	subWorkflows.workflowDagResultId = &parentRunId
+	jobName, err := launch()
+	require.Nil(t, err)
+
+	// The child run is requested for the server to launch. It is linked to the parent run, and
+	// takes the operator's input as a param.
+	require.Len(t, requests.parents, 1)
+	var childRunId uuid.UUID
+	var parent *job.ParentOperator
+	for childRunId, parent = range requests.parents {
+	}
+	require.Equal(t, shared.Parameters{"region": `"eu"`, "days": "7"}, runs.parameters[childRunId])
+	require.Equal(t, []map[string]interface{}{
+		{workflow_dag_result.ParentIdColumn: parentRunId},
+	}, runs.changes[childRunId])
+
+	require.Equal(t, parentRunId, parent.WorkflowDagResultId)
+	require.Equal(t, []string{"report"}, parent.OutputArtifactNames)
+	require.Equal(t, []string{"output"}, parent.OutputContentPaths)
+	require.Equal(t, "metadata", parent.MetadataPath)
+
+	// The job is done once the child run has finished and written the operator's metadata.
+	status, err := subWorkflows.Poll(ctx, jobName)
+	require.Nil(t, err)
+	require.Equal(t, shared.PendingExecutionStatus, status)
+
+	runs.statuses[childRunId] = shared.SucceededExecutionStatus
+	status, err = subWorkflows.Poll(ctx, jobName)
+	require.Nil(t, err)
+	require.Equal(t, shared.PendingExecutionStatus, status)
+
+	require.Nil(t, store.Put(ctx, "metadata", []byte(`{}`)))
+	status, err = subWorkflows.Poll(ctx, jobName)
+	require.Nil(t, err)
+	require.Equal(t, shared.SucceededExecutionStatus, status)
+
+	// A child run that stops without writing the metadata fails the operator.
+	require.Nil(t, store.Delete(ctx, "metadata"))
+	jobName, err = launch()
+	require.Nil(t, err)
+	childRunId = subWorkflows.childRuns[jobName].id
+	runs.statuses[childRunId] = shared.FailedExecutionStatus
+	subWorkflows.childRuns[jobName].finishedAt = time.Now().Add(-childRunMetadataGracePeriod)
+
+	status, err = subWorkflows.Poll(ctx, jobName)
+	require.Nil(t, err)
+	require.Equal(t, shared.FailedExecutionStatus, status)
+	rawMetadata, err := store.Get(ctx, "metadata")
+	require.Nil(t, err)
+	var metadata operator_result.Metadata
+	require.Nil(t, json.Unmarshal(rawMetadata, &metadata))
+	require.Contains(t, metadata.Error, "daily_report")
+
+	// Cancelling the job cancels the child run.
+	jobName, err = launch()
+	require.Nil(t, err)
+	childRunId = subWorkflows.childRuns[jobName].id
+	require.Nil(t, subWorkflows.Cancel(ctx, jobName))
+	require.Empty(t, jobs.cancelled)
+	require.Equal(t, shared.CanceledExecutionStatus, runs.changes[childRunId][1][workflow_dag_result.StatusColumn])
+
+	// Workflows of other users cannot be run.
+	report.UserId = uuid.New()
+	subWorkflows.workflowReader = &userWorkflows{workflows: map[string]workflow.Workflow{report.Name: report}}
+	_, err = launch()
+	require.NotNil(t, err)
+}
@@ -0,0 +1,100 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// `workflowTimeout` returns how long a run of the workflow may take.
+func workflowTimeout(workflowMetadata *workflow.Workflow) time.Duration {
+	if workflowMetadata == nil || workflowMetadata.ExecutionPolicy.TimeoutSeconds <= 0 {
+		return defaultTimeout
+	}
+
+	return time.Duration(workflowMetadata.ExecutionPolicy.TimeoutSeconds) * time.Second
+}
+
+func workflowTimeoutErrorMessage(timeout time.Duration) string {
+	return fmt.Sprintf("Operator was cancelled because the workflow reached its timeout of %s.", timeout)
+}
+
+func operatorTimeoutErrorMessage(timeout time.Duration) string {
+	return fmt.Sprintf("Operator timed out after %s.", timeout)
+}
+
+// `operatorTimeouts` keeps track of when the running operators with a timeout have to finish,
+// and of the operators whose jobs were cancelled because they did not finish in time.
+type operatorTimeouts struct {
+	deadlines map[uuid.UUID]time.Time
+	timedOut  map[uuid.UUID]bool
+}
+
+func newOperatorTimeouts() *operatorTimeouts {
+	return &operatorTimeouts{
+		deadlines: map[uuid.UUID]time.Time{},
+		timedOut:  map[uuid.UUID]bool{},
+	}
+}
+
+// `enforce` is called while the operator's job is running. The operator's timeout starts the first
+// time its job is seen running, so time spent in the job queue does not count towards it. Once
+// the timeout has passed, the job is cancelled.
+func (t *operatorTimeouts) enforce(
+	ctx context.Context,
+	operatorId uuid.UUID,
+	timeout time.Duration,
+	jobId string,
+	jobManager job.JobManager,
+) {
+	if timeout <= 0 || t.timedOut[operatorId] {
+		return
+	}
+
+	deadline, ok := t.deadlines[operatorId]
+	if !ok {
+		t.deadlines[operatorId] = time.Now().Add(timeout)
+		return
+	}
+
+	if time.Now().Before(deadline) {
+		return
+	}
+
+	log.Infof("Operator %s reached its timeout of %s, cancelling job %s.", operatorId, timeout, jobId)
+	err := jobManager.Cancel(ctx, jobId)
+	if err != nil && err != job.ErrJobNotExist {
+		log.Errorf("Unable to cancel job %s of timed out operator %s: %v", jobId, operatorId, err)
+		return
+	}
+
+	t.timedOut[operatorId] = true
+}
+
+// `checkTimedOut` returns the result of an operator whose job was cancelled because it reached its
+// timeout. A job that finished before it could be cancelled keeps its own result. A timeout is
+// treated as a failure of the user's code, so the rest of the workflow is cancelled.
+func (t *operatorTimeouts) checkTimedOut(
+	operatorId uuid.UUID,
+	timeout time.Duration,
+	jobStatus shared.ExecutionStatus,
+) (*operator_result.Metadata, bool) {
+	if !t.timedOut[operatorId] || jobStatus != shared.CanceledExecutionStatus {
+		return nil, false
+	}
+
+	return &operator_result.Metadata{Error: operatorTimeoutErrorMessage(timeout)}, true
+}
+
+// `reset` clears the operator's timeout, so the next attempt of the operator gets a full timeout.
+func (t *operatorTimeouts) reset(operatorId uuid.UUID) {
+	delete(t.deadlines, operatorId)
+	delete(t.timedOut, operatorId)
+}
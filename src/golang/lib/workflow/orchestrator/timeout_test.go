@@ -0,0 +1,72 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// cancelRecordingJobManager records the jobs that are cancelled.
+type cancelRecordingJobManager struct {
+	job.JobManager
+	canceled map[string]bool
+}
+
+func (m *cancelRecordingJobManager) Cancel(ctx context.Context, name string) error {
+	m.canceled[name] = true
+	return nil
+}
+
+func TestWorkflowTimeout(t *testing.T) {
+	require.Equal(t, defaultTimeout, workflowTimeout(nil))
+	require.Equal(t, defaultTimeout, workflowTimeout(&workflow.Workflow{}))
+	require.Equal(
+		t,
+		time.Hour,
+		workflowTimeout(&workflow.Workflow{ExecutionPolicy: workflow.ExecutionPolicy{TimeoutSeconds: 3600}}),
+	)
+}
+
+func TestOperatorTimeouts(t *testing.T) {
+	ctx := context.Background()
+	jobManager := &cancelRecordingJobManager{canceled: map[string]bool{}}
+	timeouts := newOperatorTimeouts()
+	operatorId := uuid.New()
+	timeout := 50 * time.Millisecond
+
+	// The timeout starts the first time the job is seen running.
+	timeouts.enforce(ctx, operatorId, timeout, "job", jobManager)
+	require.Empty(t, jobManager.canceled)
+
+	time.Sleep(timeout)
+	timeouts.enforce(ctx, operatorId, timeout, "job", jobManager)
+	require.True(t, jobManager.canceled["job"])
+
+	// A job that finished before it could be cancelled keeps its own result.
+	_, ok := timeouts.checkTimedOut(operatorId, timeout, shared.SucceededExecutionStatus)
+	require.False(t, ok)
+
+	metadata, ok := timeouts.checkTimedOut(operatorId, timeout, shared.CanceledExecutionStatus)
+	require.True(t, ok)
+	require.Equal(t, "Operator timed out after 50ms.", metadata.Error)
+
+	// The next attempt gets a full timeout.
+	timeouts.reset(operatorId)
+	_, ok = timeouts.checkTimedOut(operatorId, timeout, shared.CanceledExecutionStatus)
+	require.False(t, ok)
+}
+
+func TestOperatorWithoutTimeout(t *testing.T) {
+	jobManager := &cancelRecordingJobManager{canceled: map[string]bool{}}
+	timeouts := newOperatorTimeouts()
+
+	timeouts.enforce(context.Background(), uuid.New(), 0, "job", jobManager)
+	require.Empty(t, timeouts.deadlines)
+	require.Empty(t, jobManager.canceled)
+}
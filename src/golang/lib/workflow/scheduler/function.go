@@ -61,6 +61,7 @@ func ScheduleFunction(
 		outputMetadataPaths,
 		inputArtifactTypes,
 		outputArtifactTypes,
+		fn.ResourceLimits,
 	)
 
 	err := jobManager.Launch(ctx, jobName, jobSpec)
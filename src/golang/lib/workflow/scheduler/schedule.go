@@ -9,6 +9,7 @@ import (
 	"github.com/aqueducthq/aqueduct/lib/collections/shared"
 	"github.com/aqueducthq/aqueduct/lib/job"
 	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/function"
 	"github.com/aqueducthq/aqueduct/lib/workflow/utils"
 	"github.com/dropbox/godropbox/errors"
 	log "github.com/sirupsen/logrus"
@@ -62,21 +63,15 @@ func ScheduleOperator(
 ) (string, error) {
 	// Append to this switch for newly supported operator types
 	if opSpec.IsFunction() {
-		// A function operator takes any number of dataframes as input and outputs
-		// any number of dataframes.
-		inputArtifactTypes := make([]artifact.Type, 0, len(inputArtifactSpecs))
-		for _, inputArtifactSpec := range inputArtifactSpecs {
-			if inputArtifactSpec.Type() != artifact.TableType && inputArtifactSpec.Type() != artifact.JsonType {
-				return "", errors.New("Inputs to function operator must be Table or Parameter Artifacts.")
-			}
-			inputArtifactTypes = append(inputArtifactTypes, inputArtifactSpec.Type())
+		// A function operator takes any number of tables, floats, booleans and JSON as input and
+		// outputs any number of them.
+		inputArtifactTypes, err := functionArtifactTypes(inputArtifactSpecs)
+		if err != nil {
+			return "", errors.Wrap(err, "Inputs to function operator must be Table, Float, Bool, or Json Artifacts.")
 		}
-		outputArtifactTypes := make([]artifact.Type, 0, len(outputArtifactSpecs))
-		for _, outputArtifactSpec := range outputArtifactSpecs {
-			if outputArtifactSpec.Type() != artifact.TableType {
-				return "", errors.New("Outputs of function operator must be Table Artifacts.")
-			}
-			outputArtifactTypes = append(outputArtifactTypes, outputArtifactSpec.Type())
+		outputArtifactTypes, err := functionArtifactTypes(outputArtifactSpecs)
+		if err != nil {
+			return "", errors.Wrap(err, "Outputs of function operator must be Table, Float, Bool, or Json Artifacts.")
 		}
 
 		return ScheduleFunction(
@@ -99,14 +94,9 @@ func ScheduleOperator(
 			return "", ErrWrongNumOutputs
 		}
 
-		inputArtifactTypes := make([]artifact.Type, 0, len(inputArtifactSpecs))
-		for _, inputArtifactSpec := range inputArtifactSpecs {
-			if inputArtifactSpec.Type() != artifact.TableType &&
-				inputArtifactSpec.Type() != artifact.FloatType &&
-				inputArtifactSpec.Type() != artifact.JsonType {
-				return "", errors.New("Inputs to metric operator must be Table, Float, or Parameter Artifacts.")
-			}
-			inputArtifactTypes = append(inputArtifactTypes, inputArtifactSpec.Type())
+		inputArtifactTypes, err := functionArtifactTypes(inputArtifactSpecs)
+		if err != nil {
+			return "", errors.Wrap(err, "Inputs to metric operator must be Table, Float, Bool, or Json Artifacts.")
 		}
 		outputArtifactTypes := []artifact.Type{artifact.FloatType}
 
@@ -130,15 +120,10 @@ func ScheduleOperator(
 			return "", ErrWrongNumOutputs
 		}
 
-		// Checks can be computed on tables and metrics.
-		inputArtifactTypes := make([]artifact.Type, 0, len(inputArtifactSpecs))
-		for _, inputArtifactSpec := range inputArtifactSpecs {
-			if inputArtifactSpec.Type() != artifact.TableType &&
-				inputArtifactSpec.Type() != artifact.FloatType &&
-				inputArtifactSpec.Type() != artifact.JsonType {
-				return "", errors.New("Inputs to metric operator must be Table, Float, or Parameter Artifacts.")
-			}
-			inputArtifactTypes = append(inputArtifactTypes, inputArtifactSpec.Type())
+		// Checks can be computed on tables, metrics and the other artifacts functions take.
+		inputArtifactTypes, err := functionArtifactTypes(inputArtifactSpecs)
+		if err != nil {
+			return "", errors.Wrap(err, "Inputs to check operator must be Table, Float, Bool, or Json Artifacts.")
 		}
 		outputArtifactTypes := []artifact.Type{artifact.BoolType}
 
@@ -236,10 +221,54 @@ func ScheduleOperator(
 		)
 	}
 
+	if opSpec.IsSensor() {
+		// The operators downstream of a sensor take its output only to wait for it.
+		if len(inputArtifactSpecs) != 0 {
+			return "", ErrWrongNumInputs
+		}
+		if len(outputArtifactSpecs) != 1 {
+			return "", ErrWrongNumOutputs
+		}
+		if !outputArtifactSpecs[0].IsBool() {
+			return "", errors.Newf("Internal Error: sensor must output a boolean artifact.")
+		}
+		if len(outputContentPaths) != 1 {
+			return "", ErrWrongNumArtifactContentPaths
+		}
+		if len(outputMetadataPaths) != 1 {
+			return "", ErrWrongNumArtifactMetadataPaths
+		}
+
+		return ScheduleSensor(
+			ctx,
+			*opSpec.Sensor(),
+			metadataPath,
+			outputContentPaths[0],
+			outputMetadataPaths[0],
+			storageConfig,
+			jobManager,
+			vaultObject,
+		)
+	}
+
 	// If we reach here, the operator opSpec type is not supported.
 	return "", errors.Newf("Unsupported operator opSpec with type %s", opSpec.Type())
 }
 
+// functionArtifactTypes returns the types of the artifacts, which must all be types that functions
+// support.
+func functionArtifactTypes(artifactSpecs []artifact.Spec) ([]artifact.Type, error) {
+	artifactTypes := make([]artifact.Type, 0, len(artifactSpecs))
+	for _, artifactSpec := range artifactSpecs {
+		if !function.IsSupportedArtifactType(artifactSpec.Type()) {
+			return nil, errors.Newf("Unsupported artifact type %s.", artifactSpec.Type())
+		}
+		artifactTypes = append(artifactTypes, artifactSpec.Type())
+	}
+
+	return artifactTypes, nil
+}
+
 type FailureType int64
 
 const (
@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/auth"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+func generateSensorJobName() string {
+	return fmt.Sprintf("sensor-operator-%s", uuid.New().String())
+}
+
+func ScheduleSensor(
+	ctx context.Context,
+	spec connector.Sensor,
+	metadataPath string,
+	outputContentPath string,
+	outputMetadataPath string,
+	storageConfig *shared.StorageConfig,
+	jobManager job.JobManager,
+	vaultObject vault.Vault,
+) (string, error) {
+	config, err := auth.ReadConfigFromSecret(ctx, spec.IntegrationId, vaultObject)
+	if err != nil {
+		return "", err
+	}
+
+	jobName := generateSensorJobName()
+
+	jobSpec := job.NewSensorSpec(
+		jobName,
+		storageConfig,
+		metadataPath,
+		spec.Service,
+		config,
+		spec.Query,
+		spec.PollInterval(),
+		spec.Deadline(),
+		outputContentPath,
+		outputMetadataPath,
+	)
+
+	err = jobManager.Launch(ctx, jobName, jobSpec)
+	if err != nil {
+		return "", errors.Wrap(err, "Unable to schedule Sensor.")
+	}
+
+	return jobName, nil
+}
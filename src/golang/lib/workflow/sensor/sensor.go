@@ -0,0 +1,284 @@
+package sensor
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/sensor_observation"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultPollInterval = time.Minute
+	MinPollInterval     = 10 * time.Second
+
+	// The maximum number of new observations, and hence runs, per poll. The rest are picked up
+	// by the following polls.
+	MaxObservationsPerPoll = 100
+
+	// The directory under the server's storage that local directory sensors may watch, unless
+	// the server is configured with another one.
+	DefaultRootDir = "sensors/"
+)
+
+// Root returns the directory that local directory sensors may watch: `dir`, or DefaultRootDir
+// if it is empty, relative to the server's storage directory. The directory is created if it does
+// not exist.
+func Root(storageDir string, dir string) (string, error) {
+	if dir == "" {
+		dir = DefaultRootDir
+	}
+
+	root := filepath.Join(storageDir, dir)
+	if !isWithin(storageDir, root) {
+		return "", errors.Newf("The sensor directory %s must be within the server's storage.", dir)
+	}
+
+	if err := os.MkdirAll(root, os.ModePerm); err != nil {
+		return "", errors.Wrap(err, "Unable to create the sensor directory.")
+	}
+
+	return root, nil
+}
+
+// Validate checks that the sensor is fully specified. A local directory sensor may only watch
+// `root` or a directory within it.
+func Validate(s *workflow.Sensor, root string) error {
+	if s == nil {
+		return errors.New("A sensor trigger must specify a sensor.")
+	}
+
+	if s.Parameter == "" {
+		return errors.New("A sensor must name the parameter that receives what it observes.")
+	}
+
+	if s.PollIntervalSeconds < 0 || (s.PollIntervalSeconds > 0 && PollInterval(s) < MinPollInterval) {
+		return errors.Newf("A sensor cannot poll more often than every %v.", MinPollInterval)
+	}
+
+	switch s.Type {
+	case workflow.StorageSensorType:
+		return validateStorageSensor(s.Storage, root)
+	default:
+		return errors.Newf("Unknown sensor type %s.", s.Type)
+	}
+}
+
+func validateStorageSensor(s *workflow.StorageSensor, root string) error {
+	if s == nil {
+		return errors.New("A storage sensor must specify the storage it watches.")
+	}
+
+	if (s.IntegrationId == nil) == (s.Directory == "") {
+		return errors.New("A storage sensor must watch exactly one of an S3 integration and a local directory.")
+	}
+
+	if s.Directory == "" {
+		return nil
+	}
+
+	if !filepath.IsAbs(s.Directory) {
+		return errors.New("The directory watched by a storage sensor must be an absolute path.")
+	}
+
+	return checkWithinRoot(s.Directory, root)
+}
+
+// checkWithinRoot returns an error unless `dir` is `root` or a directory within it, after
+// resolving symbolic links.
+func checkWithinRoot(dir string, root string) error {
+	if root == "" || !isWithin(resolveSymlinks(root), resolveSymlinks(dir)) {
+		return errors.Newf("A storage sensor can only watch a directory within %s.", root)
+	}
+
+	return nil
+}
+
+// resolveSymlinks returns the path with its symbolic links resolved. The path may not exist yet,
+// in which case it is only cleaned.
+func resolveSymlinks(path string) string {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+
+	return resolved
+}
+
+// isWithin returns whether `path` is `dir` or a path within it.
+func isWithin(dir string, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// PollInterval returns how often the sensor polls.
+func PollInterval(s *workflow.Sensor) time.Duration {
+	if s.PollIntervalSeconds == 0 {
+		return DefaultPollInterval
+	}
+
+	return time.Duration(s.PollIntervalSeconds) * time.Second
+}
+
+// Observation is something that the sensor observes, e.g. the path of a file, along with a
+// version that changes whenever it changes, e.g. when the file is overwritten, and when it
+// last changed.
+type Observation struct {
+	Value      string
+	Version    string
+	ModifiedAt time.Time
+}
+
+// Parameters returns the parameters of the run that the sensor starts for the observation.
+func Parameters(s *workflow.Sensor, observation string) (shared.Parameters, error) {
+	val, err := json.Marshal(observation)
+	if err != nil {
+		return nil, err
+	}
+
+	return shared.Parameters{s.Parameter: string(val)}, nil
+}
+
+// observe returns what the sensor observes now.
+func observe(ctx context.Context, s *workflow.Sensor, root string, vaultObject vault.Vault) ([]Observation, error) {
+	switch s.Type {
+	case workflow.StorageSensorType:
+		return pollStorage(ctx, s.Storage, root, vaultObject)
+	default:
+		return nil, errors.Newf("Unknown sensor type %s.", s.Type)
+	}
+}
+
+// Seed records everything that the sensor of the workflow observes now, replacing what it has
+// observed before, so that the sensor only starts runs for what changes from now on. It is
+// called whenever a sensor is registered or edited.
+func Seed(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	s *workflow.Sensor,
+	root string,
+	sensorObservationWriter sensor_observation.Writer,
+	vaultObject vault.Vault,
+	db database.Database,
+) error {
+	current, err := observe(ctx, s, root, vaultObject)
+	if err != nil {
+		return err
+	}
+
+	if err := sensorObservationWriter.DeleteObservationsByWorkflowId(ctx, workflowId, db); err != nil {
+		return err
+	}
+
+	for _, observation := range current {
+		_, err := sensorObservationWriter.RecordObservation(ctx, workflowId, observation.Value, observation.Version, db)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Poll returns what the sensor of the workflow observes now but has not observed before, or has
+// observed at a different version, in order and at most MaxObservationsPerPoll of them. What
+// changed within the last poll interval before `now` may still be being written, so it is left
+// to a later poll. The caller records each observation once it starts its run. The observations
+// of what the sensor no longer sees are deleted, so that the workflow's observations never
+// outgrow what its sensor sees.
+func Poll(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	s *workflow.Sensor,
+	root string,
+	now time.Time,
+	sensorObservationReader sensor_observation.Reader,
+	sensorObservationWriter sensor_observation.Writer,
+	vaultObject vault.Vault,
+	db database.Database,
+) ([]Observation, error) {
+	current, err := observe(ctx, s, root, vaultObject)
+	if err != nil {
+		return nil, err
+	}
+
+	observations, err := sensorObservationReader.GetObservationsByWorkflowId(ctx, workflowId, db)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]string, len(observations))
+	for _, observation := range observations {
+		seen[observation.Observation] = observation.Version
+	}
+
+	if err := sensorObservationWriter.DeleteObservations(ctx, workflowId, gone(current, seen), db); err != nil {
+		return nil, err
+	}
+
+	return unseen(settled(current, now.Add(-PollInterval(s))), seen, MaxObservationsPerPoll), nil
+}
+
+// settled returns the elements of `current` that last changed before `before`.
+func settled(current []Observation, before time.Time) []Observation {
+	result := make([]Observation, 0, len(current))
+	for _, observation := range current {
+		if observation.ModifiedAt.Before(before) {
+			result = append(result, observation)
+		}
+	}
+
+	return result
+}
+
+// gone returns the observations in `seen` that are not in `current`.
+func gone(current []Observation, seen map[string]string) []string {
+	observed := make(map[string]bool, len(current))
+	for _, observation := range current {
+		observed[observation.Value] = true
+	}
+
+	result := []string{}
+	for value := range seen {
+		if !observed[value] {
+			result = append(result, value)
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// unseen returns the elements of `current`, sorted by value, that are not in `seen` at the same
+// version, at most `limit` of them.
+func unseen(current []Observation, seen map[string]string, limit int) []Observation {
+	sorted := append([]Observation{}, current...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Value < sorted[j].Value })
+
+	result := []Observation{}
+	for _, observation := range sorted {
+		if len(result) == limit {
+			break
+		}
+
+		if version, ok := seen[observation.Value]; !ok || version != observation.Version {
+			result = append(result, observation)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,296 @@
+package sensor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/sensor_observation"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// recordedObservations stores the observations of the workflow in memory.
+type recordedObservations struct {
+	sensor_observation.Reader
+	sensor_observation.Writer
+	versions map[string]string
+}
+
+func (r *recordedObservations) GetObservationsByWorkflowId(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	db database.Database,
+) ([]sensor_observation.SensorObservation, error) {
+	result := make([]sensor_observation.SensorObservation, 0, len(r.versions))
+	for observation, version := range r.versions {
+		result = append(result, sensor_observation.SensorObservation{
+			WorkflowId:  workflowId,
+			Observation: observation,
+			Version:     version,
+		})
+	}
+	return result, nil
+}
+
+func (r *recordedObservations) RecordObservation(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	observation string,
+	version string,
+	db database.Database,
+) (*sensor_observation.SensorObservation, error) {
+	r.versions[observation] = version
+	return &sensor_observation.SensorObservation{WorkflowId: workflowId, Observation: observation, Version: version}, nil
+}
+
+func (r *recordedObservations) DeleteObservations(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	observations []string,
+	db database.Database,
+) error {
+	for _, observation := range observations {
+		delete(r.versions, observation)
+	}
+	return nil
+}
+
+func (r *recordedObservations) DeleteObservationsByWorkflowId(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	db database.Database,
+) error {
+	r.versions = map[string]string{}
+	return nil
+}
+
+// writeFile writes the file as if its writer had finished writing it `age` ago.
+func writeFile(t *testing.T, dir string, path string, content string, age time.Duration) {
+	require.Nil(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0o755))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, path), []byte(content), 0o644))
+
+	modifiedAt := time.Now().Add(-age)
+	require.Nil(t, os.Chtimes(filepath.Join(dir, path), modifiedAt, modifiedAt))
+}
+
+func values(observations []Observation) []string {
+	result := make([]string, 0, len(observations))
+	for _, observation := range observations {
+		result = append(result, observation.Value)
+	}
+	return result
+}
+
+func mapObservations(versions map[string]string) []Observation {
+	result := make([]Observation, 0, len(versions))
+	for value, version := range versions {
+		result = append(result, Observation{Value: value, Version: version})
+	}
+	return result
+}
+
+func TestPollLocalDirectory(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	dir := filepath.Join(root, "watched")
+	for _, path := range []string{"drop/2022-01-01.csv", "other/b.csv"} {
+		writeFile(t, dir, path, "data", time.Hour)
+	}
+
+	s := &workflow.Sensor{
+		Type:      workflow.StorageSensorType,
+		Parameter: "path",
+		Storage:   &workflow.StorageSensor{Directory: dir, Prefix: "drop/"},
+	}
+	require.Nil(t, Validate(s, root))
+
+	workflowId := uuid.New()
+	db := database.NewNoopDatabase()
+	recorded := &recordedObservations{versions: map[string]string{}}
+	poll := func() []string {
+		observations, err := Poll(ctx, workflowId, s, root, time.Now(), recorded, recorded, nil /* vaultObject */, db)
+		require.Nil(t, err)
+		for _, observation := range observations {
+			recorded.versions[observation.Value] = observation.Version
+		}
+		return values(observations)
+	}
+
+	// The files that are there when the sensor is enabled do not start runs.
+	require.Nil(t, Seed(ctx, workflowId, s, root, recorded, nil /* vaultObject */, db))
+	require.Equal(t, []string{"drop/2022-01-01.csv"}, values(mapObservations(recorded.versions)))
+	require.Empty(t, poll())
+
+	writeFile(t, dir, "drop/2022-01-02.csv", "data", time.Hour)
+	writeFile(t, dir, "drop/nested/a.csv", "data", time.Hour)
+	require.Equal(t, []string{"drop/2022-01-02.csv", "drop/nested/a.csv"}, poll())
+	require.Empty(t, poll())
+
+	// Overwriting a file observes it again.
+	writeFile(t, dir, "drop/2022-01-01.csv", "more data", time.Hour)
+	require.Equal(t, []string{"drop/2022-01-01.csv"}, poll())
+
+	// A file that changed within the last poll interval may still be being written, so it is
+	// only observed once it has not changed for a poll interval.
+	writeFile(t, dir, "drop/2022-01-03.csv", "partial", 0)
+	require.Empty(t, poll())
+
+	writeFile(t, dir, "drop/2022-01-03.csv", "partial data", PollInterval(s)/2)
+	require.Empty(t, poll())
+
+	writeFile(t, dir, "drop/2022-01-03.csv", "partial data", 2*PollInterval(s))
+	require.Equal(t, []string{"drop/2022-01-03.csv"}, poll())
+
+	// The observations of deleted files are forgotten, so a file that is written again is
+	// observed again.
+	require.Nil(t, os.Remove(filepath.Join(dir, "drop/nested/a.csv")))
+	require.Empty(t, poll())
+	require.NotContains(t, recorded.versions, "drop/nested/a.csv")
+
+	writeFile(t, dir, "drop/nested/a.csv", "data", time.Hour)
+	require.Equal(t, []string{"drop/nested/a.csv"}, poll())
+
+	parameters, err := Parameters(s, "drop/nested/a.csv")
+	require.Nil(t, err)
+	require.Equal(t, shared.Parameters{"path": `"drop/nested/a.csv"`}, parameters)
+}
+
+func TestPollMissingPrefix(t *testing.T) {
+	root := t.TempDir()
+	s := &workflow.Sensor{
+		Type:      workflow.StorageSensorType,
+		Parameter: "path",
+		Storage:   &workflow.StorageSensor{Directory: root, Prefix: "drop/2022-"},
+	}
+
+	recorded := &recordedObservations{versions: map[string]string{}}
+	observations, err := Poll(
+		context.Background(),
+		uuid.New(),
+		s,
+		root,
+		time.Now(),
+		recorded,
+		recorded,
+		nil, /* vaultObject */
+		database.NewNoopDatabase(),
+	)
+	require.Nil(t, err)
+	require.Empty(t, observations)
+}
+
+func TestPollOutsideRoot(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	writeFile(t, outside, "drop/a.csv", "data", time.Hour)
+
+	// A symbolic link within the root does not let the sensor watch what it links to.
+	link := filepath.Join(root, "link")
+	require.Nil(t, os.Symlink(outside, link))
+
+	for _, dir := range []string{outside, link, filepath.Join(root, "..", filepath.Base(outside))} {
+		s := &workflow.Sensor{
+			Type:      workflow.StorageSensorType,
+			Parameter: "path",
+			Storage:   &workflow.StorageSensor{Directory: dir, Prefix: "drop/"},
+		}
+		require.NotNil(t, Validate(s, root))
+
+		recorded := &recordedObservations{versions: map[string]string{}}
+		_, err := Poll(
+			context.Background(),
+			uuid.New(),
+			s,
+			root,
+			time.Now(),
+			recorded,
+			recorded,
+			nil, /* vaultObject */
+			database.NewNoopDatabase(),
+		)
+		require.NotNil(t, err)
+	}
+}
+
+func TestRoot(t *testing.T) {
+	storageDir := t.TempDir()
+
+	root, err := Root(storageDir, "")
+	require.Nil(t, err)
+	require.Equal(t, filepath.Join(storageDir, DefaultRootDir), root)
+	info, err := os.Stat(root)
+	require.Nil(t, err)
+	require.True(t, info.IsDir())
+
+	_, err = Root(storageDir, "../sensors")
+	require.NotNil(t, err)
+}
+
+func TestUnseenLimit(t *testing.T) {
+	current := []Observation{
+		{Value: "d", Version: "1"},
+		{Value: "c", Version: "1"},
+		{Value: "b", Version: "1"},
+		{Value: "a", Version: "1"},
+	}
+	require.Equal(
+		t,
+		[]Observation{{Value: "a", Version: "1"}, {Value: "c", Version: "1"}},
+		unseen(current, map[string]string{"b": "1"}, 2),
+	)
+	require.Equal(
+		t,
+		[]Observation{{Value: "b", Version: "1"}},
+		unseen(current, map[string]string{"a": "1", "b": "0", "c": "1", "d": "1"}, 2),
+	)
+}
+
+func TestValidate(t *testing.T) {
+	integrationId := uuid.New()
+	invalid := []*workflow.Sensor{
+		nil,
+		{Type: workflow.StorageSensorType, Storage: &workflow.StorageSensor{Directory: "/drop"}},
+		{Type: "unknown", Parameter: "path"},
+		{Type: workflow.StorageSensorType, Parameter: "path"},
+		{Type: workflow.StorageSensorType, Parameter: "path", Storage: &workflow.StorageSensor{}},
+		{Type: workflow.StorageSensorType, Parameter: "path", Storage: &workflow.StorageSensor{Directory: "drop"}},
+		{
+			Type:      workflow.StorageSensorType,
+			Parameter: "path",
+			Storage:   &workflow.StorageSensor{IntegrationId: &integrationId, Directory: "/drop"},
+		},
+		{
+			Type:                workflow.StorageSensorType,
+			Parameter:           "path",
+			PollIntervalSeconds: 1,
+			Storage:             &workflow.StorageSensor{Directory: "/drop"},
+		},
+	}
+	for _, s := range invalid {
+		require.NotNil(t, Validate(s, "/drop"))
+	}
+
+	require.Nil(t, Validate(&workflow.Sensor{
+		Type:      workflow.StorageSensorType,
+		Parameter: "path",
+		Storage:   &workflow.StorageSensor{IntegrationId: &integrationId, Prefix: "drop/"},
+	}, "/drop"))
+
+	// A local directory sensor can only watch the root or a directory within it.
+	local := &workflow.Sensor{
+		Type:      workflow.StorageSensorType,
+		Parameter: "path",
+		Storage:   &workflow.StorageSensor{Directory: "/drop/incoming"},
+	}
+	require.Nil(t, Validate(local, "/drop"))
+	require.Nil(t, Validate(local, "/drop/incoming"))
+	require.NotNil(t, Validate(local, "/other"))
+	require.NotNil(t, Validate(local, "/drop/incoming/nested"))
+	require.NotNil(t, Validate(local, ""))
+}
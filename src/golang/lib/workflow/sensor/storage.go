@@ -0,0 +1,81 @@
+package sensor
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/storage"
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/aqueducthq/aqueduct/lib/workflow/operator/connector/auth"
+)
+
+// s3Credentials are the fields of an S3 integration's config that the sensor needs.
+type s3Credentials struct {
+	AccessKeyId     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	Bucket          string `json:"bucket"`
+}
+
+// pollStorage returns the paths of the files that the storage sensor watches, versioned by
+// their content.
+func pollStorage(ctx context.Context, s *workflow.StorageSensor, root string, vaultObject vault.Vault) ([]Observation, error) {
+	store, err := openStorage(ctx, s, root, vaultObject)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := store.List(ctx, s.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	observations := make([]Observation, 0, len(objects))
+	for _, object := range objects {
+		observations = append(observations, Observation{
+			Value:      object.Key,
+			Version:    object.Version,
+			ModifiedAt: object.ModifiedAt,
+		})
+	}
+
+	return observations, nil
+}
+
+func openStorage(
+	ctx context.Context,
+	s *workflow.StorageSensor,
+	root string,
+	vaultObject vault.Vault,
+) (storage.Storage, error) {
+	if s.IntegrationId == nil {
+		// The directory is checked again, since the root or a symbolic link may have changed
+		// since the sensor was validated.
+		if err := checkWithinRoot(s.Directory, root); err != nil {
+			return nil, err
+		}
+
+		return storage.NewStorage(&shared.StorageConfig{
+			Type:       shared.FileStorageType,
+			FileConfig: &shared.FileConfig{Directory: s.Directory},
+		}), nil
+	}
+
+	config, err := auth.ReadConfigFromSecret(ctx, *s.IntegrationId, vaultObject)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := config.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	var creds s3Credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, err
+	}
+
+	return storage.NewS3IntegrationStorage(ctx, creds.Bucket, creds.AccessKeyId, creds.SecretAccessKey)
+}
@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/run_request"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// triggerCascadingWorkflows requests a run of every workflow with a cascade trigger that lists
+// the workflow with id `workflowId` as a source, once its cascade condition is met. `status` is
+// the status of the run of the source workflow that just finished. The runs are created here and
+// launched by the server, since the executor's job manager only runs the operators of this run.
+func triggerCascadingWorkflows(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	status shared.ExecutionStatus,
+	workflowReader workflow.Reader,
+	workflowDagReader workflow_dag.Reader,
+	workflowDagResultReader workflow_dag_result.Reader,
+	workflowDagResultWriter workflow_dag_result.Writer,
+	runRequestWriter run_request.Writer,
+	db database.Database,
+) error {
+	workflows, err := workflowReader.GetAllWorkflows(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, downstream := range workflows {
+		if downstream.Schedule.Paused || !downstream.Schedule.IsCascadeSource(workflowId) {
+			continue
+		}
+
+		ok, err := cascadeConditionMet(ctx, &downstream, status, workflowDagResultReader, db)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		workflowDag, err := workflowDagReader.GetLatestWorkflowDag(ctx, downstream.Id, db)
+		if err != nil {
+			return err
+		}
+
+		run, err := workflowDagResultWriter.CreateWorkflowDagResult(
+			ctx,
+			workflowDag.Id,
+			nil, /* resumedFrom */
+			nil, /* parameters */
+			db,
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = runRequestWriter.CreateRunRequest(ctx, run.Id, downstream.Id, nil /* parent */, db)
+		if err != nil {
+			return err
+		}
+
+		log.Infof("Workflow %s was triggered by a run of workflow %s.", downstream.Id, workflowId)
+	}
+
+	return nil
+}
+
+// cascadeConditionMet returns whether the downstream workflow should run now that a run of one of
+// its source workflows finished with `status`.
+func cascadeConditionMet(
+	ctx context.Context,
+	downstream *workflow.Workflow,
+	status shared.ExecutionStatus,
+	workflowDagResultReader workflow_dag_result.Reader,
+	db database.Database,
+) (bool, error) {
+	if downstream.Schedule.GetCascadeCondition() == workflow.AnyFinishedCascadeCondition {
+		return true, nil
+	}
+
+	if status != shared.SucceededExecutionStatus {
+		return false, nil
+	}
+
+	lastRun, err := latestWorkflowDagResult(ctx, downstream.Id, workflowDagResultReader, db)
+	if err != nil {
+		return false, err
+	}
+
+	var lastRunAt time.Time
+	if lastRun != nil {
+		lastRunAt = lastRun.CreatedAt
+	}
+
+	// Every source workflow must have succeeded since the downstream workflow last ran.
+	for _, sourceId := range downstream.Schedule.SourceWorkflowIds {
+		sourceRun, err := latestWorkflowDagResult(ctx, sourceId, workflowDagResultReader, db)
+		if err != nil {
+			return false, err
+		}
+
+		if sourceRun == nil ||
+			sourceRun.Status != shared.SucceededExecutionStatus ||
+			!sourceRun.CreatedAt.After(lastRunAt) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// latestWorkflowDagResult returns the latest run of the workflow, or nil if it never ran.
+func latestWorkflowDagResult(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	workflowDagResultReader workflow_dag_result.Reader,
+	db database.Database,
+) (*workflow_dag_result.WorkflowDagResult, error) {
+	workflowDagResults, err := workflowDagResultReader.GetWorkflowDagResultsByWorkflowId(ctx, workflowId, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *workflow_dag_result.WorkflowDagResult
+	for i := range workflowDagResults {
+		if latest == nil || workflowDagResults[i].CreatedAt.After(latest.CreatedAt) {
+			latest = &workflowDagResults[i]
+		}
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,175 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/run_request"
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/aqueducthq/aqueduct/lib/job"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// cascadeRuns returns the runs of each workflow.
+type cascadeRuns struct {
+	workflow_dag_result.Reader
+	runs map[uuid.UUID][]workflow_dag_result.WorkflowDagResult
+}
+
+func (r *cascadeRuns) GetWorkflowDagResultsByWorkflowId(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	db database.Database,
+) ([]workflow_dag_result.WorkflowDagResult, error) {
+	return r.runs[workflowId], nil
+}
+
+func TestCascadeConditionMet(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewNoopDatabase()
+	ingest, clean, report := uuid.New(), uuid.New(), uuid.New()
+	start := time.Now()
+
+	downstream := &workflow.Workflow{
+		Id: report,
+		Schedule: workflow.Schedule{
+			Trigger:           workflow.CascadeUpdateTrigger,
+			SourceWorkflowIds: []uuid.UUID{ingest, clean},
+		},
+	}
+
+	runs := &cascadeRuns{runs: map[uuid.UUID][]workflow_dag_result.WorkflowDagResult{
+		ingest: {{Status: shared.SucceededExecutionStatus, CreatedAt: start.Add(2 * time.Minute)}},
+		clean:  {{Status: shared.FailedExecutionStatus, CreatedAt: start.Add(time.Minute)}},
+		report: {{Status: shared.SucceededExecutionStatus, CreatedAt: start}},
+	}}
+
+	// The clean workflow has not succeeded since the report workflow last ran.
+	ok, err := cascadeConditionMet(ctx, downstream, shared.SucceededExecutionStatus, runs, db)
+	require.Nil(t, err)
+	require.False(t, ok)
+
+	runs.runs[clean] = append(
+		runs.runs[clean],
+		workflow_dag_result.WorkflowDagResult{Status: shared.SucceededExecutionStatus, CreatedAt: start.Add(3 * time.Minute)},
+	)
+	ok, err = cascadeConditionMet(ctx, downstream, shared.SucceededExecutionStatus, runs, db)
+	require.Nil(t, err)
+	require.True(t, ok)
+
+	// Once the report workflow ran again, it waits for new runs of both sources.
+	runs.runs[report] = append(
+		runs.runs[report],
+		workflow_dag_result.WorkflowDagResult{Status: shared.SucceededExecutionStatus, CreatedAt: start.Add(4 * time.Minute)},
+	)
+	ok, err = cascadeConditionMet(ctx, downstream, shared.SucceededExecutionStatus, runs, db)
+	require.Nil(t, err)
+	require.False(t, ok)
+
+	// Any finished run triggers a workflow with the `any_finished` condition.
+	downstream.Schedule.CascadeCondition = workflow.AnyFinishedCascadeCondition
+	ok, err = cascadeConditionMet(ctx, downstream, shared.FailedExecutionStatus, runs, db)
+	require.Nil(t, err)
+	require.True(t, ok)
+}
+
+// cascadeWorkflows returns all workflows.
+type cascadeWorkflows struct {
+	workflow.Reader
+	workflows []workflow.Workflow
+}
+
+func (r *cascadeWorkflows) GetAllWorkflows(ctx context.Context, db database.Database) ([]workflow.Workflow, error) {
+	return r.workflows, nil
+}
+
+// cascadeDags returns a new dag of any workflow.
+type cascadeDags struct {
+	workflow_dag.Reader
+}
+
+func (*cascadeDags) GetLatestWorkflowDag(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	db database.Database,
+) (*workflow_dag.WorkflowDag, error) {
+	return &workflow_dag.WorkflowDag{Id: uuid.New(), WorkflowId: workflowId}, nil
+}
+
+// createdRuns records the runs that are created.
+type createdRuns struct {
+	workflow_dag_result.Writer
+	created []uuid.UUID
+}
+
+func (w *createdRuns) CreateWorkflowDagResult(
+	ctx context.Context,
+	workflowDagId uuid.UUID,
+	resumedFrom *uuid.UUID,
+	parameters shared.Parameters,
+	db database.Database,
+) (*workflow_dag_result.WorkflowDagResult, error) {
+	run := &workflow_dag_result.WorkflowDagResult{Id: uuid.New(), WorkflowDagId: workflowDagId}
+	w.created = append(w.created, run.Id)
+	return run, nil
+}
+
+// requestedRuns records the workflows of the runs that are requested.
+type requestedRuns struct {
+	run_request.Writer
+	requested map[uuid.UUID]uuid.UUID
+}
+
+func (w *requestedRuns) CreateRunRequest(
+	ctx context.Context,
+	workflowDagResultId uuid.UUID,
+	workflowId uuid.UUID,
+	parent *job.ParentOperator,
+	db database.Database,
+) (*run_request.RunRequest, error) {
+	w.requested[workflowDagResultId] = workflowId
+	return &run_request.RunRequest{WorkflowDagResultId: workflowDagResultId, WorkflowId: workflowId}, nil
+}
+
+func TestTriggerCascadingWorkflowsRequestsRuns(t *testing.T) {
+	ingest, report, paused := uuid.New(), uuid.New(), uuid.New()
+	cascade := workflow.Schedule{
+		Trigger:           workflow.CascadeUpdateTrigger,
+		SourceWorkflowIds: []uuid.UUID{ingest},
+		CascadeCondition:  workflow.AnyFinishedCascadeCondition,
+	}
+	pausedCascade := cascade
+	pausedCascade.Paused = true
+
+	workflows := &cascadeWorkflows{workflows: []workflow.Workflow{
+		{Id: ingest},
+		{Id: report, Schedule: cascade},
+		{Id: paused, Schedule: pausedCascade},
+	}}
+	runs := &createdRuns{}
+	requests := &requestedRuns{requested: map[uuid.UUID]uuid.UUID{}}
+
+	err := triggerCascadingWorkflows(
+		context.Background(),
+		ingest,
+		shared.FailedExecutionStatus,
+		workflows,
+		&cascadeDags{},
+		&cascadeRuns{},
+		runs,
+		requests,
+		database.NewNoopDatabase(),
+	)
+	require.Nil(t, err)
+
+	// A run of the report workflow is recorded for the server to launch, while the paused
+	// workflow is not run.
+	require.Len(t, runs.created, 1)
+	require.Equal(t, map[uuid.UUID]uuid.UUID{runs.created[0]: report}, requests.requested)
+}
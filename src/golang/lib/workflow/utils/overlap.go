@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/shared"
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/aqueducthq/aqueduct/lib/database"
+	"github.com/google/uuid"
+)
+
+// GetPendingWorkflowDagResults returns the pending workflow dag results of the workflow with id
+// `workflowId`, i.e. its runs that are in progress, await approval or are waiting to start.
+func GetPendingWorkflowDagResults(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	workflowDagResultReader workflow_dag_result.Reader,
+	db database.Database,
+) ([]workflow_dag_result.WorkflowDagResult, error) {
+	workflowDagResults, err := workflowDagResultReader.GetWorkflowDagResultsByWorkflowId(ctx, workflowId, db)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]workflow_dag_result.WorkflowDagResult, 0, len(workflowDagResults))
+	for _, workflowDagResult := range workflowDagResults {
+		if workflowDagResult.Status == shared.PendingExecutionStatus ||
+			workflowDagResult.Status == shared.AwaitingApprovalExecutionStatus {
+			pending = append(pending, workflowDagResult)
+		}
+	}
+
+	return pending, nil
+}
+
+// RunsAhead returns the runs in `pending` that were created before `run`. Runs created at the
+// same time are ordered by their ids, so that queued runs agree on the order they start in.
+func RunsAhead(
+	pending []workflow_dag_result.WorkflowDagResult,
+	run *workflow_dag_result.WorkflowDagResult,
+) []workflow_dag_result.WorkflowDagResult {
+	ahead := []workflow_dag_result.WorkflowDagResult{}
+	for _, other := range pending {
+		if other.Id == run.Id {
+			continue
+		}
+
+		if other.CreatedAt.Before(run.CreatedAt) ||
+			(other.CreatedAt.Equal(run.CreatedAt) && other.Id.String() < run.Id.String()) {
+			ahead = append(ahead, other)
+		}
+	}
+
+	return ahead
+}
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/collections/workflow_dag_result"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunsAhead(t *testing.T) {
+	now := time.Now()
+	earlier := workflow_dag_result.WorkflowDagResult{Id: uuid.New(), CreatedAt: now.Add(-time.Minute)}
+	run := workflow_dag_result.WorkflowDagResult{Id: uuid.MustParse("50000000-0000-0000-0000-000000000000"), CreatedAt: now}
+	tiedBefore := workflow_dag_result.WorkflowDagResult{Id: uuid.MustParse("10000000-0000-0000-0000-000000000000"), CreatedAt: now}
+	tiedAfter := workflow_dag_result.WorkflowDagResult{Id: uuid.MustParse("90000000-0000-0000-0000-000000000000"), CreatedAt: now}
+	later := workflow_dag_result.WorkflowDagResult{Id: uuid.New(), CreatedAt: now.Add(time.Minute)}
+
+	pending := []workflow_dag_result.WorkflowDagResult{later, tiedAfter, run, tiedBefore, earlier}
+	require.Equal(t, []workflow_dag_result.WorkflowDagResult{tiedBefore, earlier}, RunsAhead(pending, &run))
+
+	// The earliest run has nothing ahead of it.
+	require.Empty(t, RunsAhead(pending, &earlier))
+}
@@ -3,12 +3,14 @@ package utils
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 
 	"github.com/aqueducthq/aqueduct/lib/collections/artifact"
 	"github.com/aqueducthq/aqueduct/lib/collections/artifact_result"
 	"github.com/aqueducthq/aqueduct/lib/collections/notification"
 	"github.com/aqueducthq/aqueduct/lib/collections/operator"
 	"github.com/aqueducthq/aqueduct/lib/collections/operator_result"
+	"github.com/aqueducthq/aqueduct/lib/collections/run_request"
 	"github.com/aqueducthq/aqueduct/lib/collections/shared"
 	"github.com/aqueducthq/aqueduct/lib/collections/user"
 	"github.com/aqueducthq/aqueduct/lib/collections/workflow"
@@ -48,6 +50,12 @@ func GenerateWorkflowStoragePaths(dag *workflow_dag.WorkflowDag) *WorkflowStorag
 	return &workflowStoragePaths
 }
 
+// OperatorResultLogsPath returns the storage path that the stdout and stderr of the job
+// of the given operator result are streamed to.
+func OperatorResultLogsPath(operatorResultId uuid.UUID) string {
+	return fmt.Sprintf("operator-logs-%s", operatorResultId)
+}
+
 func CleanupWorkflowStorageFiles(
 	ctx context.Context,
 	workflowStoragePaths *WorkflowStoragePaths,
@@ -127,12 +135,24 @@ func WriteWorkflowDagToDatabase(
 			dag.Metadata.Description,
 			&dag.Metadata.Schedule,
 			&dag.Metadata.RetentionPolicy,
+			&dag.Metadata.ExecutionPolicy,
 			db,
 		)
 		if err != nil {
 			return uuid.Nil, errors.Wrap(err, "Unable to create workflow in the database.")
 		}
 		workflowId = workflow.Id
+	} else {
+		// The execution policy belongs to the workflow, but is registered with each of its dags.
+		_, err := workflowWriter.UpdateWorkflow(
+			ctx,
+			workflowId,
+			map[string]interface{}{workflow.ExecutionPolicyColumn: &dag.Metadata.ExecutionPolicy},
+			db,
+		)
+		if err != nil {
+			return uuid.Nil, errors.Wrap(err, "Unable to update the execution policy of the workflow.")
+		}
 	}
 
 	workflowDag, err := workflowDagWriter.CreateWorkflowDag(
@@ -412,35 +432,77 @@ func UpdateWorkflowDagToLatest(
 	)
 }
 
+// This helper function is called once a non-preview run finishes. It records the run's status
+// and requests runs of the workflows that cascade from the run's workflow.
+// The status is only recorded if the run is still running. A run that was cancelled, or failed
+// because its executor was thought to be gone, keeps that status, and the workflows that cascade
+// from it are triggered based on it.
+// It logs any error that occurs during these steps.
 func UpdateWorkflowDagResultMetadata(
 	ctx context.Context,
+	workflowId uuid.UUID,
 	workflowDagResultId uuid.UUID,
 	status shared.ExecutionStatus,
+	workflowDagResultReader workflow_dag_result.Reader,
 	workflowDagResultWriter workflow_dag_result.Writer,
 	workflowReader workflow.Reader,
 	notificationWriter notification.Writer,
 	userReader user.Reader,
+	workflowDagReader workflow_dag.Reader,
+	runRequestWriter run_request.Writer,
 	db database.Database,
 ) {
 	changes := map[string]interface{}{
 		workflow_dag_result.StatusColumn: status,
 	}
 
-	_, err := workflowDagResultWriter.UpdateWorkflowDagResult(
+	_, err := workflowDagResultWriter.UpdateWorkflowDagResultWithStatus(
 		ctx,
 		workflowDagResultId,
+		workflow_dag_result.RunningStatuses,
 		changes,
 		workflowReader,
 		notificationWriter,
 		userReader,
 		db,
 	)
-	if err != nil {
+	if err == database.ErrNoRows {
+		workflowDagResult, err := workflowDagResultReader.GetWorkflowDagResult(ctx, workflowDagResultId, db)
+		if err != nil {
+			log.Errorf("Unable to get the status of workflow dag result %s: %v", workflowDagResultId, err)
+			return
+		}
+
+		log.Infof(
+			"Workflow dag result %s already has status %s, so status %s is not recorded.",
+			workflowDagResultId,
+			workflowDagResult.Status,
+			status,
+		)
+		status = workflowDagResult.Status
+	} else if err != nil {
 		log.WithFields(
 			log.Fields{
 				"changes": changes,
 			},
 		).Errorf("Unable to update workflow dag result metadata: %v", err)
+		return
+	}
+
+	// The run has finished, so the workflows that cascade from this one may run.
+	err = triggerCascadingWorkflows(
+		ctx,
+		workflowId,
+		status,
+		workflowReader,
+		workflowDagReader,
+		workflowDagResultReader,
+		workflowDagResultWriter,
+		runRequestWriter,
+		db,
+	)
+	if err != nil {
+		log.Errorf("Unable to trigger the workflows that cascade from workflow %s: %v", workflowId, err)
 	}
 }
 
@@ -462,9 +524,15 @@ func UpdateOperatorAndArtifactResults(
 ) {
 	artifactStatuses := make(map[uuid.UUID]shared.ExecutionStatus, len(operator.Outputs))
 	artifactIdToArtifactMetadata := make(map[uuid.UUID]*artifact_result.Metadata, len(operator.Outputs))
-	// Initialize the map.
+	// Initialize the map. The output artifacts of a cancelled or skipped operator are cancelled
+	// or skipped as well.
+	defaultArtifactStatus := shared.FailedExecutionStatus
+	if operatorStatus == shared.CanceledExecutionStatus || operatorStatus == shared.SkippedExecutionStatus {
+		defaultArtifactStatus = operatorStatus
+	}
+
 	for _, artifactId := range operator.Outputs {
-		artifactStatuses[artifactId] = shared.FailedExecutionStatus
+		artifactStatuses[artifactId] = defaultArtifactStatus
 		artifactIdToArtifactMetadata[artifactId] = nil
 	}
 
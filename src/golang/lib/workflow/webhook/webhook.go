@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/dropbox/godropbox/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	signaturePrefix = "sha256="
+	secretTokenKey  = "token"
+	tokenNumBytes   = 32
+
+	// How far the timestamp of a webhook request may be from the server's clock. Requests
+	// outside this window are rejected, so that a captured request cannot be replayed later.
+	timestampTolerance = 5 * time.Minute
+)
+
+var (
+	ErrInvalidSignature = errors.New("The webhook request is not signed with the workflow's token.")
+	ErrInvalidTimestamp = errors.New("The webhook request timestamp is malformed or outside the allowed window.")
+)
+
+// secretName returns the name of the vault secret that holds the workflow's webhook token.
+func secretName(workflowId uuid.UUID) string {
+	return fmt.Sprintf("webhook-%s", workflowId)
+}
+
+// IssueToken generates a new webhook token for the workflow and stores it in the vault,
+// replacing any token issued earlier.
+func IssueToken(ctx context.Context, workflowId uuid.UUID, vaultObject vault.Vault) (string, error) {
+	raw := make([]byte, tokenNumBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	token := hex.EncodeToString(raw)
+	if err := vaultObject.Put(ctx, secretName(workflowId), map[string]string{secretTokenKey: token}); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// Sign returns the signature of a webhook request: `sha256=` followed by the hex encoded
+// HMAC-SHA256 of `<timestamp>.<body>`, keyed by the workflow's token. `timestamp` is the
+// request's time in Unix seconds.
+func Sign(token string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks that `signature` is the signature of `timestamp` and `body` with the
+// workflow's webhook token. It returns ErrInvalidTimestamp if `timestamp` is not within
+// timestampTolerance of `now`, and ErrInvalidSignature if the workflow has no token or the
+// signature does not match.
+func VerifySignature(
+	ctx context.Context,
+	workflowId uuid.UUID,
+	body []byte,
+	timestamp string,
+	signature string,
+	now time.Time,
+	vaultObject vault.Vault,
+) error {
+	unixSeconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+
+	skew := now.Sub(time.Unix(unixSeconds, 0))
+	if skew > timestampTolerance || skew < -timestampTolerance {
+		return ErrInvalidTimestamp
+	}
+
+	secrets, err := vaultObject.Get(ctx, secretName(workflowId))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	token, ok := secrets[secretTokenKey]
+	if !ok || token == "" {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(Sign(token, timestamp, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package webhook
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aqueducthq/aqueduct/lib/vault"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySignature(t *testing.T) {
+	ctx := context.Background()
+	vaultObject, err := vault.NewVault(&vault.FileConfig{
+		Directory:     t.TempDir(),
+		EncryptionKey: "aqueduct-webhook-test-key-32byte",
+	})
+	require.Nil(t, err)
+
+	workflowId := uuid.New()
+	body := []byte(`{"parameters": {"date": "2022-06-01"}}`)
+	now := time.Unix(1654041600, 0)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	verify := func(timestamp string, body []byte, signature string) error {
+		return VerifySignature(ctx, workflowId, body, timestamp, signature, now, vaultObject)
+	}
+
+	// A workflow without a token rejects every request.
+	require.Equal(t, ErrInvalidSignature, verify(timestamp, body, Sign("token", timestamp, body)))
+
+	token, err := IssueToken(ctx, workflowId, vaultObject)
+	require.Nil(t, err)
+	require.Nil(t, verify(timestamp, body, Sign(token, timestamp, body)))
+	require.Equal(t, ErrInvalidSignature, verify(timestamp, []byte("{}"), Sign(token, timestamp, body)))
+
+	// The timestamp is signed along with the body, and must be close to the server's clock.
+	later := strconv.FormatInt(now.Add(time.Minute).Unix(), 10)
+	require.Nil(t, verify(later, body, Sign(token, later, body)))
+	require.Equal(t, ErrInvalidSignature, verify(later, body, Sign(token, timestamp, body)))
+
+	stale := strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10)
+	require.Equal(t, ErrInvalidTimestamp, verify(stale, body, Sign(token, stale, body)))
+	future := strconv.FormatInt(now.Add(10*time.Minute).Unix(), 10)
+	require.Equal(t, ErrInvalidTimestamp, verify(future, body, Sign(token, future, body)))
+	require.Equal(t, ErrInvalidTimestamp, verify("", body, Sign(token, "", body)))
+
+	// Issuing a new token revokes the old one.
+	newToken, err := IssueToken(ctx, workflowId, vaultObject)
+	require.Nil(t, err)
+	require.Equal(t, ErrInvalidSignature, verify(timestamp, body, Sign(token, timestamp, body)))
+	require.Nil(t, verify(timestamp, body, Sign(newToken, timestamp, body)))
+}
@@ -1,16 +1,25 @@
 import argparse
 import base64
 import json
+import numbers
+import os
 import sys
+import time
 import traceback
+from typing import Any
 
+import numpy as np
 from pydantic import parse_obj_as
 
-from aqueduct_executor.operators.connectors.tabular import common, config, connector, spec
+from aqueduct_executor.operators.connectors.tabular import common, config, connector, extract, spec
 from aqueduct_executor.operators.utils import enums, utils
 from aqueduct_executor.operators.utils.storage.parse import parse_storage
 from aqueduct_executor.operators.utils.storage.storage import Storage
 
+# The environment variable that holds the credentials of the integration as a JSON object, for
+# jobs whose spec leaves them out, e.g. the tasks of a generated Airflow DAG.
+CONNECTOR_CONFIG_ENV_VAR = "AQUEDUCT_CONNECTOR_CONFIG"
+
 
 def run(spec: spec.Spec, storage: Storage):
     """
@@ -19,6 +28,7 @@ def run(spec: spec.Spec, storage: Storage):
     - extract
     - load
     - discover
+    - sensor
 
     Arguments:
     - spec: The spec provided for this operator.
@@ -35,6 +45,8 @@ def run(spec: spec.Spec, storage: Storage):
         run_load(spec, op, storage)
     elif spec.type == enums.JobType.DISCOVER:
         run_discover(spec, op, storage)
+    elif spec.type == enums.JobType.SENSOR:
+        run_sensor(spec, op, storage)
     else:
         raise Exception("Unknown job: %s" % spec.type)
 
@@ -71,6 +83,65 @@ def run_discover(spec: spec.DiscoverSpec, op: connector.TabularConnector, storag
     utils.write_discover_results(storage, spec.output_content_path, tables)
 
 
+def _is_true(value: Any) -> bool:
+    """
+    Returns whether a value returned by the sensor's query counts as true. Only a boolean true or
+    the number 1 does; NULL, NaN, strings and every other value count as false.
+    """
+    if isinstance(value, (bool, np.bool_)):
+        return bool(value)
+
+    if isinstance(value, numbers.Number):
+        return bool(value == 1)
+
+    return False
+
+
+def run_sensor(spec: spec.SensorSpec, op: connector.TabularConnector, storage: Storage):
+    """
+    Runs the sensor's query every poll interval until it returns true, and fails once the
+    deadline passes. A query that fails is logged and retried at the next poll.
+    """
+    deadline = time.monotonic() + spec.deadline_seconds
+    params = extract.RelationalParams(query=spec.query)
+    last_error = None
+    while True:
+        try:
+            df = op.extract(params)
+            last_error = None
+            if len(df.index) > 0 and len(df.columns) > 0 and _is_true(df.iat[0, 0]):
+                break
+            print(
+                "The sensor's query returned false, polling again in %d seconds."
+                % spec.poll_interval_seconds
+            )
+        except Exception as e:
+            last_error = e
+            print(
+                "The sensor's query failed, polling again in %d seconds."
+                % spec.poll_interval_seconds
+            )
+            traceback.print_exc()
+
+        if time.monotonic() + spec.poll_interval_seconds > deadline:
+            message = "The sensor's query did not return true within %d seconds." % (
+                spec.deadline_seconds
+            )
+            if last_error is not None:
+                message += " Its last poll failed with: %s" % last_error
+            raise Exception(message)
+
+        time.sleep(spec.poll_interval_seconds)
+
+    utils.write_artifacts(
+        storage,
+        [spec.output_content_path],
+        [spec.output_metadata_path],
+        [True],
+        [utils.OutputArtifactType.BOOL],
+    )
+
+
 def setup_connector(
     connector_name: common.Name, connector_config: config.Config
 ) -> connector.TabularConnector:
@@ -126,6 +197,11 @@ def _parse_spec(spec_json: str) -> spec.Spec:
 
     print("Job Spec: \n{}".format(json.dumps(data, indent=4)))
 
+    # The credentials are added after the spec is printed, so that they are not logged.
+    connector_config = os.environ.get(CONNECTOR_CONFIG_ENV_VAR)
+    if connector_config:
+        data["connector_config"] = {"conf": json.loads(connector_config)}
+
     return parse_obj_as(spec.Spec, data)
 
 
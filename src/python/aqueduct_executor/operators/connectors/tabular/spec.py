@@ -100,6 +100,25 @@ class LoadSpec(models.BaseSpec):
     )
 
 
+class SensorSpec(models.BaseSpec):
+    name: str
+    type: Literal[enums.JobType.SENSOR]
+    storage_config: sconfig.StorageConfig
+    metadata_path: str
+    connector_name: common.Name
+    connector_config: config.Config
+    query: str
+    poll_interval_seconds: float
+    deadline_seconds: float
+    output_content_path: str
+    output_metadata_path: str
+
+    # validators
+    _unwrap_connector_config = validator("connector_config", allow_reuse=True, pre=True)(
+        unwrap_connector_config
+    )
+
+
 class DiscoverSpec(models.BaseSpec):
     name: str
     type: Literal[enums.JobType.DISCOVER]
@@ -115,4 +134,4 @@ class DiscoverSpec(models.BaseSpec):
     )
 
 
-Spec = Union[AuthenticateSpec, ExtractSpec, LoadSpec, DiscoverSpec]
+Spec = Union[AuthenticateSpec, ExtractSpec, LoadSpec, DiscoverSpec, SensorSpec]
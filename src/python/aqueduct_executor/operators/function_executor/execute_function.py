@@ -12,6 +12,7 @@ from typing import Any, Callable, List, Tuple, Dict
 from aqueduct_executor.operators.function_executor import spec
 from aqueduct_executor.operators.function_executor.utils import OP_DIR
 from aqueduct_executor.operators.utils import utils
+from aqueduct_executor.operators.utils.enums import OutputArtifactType
 from aqueduct_executor.operators.utils.storage.storage import Storage
 from aqueduct_executor.operators.utils.storage.parse import parse_storage
 
@@ -162,6 +163,24 @@ def _write_outputs(
     utils.write_operator_metadata(storage, spec.metadata_path, "", logs)
 
 
+def _serialize_json_outputs(
+    results: List[Any], artifact_types: List[OutputArtifactType]
+) -> List[Any]:
+    """
+    Serializes the results that are written to JSON artifacts, e.g. a dict of configs,
+    since JSON artifacts store their content as serialized JSON.
+    """
+    serialized = []
+    for (result, artifact_type) in zip(results, artifact_types):
+        if artifact_type == OutputArtifactType.JSON:
+            try:
+                result = json.dumps(result)
+            except TypeError as e:
+                raise Exception("Expected output to be JSON serializable, but %s" % e)
+        serialized.append(result)
+    return serialized
+
+
 def run(spec: spec.FunctionSpec) -> None:
     """
     Executes a function operator.
@@ -181,10 +200,17 @@ def run(spec: spec.FunctionSpec) -> None:
 
         print("Function invoked successfully!")
 
-        # Force all results to be of type `list`, so we can always loop over them.
-        if not isinstance(results, list):
+        # Force all results to be of type `list`, so we can always loop over them. Multiple
+        # outputs can also be returned as a tuple. A list is a single output if the function
+        # only outputs JSON, since JSON can be a list itself.
+        if isinstance(results, tuple) and len(spec.output_artifact_types) > 1:
+            results = list(results)
+        elif not isinstance(results, list) or spec.output_artifact_types == [
+            OutputArtifactType.JSON
+        ]:
             results = [results]
 
+        results = _serialize_json_outputs(results, spec.output_artifact_types)
         utils.write_artifacts(
             storage,
             spec.output_content_paths,
@@ -197,7 +223,9 @@ def run(spec: spec.FunctionSpec) -> None:
     except Exception as e:
         utils.write_operator_metadata(storage, spec.metadata_path, str(e), logs)
         print("Exception Raised: ", e)
-        traceback.print_tb(e.__traceback__)
+        # The full traceback ends with the exception, which tells the job manager whether the
+        # function ran out of memory.
+        traceback.print_exc()
         sys.exit(1)
 
 
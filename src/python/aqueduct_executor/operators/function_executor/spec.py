@@ -7,7 +7,7 @@ except ImportError:
     # Python 3.7 does not support typing.Literal
     from typing_extensions import Literal
 
-from pydantic import BaseModel, Extra, parse_obj_as, validator
+from pydantic import BaseModel, Extra, parse_obj_as
 from aqueduct_executor.operators.utils import enums
 from aqueduct_executor.operators.utils.storage import config
 
@@ -33,15 +33,6 @@ class FunctionSpec(BaseModel):
     class Config:
         extra = Extra.forbid
 
-    @validator("output_artifact_types")
-    def check_metric_outputs(cls, output_artifact_types):
-        if (
-            len(output_artifact_types) > 1
-            and enums.OutputArtifactType.FLOAT in output_artifact_types
-        ):
-            raise ValueError("A metric operator cannot have multiple outputs.")
-        return output_artifact_types
-
 
 def parse_spec(spec_json: str) -> FunctionSpec:
     """
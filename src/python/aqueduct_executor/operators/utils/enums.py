@@ -20,11 +20,13 @@ class JobType(str, Enum, metaclass=MetaEnum):
     LOAD = "load"
     DISCOVER = "discover"
     PARAM = "param"
+    SENSOR = "sensor"
 
 
 class InputArtifactType(str, Enum, metaclass=MetaEnum):
     TABLE = "table"
     FLOAT = "float"
+    BOOL = "boolean"
     JSON = "json"
 
 
@@ -12,7 +12,7 @@ _DEFAULT_ENCODING = "utf8"
 
 
 # Typing: all the possible artifact types to a function. Should be in sync with `InputArtifactType`.
-InputArtifact = Union[pd.DataFrame, float, int]
+InputArtifact = Union[pd.DataFrame, float, int, bool, Any]
 
 
 def read_artifacts(
@@ -37,6 +37,8 @@ def read_artifacts(
         elif artifact_type == InputArtifactType.FLOAT:
             # TODO(ENG-1119): A float artifact currently also represents integers.
             inputs.append(_read_numeric_input(storage, input_path))
+        elif artifact_type == InputArtifactType.BOOL:
+            inputs.append(_read_bool_input(storage, input_path))
         elif artifact_type == InputArtifactType.JSON:
             inputs.append(_read_json_input(storage, input_path))
         else:
@@ -62,6 +64,11 @@ def _read_numeric_input(storage: Storage, path: str) -> Union[float, int]:
     return float(input_bytes)
 
 
+def _read_bool_input(storage: Storage, path: str) -> bool:
+    input_bytes = storage.get(path)
+    return input_bytes.decode(_DEFAULT_ENCODING) == str(True)
+
+
 def _read_json_input(storage: Storage, path: str) -> Any:
     input_bytes = storage.get(path)
     return json.loads(input_bytes)